@@ -0,0 +1,523 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// command is a command of the cli. setup registers the flags of the command and returns the function running it with the remaining arguments.
+type command struct {
+	name  string
+	args  string
+	help  string
+	setup func(flags *flag.FlagSet) func(e *env, args []string) error
+}
+
+// noFlags is the setup of commands without flags.
+func noFlags(run func(e *env, args []string) error) func(flags *flag.FlagSet) func(e *env, args []string) error {
+	return func(*flag.FlagSet) func(e *env, args []string) error {
+		return run
+	}
+}
+
+// nargs checks the number of arguments of a command.
+func nargs(args []string, n int, usage string) error {
+	if len(args) != n {
+		return usagef("expected %d arguments: %s", n, usage)
+	}
+	return nil
+}
+
+// listFlag is a flag which can be repeated.
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// parsePayment parses a payment written as address:amount[:label], e.g. bc1q...:0.001BTC:rent.
+func parsePayment(s string) (wasabi.Payment, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) < 2 {
+		return wasabi.Payment{}, usagef("invalid payment %q, expected address:amount[:label]", s)
+	}
+	amount, err := wasabi.ParseAmount(parts[1])
+	if err != nil {
+		return wasabi.Payment{}, usagef("invalid payment %q: %v", s, err)
+	}
+	payment := wasabi.Payment{SendTo: wasabi.Address(parts[0]), Amount: amount}
+	if len(parts) == 3 {
+		payment.Label = parts[2]
+	}
+	return payment, nil
+}
+
+// parseCoin parses a coin written as txid:index.
+func parseCoin(s string) (wasabi.Coin, error) {
+	txID, index, ok := strings.Cut(s, ":")
+	n, err := strconv.Atoi(index)
+	if !ok || err != nil || n < 0 {
+		return wasabi.Coin{}, usagef("invalid coin %q, expected txid:index", s)
+	}
+	id, err := wasabi.ParseTxID(txID)
+	if err != nil {
+		return wasabi.Coin{}, usagef("invalid coin %q: %v", s, err)
+	}
+	return wasabi.Coin{TransactionID: id, Index: n}, nil
+}
+
+// transactionFlags are the flags of the commands building transactions.
+type transactionFlags struct {
+	payments    listFlag
+	coins       listFlag
+	feeTarget   wasabi.FeeTarget
+	feeRate     float64
+	subtractFee int
+}
+
+func (t *transactionFlags) register(flags *flag.FlagSet) {
+	flags.Var(&t.payments, "to", "payment as address:amount[:label], e.g. tb1q...:1500sats (repeatable)")
+	flags.Var(&t.coins, "coin", "coin to spend as txid:index (repeatable, default: selected by the daemon)")
+	flags.Func("fee-target", "confirmation target in blocks, or next-block, half-hour, hour or economy", func(s string) (err error) {
+		t.feeTarget, err = wasabi.ParseFeeTarget(s)
+		return err
+	})
+	flags.Float64Var(&t.feeRate, "fee-rate", 0, "fee rate in sat/vB")
+	flags.IntVar(&t.subtractFee, "subtract-fee", 0, "number (1-based) of the payment paying the fee")
+}
+
+func (t *transactionFlags) parse() ([]wasabi.Payment, []wasabi.Coin, error) {
+	if len(t.payments) == 0 {
+		return nil, nil, usagef("at least one payment (-to) is required")
+	}
+	if t.feeTarget == 0 && t.feeRate == 0 {
+		return nil, nil, usagef("-fee-target or -fee-rate is required")
+	}
+	payments := make([]wasabi.Payment, 0, len(t.payments))
+	for _, p := range t.payments {
+		payment, err := parsePayment(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		payments = append(payments, payment)
+	}
+	if t.subtractFee != 0 {
+		if t.subtractFee < 1 || t.subtractFee > len(payments) {
+			return nil, nil, usagef("-subtract-fee must be between 1 and %d", len(payments))
+		}
+		payments[t.subtractFee-1].SubtractFee = true
+	}
+	var coins []wasabi.Coin
+	for _, c := range t.coins {
+		coin, err := parseCoin(c)
+		if err != nil {
+			return nil, nil, err
+		}
+		coins = append(coins, coin)
+	}
+	return payments, coins, nil
+}
+
+// walletCommand returns the run function of a command of a wallet without arguments which prints the result of call.
+func walletCommand[T any](call func(c wasabi.Client, walletName string) (T, error)) func(flags *flag.FlagSet) func(e *env, args []string) error {
+	return noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 0, ""); err != nil {
+			return err
+		}
+		walletName, err := e.wallet()
+		if err != nil {
+			return err
+		}
+		result, err := call(e.client, walletName)
+		if err != nil {
+			return err
+		}
+		return e.print(result)
+	})
+}
+
+// done is printed by the commands without result.
+var done = struct {
+	Result string `json:"result"`
+}{"ok"}
+
+var commands = []command{
+	{name: "getstatus", help: "show the status of the daemon", setup: noFlags(func(e *env, args []string) error {
+		status, err := e.client.GetStatus()
+		if err != nil {
+			return err
+		}
+		return e.print(status)
+	})},
+	{name: "ping", help: "check that the daemon answers calls and show the latency", setup: noFlags(func(e *env, args []string) error {
+		result, err := e.client.Ping(context.Background())
+		if err != nil {
+			return err
+		}
+		return e.print(struct {
+			Latency       string `json:"latency"`
+			Authenticated bool   `json:"authenticated"`
+		}{result.Latency.String(), result.Authenticated})
+	})},
+	{name: "listwallets", help: "list the wallets", setup: func(flags *flag.FlagSet) func(e *env, args []string) error {
+		loaded := flags.Bool("loaded", false, "list only the loaded wallets")
+		return func(e *env, args []string) error {
+			list := e.client.ListWallets
+			if *loaded {
+				list = func() ([]wasabi.ListWalletsResponseItem, error) {
+					return wasabi.ListLoadedWallets(e.client)
+				}
+			}
+			wallets, err := list()
+			if err != nil {
+				return err
+			}
+			return e.print(wallets)
+		}
+	}},
+	{name: "getfeerates", help: "show the fee rates by confirmation target", setup: noFlags(func(e *env, args []string) error {
+		rates, err := e.client.GetFeeRates()
+		if err != nil {
+			return err
+		}
+		return e.print(rates)
+	})},
+	{name: "createwallet", args: "<name>", help: "create a wallet with the -password and print its mnemonic", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 1, "createwallet <name>"); err != nil {
+			return err
+		}
+		mnemonic, err := e.client.CreateWallet(args[0], e.cfg.WalletPassword)
+		if err != nil {
+			return err
+		}
+		return e.print(mnemonic)
+	})},
+	{name: "recoverwallet", args: "<name>", help: "recover a wallet from the mnemonic read from stdin", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 1, "recoverwallet <name>"); err != nil {
+			return err
+		}
+		mnemonic, err := bufio.NewReader(e.stdin).ReadString('\n')
+		mnemonic = strings.TrimSpace(mnemonic)
+		if mnemonic == "" {
+			return usagef("the mnemonic must be written to stdin: %v", err)
+		}
+		if err := e.client.RecoverWallet(args[0], mnemonic, e.cfg.WalletPassword); err != nil {
+			return err
+		}
+		return e.print(done)
+	})},
+	{name: "loadwallet", args: "[name]", help: "load a wallet (default -wallet)", setup: noFlags(func(e *env, args []string) error {
+		if len(args) > 1 {
+			return usagef("expected at most 1 argument: loadwallet [name]")
+		}
+		walletName := e.cfg.Wallet
+		if len(args) == 1 {
+			walletName = args[0]
+		}
+		if walletName == "" {
+			return usagef("the command requires a wallet")
+		}
+		if err := e.client.LoadWallet(walletName); err != nil {
+			return err
+		}
+		return e.print(done)
+	})},
+	{name: "getwalletinfo", help: "show information about the wallet", setup: walletCommand(func(c wasabi.Client, walletName string) (wasabi.GetWalletInfoResponse, error) {
+		return c.GetWalletInfo(walletName)
+	})},
+	{name: "listcoins", help: "list the coins of the wallet", setup: walletCommand(func(c wasabi.Client, walletName string) ([]wasabi.ListCoinsResponse, error) {
+		return c.ListCoins(walletName)
+	})},
+	{name: "listunspentcoins", help: "list the unspent coins of the wallet", setup: walletCommand(func(c wasabi.Client, walletName string) ([]wasabi.ListCoinsResponse, error) {
+		return c.ListUnspentCoins(walletName)
+	})},
+	{name: "gethistory", help: "list the transactions of the wallet", setup: walletCommand(func(c wasabi.Client, walletName string) ([]wasabi.Transaction, error) {
+		return c.GetHistory(walletName)
+	})},
+	{name: "listkeys", help: "list the keys of the wallet", setup: walletCommand(func(c wasabi.Client, walletName string) ([]wasabi.GeneratedKey, error) {
+		return c.ListKeys(walletName)
+	})},
+	{name: "listpaymentsincoinjoin", help: "list the payments in coinjoin of the wallet", setup: walletCommand(func(c wasabi.Client, walletName string) ([]wasabi.ListPaymentsInCoinJoinResponseItem, error) {
+		return c.ListPaymentsInCoinJoin(walletName)
+	})},
+	{name: "getnewaddress", args: "<label>", help: "generate a receive address", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 1, "getnewaddress <label>"); err != nil {
+			return err
+		}
+		walletName, err := e.wallet()
+		if err != nil {
+			return err
+		}
+		address, err := e.client.GetNewAddress(walletName, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(address)
+	})},
+	{name: "send", args: "-to addr:amount ...", help: "build, sign and broadcast a transaction", setup: func(flags *flag.FlagSet) func(e *env, args []string) error {
+		var t transactionFlags
+		t.register(flags)
+		return func(e *env, args []string) error {
+			walletName, err := e.wallet()
+			if err != nil {
+				return err
+			}
+			payments, coins, err := t.parse()
+			if err != nil {
+				return err
+			}
+			response, err := e.client.Send(walletName, wasabi.SendRequest{Payments: payments, Coins: coins, FeeTarget: t.feeTarget, FeeRate: t.feeRate, Password: e.cfg.WalletPassword})
+			if err != nil {
+				return err
+			}
+			return e.print(response)
+		}
+	}},
+	{name: "build", args: "-to addr:amount ...", help: "build and sign a transaction without broadcasting it", setup: func(flags *flag.FlagSet) func(e *env, args []string) error {
+		var t transactionFlags
+		t.register(flags)
+		return func(e *env, args []string) error {
+			walletName, err := e.wallet()
+			if err != nil {
+				return err
+			}
+			payments, coins, err := t.parse()
+			if err != nil {
+				return err
+			}
+			tx, err := e.client.Build(walletName, wasabi.BuildRequest{Payments: payments, Coins: coins, FeeTarget: t.feeTarget, FeeRate: t.feeRate, Password: e.cfg.WalletPassword})
+			if err != nil {
+				return err
+			}
+			return e.print(tx)
+		}
+	}},
+	{name: "buildunsafetransaction", args: "-to addr:amount ...", help: "build a transaction without the privacy checks", setup: func(flags *flag.FlagSet) func(e *env, args []string) error {
+		var t transactionFlags
+		t.register(flags)
+		maxFee := flags.String("max-fee", "", "highest acceptable fee, e.g. 5000sats")
+		return func(e *env, args []string) error {
+			walletName, err := e.wallet()
+			if err != nil {
+				return err
+			}
+			payments, coins, err := t.parse()
+			if err != nil {
+				return err
+			}
+			req := wasabi.BuildUnsafeRequest{Payments: payments, Coins: coins, FeeTarget: t.feeTarget, FeeRate: t.feeRate, Password: e.cfg.WalletPassword}
+			if *maxFee != "" {
+				if req.MaxFee, err = wasabi.ParseAmount(*maxFee); err != nil {
+					return usagef("invalid -max-fee: %v", err)
+				}
+			}
+			tx, err := e.client.BuildUnsafeTransaction(walletName, req)
+			if err != nil {
+				return err
+			}
+			return e.print(tx)
+		}
+	}},
+	{name: "broadcast", args: "<tx hex>", help: "broadcast a signed transaction", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 1, "broadcast <tx hex>"); err != nil {
+			return err
+		}
+		txID, err := e.client.Broadcast(e.cfg.Wallet, args[0])
+		if err != nil {
+			return err
+		}
+		return e.print(txID)
+	})},
+	{name: "startcoinjoin", help: "start the coinjoin of the wallet", setup: func(flags *flag.FlagSet) func(e *env, args []string) error {
+		var opts wasabi.StartCoinJoinOptions
+		flags.BoolVar(&opts.StopWhenAllMixed, "stop-when-all-mixed", false, "stop when all coins are mixed")
+		flags.BoolVar(&opts.OverridePlebStop, "override-pleb-stop", false, "start even below the pleb stop threshold")
+		return func(e *env, args []string) error {
+			walletName, err := e.wallet()
+			if err != nil {
+				return err
+			}
+			if err := e.client.StartCoinJoin(walletName, e.cfg.WalletPassword, opts); err != nil {
+				return err
+			}
+			return e.print(done)
+		}
+	}},
+	{name: "startcoinjoinsweep", args: "<output wallet>", help: "coinjoin all coins of the wallet into another wallet", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 1, "startcoinjoinsweep <output wallet>"); err != nil {
+			return err
+		}
+		walletName, err := e.wallet()
+		if err != nil {
+			return err
+		}
+		if err := e.client.StartCoinJoinSweep(walletName, e.cfg.WalletPassword, args[0]); err != nil {
+			return err
+		}
+		return e.print(done)
+	})},
+	{name: "stopcoinjoin", help: "stop the coinjoin of the wallet", setup: noFlags(func(e *env, args []string) error {
+		walletName, err := e.wallet()
+		if err != nil {
+			return err
+		}
+		if err := e.client.StopCoinJoin(walletName); err != nil {
+			return err
+		}
+		return e.print(done)
+	})},
+	{name: "excludefromcoinjoin", args: "<txid:index> ...", help: "exclude coins from the coinjoin", setup: func(flags *flag.FlagSet) func(e *env, args []string) error {
+		include := flags.Bool("include", false, "include the coins again")
+		return func(e *env, args []string) error {
+			if len(args) == 0 {
+				return usagef("expected at least 1 coin: excludefromcoinjoin <txid:index> ...")
+			}
+			walletName, err := e.wallet()
+			if err != nil {
+				return err
+			}
+			coins := make([]wasabi.Coin, 0, len(args))
+			for _, arg := range args {
+				coin, err := parseCoin(arg)
+				if err != nil {
+					return err
+				}
+				coins = append(coins, coin)
+			}
+			if err := e.client.ExcludeCoinsFromCoinJoin(walletName, coins, !*include); err != nil {
+				return err
+			}
+			return e.print(done)
+		}
+	}},
+	{name: "payincoinjoin", args: "<address> <amount>", help: "pay an address in a coinjoin, e.g. 0.001BTC", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 2, "payincoinjoin <address> <amount>"); err != nil {
+			return err
+		}
+		walletName, err := e.wallet()
+		if err != nil {
+			return err
+		}
+		amount, err := wasabi.ParseAmount(args[1])
+		if err != nil {
+			return usagef("%v", err)
+		}
+		id, err := e.client.PayInCoinJoin(walletName, wasabi.Address(args[0]), amount, e.cfg.WalletPassword)
+		if err != nil {
+			return err
+		}
+		return e.print(id)
+	})},
+	{name: "cancelpaymentincoinjoin", args: "<payment id>", help: "cancel a pending payment in coinjoin", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 1, "cancelpaymentincoinjoin <payment id>"); err != nil {
+			return err
+		}
+		walletName, err := e.wallet()
+		if err != nil {
+			return err
+		}
+		if err := e.client.CancelPaymentInCoinJoin(walletName, args[0]); err != nil {
+			return err
+		}
+		return e.print(done)
+	})},
+	{name: "canceltransaction", args: "<txid>", help: "build a transaction canceling an unconfirmed transaction", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 1, "canceltransaction <txid>"); err != nil {
+			return err
+		}
+		walletName, err := e.wallet()
+		if err != nil {
+			return err
+		}
+		txID, err := wasabi.ParseTxID(args[0])
+		if err != nil {
+			return usagef("%v", err)
+		}
+		tx, err := e.client.CancelTransaction(walletName, txID, e.cfg.WalletPassword)
+		if err != nil {
+			return err
+		}
+		return e.print(tx)
+	})},
+	{name: "speeduptransaction", args: "<txid>", help: "build a transaction speeding up an unconfirmed transaction", setup: noFlags(func(e *env, args []string) error {
+		if err := nargs(args, 1, "speeduptransaction <txid>"); err != nil {
+			return err
+		}
+		walletName, err := e.wallet()
+		if err != nil {
+			return err
+		}
+		txID, err := wasabi.ParseTxID(args[0])
+		if err != nil {
+			return usagef("%v", err)
+		}
+		tx, err := e.client.SpeedUpTransaction(walletName, txID, e.cfg.WalletPassword)
+		if err != nil {
+			return err
+		}
+		return e.print(tx)
+	})},
+	{name: "stop", help: "stop the daemon", setup: func(flags *flag.FlagSet) func(e *env, args []string) error {
+		wait := flags.Bool("wait", false, "wait until the daemon exited")
+		waitTimeout := flags.Duration("wait-timeout", wasabi.DefaultStopTimeout, "how long to wait for the daemon to exit")
+		return func(e *env, args []string) error {
+			if !*wait {
+				if err := e.client.Stop(); err != nil {
+					return err
+				}
+				return e.print(done)
+			}
+			if err := wasabi.StopAndWait(context.Background(), e.client, wasabi.StopAndWaitOptions{Timeout: *waitTimeout}); err != nil {
+				return err
+			}
+			return e.print(done)
+		}
+	}},
+	{name: "capabilities", help: "show the optional methods supported by the daemon", setup: noFlags(func(e *env, args []string) error {
+		capabilities, err := e.client.Capabilities()
+		if err != nil {
+			return err
+		}
+		return e.print(capabilities.Methods)
+	})},
+	{name: "call", args: "<method> [params JSON]", help: "call any method (of -wallet) with raw JSON params", setup: noFlags(func(e *env, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return usagef("expected 1 or 2 arguments: call <method> [params JSON]")
+		}
+		var params interface{}
+		if len(args) == 2 {
+			if err := json.Unmarshal([]byte(args[1]), &params); err != nil {
+				return usagef("invalid params: %v", err)
+			}
+		}
+		var result json.RawMessage
+		if err := e.client.RawCall(e.cfg.Wallet, args[0], params, &result); err != nil {
+			return err
+		}
+		var v interface{}
+		if len(result) > 0 {
+			if err := json.Unmarshal(result, &v); err != nil {
+				return fmt.Errorf("invalid result: %w", err)
+			}
+		}
+		return e.print(v)
+	})},
+	{name: "dashboard", help: "show a live dashboard of the daemon and the wallets", setup: dashboardCommand},
+}
+
+func init() {
+	sort.Slice(commands, func(i, j int) bool { return commands[i].name < commands[j].name })
+}
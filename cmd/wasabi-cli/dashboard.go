@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// dashboardOptions are the flags of the dashboard command.
+type dashboardOptions struct {
+	interval time.Duration
+	wallets  string
+	history  int
+	once     bool
+}
+
+// walletView is the state of a wallet shown by the dashboard.
+type walletView struct {
+	name     string
+	info     wasabi.GetWalletInfoResponse
+	balance  wasabi.Balance
+	buckets  []wasabi.BucketBalance
+	payments []wasabi.ListPaymentsInCoinJoinResponseItem
+	history  []wasabi.Transaction
+	err      error
+}
+
+// dashboardView is the state shown by the dashboard.
+type dashboardView struct {
+	time    time.Time
+	status  wasabi.GetStatusResponse
+	err     error
+	wallets []walletView
+	events  []string
+}
+
+// dashboardCommand sets up the dashboard command.
+func dashboardCommand(flags *flag.FlagSet) func(e *env, args []string) error {
+	var opts dashboardOptions
+	flags.DurationVar(&opts.interval, "interval", 10*time.Second, "refresh interval")
+	flags.StringVar(&opts.wallets, "wallets", "", "comma separated wallets (default -wallet, or all wallets)")
+	flags.IntVar(&opts.history, "history", 5, "number of recent transactions per wallet")
+	flags.BoolVar(&opts.once, "once", false, "print the dashboard once and exit")
+	return func(e *env, args []string) error {
+		if err := nargs(args, 0, "dashboard"); err != nil {
+			return err
+		}
+		if opts.interval <= 0 {
+			return usagef("interval must be positive")
+		}
+		return runDashboard(e, opts)
+	}
+}
+
+// runDashboard renders the dashboard until it is interrupted. The view is refreshed every interval and when the event bus watching the wallets publishes an event.
+func runDashboard(e *env, opts dashboardOptions) error {
+	wallets, err := dashboardWallets(e, opts)
+	if err != nil {
+		return err
+	}
+	if opts.once {
+		view := collect(e.client, wallets, opts.history)
+		renderDashboard(e.stdout, view, false)
+		return view.err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	bus := wasabi.NewEventBus()
+	defer bus.Close()
+	sub := bus.Subscribe(wasabi.SubscribeOptions{Buffer: 64})
+	defer sub.Close()
+	go bus.Watch(ctx, e.client, wasabi.EventWatchOptions{Wallets: wallets, Interval: opts.interval, OnError: func(error) {}})
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	var events []string
+	for {
+		view := collect(e.client.WithContext(ctx), wallets, opts.history)
+		view.events = events
+		renderDashboard(e.stdout, view, true)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case event := <-sub.Events():
+			events = append(events, time.Now().Format("15:04:05")+" "+describeEvent(event))
+			if len(events) > 10 {
+				events = events[len(events)-10:]
+			}
+		}
+	}
+}
+
+// dashboardWallets returns the wallets shown by the dashboard.
+func dashboardWallets(e *env, opts dashboardOptions) ([]string, error) {
+	switch {
+	case opts.wallets != "":
+		return strings.Split(opts.wallets, ","), nil
+	case e.cfg.Wallet != "":
+		return []string{e.cfg.Wallet}, nil
+	}
+	items, err := e.client.ListWallets()
+	if err != nil {
+		return nil, err
+	}
+	wallets := make([]string, len(items))
+	for i, item := range items {
+		wallets[i] = item.Name
+	}
+	return wallets, nil
+}
+
+// collect reads the state of the daemon and the wallets. Errors are shown in the view instead of ending the dashboard.
+func collect(c wasabi.Client, wallets []string, history int) dashboardView {
+	view := dashboardView{time: time.Now()}
+	view.status, view.err = c.GetStatus()
+	for _, name := range wallets {
+		w := walletView{name: name}
+		w.info, w.err = c.GetWalletInfo(name)
+		if w.err == nil {
+			var coins []wasabi.ListCoinsResponse
+			coins, w.err = c.ListUnspentCoins(name)
+			w.balance = wasabi.ComputeBalance(coins, w.info.AnonScoreTarget)
+			w.buckets = wasabi.ComputeBalanceByAnonScore(coins, wasabi.DefaultAnonScoreBuckets(w.info.AnonScoreTarget))
+		}
+		if w.err == nil {
+			payments, err := c.ListPaymentsInCoinJoin(name)
+			if err == nil {
+				for _, payment := range payments {
+					if status := lastStatus(payment); status != wasabi.PaymentStatusFinished {
+						w.payments = append(w.payments, payment)
+					}
+				}
+			}
+			txs, err := c.GetHistory(name)
+			if err == nil {
+				sort.SliceStable(txs, func(i, j int) bool { return txs[i].DateTime.After(txs[j].DateTime) })
+				if len(txs) > history {
+					txs = txs[:history]
+				}
+				w.history = txs
+			}
+		}
+		view.wallets = append(view.wallets, w)
+	}
+	return view
+}
+
+func lastStatus(payment wasabi.ListPaymentsInCoinJoinResponseItem) wasabi.PaymentStatus {
+	if len(payment.State) == 0 {
+		return ""
+	}
+	return payment.State[len(payment.State)-1].Status
+}
+
+// describeEvent returns a line describing the event.
+func describeEvent(event wasabi.Event) string {
+	switch e := event.(type) {
+	case wasabi.NewTransactionEvent:
+		return fmt.Sprintf("%s: new transaction of %s", e.WalletName, e.Transaction.Amount)
+	case wasabi.ConfirmationReachedEvent:
+		return fmt.Sprintf("%s: transaction of %s confirmed at height %d", e.WalletName, e.Transaction.Amount, e.Height)
+	case wasabi.CoinJoinStatusChangedEvent:
+		return fmt.Sprintf("%s: coinjoin %s -> %s", e.WalletName, e.Previous, e.Current)
+	case wasabi.BackendDisconnectedEvent:
+		if e.Err != nil {
+			return fmt.Sprintf("daemon unreachable: %v", e.Err)
+		}
+		return fmt.Sprintf("backend %s", e.Status)
+	case wasabi.PaymentInCoinJoinFinishedEvent:
+		return fmt.Sprintf("%s: payment of %s to %s finished", e.WalletName, e.Payment.Amount, e.Payment.Address)
+	default:
+		return string(event.Type())
+	}
+}
+
+// renderDashboard writes the view. If clear is set, the terminal is cleared first.
+func renderDashboard(w io.Writer, view dashboardView, clear bool) {
+	if clear {
+		fmt.Fprint(w, "\x1b[H\x1b[2J")
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Wasabi daemon\t%s\n", view.time.Format("2006-01-02 15:04:05"))
+	if view.err != nil {
+		fmt.Fprintf(tw, "  error\t%v\n", view.err)
+	} else {
+		s := view.status
+		sync := "synced"
+		if s.FiltersLeft > 0 {
+			sync = fmt.Sprintf("%d filters left", s.FiltersLeft)
+		}
+		connected := 0
+		for _, peer := range s.Peers {
+			if peer.IsConnected {
+				connected++
+			}
+		}
+		fmt.Fprintf(tw, "  network\t%s\theight %d, %s\n", s.Network, s.BestBlockchainHeight, sync)
+		fmt.Fprintf(tw, "  backend\t%s\ttor %s, %d peers\n", s.BackendStatus, s.TorStatus, connected)
+		if s.ExchangeRate.Sign() > 0 {
+			fmt.Fprintf(tw, "  exchange rate\t%s USD\n", s.ExchangeRate)
+		}
+	}
+
+	for _, wallet := range view.wallets {
+		fmt.Fprintf(tw, "\nWallet %s\t\n", wallet.name)
+		if wallet.err != nil {
+			fmt.Fprintf(tw, "  error\t%v\n", wallet.err)
+			continue
+		}
+		b := wallet.balance
+		fmt.Fprintf(tw, "  state\t%s\tcoinjoin %s\n", wallet.info.State, orDash(string(wallet.info.CoinJoinStatus)))
+		fmt.Fprintf(tw, "  balance\t%s\tconfirmed %s, unconfirmed %s\n", b.Total, b.Confirmed, b.Unconfirmed)
+		fmt.Fprintf(tw, "  private\t%s\t%s of the balance (anon score target %d)\n", b.Private, percent(b.Private, b.Total), b.AnonScoreTarget)
+		for _, bucket := range wallet.buckets {
+			fmt.Fprintf(tw, "    anon score %s\t%s\t%d coins %s\n", bucket.Bucket.Label, bucket.Amount, bucket.Coins, bar(bucket.Amount, b.Total, 20))
+		}
+		if len(wallet.payments) > 0 {
+			fmt.Fprintf(tw, "  payments in coinjoin\t\n")
+			for _, payment := range wallet.payments {
+				fmt.Fprintf(tw, "    %s\t%s\t%s to %s\n", shortID(payment.ID), lastStatus(payment), payment.Amount, payment.Address)
+			}
+		}
+		if len(wallet.history) > 0 {
+			fmt.Fprintf(tw, "  recent transactions\t\n")
+			for _, tx := range wallet.history {
+				confirmed := "unconfirmed"
+				if tx.Height > 0 {
+					confirmed = fmt.Sprintf("height %d", tx.Height)
+				}
+				fmt.Fprintf(tw, "    %s\t%s\t%s %s\n", tx.DateTime.Local().Format("2006-01-02 15:04"), tx.Amount, confirmed, tx.Label)
+			}
+		}
+	}
+
+	if len(view.events) > 0 {
+		fmt.Fprintf(tw, "\nEvents\t\n")
+		for _, event := range view.events {
+			fmt.Fprintf(tw, "  %s\t\n", event)
+		}
+	}
+	tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+func percent(part, total wasabi.Amount) string {
+	if total <= 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", 100*float64(part)/float64(total))
+}
+
+// bar returns a bar of the part of the total, width characters wide.
+func bar(part, total wasabi.Amount, width int) string {
+	if total <= 0 {
+		return ""
+	}
+	n := int(float64(width) * float64(part) / float64(total))
+	return "[" + strings.Repeat("#", n) + strings.Repeat(".", width-n) + "]"
+}
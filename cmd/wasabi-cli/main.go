@@ -0,0 +1,270 @@
+// Command wasabi-cli calls the rpc methods of a Wasabi daemon from the command line.
+//
+//	wasabi-cli [flags] <command> [command flags] [args]
+//
+// The connection is configured with flags, environment variables (WASABI_HOST, WASABI_PORT, WASABI_RPC_USER, WASABI_RPC_PASSWORD, WASABI_WALLET, WASABI_WALLET_PASSWORD) or a JSON config file, in this order of precedence. Results are printed as tables or, with -o json, as JSON.
+//
+// The dashboard command shows the status of the daemon and the wallets, refreshed when the daemon reports new transactions, confirmations or coinjoin progress, like a headless Wasabi GUI.
+//
+// The exit code tells the kind of failure, so scripts can react to it: 0 success, 1 unknown error, 2 usage error, 3 daemon unreachable, 4 protocol error, 5 daemon error, 6 request rejected by the wallet.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Exit codes.
+const (
+	exitOK         = 0
+	exitError      = 1
+	exitUsage      = 2
+	exitConnection = 3
+	exitProtocol   = 4
+	exitDaemon     = 5
+	exitWallet     = 6
+)
+
+// config is the configuration of the cli. The JSON names are the keys of the config file.
+type config struct {
+	Host           string `json:"host"`
+	Port           int    `json:"port"`
+	RPCUser        string `json:"rpcUser"`
+	RPCPassword    string `json:"rpcPassword"`
+	Wallet         string `json:"wallet"`
+	WalletPassword string `json:"walletPassword"`
+	Output         string `json:"output"`
+	Timeout        string `json:"timeout"`
+}
+
+// usageError is an error in the command line.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the cli and returns its exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("wasabi-cli", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		configPath = flags.String("config", defaultConfigPath(), "path of the JSON config file")
+		host       = flags.String("host", "", "host of the daemon (default 127.0.0.1)")
+		port       = flags.Int("port", 0, "rpc port of the daemon (default 37128)")
+		user       = flags.String("rpc-user", "", "rpc user")
+		rpcPass    = flags.String("rpc-password", "", "rpc password")
+		walletName = flags.String("wallet", "", "wallet of the wallet commands")
+		walletPass = flags.String("password", "", "password of the wallet")
+		output     = flags.String("o", "", "output format: table or json (default table)")
+		timeout    = flags.Duration("timeout", 0, "timeout of the command (default none)")
+	)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: wasabi-cli [flags] <command> [command flags] [args]")
+		fmt.Fprintln(stderr, "\nCommands:")
+		for _, cmd := range commands {
+			fmt.Fprintf(stderr, "  %-26s %s\n", cmd.name+" "+cmd.args, cmd.help)
+		}
+		fmt.Fprintln(stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "wasabi-cli: %v\n", err)
+		return exitUsage
+	}
+	applyEnv(&cfg)
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "host":
+			cfg.Host = *host
+		case "port":
+			cfg.Port = *port
+		case "rpc-user":
+			cfg.RPCUser = *user
+		case "rpc-password":
+			cfg.RPCPassword = *rpcPass
+		case "wallet":
+			cfg.Wallet = *walletName
+		case "password":
+			cfg.WalletPassword = *walletPass
+		case "o":
+			cfg.Output = *output
+		case "timeout":
+			cfg.Timeout = timeout.String()
+		}
+	})
+
+	err = execute(cfg, flags.Args(), stdin, stdout)
+	if err == nil {
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "wasabi-cli: %v\n", err)
+	return exitCode(err)
+}
+
+// exitCode returns the exit code of the error.
+func exitCode(err error) int {
+	var usage *usageError
+	if errors.As(err, &usage) {
+		return exitUsage
+	}
+	switch wasabi.Classify(err) {
+	case wasabi.ErrorCategoryConnection:
+		return exitConnection
+	case wasabi.ErrorCategoryProtocol:
+		return exitProtocol
+	case wasabi.ErrorCategoryDaemon:
+		return exitDaemon
+	case wasabi.ErrorCategoryWallet:
+		return exitWallet
+	default:
+		return exitError
+	}
+}
+
+// defaultConfigPath returns the path of the config file in the user config directory, or an empty path if there is no such directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "wasabi-cli", "config.json")
+}
+
+// loadConfig reads the config file. A missing file is not an error.
+func loadConfig(path string) (config, error) {
+	var cfg config
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides the config with the environment variables.
+func applyEnv(cfg *config) {
+	for name, field := range map[string]*string{
+		"WASABI_HOST":            &cfg.Host,
+		"WASABI_RPC_USER":        &cfg.RPCUser,
+		"WASABI_RPC_PASSWORD":    &cfg.RPCPassword,
+		"WASABI_WALLET":          &cfg.Wallet,
+		"WASABI_WALLET_PASSWORD": &cfg.WalletPassword,
+		"WASABI_OUTPUT":          &cfg.Output,
+		"WASABI_TIMEOUT":         &cfg.Timeout,
+	} {
+		if value, ok := os.LookupEnv(name); ok {
+			*field = value
+		}
+	}
+	if value, ok := os.LookupEnv("WASABI_PORT"); ok {
+		if port, err := strconv.Atoi(value); err == nil {
+			cfg.Port = port
+		}
+	}
+}
+
+// env is the environment of a command.
+type env struct {
+	cfg    config
+	client wasabi.Client
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// wallet returns the wallet of the wallet commands.
+func (e *env) wallet() (string, error) {
+	if e.cfg.Wallet == "" {
+		return "", usagef("the command requires a wallet (-wallet or WASABI_WALLET)")
+	}
+	return e.cfg.Wallet, nil
+}
+
+// print prints the result in the configured format.
+func (e *env) print(v interface{}) error {
+	if e.cfg.Output == "json" {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	return printTable(e.stdout, v)
+}
+
+// execute runs the command of args.
+func execute(cfg config, args []string, stdin io.Reader, stdout io.Writer) error {
+	if cfg.Output != "" && cfg.Output != "table" && cfg.Output != "json" {
+		return usagef("unknown output format %q", cfg.Output)
+	}
+	name := args[0]
+	i := sort.Search(len(commands), func(i int) bool { return commands[i].name >= name })
+	if i == len(commands) || commands[i].name != name {
+		return usagef("unknown command %q", name)
+	}
+	cmd := commands[i]
+
+	if cfg.Host == "" {
+		cfg.Host = "127.0.0.1"
+	}
+	c, err := wasabi.NewClient(wasabi.Config{Host: cfg.Host, Port: cfg.Port, RpcUser: cfg.RPCUser, RpcPassword: cfg.RPCPassword})
+	if err != nil {
+		return usagef("%v", err)
+	}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return usagef("invalid timeout %q", cfg.Timeout)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		c = c.WithContext(ctx)
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	run := cmd.setup(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		return usagef("%s: %v (usage: %s %s)", name, err, name, cmd.args)
+	}
+	return run(&env{cfg: cfg, client: c, stdin: stdin, stdout: stdout}, flags.Args())
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// printTable prints a result as a table: lists of structs as one row per item, structs and maps as one line per field and other values as is.
+func printTable(w io.Writer, v interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	value := reflect.ValueOf(v)
+	switch {
+	case !value.IsValid():
+		fmt.Fprintln(tw, "null")
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Struct:
+		columns := fields(value.Type().Elem())
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			names[i] = strings.ToUpper(column.name)
+		}
+		fmt.Fprintln(tw, strings.Join(names, "\t"))
+		for i := 0; i < value.Len(); i++ {
+			cells := make([]string, len(columns))
+			for j, column := range columns {
+				cells[j] = cell(value.Index(i).FieldByIndex(column.index))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	case value.Kind() == reflect.Struct:
+		for _, field := range fields(value.Type()) {
+			fmt.Fprintf(tw, "%s\t%s\n", field.name, cell(value.FieldByIndex(field.index)))
+		}
+	case value.Kind() == reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return lessKey(fmt.Sprint(keys[i]), fmt.Sprint(keys[j])) })
+		for _, key := range keys {
+			fmt.Fprintf(tw, "%v\t%s\n", key, cell(value.MapIndex(key)))
+		}
+	case value.Kind() == reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			fmt.Fprintln(tw, cell(value.Index(i)))
+		}
+	default:
+		fmt.Fprintln(tw, cell(value))
+	}
+	return tw.Flush()
+}
+
+// column is a field of a struct printed in a table.
+type column struct {
+	name  string
+	index []int
+}
+
+// fields returns the exported fields of the struct type named by their JSON names. The fields of embedded structs are inlined.
+func fields(t reflect.Type) []column {
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && name == "" {
+			for _, inner := range fields(field.Type) {
+				columns = append(columns, column{name: inner.name, index: append([]int{i}, inner.index...)})
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, column{name: name, index: []int{i}})
+	}
+	return columns
+}
+
+// cell formats a value for a table cell. Nested lists, structs and maps are printed as compact JSON.
+func cell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if stringer, ok := v.Interface().(fmt.Stringer); ok && v.Kind() != reflect.Struct {
+		return stringer.String()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return cell(v.Elem())
+	case reflect.Slice, reflect.Map, reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format(time.RFC3339)
+		}
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// lessKey orders numeric keys (e.g. confirmation targets) numerically and other keys alphabetically.
+func lessKey(a, b string) bool {
+	if len(a) != len(b) && strings.Trim(a, "0123456789") == "" && strings.Trim(b, "0123456789") == "" {
+		return len(a) < len(b)
+	}
+	return a < b
+}
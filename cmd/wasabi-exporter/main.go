@@ -0,0 +1,214 @@
+// Command wasabi-exporter exposes the status of a Wasabi daemon and of its wallets as Prometheus metrics.
+//
+//	wasabi-exporter [flags]
+//
+// The daemon is scraped on every request of /metrics, so the metrics are as fresh as the scrape interval of Prometheus. The connection is configured with flags or the environment variables of wasabi-cli (WASABI_HOST, WASABI_PORT, WASABI_RPC_USER, WASABI_RPC_PASSWORD). Amounts are exported in satoshis.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+func main() {
+	var (
+		listen  = flag.String("listen", ":9889", "address of the metrics server")
+		host    = flag.String("host", envOr("WASABI_HOST", "127.0.0.1"), "host of the daemon")
+		port    = flag.Int("port", envInt("WASABI_PORT"), "rpc port of the daemon (default 37128)")
+		user    = flag.String("rpc-user", os.Getenv("WASABI_RPC_USER"), "rpc user")
+		pass    = flag.String("rpc-password", os.Getenv("WASABI_RPC_PASSWORD"), "rpc password")
+		wallets = flag.String("wallets", "", "comma separated wallets to export (default all wallets)")
+		timeout = flag.Duration("timeout", 10*time.Second, "timeout of a scrape")
+	)
+	flag.Parse()
+
+	c, err := wasabi.NewClient(wasabi.Config{Host: *host, Port: *port, RpcUser: *user, RpcPassword: *pass})
+	if err != nil {
+		log.Fatalf("wasabi-exporter: %v", err)
+	}
+	e := &exporter{client: c, timeout: *timeout}
+	if *wallets != "" {
+		e.wallets = strings.Split(*wallets, ",")
+	}
+
+	http.Handle("/metrics", e)
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `<html><body><a href="/metrics">Metrics</a></body></html>`)
+	})
+	log.Printf("wasabi-exporter: listening on %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
+
+func envOr(name, value string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return value
+}
+
+func envInt(name string) int {
+	n, _ := strconv.Atoi(os.Getenv(name))
+	return n
+}
+
+// exporter scrapes the daemon on every request.
+type exporter struct {
+	client  wasabi.Client
+	wallets []string
+	timeout time.Duration
+}
+
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), e.timeout)
+	defer cancel()
+	m := newMetrics()
+	e.scrape(e.client.WithContext(ctx), m)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w)
+}
+
+// scrape collects the metrics of the daemon and the wallets. A failing wallet is reported by wasabi_wallet_up and does not fail the scrape.
+func (e *exporter) scrape(c wasabi.Client, m *metrics) {
+	start := time.Now()
+	defer func() {
+		m.set("wasabi_scrape_duration_seconds", "Duration of the scrape of the daemon.", time.Since(start).Seconds())
+	}()
+
+	status, err := c.GetStatus()
+	if err != nil {
+		log.Printf("wasabi-exporter: %v", err)
+		m.set("wasabi_up", "Whether the daemon answered the scrape.", 0)
+		return
+	}
+	m.set("wasabi_up", "Whether the daemon answered the scrape.", 1)
+	m.set("wasabi_best_height", "Height of the best block known by the daemon.", float64(status.BestBlockchainHeight))
+	m.set("wasabi_filters_left", "Number of block filters left to synchronize.", float64(status.FiltersLeft))
+	m.set("wasabi_exchange_rate", "Exchange rate of bitcoin in USD.", status.ExchangeRate.Float64())
+	for _, s := range []wasabi.TorStatus{wasabi.TorStatusNotRunning, wasabi.TorStatusRunning, wasabi.TorStatusTurnedOff} {
+		m.set("wasabi_tor_status", "Status of tor, 1 for the current status.", boolValue(status.TorStatus == s), "status", string(s))
+	}
+	for _, s := range []wasabi.BackendStatus{wasabi.BackendStatusConnected, wasabi.BackendStatusDisconnected} {
+		m.set("wasabi_backend_status", "Status of the backend, 1 for the current status.", boolValue(status.BackendStatus == s), "status", string(s))
+	}
+	connected := 0
+	for _, peer := range status.Peers {
+		if peer.IsConnected {
+			connected++
+		}
+	}
+	m.set("wasabi_peers_connected", "Number of connected peers.", float64(connected))
+
+	wallets := e.wallets
+	if wallets == nil {
+		items, err := c.ListWallets()
+		if err != nil {
+			log.Printf("wasabi-exporter: %v", err)
+			return
+		}
+		for _, item := range items {
+			wallets = append(wallets, item.Name)
+		}
+	}
+	for _, name := range wallets {
+		if err := scrapeWallet(c, name, m); err != nil {
+			log.Printf("wasabi-exporter: wallet %s: %v", name, err)
+			m.set("wasabi_wallet_up", "Whether the wallet answered the scrape.", 0, "wallet", name)
+			continue
+		}
+		m.set("wasabi_wallet_up", "Whether the wallet answered the scrape.", 1, "wallet", name)
+	}
+}
+
+// scrapeWallet collects the metrics of a wallet.
+func scrapeWallet(c wasabi.Client, name string, m *metrics) error {
+	info, err := c.GetWalletInfo(name)
+	if err != nil {
+		return err
+	}
+	coins, err := c.ListUnspentCoins(name)
+	if err != nil {
+		return err
+	}
+	balance := wasabi.ComputeBalance(coins, info.AnonScoreTarget)
+	m.set("wasabi_wallet_balance_sats", "Balance of the wallet in satoshis.", float64(balance.Total), "wallet", name)
+	m.set("wasabi_wallet_confirmed_balance_sats", "Confirmed balance of the wallet in satoshis.", float64(balance.Confirmed), "wallet", name)
+	m.set("wasabi_wallet_unconfirmed_balance_sats", "Unconfirmed balance of the wallet in satoshis.", float64(balance.Unconfirmed), "wallet", name)
+	m.set("wasabi_wallet_private_balance_sats", "Balance of the coins which reached the anonymity score target in satoshis.", float64(balance.Private), "wallet", name)
+	m.set("wasabi_wallet_anon_score_target", "Anonymity score target of the wallet.", float64(info.AnonScoreTarget), "wallet", name)
+	utxos := 0
+	for _, coin := range coins {
+		if coin.SpentBy == nil || *coin.SpentBy == "" {
+			utxos++
+		}
+	}
+	m.set("wasabi_wallet_utxos", "Number of unspent coins of the wallet.", float64(utxos), "wallet", name)
+	for _, bucket := range wasabi.ComputeBalanceByAnonScore(coins, wasabi.DefaultAnonScoreBuckets(info.AnonScoreTarget)) {
+		m.set("wasabi_wallet_anon_score_balance_sats", "Balance of the coins by anonymity score in satoshis.", float64(bucket.Amount), "wallet", name, "anon_score", bucket.Bucket.Label)
+		m.set("wasabi_wallet_anon_score_utxos", "Number of unspent coins by anonymity score.", float64(bucket.Coins), "wallet", name, "anon_score", bucket.Bucket.Label)
+	}
+	if info.CoinJoinStatus != "" {
+		for _, s := range []wasabi.CoinJoinStatus{wasabi.CoinJoinStatusIdle, wasabi.CoinJoinStatusInSchedule, wasabi.CoinJoinStatusInProgress, wasabi.CoinJoinStatusInCriticalPhase} {
+			m.set("wasabi_wallet_coinjoin_status", "Coinjoin status of the wallet, 1 for the current status.", boolValue(info.CoinJoinStatus == s), "wallet", name, "status", string(s))
+		}
+	}
+	return nil
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// metrics collects gauges and writes them in the Prometheus text format.
+type metrics struct {
+	help    map[string]string
+	samples map[string][]string
+}
+
+func newMetrics() *metrics {
+	return &metrics{help: map[string]string{}, samples: map[string][]string{}}
+}
+
+// labelEscaper escapes label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// set sets the gauge with the labels, given as name and value pairs.
+func (m *metrics) set(name, help string, value float64, labels ...string) {
+	m.help[name] = help
+	sample := name
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, labels[i]+`="`+labelEscaper.Replace(labels[i+1])+`"`)
+		}
+		sample += "{" + strings.Join(pairs, ",") + "}"
+	}
+	m.samples[name] = append(m.samples[name], sample+" "+strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (m *metrics) write(w io.Writer) {
+	names := make([]string, 0, len(m.help))
+	for name := range m.help {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, m.help[name], name)
+		for _, sample := range m.samples[name] {
+			fmt.Fprintln(w, sample)
+		}
+	}
+}
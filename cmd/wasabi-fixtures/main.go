@@ -0,0 +1,60 @@
+// Command wasabi-fixtures captures the responses of a Wasabi daemon as fixtures of the wasabi/fixtures package.
+//
+//	wasabi-fixtures -version 2.2.1 -wallet test [flags]
+//
+// The read-only methods of fixtures.CaptureMethods are called on the daemon and their responses are written to <out>/<version>/<method>.json, where the conformance runner (go test ./wasabi/fixtures) checks that the client decodes them. The connection is configured with flags or the environment variables of wasabi-cli (WASABI_HOST, WASABI_PORT, WASABI_RPC_USER, WASABI_RPC_PASSWORD). The responses hold the addresses, keys and history of the wallet, so capture from a regtest or testnet wallet.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/fixtures"
+)
+
+func main() {
+	var (
+		host    = flag.String("host", envOr("WASABI_HOST", "127.0.0.1"), "host of the daemon")
+		port    = flag.Int("port", envInt("WASABI_PORT"), "rpc port of the daemon (default 37128)")
+		user    = flag.String("rpc-user", os.Getenv("WASABI_RPC_USER"), "rpc user")
+		pass    = flag.String("rpc-password", os.Getenv("WASABI_RPC_PASSWORD"), "rpc password")
+		version = flag.String("version", "", "version of the daemon, e.g. 2.2.1 (required)")
+		wallet  = flag.String("wallet", "", "loaded wallet of the wallet scoped methods (required)")
+		out     = flag.String("out", "wasabi/fixtures/data", "directory of the fixtures")
+	)
+	flag.Parse()
+	if *version == "" || *wallet == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c, err := wasabi.NewClient(wasabi.Config{Host: *host, Port: *port, RpcUser: *user, RpcPassword: *pass})
+	if err != nil {
+		log.Fatalf("wasabi-fixtures: %v", err)
+	}
+	captured, err := fixtures.Capture(c, *version, *wallet)
+	if err != nil {
+		log.Fatalf("wasabi-fixtures: %v", err)
+	}
+	if err := fixtures.Write(*out, captured); err != nil {
+		log.Fatalf("wasabi-fixtures: %v", err)
+	}
+	for _, f := range captured {
+		log.Printf("wasabi-fixtures: captured %s", f)
+	}
+}
+
+func envOr(name, value string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return value
+}
+
+func envInt(name string) int {
+	n, _ := strconv.Atoi(os.Getenv(name))
+	return n
+}
@@ -0,0 +1,68 @@
+// Command wasabi-gateway serves the REST API of package gateway in front of a Wasabi daemon.
+//
+//	wasabi-gateway [flags]
+//
+// The daemon is configured with flags or the environment variables of wasabi-cli (WASABI_HOST, WASABI_PORT, WASABI_RPC_USER, WASABI_RPC_PASSWORD). The bearer token of the clients can be given with WASABI_GATEWAY_TOKEN. The OpenAPI description is served at /openapi.json, and printed with -spec.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/gateway"
+)
+
+func main() {
+	var (
+		listen   = flag.String("listen", "127.0.0.1:8080", "address of the REST server")
+		host     = flag.String("host", envOr("WASABI_HOST", "127.0.0.1"), "host of the daemon")
+		port     = flag.Int("port", envInt("WASABI_PORT"), "rpc port of the daemon (default 37128)")
+		user     = flag.String("rpc-user", os.Getenv("WASABI_RPC_USER"), "rpc user")
+		pass     = flag.String("rpc-password", os.Getenv("WASABI_RPC_PASSWORD"), "rpc password")
+		token    = flag.String("token", os.Getenv("WASABI_GATEWAY_TOKEN"), "bearer token required from the clients (default none)")
+		readOnly = flag.Bool("read-only", false, "reject the endpoints changing the state of a wallet or the daemon")
+		tlsCert  = flag.String("tls-cert", "", "certificate file to serve https")
+		tlsKey   = flag.String("tls-key", "", "key file to serve https")
+		spec     = flag.Bool("spec", false, "print the OpenAPI description and exit")
+	)
+	flag.Parse()
+
+	if *spec {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(gateway.Spec()); err != nil {
+			log.Fatalf("wasabi-gateway: %v", err)
+		}
+		return
+	}
+	c, err := wasabi.NewClient(wasabi.Config{Host: *host, Port: *port, RpcUser: *user, RpcPassword: *pass})
+	if err != nil {
+		log.Fatalf("wasabi-gateway: %v", err)
+	}
+	g, err := gateway.New(gateway.Options{Client: c, ReadOnly: *readOnly, Token: *token})
+	if err != nil {
+		log.Fatalf("wasabi-gateway: %v", err)
+	}
+	log.Printf("wasabi-gateway: listening on %s", *listen)
+	if *tlsCert != "" {
+		log.Fatal(http.ListenAndServeTLS(*listen, *tlsCert, *tlsKey, g))
+	}
+	log.Fatal(http.ListenAndServe(*listen, g))
+}
+
+func envOr(name, value string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return value
+}
+
+func envInt(name string) int {
+	n, _ := strconv.Atoi(os.Getenv(name))
+	return n
+}
@@ -0,0 +1,86 @@
+// Command wasabi-proxy serves several Wasabi daemons behind one JSON-RPC endpoint, see package proxy.
+//
+//	wasabi-proxy -config proxy.json
+//
+// The config file looks like:
+//
+//	{
+//	  "listen": ":37127",
+//	  "tlsCert": "cert.pem", "tlsKey": "key.pem",
+//	  "backends": [
+//	    {"name": "customer-a", "host": "10.0.0.1", "rpcUser": "user", "rpcPassword": "password"},
+//	    {"name": "customer-b", "host": "10.0.0.2", "wallets": ["b-hot", "b-cold"]}
+//	  ],
+//	  "users": [
+//	    {"name": "ops", "password": "..."},
+//	    {"name": "analyst", "password": "...", "readOnly": true, "wallets": ["b-cold"]}
+//	  ],
+//	  "readOnly": false,
+//	  "rateLimit": 10, "burst": 20
+//	}
+//
+// Without tlsCert and tlsKey the proxy serves plain http; the passwords of the users then travel in clear text.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/proxy"
+)
+
+// config is the configuration of the proxy.
+type config struct {
+	Listen         string          `json:"listen"`
+	TLSCert        string          `json:"tlsCert"`
+	TLSKey         string          `json:"tlsKey"`
+	Backends       []proxy.Backend `json:"backends"`
+	DefaultBackend string          `json:"defaultBackend"`
+	Users          []proxy.User    `json:"users"`
+	ReadOnly       bool            `json:"readOnly"`
+	RateLimit      float64         `json:"rateLimit"`
+	Burst          int             `json:"burst"`
+}
+
+func main() {
+	configPath := flag.String("config", "wasabi-proxy.json", "path of the JSON config file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*configPath)
+	if err != nil {
+		log.Fatalf("wasabi-proxy: %v", err)
+	}
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		log.Fatalf("wasabi-proxy: invalid config file %s: %v", *configPath, err)
+	}
+	if cfg.Listen == "" {
+		cfg.Listen = ":37127"
+	}
+	p, err := proxy.New(proxy.Options{
+		Backends:       cfg.Backends,
+		DefaultBackend: cfg.DefaultBackend,
+		Users:          cfg.Users,
+		ReadOnly:       cfg.ReadOnly,
+		RateLimit:      cfg.RateLimit,
+		Burst:          cfg.Burst,
+	})
+	if err != nil {
+		log.Fatalf("wasabi-proxy: %v", err)
+	}
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		log.Fatal("wasabi-proxy: tlsCert and tlsKey must be set together")
+	}
+	log.Printf("wasabi-proxy: serving %d daemons on %s", len(cfg.Backends), cfg.Listen)
+	server := &http.Server{Addr: cfg.Listen, Handler: p}
+	if cfg.TLSCert != "" {
+		err = server.ListenAndServeTLS(cfg.TLSCert, cfg.TLSKey)
+	} else {
+		err = server.ListenAndServe()
+	}
+	log.Fatal(fmt.Errorf("wasabi-proxy: %w", err))
+}
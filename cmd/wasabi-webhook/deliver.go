@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers of the webhook requests.
+const (
+	headerEvent     = "X-Wasabi-Event"
+	headerDelivery  = "X-Wasabi-Delivery"
+	headerSignature = "X-Wasabi-Signature"
+)
+
+// queueSize is the number of deliveries queued for an endpoint.
+const queueSize = 256
+
+// Backoff of the retries.
+const (
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
+// dispatcher posts the payloads to the endpoints. Each endpoint has its own queue, so a failing endpoint does not delay the others.
+type dispatcher struct {
+	ctx       context.Context
+	client    *http.Client
+	retries   int
+	endpoints []endpoint
+	queues    []chan delivery
+	wg        sync.WaitGroup
+}
+
+// delivery is a payload queued for an endpoint.
+type delivery struct {
+	id        string
+	eventType string
+	body      []byte
+}
+
+func newDispatcher(ctx context.Context, endpoints []endpoint, retries int) *dispatcher {
+	d := &dispatcher{ctx: ctx, client: &http.Client{Timeout: 30 * time.Second}, retries: retries, endpoints: endpoints}
+	for _, e := range endpoints {
+		queue := make(chan delivery, queueSize)
+		d.queues = append(d.queues, queue)
+		d.wg.Add(1)
+		go d.work(e, queue)
+	}
+	return d
+}
+
+// post queues the event for the endpoints receiving its type. It does not block the event loop: if the queue of an endpoint is full, e.g. because the endpoint is down and its deliveries are retried, the event is dropped for that endpoint and logged.
+func (d *dispatcher) post(eventType, wallet string, data interface{}) {
+	p := payload{ID: newID(), Type: eventType, Time: time.Now().UTC(), Wallet: wallet, Data: data}
+	body, err := json.Marshal(p)
+	if err != nil {
+		log.Printf("wasabi-webhook: %v", err)
+		return
+	}
+	for i, queue := range d.queues {
+		if !d.endpoints[i].wants(eventType) {
+			continue
+		}
+		select {
+		case queue <- delivery{id: p.ID, eventType: eventType, body: body}:
+		default:
+			log.Printf("wasabi-webhook: queue of %s is full, dropping %s %s", d.endpoints[i].URL, eventType, p.ID)
+		}
+	}
+}
+
+// wait waits until the workers stop. The workers stop when the context is done.
+func (d *dispatcher) wait() {
+	d.wg.Wait()
+}
+
+// work delivers the payloads of the queue to the endpoint.
+func (d *dispatcher) work(e endpoint, queue chan delivery) {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case dl := <-queue:
+			if err := d.deliver(e, dl); err != nil {
+				log.Printf("wasabi-webhook: %s %s to %s: %v", dl.eventType, dl.id, e.URL, err)
+			}
+		}
+	}
+}
+
+// deliver posts the payload, retrying network errors, 429 and 5xx responses with exponential backoff.
+func (d *dispatcher) deliver(e endpoint, dl delivery) error {
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := d.send(e, dl)
+		if err == nil || !retry || attempt >= d.retries {
+			return err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-d.ctx.Done():
+			timer.Stop()
+			return d.ctx.Err()
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// send posts the payload once and reports whether a failure can be retried.
+func (d *dispatcher) send(e endpoint, dl delivery) (bool, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, e.URL, bytes.NewReader(dl.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "wasabi-webhook")
+	req.Header.Set(headerEvent, dl.eventType)
+	req.Header.Set(headerDelivery, dl.id)
+	if e.Secret != "" {
+		req.Header.Set(headerSignature, sign(e.Secret, time.Now(), dl.body))
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("http status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("http status %d", resp.StatusCode)
+	}
+}
+
+// sign returns the signature header of the body: "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>" keyed with the secret>". Receivers recompute the HMAC and reject old timestamps to prevent replays.
+func sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newID returns a random id of a delivery.
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
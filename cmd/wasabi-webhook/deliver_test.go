@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPostDropsWhenQueueIsFull(t *testing.T) {
+	// No worker reads the queue, as when the endpoint is down and its deliveries are retried.
+	queue := make(chan delivery, 1)
+	d := &dispatcher{ctx: context.Background(), endpoints: []endpoint{{URL: "http://example.invalid"}}, queues: []chan delivery{queue}}
+	done := make(chan struct{})
+	go func() {
+		d.post(eventNewTransaction, "w", nil)
+		d.post(eventNewTransaction, "w", nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("post blocked on the full queue")
+	}
+	if len(queue) != 1 {
+		t.Fatalf("%d deliveries queued, want 1", len(queue))
+	}
+}
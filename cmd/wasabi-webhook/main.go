@@ -0,0 +1,255 @@
+// Command wasabi-webhook posts the events of Wasabi wallets to webhooks.
+//
+//	wasabi-webhook -config webhook.json
+//
+// The daemon watches the wallets with the event bus of the client and posts a JSON payload for new transactions, confirmation thresholds, finished coinjoins and finished payments in coinjoin to the configured endpoints. Failed deliveries are retried with exponential backoff. Each request is signed with the secret of its endpoint, see sign.
+//
+// The config file looks like:
+//
+//	{
+//	  "host": "127.0.0.1", "port": 37128, "rpcUser": "user", "rpcPassword": "password",
+//	  "wallets": ["wallet"],
+//	  "interval": "10s",
+//	  "confirmations": [1, 6],
+//	  "stateDir": "/var/lib/wasabi-webhook",
+//	  "endpoints": [{"url": "https://example.com/hook", "secret": "...", "events": ["transaction.new"]}]
+//	}
+//
+// An endpoint without events receives all events. With a stateDir, transactions already notified are not notified again after a restart.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Webhook event types.
+const (
+	eventNewTransaction       = "transaction.new"
+	eventTransactionConfirmed = "transaction.confirmed"
+	eventCoinJoinFinished     = "coinjoin.finished"
+	eventPaymentFinished      = "payment_in_coinjoin.finished"
+)
+
+// config is the configuration of the daemon.
+type config struct {
+	Host          string     `json:"host"`
+	Port          int        `json:"port"`
+	RPCUser       string     `json:"rpcUser"`
+	RPCPassword   string     `json:"rpcPassword"`
+	Wallets       []string   `json:"wallets"`
+	Interval      string     `json:"interval"`
+	Confirmations []int      `json:"confirmations"`
+	StateDir      string     `json:"stateDir"`
+	Retries       int        `json:"retries"`
+	Endpoints     []endpoint `json:"endpoints"`
+}
+
+// endpoint is a webhook receiving the events.
+type endpoint struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// wants reports whether the endpoint receives events of the type.
+func (e endpoint) wants(eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func main() {
+	configPath := flag.String("config", "wasabi-webhook.json", "path of the JSON config file")
+	flag.Parse()
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("wasabi-webhook: %v", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, cfg); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("wasabi-webhook: %v", err)
+	}
+}
+
+// loadConfig reads and validates the config file. The rpc password can also be given with WASABI_RPC_PASSWORD.
+func loadConfig(path string) (config, error) {
+	var cfg config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if password, ok := os.LookupEnv("WASABI_RPC_PASSWORD"); ok {
+		cfg.RPCPassword = password
+	}
+	if cfg.Host == "" {
+		cfg.Host = "127.0.0.1"
+	}
+	if len(cfg.Wallets) == 0 {
+		return cfg, fmt.Errorf("wallets must not be empty")
+	}
+	if len(cfg.Endpoints) == 0 {
+		return cfg, fmt.Errorf("endpoints must not be empty")
+	}
+	for _, e := range cfg.Endpoints {
+		if e.URL == "" {
+			return cfg, fmt.Errorf("endpoint url must not be empty")
+		}
+	}
+	if len(cfg.Confirmations) == 0 {
+		cfg.Confirmations = []int{1}
+	}
+	sort.Ints(cfg.Confirmations)
+	if cfg.Confirmations[0] < 1 {
+		return cfg, fmt.Errorf("confirmations must not be less than 1")
+	}
+	if cfg.Retries == 0 {
+		cfg.Retries = 5
+	}
+	return cfg, nil
+}
+
+// payload is the body posted to the webhooks.
+type payload struct {
+	ID     string      `json:"id"`
+	Type   string      `json:"type"`
+	Time   time.Time   `json:"time"`
+	Wallet string      `json:"wallet"`
+	Data   interface{} `json:"data"`
+}
+
+// transactionData is the data of the transaction events.
+type transactionData struct {
+	Transaction   wasabi.Transaction `json:"transaction"`
+	Confirmations int                `json:"confirmations,omitempty"`
+}
+
+// coinJoinData is the data of the coinjoin events.
+type coinJoinData struct {
+	Previous wasabi.CoinJoinStatus `json:"previous"`
+	Current  wasabi.CoinJoinStatus `json:"current"`
+}
+
+// confirming is a confirmed transaction whose higher confirmation thresholds are not reached yet.
+type confirming struct {
+	wallet string
+	tx     wasabi.Transaction
+	// next is the index of the next confirmation threshold.
+	next int
+}
+
+// run watches the wallets and posts their events until the context is done.
+func run(ctx context.Context, cfg config) error {
+	interval := wasabi.DefaultPollInterval
+	if cfg.Interval != "" {
+		var err error
+		if interval, err = time.ParseDuration(cfg.Interval); err != nil {
+			return fmt.Errorf("invalid interval %q", cfg.Interval)
+		}
+	}
+	c, err := wasabi.NewClient(wasabi.Config{Host: cfg.Host, Port: cfg.Port, RpcUser: cfg.RPCUser, RpcPassword: cfg.RPCPassword})
+	if err != nil {
+		return err
+	}
+	c = c.WithContext(ctx)
+
+	d := newDispatcher(ctx, cfg.Endpoints, cfg.Retries)
+	defer d.wait()
+
+	bus := wasabi.NewEventBus()
+	defer bus.Close()
+	sub := bus.Subscribe(wasabi.SubscribeOptions{Buffer: 256, Policy: wasabi.Block}, wasabi.EventNewTransaction, wasabi.EventConfirmationReached, wasabi.EventCoinJoinStatusChanged, wasabi.EventPaymentInCoinJoinFinished)
+	opts := wasabi.EventWatchOptions{
+		Wallets:  cfg.Wallets,
+		Interval: interval,
+		OnError:  func(err error) { log.Printf("wasabi-webhook: %v", err) },
+	}
+	if cfg.StateDir != "" {
+		if err := os.MkdirAll(cfg.StateDir, 0o700); err != nil {
+			return err
+		}
+		opts.TxStateStore = func(walletName string) wasabi.TxStateStore {
+			return wasabi.FileTxStateStore{Path: filepath.Join(cfg.StateDir, walletName+".json")}
+		}
+	}
+	go bus.Watch(ctx, c, opts)
+	log.Printf("wasabi-webhook: watching %d wallets for %d endpoints", len(cfg.Wallets), len(cfg.Endpoints))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var pending []*confirming
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if len(pending) == 0 {
+				continue
+			}
+			status, err := c.GetStatus()
+			if err != nil {
+				log.Printf("wasabi-webhook: %v", err)
+				continue
+			}
+			pending = checkConfirmations(d, pending, cfg.Confirmations, int(status.BestBlockchainHeight))
+		case event := <-sub.Events():
+			switch e := event.(type) {
+			case wasabi.NewTransactionEvent:
+				d.post(eventNewTransaction, e.WalletName, transactionData{Transaction: e.Transaction})
+			case wasabi.ConfirmationReachedEvent:
+				e.Transaction.Height = e.Height
+				pending = checkConfirmations(d, append(pending, &confirming{wallet: e.WalletName, tx: e.Transaction}), cfg.Confirmations, e.Height)
+			case wasabi.CoinJoinStatusChangedEvent:
+				if coinJoinFinished(e.Previous, e.Current) {
+					d.post(eventCoinJoinFinished, e.WalletName, coinJoinData{Previous: e.Previous, Current: e.Current})
+				}
+			case wasabi.PaymentInCoinJoinFinishedEvent:
+				d.post(eventPaymentFinished, e.WalletName, e.Payment)
+			}
+		}
+	}
+}
+
+// checkConfirmations posts the confirmation thresholds reached at the height and returns the transactions still waiting for a threshold.
+func checkConfirmations(d *dispatcher, pending []*confirming, thresholds []int, height int) []*confirming {
+	var waiting []*confirming
+	for _, p := range pending {
+		confirmations := height - p.tx.Height + 1
+		for p.next < len(thresholds) && confirmations >= thresholds[p.next] {
+			d.post(eventTransactionConfirmed, p.wallet, transactionData{Transaction: p.tx, Confirmations: thresholds[p.next]})
+			p.next++
+		}
+		if p.next < len(thresholds) {
+			waiting = append(waiting, p)
+		}
+	}
+	return waiting
+}
+
+// coinJoinFinished reports whether the status change ends a coinjoin.
+func coinJoinFinished(previous, current wasabi.CoinJoinStatus) bool {
+	return previous.IsActive() && (current.IsIdle() || current.IsScheduled())
+}
@@ -1,3 +1,3 @@
 module github.com/acfnv/go-wasabi-rpc-client
 
-go 1.20
+go 1.21
@@ -0,0 +1,303 @@
+package wasabi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// AddressType is the type of a bitcoin address.
+type AddressType int
+
+const (
+	AddressTypeUnknown AddressType = iota
+	AddressTypeP2PKH
+	AddressTypeP2SH
+	AddressTypeP2WPKH
+	AddressTypeP2WSH
+	AddressTypeP2TR
+	// AddressTypeWitnessUnknown is a segwit address of a witness version or program length without a standard script type.
+	AddressTypeWitnessUnknown
+)
+
+// String returns the string representation of the address type.
+func (t AddressType) String() string {
+	switch t {
+	case AddressTypeP2PKH:
+		return "p2pkh"
+	case AddressTypeP2SH:
+		return "p2sh"
+	case AddressTypeP2WPKH:
+		return "p2wpkh"
+	case AddressTypeP2WSH:
+		return "p2wsh"
+	case AddressTypeP2TR:
+		return "p2tr"
+	case AddressTypeWitnessUnknown:
+		return "witness_unknown"
+	default:
+		return "unknown"
+	}
+}
+
+// AddressInfo is a decoded address.
+type AddressInfo struct {
+	// Network is the network of the address. Base58 test addresses are shared by the test networks and are reported as BitcoinNetworkTestnet.
+	Network BitcoinNetwork
+	Type    AddressType
+	// WitnessVersion is the witness version of a segwit address, -1 for base58 addresses.
+	WitnessVersion int
+	// Program is the witness program of a segwit address or the hash of a base58 address.
+	Program []byte
+}
+
+// ErrInvalidAddress is matched (with errors.Is) by the errors of addresses which cannot be decoded.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// AddressError is returned when an address is invalid.
+type AddressError struct {
+	Address string
+	Err     error
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("address %q: %v", e.Address, e.Err)
+}
+
+func (e *AddressError) Unwrap() error {
+	return e.Err
+}
+
+// segwitNetworks maps the human-readable parts of segwit addresses to their networks.
+var segwitNetworks = map[string]BitcoinNetwork{
+	"bc":   BitcoinNetworkMainnet,
+	"tb":   BitcoinNetworkTestnet,
+	"bcrt": BitcoinNetworkRegtest,
+}
+
+// base58Versions maps the version bytes of base58 addresses to their networks and types.
+var base58Versions = map[byte]struct {
+	network BitcoinNetwork
+	typ     AddressType
+}{
+	0x00: {BitcoinNetworkMainnet, AddressTypeP2PKH},
+	0x05: {BitcoinNetworkMainnet, AddressTypeP2SH},
+	0x6f: {BitcoinNetworkTestnet, AddressTypeP2PKH},
+	0xc4: {BitcoinNetworkTestnet, AddressTypeP2SH},
+}
+
+// DecodeAddress decodes a bech32 (segwit v0), bech32m (segwit v1+) or base58check address without contacting the daemon.
+func DecodeAddress(address string) (AddressInfo, error) {
+	info, err := decodeAddress(address)
+	if err != nil {
+		return AddressInfo{}, &AddressError{Address: address, Err: fmt.Errorf("%w: %v", ErrInvalidAddress, err)}
+	}
+	return info, nil
+}
+
+// ValidateAddress checks that the address is valid and belongs to the network. It returns an AddressError for invalid addresses and a NetworkMismatchError for addresses of another network. Base58 test addresses are accepted for all test networks.
+func ValidateAddress(address string, network BitcoinNetwork) error {
+	info, err := DecodeAddress(address)
+	if err != nil {
+		return err
+	}
+	if !sameNetwork(info, network) {
+		return &NetworkMismatchError{Address: address, Network: info.Network, Expected: network}
+	}
+	return nil
+}
+
+// sameNetwork reports whether the decoded address belongs to the network.
+func sameNetwork(info AddressInfo, network BitcoinNetwork) bool {
+	if info.Network == network {
+		return true
+	}
+	// Base58 addresses do not tell the test networks apart.
+	return info.WitnessVersion < 0 && info.Network == BitcoinNetworkTestnet && network == BitcoinNetworkRegtest
+}
+
+func decodeAddress(address string) (AddressInfo, error) {
+	if i := strings.LastIndexByte(address, '1'); i > 0 {
+		if _, ok := segwitNetworks[strings.ToLower(address[:i])]; ok {
+			return decodeSegwitAddress(address)
+		}
+	}
+	return decodeBase58Address(address)
+}
+
+func decodeSegwitAddress(address string) (AddressInfo, error) {
+	hrp, data, encoding, err := bech32Decode(address)
+	if err != nil {
+		return AddressInfo{}, err
+	}
+	if len(data) == 0 {
+		return AddressInfo{}, errors.New("missing witness version")
+	}
+	version := int(data[0])
+	if version > 16 {
+		return AddressInfo{}, fmt.Errorf("invalid witness version %d", version)
+	}
+	if (version == 0) != (encoding == bech32Const) {
+		return AddressInfo{}, errors.New("wrong checksum variant for the witness version")
+	}
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return AddressInfo{}, err
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return AddressInfo{}, fmt.Errorf("invalid witness program length %d", len(program))
+	}
+	info := AddressInfo{Network: segwitNetworks[hrp], WitnessVersion: version, Program: program, Type: AddressTypeWitnessUnknown}
+	switch {
+	case version == 0 && len(program) == 20:
+		info.Type = AddressTypeP2WPKH
+	case version == 0 && len(program) == 32:
+		info.Type = AddressTypeP2WSH
+	case version == 0:
+		return AddressInfo{}, fmt.Errorf("invalid witness v0 program length %d", len(program))
+	case version == 1 && len(program) == 32:
+		info.Type = AddressTypeP2TR
+	}
+	return info, nil
+}
+
+func decodeBase58Address(address string) (AddressInfo, error) {
+	payload, err := base58CheckDecode(address)
+	if err != nil {
+		return AddressInfo{}, err
+	}
+	if len(payload) != 21 {
+		return AddressInfo{}, fmt.Errorf("invalid payload length %d", len(payload))
+	}
+	version, ok := base58Versions[payload[0]]
+	if !ok {
+		return AddressInfo{}, fmt.Errorf("unknown version byte 0x%02x", payload[0])
+	}
+	return AddressInfo{Network: version.network, Type: version.typ, WitnessVersion: -1, Program: payload[1:]}, nil
+}
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32Const   = 1
+	bech32mConst  = 0x2bc830a3
+)
+
+// bech32Decode decodes a bech32 or bech32m string and returns its human-readable part, its data without checksum and the checksum constant.
+func bech32Decode(s string) (string, []byte, int, error) {
+	if len(s) > 90 {
+		return "", nil, 0, errors.New("too long")
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, errors.New("mixed case")
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, 0, errors.New("invalid separator position")
+	}
+	hrp := s[:sep]
+	data := make([]byte, 0, len(s)-sep-1)
+	for _, c := range s[sep+1:] {
+		i := strings.IndexRune(bech32Charset, c)
+		if i < 0 {
+			return "", nil, 0, fmt.Errorf("invalid character %q", c)
+		}
+		data = append(data, byte(i))
+	}
+	values := append(bech32HRPExpand(hrp), data...)
+	encoding := bech32Polymod(values)
+	if encoding != bech32Const && encoding != bech32mConst {
+		return "", nil, 0, errors.New("invalid checksum")
+	}
+	return hrp, data[:len(data)-6], encoding, nil
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	values := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	return values
+}
+
+func bech32Polymod(values []byte) int {
+	generator := [5]int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := 1
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ int(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// convertBits regroups the bits of data from groups of fromBits to groups of toBits.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc, bits := 0, uint(0)
+	maxv := 1<<toBits - 1
+	var out []byte
+	for _, v := range data {
+		if int(v)>>fromBits != 0 {
+			return nil, errors.New("invalid data range")
+		}
+		acc = acc<<fromBits | int(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58CheckDecode decodes a base58check string and returns the payload without checksum.
+func base58CheckDecode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty address")
+	}
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		i := strings.IndexRune(base58Alphabet, c)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	decoded := append(make([]byte, zeros), n.Bytes()...)
+	if len(decoded) < 5 {
+		return nil, errors.New("too short")
+	}
+	payload, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return nil, errors.New("invalid checksum")
+	}
+	return payload, nil
+}
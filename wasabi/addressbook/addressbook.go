@@ -0,0 +1,159 @@
+// Package addressbook maps contacts to bitcoin addresses: the addresses to pay them and the receiving addresses given to them.
+package addressbook
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Contact is a person or organization in the address book.
+type Contact struct {
+	Name string `json:"name"`
+	// Addresses are the addresses used to pay the contact. The last one is used by Resolve.
+	Addresses []wasabi.Address `json:"addresses"`
+	Note      string           `json:"note,omitempty"`
+}
+
+// IssuedAddress is a receiving address of a wallet given to a contact.
+type IssuedAddress struct {
+	Address    wasabi.Address        `json:"address"`
+	KeyPath    wasabi.DerivationPath `json:"keyPath,omitempty"`
+	WalletName string                `json:"walletName"`
+	Contact    string                `json:"contact"`
+	Label      string                `json:"label,omitempty"`
+	Time       time.Time             `json:"time"`
+}
+
+// Book is an address book integrated with a client: new receiving addresses are recorded per contact and payments to contacts are resolved to their addresses.
+type Book struct {
+	client wasabi.Client
+	store  Store
+}
+
+// New creates an address book using the client and the store.
+func New(c wasabi.Client, store Store) *Book {
+	return &Book{client: c, store: store}
+}
+
+// Store returns the store of the book.
+func (b *Book) Store() Store {
+	return b.store
+}
+
+// AddContact creates or replaces a contact. Its addresses are decoded locally and refused if invalid.
+func (b *Book) AddContact(contact Contact) error {
+	if contact.Name == "" {
+		return errors.New("contact name must not be empty")
+	}
+	for _, address := range contact.Addresses {
+		if _, err := address.Decode(); err != nil {
+			return err
+		}
+	}
+	return b.store.PutContact(contact)
+}
+
+// AddAddress appends an address to pay the contact, creating the contact if needed. The address becomes the one used by Resolve.
+func (b *Book) AddAddress(name string, address wasabi.Address) error {
+	contact, err := b.store.Contact(name)
+	if errors.Is(err, ErrNotFound) {
+		contact = Contact{Name: name}
+	} else if err != nil {
+		return err
+	}
+	contact.Addresses = append(contact.Addresses, address)
+	return b.AddContact(contact)
+}
+
+// Resolve returns the address to pay for a contact name or an address. A name is resolved to the last address of the contact; anything which is not a contact must be a valid address.
+func (b *Book) Resolve(nameOrAddress string) (wasabi.Address, error) {
+	contact, err := b.store.Contact(nameOrAddress)
+	switch {
+	case err == nil:
+		if len(contact.Addresses) == 0 {
+			return "", fmt.Errorf("contact %q has no address", contact.Name)
+		}
+		return contact.Addresses[len(contact.Addresses)-1], nil
+	case !errors.Is(err, ErrNotFound):
+		return "", err
+	}
+	if _, err := wasabi.DecodeAddress(nameOrAddress); err != nil {
+		return "", fmt.Errorf("%q is neither a contact nor a valid address: %w", nameOrAddress, err)
+	}
+	return wasabi.Address(nameOrAddress), nil
+}
+
+// NewAddressFor creates a receiving address of the wallet for the contact and records that it was given to the contact. If label is empty, the contact name is used as label.
+func (b *Book) NewAddressFor(walletName string, contact string, label string) (wasabi.GetNewAddressResponse, error) {
+	if contact == "" {
+		return wasabi.GetNewAddressResponse{}, errors.New("contact must not be empty")
+	}
+	if label == "" {
+		label = contact
+	}
+	resp, err := b.client.GetNewAddress(walletName, label)
+	if err != nil {
+		return wasabi.GetNewAddressResponse{}, err
+	}
+	err = b.store.RecordIssued(IssuedAddress{
+		Address:    resp.Address,
+		KeyPath:    resp.KeyPath,
+		WalletName: walletName,
+		Contact:    contact,
+		Label:      label,
+		Time:       time.Now(),
+	})
+	if err != nil {
+		return resp, fmt.Errorf("address %s created but not recorded: %w", resp.Address, err)
+	}
+	return resp, nil
+}
+
+// IssuedTo returns the addresses given to the contact, or all given addresses if contact is empty.
+func (b *Book) IssuedTo(contact string) ([]IssuedAddress, error) {
+	return b.store.Issued(contact)
+}
+
+// WhoHas returns the record of the address given to a contact, or ErrNotFound.
+func (b *Book) WhoHas(address wasabi.Address) (IssuedAddress, error) {
+	issued, err := b.store.Issued("")
+	if err != nil {
+		return IssuedAddress{}, err
+	}
+	for _, i := range issued {
+		if i.Address == address {
+			return i, nil
+		}
+	}
+	return IssuedAddress{}, ErrNotFound
+}
+
+// Send sends the request after resolving the destination of each payment with Resolve, so payments can be addressed to contact names. Payments to contacts without label are labeled with the contact name.
+func (b *Book) Send(walletName string, req wasabi.SendRequest) (wasabi.SendResponse, error) {
+	payments, err := b.ResolvePayments(req.Payments)
+	if err != nil {
+		return wasabi.SendResponse{}, err
+	}
+	req.Payments = payments
+	return b.client.Send(walletName, req)
+}
+
+// ResolvePayments returns a copy of the payments with their destinations resolved with Resolve.
+func (b *Book) ResolvePayments(payments []wasabi.Payment) ([]wasabi.Payment, error) {
+	resolved := make([]wasabi.Payment, len(payments))
+	for i, payment := range payments {
+		address, err := b.Resolve(string(payment.SendTo))
+		if err != nil {
+			return nil, err
+		}
+		if address != payment.SendTo && payment.Label == "" {
+			payment.Label = string(payment.SendTo)
+		}
+		payment.SendTo = address
+		resolved[i] = payment
+	}
+	return resolved, nil
+}
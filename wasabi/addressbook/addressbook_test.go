@@ -0,0 +1,154 @@
+package addressbook
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+const (
+	aliceOld wasabi.Address = "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"
+	aliceNew wasabi.Address = "tb1qttn7vxzfh62ssm7asg6ycwwxxdxsjlj8qvd9ez"
+)
+
+func TestResolve(t *testing.T) {
+	book := New(wasabitest.NewMockClient(), NewMemoryStore())
+	if err := book.AddAddress("alice", aliceOld); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.AddAddress("alice", aliceNew); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.AddContact(Contact{Name: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		input   string
+		want    wasabi.Address
+		wantErr bool
+	}{
+		{input: "alice", want: aliceNew},
+		{input: string(aliceOld), want: aliceOld},
+		{input: "bob", wantErr: true},
+		{input: "carol", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := book.Resolve(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestAddContactInvalid(t *testing.T) {
+	book := New(wasabitest.NewMockClient(), NewMemoryStore())
+	if err := book.AddContact(Contact{}); err == nil {
+		t.Error("AddContact() without name = nil error")
+	}
+	if err := book.AddContact(Contact{Name: "alice", Addresses: []wasabi.Address{"tb1qinvalid"}}); err == nil {
+		t.Error("AddContact() with an invalid address = nil error")
+	}
+	if _, err := book.Store().Contact("alice"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Contact() = %v, want ErrNotFound", err)
+	}
+}
+
+func TestNewAddressFor(t *testing.T) {
+	c := wasabitest.NewMockClient()
+	c.On(wasabi.MethodGetNewAddress, "w", "alice").Return(wasabi.GetNewAddressResponse{Address: aliceNew, Label: "alice"}, nil).Once()
+	book := New(c, NewMemoryStore())
+	if _, err := book.NewAddressFor("w", "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+	c.AssertExpectations(t)
+
+	issued, err := book.WhoHas(aliceNew)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issued.Contact != "alice" || issued.WalletName != "w" || issued.Label != "alice" {
+		t.Errorf("WhoHas() = %+v", issued)
+	}
+	if _, err := book.WhoHas(aliceOld); !errors.Is(err, ErrNotFound) {
+		t.Errorf("WhoHas() of an address not issued = %v, want ErrNotFound", err)
+	}
+	if issued, _ := book.IssuedTo("bob"); len(issued) != 0 {
+		t.Errorf("IssuedTo(bob) = %+v, want none", issued)
+	}
+}
+
+func TestSend(t *testing.T) {
+	c := wasabitest.NewMockClient()
+	c.On(wasabi.MethodSend).Return(wasabi.SendResponse{TransactionID: "tx"}, nil).Once()
+	book := New(c, NewMemoryStore())
+	if err := book.AddAddress("alice", aliceNew); err != nil {
+		t.Fatal(err)
+	}
+	_, err := book.Send("w", wasabi.SendRequest{Payments: []wasabi.Payment{
+		{SendTo: "alice", Amount: 1000},
+		{SendTo: aliceOld, Amount: 2000, Label: "rent"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.AssertExpectations(t)
+	req := c.CallsOf(wasabi.MethodSend)[0].Args[1].(wasabi.SendRequest)
+	want := []wasabi.Payment{
+		{SendTo: aliceNew, Amount: 1000, Label: "alice"},
+		{SendTo: aliceOld, Amount: 2000, Label: "rent"},
+	}
+	for i, payment := range req.Payments {
+		if payment != want[i] {
+			t.Errorf("payment %d = %+v, want %+v", i, payment, want[i])
+		}
+	}
+
+	if _, err := book.Send("w", wasabi.SendRequest{Payments: []wasabi.Payment{{SendTo: "carol", Amount: 1000}}}); err == nil {
+		t.Error("Send() to an unknown contact = nil error")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutContact(Contact{Name: "bob", Addresses: []wasabi.Address{aliceOld}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutContact(Contact{Name: "alice", Addresses: []wasabi.Address{aliceNew}, Note: "friend"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordIssued(IssuedAddress{Address: aliceNew, WalletName: "w", Contact: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteContact("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteContact("bob"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("DeleteContact() of a deleted contact = %v, want ErrNotFound", err)
+	}
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contacts, err := reopened.Contacts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contacts) != 1 || contacts[0].Name != "alice" || contacts[0].Note != "friend" || contacts[0].Addresses[0] != aliceNew {
+		t.Fatalf("Contacts() = %+v", contacts)
+	}
+	issued, err := reopened.Issued("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issued) != 1 || issued[0].Address != aliceNew {
+		t.Fatalf("Issued() = %+v", issued)
+	}
+}
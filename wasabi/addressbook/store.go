@@ -0,0 +1,198 @@
+package addressbook
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// ErrNotFound is returned by stores for unknown contacts.
+var ErrNotFound = errors.New("contact not found")
+
+// Store persists the contacts and the issued addresses of a Book.
+type Store interface {
+	// Contact returns the contact with the name, or ErrNotFound.
+	Contact(name string) (Contact, error)
+	// PutContact creates or replaces the contact with the same name.
+	PutContact(contact Contact) error
+	// DeleteContact deletes the contact. The addresses issued to it are kept.
+	DeleteContact(name string) error
+	// Contacts returns all contacts sorted by name.
+	Contacts() ([]Contact, error)
+	// RecordIssued records an address given to a contact.
+	RecordIssued(issued IssuedAddress) error
+	// Issued returns the addresses given to the contact in issuing order, or all issued addresses if contact is empty.
+	Issued(contact string) ([]IssuedAddress, error)
+}
+
+// data is the content of the stores.
+type data struct {
+	Contacts map[string]Contact `json:"contacts"`
+	Issued   []IssuedAddress    `json:"issued"`
+}
+
+// MemoryStore is a Store keeping the address book in memory.
+type MemoryStore struct {
+	mutex sync.RWMutex
+	data  data
+}
+
+// NewMemoryStore creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: data{Contacts: map[string]Contact{}}}
+}
+
+// Contact implements Store.
+func (s *MemoryStore) Contact(name string) (Contact, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	contact, ok := s.data.Contacts[name]
+	if !ok {
+		return Contact{}, ErrNotFound
+	}
+	contact.Addresses = append([]wasabi.Address(nil), contact.Addresses...)
+	return contact, nil
+}
+
+// PutContact implements Store.
+func (s *MemoryStore) PutContact(contact Contact) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	contact.Addresses = append([]wasabi.Address(nil), contact.Addresses...)
+	s.data.Contacts[contact.Name] = contact
+	return nil
+}
+
+// DeleteContact implements Store.
+func (s *MemoryStore) DeleteContact(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.data.Contacts[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.data.Contacts, name)
+	return nil
+}
+
+// Contacts implements Store.
+func (s *MemoryStore) Contacts() ([]Contact, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	contacts := make([]Contact, 0, len(s.data.Contacts))
+	for _, contact := range s.data.Contacts {
+		contact.Addresses = append([]wasabi.Address(nil), contact.Addresses...)
+		contacts = append(contacts, contact)
+	}
+	sort.Slice(contacts, func(i, j int) bool { return contacts[i].Name < contacts[j].Name })
+	return contacts, nil
+}
+
+// RecordIssued implements Store.
+func (s *MemoryStore) RecordIssued(issued IssuedAddress) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Issued = append(s.data.Issued, issued)
+	return nil
+}
+
+// Issued implements Store.
+func (s *MemoryStore) Issued(contact string) ([]IssuedAddress, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var issued []IssuedAddress
+	for _, i := range s.data.Issued {
+		if contact == "" || i.Contact == contact {
+			issued = append(issued, i)
+		}
+	}
+	return issued, nil
+}
+
+// FileStore is a Store keeping the address book as JSON in a file. The file is rewritten atomically after each change.
+type FileStore struct {
+	path   string
+	memory *MemoryStore
+	mutex  sync.Mutex
+}
+
+// OpenFileStore opens the address book in the file, which is created on the first change if it does not exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, memory: NewMemoryStore()}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &s.memory.data); err != nil {
+		return nil, err
+	}
+	if s.memory.data.Contacts == nil {
+		s.memory.data.Contacts = map[string]Contact{}
+	}
+	return s, nil
+}
+
+// Contact implements Store.
+func (s *FileStore) Contact(name string) (Contact, error) {
+	return s.memory.Contact(name)
+}
+
+// PutContact implements Store.
+func (s *FileStore) PutContact(contact Contact) error {
+	return s.change(func() error { return s.memory.PutContact(contact) })
+}
+
+// DeleteContact implements Store.
+func (s *FileStore) DeleteContact(name string) error {
+	return s.change(func() error { return s.memory.DeleteContact(name) })
+}
+
+// Contacts implements Store.
+func (s *FileStore) Contacts() ([]Contact, error) {
+	return s.memory.Contacts()
+}
+
+// RecordIssued implements Store.
+func (s *FileStore) RecordIssued(issued IssuedAddress) error {
+	return s.change(func() error { return s.memory.RecordIssued(issued) })
+}
+
+// Issued implements Store.
+func (s *FileStore) Issued(contact string) ([]IssuedAddress, error) {
+	return s.memory.Issued(contact)
+}
+
+// change applies the change in memory and writes the file.
+func (s *FileStore) change(apply func() error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := apply(); err != nil {
+		return err
+	}
+	s.memory.mutex.RLock()
+	content, err := json.MarshalIndent(s.memory.data, "", "  ")
+	s.memory.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
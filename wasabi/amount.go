@@ -0,0 +1,110 @@
+package wasabi
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SatoshiPerBitcoin is the number of satoshi in one bitcoin.
+const SatoshiPerBitcoin = 100_000_000
+
+// Amount is an amount of bitcoin in satoshi. It is encoded in JSON as a number of satoshi, like the daemon does.
+type Amount int64
+
+// FromBTC converts a bitcoin amount to satoshi, rounded to the nearest satoshi. Use ParseAmount to convert decimal strings exactly.
+func FromBTC(btc float64) Amount {
+	return Amount(math.Round(btc * SatoshiPerBitcoin))
+}
+
+// FromSats returns the amount of the given satoshi.
+func FromSats(sats int64) Amount {
+	return Amount(sats)
+}
+
+// Sats returns the amount in satoshi.
+func (a Amount) Sats() int64 {
+	return int64(a)
+}
+
+// BTC returns the amount in bitcoin. The result is a float, so use String or FormatBTC for display.
+func (a Amount) BTC() float64 {
+	return float64(a) / SatoshiPerBitcoin
+}
+
+// FormatBTC formats the amount in bitcoin with 8 decimals, without unit.
+func (a Amount) FormatBTC() string {
+	sign := ""
+	abs := uint64(a)
+	if a < 0 {
+		sign = "-"
+		abs = uint64(-a)
+	}
+	return fmt.Sprintf("%s%d.%08d", sign, abs/SatoshiPerBitcoin, abs%SatoshiPerBitcoin)
+}
+
+// String returns the amount in bitcoin with 8 decimals, e.g. "0.00150000 BTC".
+func (a Amount) String() string {
+	return a.FormatBTC() + " BTC"
+}
+
+// ParseAmount parses an amount with its unit: "0.001 BTC", "1500 sats", "1500 sat". The unit is case-insensitive and may be attached to the number. A number without unit is refused because it is ambiguous.
+func ParseAmount(s string) (Amount, error) {
+	trimmed := strings.TrimSpace(s)
+	lower := strings.ToLower(trimmed)
+	for _, unit := range []string{"satoshis", "satoshi", "sats", "sat"} {
+		if number, ok := strings.CutSuffix(lower, unit); ok {
+			sats, err := strconv.ParseInt(strings.TrimSpace(number), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid amount %q", s)
+			}
+			return Amount(sats), nil
+		}
+	}
+	if number, ok := strings.CutSuffix(lower, "btc"); ok {
+		amount, err := ParseBTC(strings.TrimSpace(number))
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+		}
+		return amount, nil
+	}
+	return 0, fmt.Errorf("invalid amount %q: missing unit BTC or sats", s)
+}
+
+// ParseBTC parses a decimal bitcoin amount without unit (e.g. "0.0015") into satoshi without floating point rounding.
+func ParseBTC(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("invalid bitcoin amount %q", s)
+	}
+	if len(fraction) > 8 {
+		return 0, fmt.Errorf("invalid bitcoin amount %q: more than 8 decimals", s)
+	}
+	if strings.ContainsAny(whole+fraction, "+-") {
+		return 0, fmt.Errorf("invalid bitcoin amount %q", s)
+	}
+	fraction += strings.Repeat("0", 8-len(fraction))
+	if whole == "" {
+		whole = "0"
+	}
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bitcoin amount %q", s)
+	}
+	f, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bitcoin amount %q", s)
+	}
+	if w > (math.MaxInt64-f)/SatoshiPerBitcoin {
+		return 0, fmt.Errorf("invalid bitcoin amount %q: out of range", s)
+	}
+	sats := w*SatoshiPerBitcoin + f
+	if negative {
+		sats = -sats
+	}
+	return Amount(sats), nil
+}
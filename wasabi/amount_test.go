@@ -0,0 +1,103 @@
+package wasabi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransactionNegativeAmount(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Amount
+	}{
+		{name: "outgoing", json: `{"amount":-101500,"tx":"a"}`, want: -101500},
+		{name: "incoming", json: `{"amount":150000,"tx":"a"}`, want: 150000},
+		{name: "below int32", json: `{"amount":-2147483649,"tx":"a"}`, want: -2147483649},
+		{name: "all bitcoin", json: `{"amount":-2100000000000000,"tx":"a"}`, want: -2100000000000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, useNumber := range []bool{false, true} {
+				var tx Transaction
+				var err error
+				if useNumber {
+					err = decodeNumbers([]byte(tt.json), &tx)
+				} else {
+					err = json.Unmarshal([]byte(tt.json), &tx)
+				}
+				if err != nil {
+					t.Fatalf("decode (useNumber %v): %v", useNumber, err)
+				}
+				if tx.Amount != tt.want {
+					t.Fatalf("Amount (useNumber %v) = %d, want %d", useNumber, tx.Amount, tt.want)
+				}
+			}
+			data, err := json.Marshal(Transaction{Amount: tt.want})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var back Transaction
+			if err := json.Unmarshal(data, &back); err != nil || back.Amount != tt.want {
+				t.Fatalf("round trip of %s = %d, %v, want %d", data, back.Amount, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionFractionalAmount(t *testing.T) {
+	var tx Transaction
+	err := decodeNumbers([]byte(`{"amount":-1.5,"tx":"a"}`), &tx)
+	var numErr *NumberError
+	if !errors.As(err, &numErr) {
+		t.Fatalf("decodeNumbers() = %v, want a NumberError", err)
+	}
+}
+
+func TestGetHistoryFixturesNegativeAmounts(t *testing.T) {
+	for _, version := range []string{"2.0.4", "2.1.0", "2.2.1"} {
+		t.Run(version, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("fixtures", "data", version, "gethistory.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var response struct {
+				Result []Transaction `json:"result"`
+			}
+			if err := json.Unmarshal(data, &response); err != nil {
+				t.Fatal(err)
+			}
+			var outgoing []Amount
+			for _, tx := range response.Result {
+				if tx.Amount < 0 {
+					outgoing = append(outgoing, tx.Amount)
+				}
+			}
+			if len(outgoing) != 2 || outgoing[0] != -101500 || outgoing[1] != -3211 {
+				t.Fatalf("outgoing amounts = %v, want [-101500 -3211]", outgoing)
+			}
+		})
+	}
+}
+
+func TestAmountFormatNegative(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{amount: -101500, want: "-0.00101500"},
+		{amount: -2100000000000000, want: "-21000000.00000000"},
+		{amount: -1, want: "-0.00000001"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.FormatBTC(); got != tt.want {
+			t.Errorf("Amount(%d).FormatBTC() = %q, want %q", tt.amount, got, tt.want)
+		}
+		if parsed, err := ParseBTC(tt.want); err != nil || parsed != tt.amount {
+			t.Errorf("ParseBTC(%q) = %d, %v, want %d", tt.want, parsed, err, tt.amount)
+		}
+	}
+}
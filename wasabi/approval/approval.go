@@ -0,0 +1,304 @@
+// Package approval implements a two-step spending workflow: one actor proposes a spend, which is built and stored with its preview, and other actors approve it. The transaction is broadcast once the quorum of approvals is reached.
+//
+//	w := approval.New(client, approval.NewMemoryStore(), approval.Options{Quorum: 2, Approvers: []string{"alice", "bob", "carol"}})
+//	proposal, err := w.Propose("dave", "treasury", wasabi.BuildRequest{...})
+//	_, err = w.Approve(proposal.ID, "alice")
+//	proposal, err = w.Approve(proposal.ID, "bob") // broadcast
+//
+// The transaction of a proposal is built when it is proposed and is not changed by the approvals, so the approvers sign off the exact transaction which is broadcast. Its coins are not reserved: if they are spent meanwhile, the broadcast fails.
+package approval
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// DefaultExpiry is the default time after which pending proposals expire.
+const DefaultExpiry = 24 * time.Hour
+
+// Status is the status of a proposal.
+type Status string
+
+const (
+	// StatusPending is the status of a proposal waiting for approvals.
+	StatusPending Status = "pending"
+	// StatusApproved is the status of a proposal which reached the quorum but whose broadcast failed. Broadcast retries it.
+	StatusApproved Status = "approved"
+	// StatusBroadcast is the status of a proposal whose transaction was broadcast.
+	StatusBroadcast Status = "broadcast"
+	// StatusRejected is the status of a proposal rejected by an approver or withdrawn by its proposer.
+	StatusRejected Status = "rejected"
+	// StatusExpired is the status of a proposal which did not reach the quorum before its expiry.
+	StatusExpired Status = "expired"
+)
+
+// Decision is an approval or a rejection of a proposal.
+type Decision struct {
+	Actor  string    `json:"actor"`
+	Time   time.Time `json:"time"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Proposal is a proposed spend.
+type Proposal struct {
+	ID         string           `json:"id"`
+	WalletName string           `json:"walletName"`
+	Proposer   string           `json:"proposer"`
+	Payments   []wasabi.Payment `json:"payments"`
+	// Tx is the hex of the built transaction, broadcast once approved.
+	Tx        string        `json:"tx"`
+	TxID      wasabi.TxID   `json:"txid"`
+	Fee       wasabi.Amount `json:"fee"`
+	Quorum    int           `json:"quorum"`
+	Approvals []Decision    `json:"approvals,omitempty"`
+	// Rejection is the decision which rejected the proposal.
+	Rejection *Decision `json:"rejection,omitempty"`
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// BroadcastAt is the time the transaction was broadcast.
+	BroadcastAt time.Time `json:"broadcastAt,omitempty"`
+	// BroadcastError is the error of the last failed broadcast.
+	BroadcastError string `json:"broadcastError,omitempty"`
+}
+
+var (
+	// ErrNotPending is returned when deciding on a proposal which is not pending.
+	ErrNotPending = errors.New("proposal is not pending")
+	// ErrSelfApproval is returned when the proposer approves their own proposal.
+	ErrSelfApproval = errors.New("proposer must not approve their own proposal")
+	// ErrNotApprover is returned when an actor who is not an approver decides on a proposal.
+	ErrNotApprover = errors.New("actor is not an approver")
+	// ErrAlreadyApproved is returned when an approver approves a proposal twice.
+	ErrAlreadyApproved = errors.New("proposal is already approved by the actor")
+)
+
+// Options holds the options of a Workflow.
+type Options struct {
+	// Quorum is the number of approvals needed to broadcast a proposal. Default is 1.
+	Quorum int
+	// Approvers are the actors allowed to approve and reject proposals. If empty, any actor but the proposer may approve.
+	Approvers []string
+	// Expiry is the time after which pending proposals expire. Default is DefaultExpiry.
+	Expiry time.Duration
+	// Clock provides the time of the proposals and decisions. Default is wasabi.SystemClock.
+	Clock wasabi.Clock
+}
+
+// Workflow proposes, approves and broadcasts spends. Decisions are serialized, so concurrent approvals are all counted.
+type Workflow struct {
+	client wasabi.Client
+	store  Store
+	opts   Options
+	mutex  sync.Mutex
+}
+
+// New creates a workflow building and broadcasting the transactions with the client and keeping the proposals in the store. The client may be a policy client, so approved spends are still checked by the spending rules.
+func New(c wasabi.Client, store Store, opts Options) *Workflow {
+	if opts.Quorum <= 0 {
+		opts.Quorum = 1
+	}
+	if opts.Expiry <= 0 {
+		opts.Expiry = DefaultExpiry
+	}
+	if opts.Clock == nil {
+		opts.Clock = wasabi.SystemClock
+	}
+	return &Workflow{client: c, store: store, opts: opts}
+}
+
+// Propose builds the transaction of the request and stores it as a pending proposal.
+func (w *Workflow) Propose(proposer string, walletName string, req wasabi.BuildRequest) (Proposal, error) {
+	if proposer == "" {
+		return Proposal{}, fmt.Errorf("proposer must not be empty")
+	}
+	txHex, err := w.client.Build(walletName, req)
+	if err != nil {
+		return Proposal{}, err
+	}
+	tx, err := wasabi.DecodeTransaction(txHex)
+	if err != nil {
+		return Proposal{}, err
+	}
+	coins, err := w.client.ListUnspentCoins(walletName)
+	if err != nil {
+		return Proposal{}, err
+	}
+	fee, err := wasabi.TransactionFee(tx, coins)
+	if err != nil {
+		return Proposal{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Proposal{}, err
+	}
+	now := w.opts.Clock.Now()
+	proposal := Proposal{
+		ID:         id,
+		WalletName: walletName,
+		Proposer:   proposer,
+		Payments:   req.Payments,
+		Tx:         txHex,
+		TxID:       tx.TxID,
+		Fee:        fee,
+		Quorum:     w.opts.Quorum,
+		Status:     StatusPending,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(w.opts.Expiry),
+	}
+	if err := w.store.PutProposal(proposal); err != nil {
+		return Proposal{}, err
+	}
+	return proposal, nil
+}
+
+// Approve records the approval of the proposal by the approver. The transaction is broadcast when the quorum is reached; if the broadcast fails, the proposal is approved and the error is returned with it.
+func (w *Workflow) Approve(id string, approver string) (Proposal, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	proposal, err := w.get(id)
+	if err != nil {
+		return Proposal{}, err
+	}
+	if err := w.checkDecision(proposal, approver); err != nil {
+		return proposal, err
+	}
+	for _, approval := range proposal.Approvals {
+		if approval.Actor == approver {
+			return proposal, ErrAlreadyApproved
+		}
+	}
+	proposal.Approvals = append(proposal.Approvals, Decision{Actor: approver, Time: w.opts.Clock.Now()})
+	if len(proposal.Approvals) < proposal.Quorum {
+		return proposal, w.store.PutProposal(proposal)
+	}
+	proposal.Status = StatusApproved
+	return w.broadcast(proposal)
+}
+
+// Reject rejects the proposal. Approvers may reject any pending proposal and proposers may withdraw their own.
+func (w *Workflow) Reject(id string, actor string, reason string) (Proposal, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	proposal, err := w.get(id)
+	if err != nil {
+		return Proposal{}, err
+	}
+	if actor != proposal.Proposer {
+		if err := w.checkDecision(proposal, actor); err != nil {
+			return proposal, err
+		}
+	} else if proposal.Status != StatusPending {
+		return proposal, ErrNotPending
+	}
+	proposal.Rejection = &Decision{Actor: actor, Time: w.opts.Clock.Now(), Reason: reason}
+	proposal.Status = StatusRejected
+	return proposal, w.store.PutProposal(proposal)
+}
+
+// Broadcast retries the broadcast of an approved proposal whose broadcast failed.
+func (w *Workflow) Broadcast(id string) (Proposal, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	proposal, err := w.get(id)
+	if err != nil {
+		return Proposal{}, err
+	}
+	if proposal.Status != StatusApproved {
+		return proposal, fmt.Errorf("proposal %s is %s, not approved", id, proposal.Status)
+	}
+	return w.broadcast(proposal)
+}
+
+// Get returns the proposal, or ErrNotFound. Pending proposals past their expiry are marked as expired.
+func (w *Workflow) Get(id string) (Proposal, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.get(id)
+}
+
+func (w *Workflow) get(id string) (Proposal, error) {
+	proposal, err := w.store.Proposal(id)
+	if err != nil {
+		return Proposal{}, err
+	}
+	return w.expire(proposal)
+}
+
+// List returns all proposals in creation order. Pending proposals past their expiry are marked as expired.
+func (w *Workflow) List() ([]Proposal, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	proposals, err := w.store.Proposals()
+	if err != nil {
+		return nil, err
+	}
+	for i := range proposals {
+		if proposals[i], err = w.expire(proposals[i]); err != nil {
+			return nil, err
+		}
+	}
+	return proposals, nil
+}
+
+// expire marks the proposal as expired if it is pending past its expiry.
+func (w *Workflow) expire(proposal Proposal) (Proposal, error) {
+	if proposal.Status != StatusPending || w.opts.Clock.Now().Before(proposal.ExpiresAt) {
+		return proposal, nil
+	}
+	proposal.Status = StatusExpired
+	return proposal, w.store.PutProposal(proposal)
+}
+
+// checkDecision checks that the actor may approve or reject the proposal.
+func (w *Workflow) checkDecision(proposal Proposal, actor string) error {
+	if proposal.Status != StatusPending {
+		return fmt.Errorf("%w: %s", ErrNotPending, proposal.Status)
+	}
+	if actor == proposal.Proposer {
+		return ErrSelfApproval
+	}
+	if len(w.opts.Approvers) == 0 {
+		return nil
+	}
+	for _, approver := range w.opts.Approvers {
+		if approver == actor {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNotApprover, actor)
+}
+
+// broadcast broadcasts the transaction of the approved proposal and stores the outcome.
+func (w *Workflow) broadcast(proposal Proposal) (Proposal, error) {
+	_, err := w.client.Broadcast(proposal.WalletName, proposal.Tx)
+	if err != nil {
+		proposal.BroadcastError = err.Error()
+		if storeErr := w.store.PutProposal(proposal); storeErr != nil {
+			return proposal, errors.Join(err, storeErr)
+		}
+		return proposal, err
+	}
+	proposal.Status = StatusBroadcast
+	proposal.BroadcastAt = w.opts.Clock.Now()
+	proposal.BroadcastError = ""
+	if err := w.store.PutProposal(proposal); err != nil {
+		return proposal, fmt.Errorf("transaction %s broadcast but not recorded: %w", proposal.TxID, err)
+	}
+	return proposal, nil
+}
+
+// newID returns a random proposal id.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate proposal id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,218 @@
+package approval
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+// newTestWorkflow starts a fake daemon with the wallet "treasury" (password "pw") holding 1000000 sat and returns a workflow of the approvers alice, bob and carol.
+func newTestWorkflow(t *testing.T, store Store, clock wasabi.Clock) (*wasabitest.Server, *Workflow) {
+	t.Helper()
+	s := wasabitest.NewServer(wasabitest.ServerOptions{})
+	t.Cleanup(s.Close)
+	if err := s.AddWallet("treasury", "pw"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Fund("treasury", 1_000_000, "salary"); err != nil {
+		t.Fatal(err)
+	}
+	s.Mine(1)
+	return s, New(s.Client(), store, Options{Quorum: 2, Approvers: []string{"alice", "bob", "carol"}, Clock: clock})
+}
+
+// propose proposes a payment of 100000 sat to a fresh address of the wallet.
+func propose(t *testing.T, s *wasabitest.Server, w *Workflow) Proposal {
+	t.Helper()
+	address, err := s.Client().GetNewAddress("treasury", "supplier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal, err := w.Propose("dave", "treasury", wasabi.BuildRequest{
+		Payments:  []wasabi.Payment{{SendTo: address.Address, Amount: 100_000, Label: "supplier"}},
+		FeeTarget: wasabi.FeeTargetHour,
+		Password:  "pw",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proposal
+}
+
+func TestWorkflow(t *testing.T) {
+	clock := wasabitest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	s, w := newTestWorkflow(t, NewMemoryStore(), clock)
+	proposal := propose(t, s, w)
+	if proposal.Status != StatusPending || proposal.TxID == "" || proposal.Fee <= 0 || !proposal.ExpiresAt.Equal(clock.Now().Add(DefaultExpiry)) {
+		t.Fatalf("proposal = %+v", proposal)
+	}
+
+	tests := []struct {
+		approver   string
+		wantErr    error
+		wantStatus Status
+	}{
+		{approver: "dave", wantErr: ErrSelfApproval, wantStatus: StatusPending},
+		{approver: "eve", wantErr: ErrNotApprover, wantStatus: StatusPending},
+		{approver: "alice", wantStatus: StatusPending},
+		{approver: "alice", wantErr: ErrAlreadyApproved, wantStatus: StatusPending},
+		{approver: "bob", wantStatus: StatusBroadcast},
+		{approver: "carol", wantErr: ErrNotPending, wantStatus: StatusBroadcast},
+	}
+	for _, tt := range tests {
+		got, err := w.Approve(proposal.ID, tt.approver)
+		if !errors.Is(err, tt.wantErr) || got.Status != tt.wantStatus {
+			t.Fatalf("Approve(%s) = %s, %v, want %s, %v", tt.approver, got.Status, err, tt.wantStatus, tt.wantErr)
+		}
+	}
+
+	proposal, err := w.Get(proposal.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposal.Approvals) != 2 || proposal.Approvals[0].Actor != "alice" || proposal.Approvals[1].Actor != "bob" || !proposal.BroadcastAt.Equal(clock.Now()) {
+		t.Fatalf("proposal = %+v, want the approvals of alice and bob", proposal)
+	}
+	history, err := s.Client().GetHistory("treasury")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].TxID != proposal.TxID {
+		t.Fatalf("history = %+v, want the transaction of the proposal", history)
+	}
+}
+
+func TestPropose(t *testing.T) {
+	_, w := newTestWorkflow(t, NewMemoryStore(), nil)
+	if _, err := w.Propose("", "treasury", wasabi.BuildRequest{}); err == nil {
+		t.Error("Propose() without proposer = nil error")
+	}
+	if _, err := w.Propose("dave", "treasury", wasabi.BuildRequest{Payments: []wasabi.Payment{{SendTo: "bcrt1qinvalid", Amount: 100_000, Label: "x"}}, FeeTarget: wasabi.FeeTargetHour, Password: "wrong"}); err == nil {
+		t.Error("Propose() of a transaction the daemon cannot build = nil error")
+	}
+	if proposals, err := w.List(); err != nil || len(proposals) != 0 {
+		t.Errorf("List() = %+v, %v, want no proposals", proposals, err)
+	}
+	if _, err := w.Get("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(unknown) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReject(t *testing.T) {
+	s, w := newTestWorkflow(t, NewMemoryStore(), nil)
+
+	rejected := propose(t, s, w)
+	if _, err := w.Reject(rejected.ID, "eve", "who?"); !errors.Is(err, ErrNotApprover) {
+		t.Fatalf("Reject() by a stranger = %v, want ErrNotApprover", err)
+	}
+	got, err := w.Reject(rejected.ID, "carol", "too expensive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != StatusRejected || got.Rejection == nil || got.Rejection.Actor != "carol" || got.Rejection.Reason != "too expensive" {
+		t.Fatalf("rejected proposal = %+v", got)
+	}
+	if _, err := w.Approve(rejected.ID, "alice"); !errors.Is(err, ErrNotPending) {
+		t.Fatalf("Approve() of a rejected proposal = %v, want ErrNotPending", err)
+	}
+
+	// The proposer may withdraw their own proposal, but only once.
+	withdrawn := propose(t, s, w)
+	if got, err := w.Reject(withdrawn.ID, "dave", "typo"); err != nil || got.Status != StatusRejected {
+		t.Fatalf("Reject() by the proposer = %s, %v", got.Status, err)
+	}
+	if _, err := w.Reject(withdrawn.ID, "dave", "typo"); !errors.Is(err, ErrNotPending) {
+		t.Fatalf("Reject() of a withdrawn proposal = %v, want ErrNotPending", err)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	clock := wasabitest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	s, w := newTestWorkflow(t, NewMemoryStore(), clock)
+	proposal := propose(t, s, w)
+	if _, err := w.Approve(proposal.ID, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(DefaultExpiry)
+	proposals, err := w.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 1 || proposals[0].Status != StatusExpired {
+		t.Fatalf("List() = %+v, want the expired proposal", proposals)
+	}
+	if _, err := w.Approve(proposal.ID, "bob"); !errors.Is(err, ErrNotPending) {
+		t.Fatalf("Approve() of an expired proposal = %v, want ErrNotPending", err)
+	}
+}
+
+func TestBroadcastRetry(t *testing.T) {
+	s, w := newTestWorkflow(t, NewMemoryStore(), nil)
+	proposal := propose(t, s, w)
+	if _, err := w.Broadcast(proposal.ID); err == nil {
+		t.Fatal("Broadcast() of a pending proposal = nil error")
+	}
+	if _, err := w.Approve(proposal.ID, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.On(wasabi.MethodBroadcast).FailHTTP(http.StatusBadGateway).Once()
+	got, err := w.Approve(proposal.ID, "bob")
+	if err == nil || got.Status != StatusApproved || got.BroadcastError == "" {
+		t.Fatalf("Approve() with a failing broadcast = %s (%q), %v, want the approved proposal and the error", got.Status, got.BroadcastError, err)
+	}
+	got, err = w.Broadcast(proposal.ID)
+	if err != nil || got.Status != StatusBroadcast || got.BroadcastError != "" {
+		t.Fatalf("Broadcast() = %s (%q), %v", got.Status, got.BroadcastError, err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proposals.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := wasabitest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	s, w := newTestWorkflow(t, store, clock)
+	first := propose(t, s, w)
+	clock.Advance(time.Minute)
+	second := propose(t, s, w)
+	if _, err := w.Approve(first.ID, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposals, err := reopened.Proposals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 2 || proposals[0].ID != first.ID || proposals[1].ID != second.ID {
+		t.Fatalf("reopened proposals = %+v, want both proposals in creation order", proposals)
+	}
+	if len(proposals[0].Approvals) != 1 || proposals[0].Tx != first.Tx {
+		t.Fatalf("reopened proposal = %+v, want the approval of alice", proposals[0])
+	}
+	if _, err := reopened.Proposal("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Proposal(unknown) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStoreClones(t *testing.T) {
+	store := NewMemoryStore()
+	store.PutProposal(Proposal{ID: "p", Approvals: []Decision{{Actor: "alice"}}})
+	proposal, _ := store.Proposal("p")
+	proposal.Approvals[0].Actor = "mallory"
+	if stored, _ := store.Proposal("p"); stored.Approvals[0].Actor != "alice" {
+		t.Fatalf("stored approval changed to %s by a caller", stored.Approvals[0].Actor)
+	}
+}
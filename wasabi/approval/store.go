@@ -0,0 +1,147 @@
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// ErrNotFound is returned by stores for unknown proposals.
+var ErrNotFound = errors.New("proposal not found")
+
+// Store persists the proposals of a Workflow.
+type Store interface {
+	// Proposal returns the proposal with the id, or ErrNotFound.
+	Proposal(id string) (Proposal, error)
+	// PutProposal creates or replaces the proposal with the same id.
+	PutProposal(proposal Proposal) error
+	// Proposals returns all proposals in creation order.
+	Proposals() ([]Proposal, error)
+}
+
+// MemoryStore is a Store keeping the proposals in memory.
+type MemoryStore struct {
+	mutex     sync.RWMutex
+	proposals map[string]Proposal
+}
+
+// NewMemoryStore creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{proposals: map[string]Proposal{}}
+}
+
+// Proposal implements Store.
+func (s *MemoryStore) Proposal(id string) (Proposal, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	proposal, ok := s.proposals[id]
+	if !ok {
+		return Proposal{}, ErrNotFound
+	}
+	return clone(proposal), nil
+}
+
+// PutProposal implements Store.
+func (s *MemoryStore) PutProposal(proposal Proposal) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.proposals[proposal.ID] = clone(proposal)
+	return nil
+}
+
+// Proposals implements Store.
+func (s *MemoryStore) Proposals() ([]Proposal, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	proposals := make([]Proposal, 0, len(s.proposals))
+	for _, proposal := range s.proposals {
+		proposals = append(proposals, clone(proposal))
+	}
+	sort.Slice(proposals, func(i, j int) bool { return proposals[i].CreatedAt.Before(proposals[j].CreatedAt) })
+	return proposals, nil
+}
+
+// clone copies the slices of the proposal, so stored proposals are not changed by callers.
+func clone(proposal Proposal) Proposal {
+	proposal.Payments = append([]wasabi.Payment(nil), proposal.Payments...)
+	proposal.Approvals = append([]Decision(nil), proposal.Approvals...)
+	if proposal.Rejection != nil {
+		rejection := *proposal.Rejection
+		proposal.Rejection = &rejection
+	}
+	return proposal
+}
+
+// FileStore is a Store keeping the proposals as JSON in a file. The file is rewritten atomically after each change.
+type FileStore struct {
+	path   string
+	memory *MemoryStore
+	mutex  sync.Mutex
+}
+
+// OpenFileStore opens the proposals in the file, which is created on the first change if it does not exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, memory: NewMemoryStore()}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &s.memory.proposals); err != nil {
+		return nil, err
+	}
+	if s.memory.proposals == nil {
+		s.memory.proposals = map[string]Proposal{}
+	}
+	return s, nil
+}
+
+// Proposal implements Store.
+func (s *FileStore) Proposal(id string) (Proposal, error) {
+	return s.memory.Proposal(id)
+}
+
+// PutProposal implements Store.
+func (s *FileStore) PutProposal(proposal Proposal) error {
+	return s.change(func() error { return s.memory.PutProposal(proposal) })
+}
+
+// Proposals implements Store.
+func (s *FileStore) Proposals() ([]Proposal, error) {
+	return s.memory.Proposals()
+}
+
+// change applies the change in memory and writes the file.
+func (s *FileStore) change(apply func() error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := apply(); err != nil {
+		return err
+	}
+	s.memory.mutex.RLock()
+	content, err := json.MarshalIndent(s.memory.proposals, "", "  ")
+	s.memory.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,77 @@
+package wasabi
+
+import (
+	"context"
+	"time"
+)
+
+// AuditEntry is a record of a state-changing call.
+type AuditEntry struct {
+	// Time is the time the call was started.
+	Time time.Time
+	// Actor is the identity of the caller, set with WithActor. It is empty if the caller did not provide it.
+	Actor string
+	// Method is the called rpc method.
+	Method Method
+	// WalletName is the name of the target wallet.
+	WalletName string
+	// CorrelationID is the correlation id sent with the request.
+	CorrelationID string
+	// Params are the parameters of the call with passwords and mnemonics redacted.
+	Params interface{}
+	// Result is the result of the call (nil for methods without result or failed calls). Secret results are redacted.
+	Result interface{}
+	// Err is the error of the call, nil on success.
+	Err error
+}
+
+// AuditSink records state-changing calls (see Method.IsMutating). Record is called synchronously after each call, so it should not block for long.
+type AuditSink interface {
+	Record(entry AuditEntry)
+}
+
+// AuditSinkFunc is a function implementing AuditSink.
+type AuditSinkFunc func(entry AuditEntry)
+
+func (f AuditSinkFunc) Record(entry AuditEntry) {
+	f(entry)
+}
+
+type actorContextKey struct{}
+
+// WithActor returns a context carrying the identity of the caller, which is recorded in audit entries.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the identity of the caller set with WithActor.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	return actor
+}
+
+// audit records the call in the audit sink if the method is mutating.
+func (c *client) audit(start time.Time, method Method, walletName string, correlationID string, params, result interface{}, err error) {
+	if c.auditSink == nil || !method.IsMutating() {
+		return
+	}
+	entry := AuditEntry{
+		Time:          start,
+		Actor:         ActorFromContext(c.ctx),
+		Method:        method,
+		WalletName:    walletName,
+		CorrelationID: correlationID,
+		Params:        redactParams(method, params),
+		Err:           err,
+	}
+	if _, streamed := result.(*resultWriter); streamed {
+		result = nil
+	}
+	if err == nil && result != nil {
+		entry.Result = result
+		if isSecretResult(method) {
+			entry.Result = redacted
+		}
+	}
+	c.auditSink.Record(entry)
+}
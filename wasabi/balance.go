@@ -0,0 +1,143 @@
+package wasabi
+
+import "fmt"
+
+// Balance is the balance of a wallet.
+type Balance struct {
+	// Total is the sum of all unspent coins.
+	Total Amount
+	// Confirmed is the sum of the confirmed unspent coins.
+	Confirmed Amount
+	// Unconfirmed is the sum of the unconfirmed unspent coins.
+	Unconfirmed Amount
+	// Private is the sum of the unspent coins whose anonymity score reached the anonymity score target of the wallet.
+	Private Amount
+	// AnonScoreTarget is the anonymity score target used to compute the private balance.
+	AnonScoreTarget int
+}
+
+// GetBalance returns the balance of the wallet computed from its unspent coins and its anonymity score target.
+func GetBalance(c WalletReader, walletName string) (Balance, error) {
+	info, err := c.GetWalletInfo(walletName)
+	if err != nil {
+		return Balance{}, err
+	}
+	coins, err := c.ListUnspentCoins(walletName)
+	if err != nil {
+		return Balance{}, err
+	}
+	return ComputeBalance(coins, info.AnonScoreTarget), nil
+}
+
+// ComputeBalance computes the balance of the coins. Spent coins are ignored.
+func ComputeBalance(coins []ListCoinsResponse, anonScoreTarget int) Balance {
+	balance := Balance{AnonScoreTarget: anonScoreTarget}
+	for _, coin := range coins {
+		if coin.SpentBy != nil && *coin.SpentBy != "" {
+			continue
+		}
+		balance.Total += coin.Amount
+		if coin.Confirmed {
+			balance.Confirmed += coin.Amount
+		} else {
+			balance.Unconfirmed += coin.Amount
+		}
+		if coin.AnonymityScore >= float64(anonScoreTarget) {
+			balance.Private += coin.Amount
+		}
+	}
+	return balance
+}
+
+// Balance returns the balance of the wallet.
+func (w *Wallet) Balance() (Balance, error) {
+	return GetBalance(w.client, w.name)
+}
+
+// AnonScoreBucket is a range of anonymity scores. Min is inclusive, Max is exclusive and a zero Max means no upper bound.
+type AnonScoreBucket struct {
+	Label string
+	Min   float64
+	Max   float64
+}
+
+// Contains reports whether the anonymity score is in the bucket.
+func (b AnonScoreBucket) Contains(score float64) bool {
+	return score >= b.Min && (b.Max == 0 || score < b.Max)
+}
+
+// BucketBalance is the balance of the unspent coins in an anonymity score bucket.
+type BucketBalance struct {
+	Bucket AnonScoreBucket
+	// Amount is the sum of the coins in the bucket.
+	Amount Amount
+	// Coins is the number of coins in the bucket.
+	Coins int
+}
+
+// DefaultAnonScoreBuckets returns the buckets 1, 2–4, 5–target and ≥target. Buckets made empty by a low target are omitted.
+func DefaultAnonScoreBuckets(anonScoreTarget int) []AnonScoreBucket {
+	target := float64(anonScoreTarget)
+	candidates := []AnonScoreBucket{
+		{Label: "1", Min: 0, Max: 2},
+		{Label: "2-4", Min: 2, Max: 5},
+		{Label: fmt.Sprintf("5-%d", anonScoreTarget-1), Min: 5, Max: target},
+	}
+	var buckets []AnonScoreBucket
+	for _, bucket := range candidates {
+		if bucket.Min >= target {
+			break
+		}
+		if bucket.Max > target {
+			bucket.Max = target
+			bucket.Label = fmt.Sprintf("%g-%d", bucket.Min, anonScoreTarget-1)
+			if bucket.Min >= target-1 {
+				bucket.Label = fmt.Sprintf("%g", bucket.Min)
+			}
+		}
+		buckets = append(buckets, bucket)
+	}
+	return append(buckets, AnonScoreBucket{Label: fmt.Sprintf(">=%d", anonScoreTarget), Min: target})
+}
+
+// GetBalanceByAnonScore returns the balance of the wallet grouped into anonymity score buckets. If buckets is nil, DefaultAnonScoreBuckets with the anonymity score target of the wallet is used.
+func GetBalanceByAnonScore(c Client, walletName string, buckets []AnonScoreBucket) ([]BucketBalance, error) {
+	if buckets == nil {
+		info, err := c.GetWalletInfo(walletName)
+		if err != nil {
+			return nil, err
+		}
+		buckets = DefaultAnonScoreBuckets(info.AnonScoreTarget)
+	}
+	coins, err := c.ListUnspentCoins(walletName)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeBalanceByAnonScore(coins, buckets), nil
+}
+
+// ComputeBalanceByAnonScore groups the coins into the buckets. A coin is counted in the first bucket containing its anonymity score; spent coins and coins outside every bucket are ignored.
+func ComputeBalanceByAnonScore(coins []ListCoinsResponse, buckets []AnonScoreBucket) []BucketBalance {
+	balances := make([]BucketBalance, len(buckets))
+	for i, bucket := range buckets {
+		balances[i].Bucket = bucket
+	}
+	for _, coin := range coins {
+		if coin.SpentBy != nil && *coin.SpentBy != "" {
+			continue
+		}
+		for i := range balances {
+			if balances[i].Bucket.Contains(coin.AnonymityScore) {
+				balances[i].Amount += coin.Amount
+				balances[i].Coins++
+				break
+			}
+		}
+	}
+	return balances
+}
+
+// BalanceByAnonScore returns the balance of the wallet grouped into anonymity score buckets.
+func (w *Wallet) BalanceByAnonScore(buckets []AnonScoreBucket) ([]BucketBalance, error) {
+	return GetBalanceByAnonScore(w.client, w.name, buckets)
+}
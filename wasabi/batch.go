@@ -0,0 +1,179 @@
+package wasabi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxBatchSize is the maximum number of requests sent in one JSON-RPC batch.
+const maxBatchSize = 100
+
+// errNotBatchResponse is returned when a daemon answers a JSON-RPC batch with something else than an array of responses.
+var errNotBatchResponse = errors.New("expected a batch response")
+
+// batchParams holds the params of the requests of a JSON-RPC batch of calls of the same method.
+type batchParams [][]interface{}
+
+// BatchError is returned when some calls of a JSON-RPC batch failed.
+type BatchError struct {
+	// Errors holds the error of each call of the batch, in the order of the calls. It is nil for successful calls.
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	var messages []string
+	for i, err := range e.Errors {
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("#%d: %v", i, err))
+		}
+	}
+	return fmt.Sprintf("%d of %d batched calls failed: %s", len(messages), len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *BatchError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// encodeBatchRequest encodes a JSON-RPC batch and returns the ids of its requests. The requests are numbered sequentially, the ids only need to be unique within the batch.
+func encodeBatchRequest(method string, batch batchParams) ([]byte, []uint64, error) {
+	requests := make([]clientRequest, len(batch))
+	ids := make([]uint64, len(batch))
+	for i, params := range batch {
+		ids[i] = uint64(i + 1)
+		requests[i] = clientRequest{
+			Version: "2.0",
+			Method:  method,
+			Params:  params,
+			Id:      ids[i],
+		}
+	}
+	payload, err := json.Marshal(requests)
+	return payload, ids, err
+}
+
+// decodeBatchResponse decodes the responses of a JSON-RPC batch. A daemon which does not support batches answers with a single error object, which is returned as error.
+func decodeBatchResponse(r io.Reader, ids []uint64) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return &ProtocolError{Err: err}
+	}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := decodeClientResponse(bytes.NewReader(body), nil, false); err != nil && !errors.Is(err, RPCErrNullResult) {
+			return classifyResponseError(err)
+		}
+		return &ProtocolError{Err: errNotBatchResponse}
+	}
+	var responses []json.RawMessage
+	if err := json.Unmarshal(body, &responses); err != nil {
+		return &ProtocolError{Err: fmt.Errorf("%w: %v", errNotBatchResponse, err)}
+	}
+	byID := make(map[uint64]json.RawMessage, len(responses))
+	for _, response := range responses {
+		var decoded clientResponse
+		if err := json.Unmarshal(response, &decoded); err != nil {
+			return &ProtocolError{Err: err}
+		}
+		byID[decoded.Id] = response
+	}
+	batchErr := &BatchError{Errors: make([]error, len(ids))}
+	failed := false
+	for i, id := range ids {
+		response, ok := byID[id]
+		if !ok {
+			batchErr.Errors[i] = &ProtocolError{Err: fmt.Errorf("missing response for request %d", id)}
+			failed = true
+			continue
+		}
+		var discard interface{}
+		if err := decodeClientResponse(bytes.NewReader(response), &discard, false); err != nil && !errors.Is(err, RPCErrNullResult) {
+			batchErr.Errors[i] = classifyResponseError(err)
+			failed = true
+		}
+	}
+	if failed {
+		return batchErr
+	}
+	return nil
+}
+
+func (c *client) ExcludeCoinsFromCoinJoin(walletName string, coins []Coin, exclude bool) error {
+	for start := 0; start < len(coins); start += maxBatchSize {
+		chunk := coins[start:min(start+maxBatchSize, len(coins))]
+		batch := make(batchParams, len(chunk))
+		for i, coin := range chunk {
+			batch[i] = []interface{}{coin.TransactionID, coin.Index, exclude}
+		}
+		err := c.do(MethodExcludeFromCoinJoin, walletName, batch, nil)
+		if err == nil {
+			continue
+		}
+		if !batchUnsupported(err) {
+			return err
+		}
+		// The daemon does not support batches, fall back to one call per coin.
+		for _, coin := range chunk {
+			if err := c.ExcludeFromCoinJoin(walletName, coin.TransactionID, coin.Index, exclude); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// batchUnsupported reports whether err means that the daemon does not support JSON-RPC batches: it rejected the batch as an invalid request or did not answer with an array of responses.
+func batchUnsupported(err error) bool {
+	var (
+		batchErr *BatchError
+		rpcErr   *RPCError
+	)
+	if errors.As(err, &batchErr) {
+		return false
+	}
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == E_INVALID_REQ
+	}
+	return errors.Is(err, errNotBatchResponse)
+}
+
+// SyncCoinJoinExclusions makes the set of unspent coins excluded from coinjoin equal to the given set: coins in the set are excluded, all other unspent coins are included again. Only coins whose state differs are changed.
+func SyncCoinJoinExclusions(c Client, walletName string, excluded []Coin) error {
+	coins, err := c.ListUnspentCoins(walletName)
+	if err != nil {
+		return err
+	}
+	desired := make(map[Coin]bool, len(excluded))
+	for _, coin := range excluded {
+		desired[coin] = true
+	}
+	var toExclude, toInclude []Coin
+	for _, coin := range coins {
+		outpoint := Coin{TransactionID: coin.TxID, Index: coin.Index}
+		switch {
+		case desired[outpoint] && !coin.ExcludedFromCoinJoin:
+			toExclude = append(toExclude, outpoint)
+		case !desired[outpoint] && coin.ExcludedFromCoinJoin:
+			toInclude = append(toInclude, outpoint)
+		}
+	}
+	if len(toExclude) > 0 {
+		if err := c.ExcludeCoinsFromCoinJoin(walletName, toExclude, true); err != nil {
+			return err
+		}
+	}
+	if len(toInclude) > 0 {
+		if err := c.ExcludeCoinsFromCoinJoin(walletName, toInclude, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package wasabi
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExcludeCoinsFromCoinJoinFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		fallback bool
+		wantErr  bool
+	}{
+		{name: "batch", status: http.StatusOK, body: `[{"jsonrpc":"2.0","id":1,"result":null},{"jsonrpc":"2.0","id":2,"result":null}]`},
+		{name: "invalid request", status: http.StatusOK, body: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"invalid request"}}`, fallback: true},
+		{name: "single response", status: http.StatusOK, body: `{"jsonrpc":"2.0","id":1,"result":null}`, fallback: true},
+		{name: "not an array", status: http.StatusOK, body: `"ok"`, fallback: true},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: unauthorizedResponse, wantErr: true},
+		{name: "internal server error", status: http.StatusInternalServerError, body: "boom", wantErr: true},
+		{name: "no method", status: http.StatusOK, body: noMethodResponse, wantErr: true},
+		{name: "bad params", status: http.StatusOK, body: badParamsResponse, wantErr: true},
+	}
+	coins := []Coin{
+		{TransactionID: "a", Index: 0},
+		{TransactionID: "b", Index: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var single atomic.Int32
+			c := newTestClient(t, func(method string) (int, string) {
+				switch Method(method) {
+				case "":
+					return tt.status, tt.body
+				case MethodExcludeFromCoinJoin:
+					single.Add(1)
+					return http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`
+				}
+				// Capabilities probe: every optional method is known.
+				return http.StatusOK, badParamsResponse
+			})
+			err := c.ExcludeCoinsFromCoinJoin("wallet", coins, true)
+			if tt.fallback {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := single.Load(); got != int32(len(coins)) {
+					t.Fatalf("%d per-coin calls, want %d", got, len(coins))
+				}
+				return
+			}
+			if got := single.Load(); got != 0 {
+				t.Fatalf("%d per-coin calls, want none", got)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExcludeCoinsFromCoinJoin() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExcludeCoinsFromCoinJoinBatchError(t *testing.T) {
+	var single atomic.Int32
+	c := newTestClient(t, func(method string) (int, string) {
+		switch Method(method) {
+		case "":
+			return http.StatusOK, `[{"jsonrpc":"2.0","id":1,"result":null},{"jsonrpc":"2.0","id":2,"error":{"code":-32600,"message":"invalid request"}}]`
+		case MethodExcludeFromCoinJoin:
+			single.Add(1)
+			return http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`
+		}
+		return http.StatusOK, badParamsResponse
+	})
+	err := c.ExcludeCoinsFromCoinJoin("wallet", []Coin{{TransactionID: "a"}, {TransactionID: "b"}}, true)
+	if err == nil {
+		t.Fatal("ExcludeCoinsFromCoinJoin() = nil error, want the batch error")
+	}
+	if got := single.Load(); got != 0 {
+		t.Fatalf("%d per-coin calls, want none", got)
+	}
+}
@@ -0,0 +1,153 @@
+package wasabi
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultFeeTarget is the confirmation target (in blocks) used by helpers which build transactions without a fee target or fee rate from the caller.
+const DefaultFeeTarget = FeeTargetNextBlock
+
+// BIP21URI is a bitcoin: payment URI as defined by BIP21.
+type BIP21URI struct {
+	Address Address
+	// Amount is the requested amount. Zero if the URI has no amount.
+	Amount  Amount
+	Label   string
+	Message string
+	// Params holds the other optional params of the URI (e.g. pj for payjoin).
+	Params map[string]string
+}
+
+// String encodes the URI. The params are sorted, so the encoding is stable.
+func (u BIP21URI) String() string {
+	values := url.Values{}
+	if u.Amount > 0 {
+		values.Set("amount", strings.TrimRight(strings.TrimRight(u.Amount.FormatBTC(), "0"), "."))
+	}
+	if u.Label != "" {
+		values.Set("label", u.Label)
+	}
+	if u.Message != "" {
+		values.Set("message", u.Message)
+	}
+	for key, value := range u.Params {
+		values.Set(key, value)
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString("bitcoin:")
+	sb.WriteString(string(u.Address))
+	for i, key := range keys {
+		if i == 0 {
+			sb.WriteByte('?')
+		} else {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(url.QueryEscape(key))
+		sb.WriteByte('=')
+		// BIP21 uses percent-encoding, not the form encoding of spaces as +.
+		sb.WriteString(strings.ReplaceAll(url.QueryEscape(values.Get(key)), "+", "%20"))
+	}
+	return sb.String()
+}
+
+// ParseBIP21 decodes a bitcoin: payment URI. The address is decoded locally and unknown required params (prefixed with req-) are refused as BIP21 demands.
+func ParseBIP21(uri string) (BIP21URI, error) {
+	scheme, rest, ok := strings.Cut(uri, ":")
+	if !ok || !strings.EqualFold(scheme, "bitcoin") {
+		return BIP21URI{}, fmt.Errorf("invalid bip21 uri %q: scheme must be bitcoin", uri)
+	}
+	address, query, _ := strings.Cut(rest, "?")
+	if address == "" {
+		return BIP21URI{}, fmt.Errorf("invalid bip21 uri %q: missing address", uri)
+	}
+	parsedAddress, err := ParseAddress(address)
+	if err != nil {
+		return BIP21URI{}, fmt.Errorf("invalid bip21 uri %q: %w", uri, err)
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return BIP21URI{}, fmt.Errorf("invalid bip21 uri %q: %w", uri, err)
+	}
+	parsed := BIP21URI{Address: parsedAddress, Params: map[string]string{}}
+	for key, value := range values {
+		if len(value) != 1 {
+			return BIP21URI{}, fmt.Errorf("invalid bip21 uri %q: param %s repeated", uri, key)
+		}
+		switch key {
+		case "amount":
+			if parsed.Amount, err = ParseBTC(value[0]); err != nil || parsed.Amount <= 0 {
+				return BIP21URI{}, fmt.Errorf("invalid bip21 uri %q: invalid amount %q", uri, value[0])
+			}
+		case "label":
+			parsed.Label = value[0]
+		case "message":
+			parsed.Message = value[0]
+		default:
+			if strings.HasPrefix(key, "req-") {
+				return BIP21URI{}, fmt.Errorf("invalid bip21 uri %q: unsupported required param %s", uri, key)
+			}
+			parsed.Params[key] = value[0]
+		}
+	}
+	return parsed, nil
+}
+
+// BuildBIP21 encodes a bitcoin: payment URI. The amount and the label are omitted if empty.
+func BuildBIP21(address Address, amount Amount, label string) string {
+	return BIP21URI{Address: address, Amount: amount, Label: label}.String()
+}
+
+// Payment returns the payment of the URI. Its label is the label of the URI, or the message if there is no label.
+func (u BIP21URI) Payment() (Payment, error) {
+	if u.Amount <= 0 {
+		return Payment{}, fmt.Errorf("bip21 uri for %s has no amount", u.Address)
+	}
+	label := u.Label
+	if label == "" {
+		label = u.Message
+	}
+	return Payment{SendTo: u.Address, Amount: u.Amount, Label: label}, nil
+}
+
+// PayURI sends the payment of a BIP21 URI with the DefaultFeeTarget. A payjoin endpoint in the pj param is used. The URI must have an amount.
+func PayURI(c Client, walletName string, uri string, password string) (SendResponse, error) {
+	req, err := uriSendRequest(uri)
+	if err != nil {
+		return SendResponse{}, err
+	}
+	req.Password = password
+	return c.Send(walletName, req)
+}
+
+// PayURI sends the payment of a BIP21 URI with the DefaultFeeTarget and the password of the handle.
+func (w *Wallet) PayURI(uri string) (SendResponse, error) {
+	req, err := uriSendRequest(uri)
+	if err != nil {
+		return SendResponse{}, err
+	}
+	return w.Send(req)
+}
+
+func uriSendRequest(uri string) (SendRequest, error) {
+	parsed, err := ParseBIP21(uri)
+	if err != nil {
+		return SendRequest{}, err
+	}
+	payment, err := parsed.Payment()
+	if err != nil {
+		return SendRequest{}, err
+	}
+	return SendRequest{
+		Payments:        []Payment{payment},
+		FeeTarget:       DefaultFeeTarget,
+		PayjoinEndpoint: parsed.Params["pj"],
+	}, nil
+}
@@ -0,0 +1,127 @@
+// Package boltqueue is a queue.Store keeping the items in a bbolt database, for queues too large to be rewritten to a JSON file after each change (see queue.FileStore).
+//
+//	store, err := boltqueue.Open("queue.db")
+//	defer store.Close()
+//	q := queue.New(client, store, queue.Options{Password: passwords})
+//
+// It lives in its own module, so the queue package does not depend on bbolt.
+package boltqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/queue"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// itemsBucket maps the item ids to the JSON of the items.
+	itemsBucket = []byte("items")
+	// keysBucket maps the intent keys to the item ids.
+	keysBucket = []byte("keys")
+)
+
+// Store is a queue.Store on a bbolt database. Each PutItem is a transaction synced to disk before it returns. It is safe for concurrent use.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the database at path, creating it if it does not exist. The database is locked until the store is closed, so a second Open of the same path waits for it.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{itemsBucket, keysBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Item implements queue.Store.
+func (s *Store) Item(id string) (queue.Item, error) {
+	var item queue.Item
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return get(tx, []byte(id), &item)
+	})
+	return item, err
+}
+
+// ItemByKey implements queue.Store.
+func (s *Store) ItemByKey(key string) (queue.Item, error) {
+	var item queue.Item
+	err := s.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(keysBucket).Get([]byte(key))
+		if id == nil {
+			return queue.ErrNotFound
+		}
+		return get(tx, id, &item)
+	})
+	return item, err
+}
+
+// get decodes the item with the id.
+func get(tx *bolt.Tx, id []byte, item *queue.Item) error {
+	data := tx.Bucket(itemsBucket).Get(id)
+	if data == nil {
+		return queue.ErrNotFound
+	}
+	return json.Unmarshal(data, item)
+}
+
+// PutItem implements queue.Store.
+func (s *Store) PutItem(item queue.Item) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(itemsBucket).Put([]byte(item.ID), data); err != nil {
+			return err
+		}
+		return tx.Bucket(keysBucket).Put([]byte(item.Key), []byte(item.ID))
+	})
+}
+
+// Items implements queue.Store.
+func (s *Store) Items() ([]queue.Item, error) {
+	var items []queue.Item
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(itemsBucket).ForEach(func(id, data []byte) error {
+			var item queue.Item
+			if err := json.Unmarshal(data, &item); err != nil {
+				return fmt.Errorf("item %s: %w", id, err)
+			}
+			items = append(items, item)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Like the stores of the queue package, items are ordered by their creation.
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.Before(items[j].CreatedAt)
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
@@ -0,0 +1,82 @@
+package boltqueue
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/queue"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.db")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := queue.Item{ID: "a", Intent: queue.Intent{Key: "withdrawal-2", WalletName: "hot", Amount: 2000}, State: queue.StateQueued, CreatedAt: created.Add(time.Minute)}
+	sooner := queue.Item{ID: "b", Intent: queue.Intent{Key: "withdrawal-1", WalletName: "hot", Amount: 1000}, State: queue.StateQueued, CreatedAt: created}
+	for _, item := range []queue.Item{later, sooner} {
+		if err := store.PutItem(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sooner.State = queue.StateFailed
+	sooner.Error = "rejected"
+	if err := store.PutItem(sooner); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	items, err := store.Items()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].ID != "b" || items[1].ID != "a" {
+		t.Fatalf("Items() = %+v, want the items ordered by their creation", items)
+	}
+	if item, err := store.Item("b"); err != nil || item.State != queue.StateFailed || item.Error != "rejected" || !item.CreatedAt.Equal(created) {
+		t.Fatalf("Item(b) = %+v, %v, want the last version of the item", item, err)
+	}
+	if item, err := store.ItemByKey("withdrawal-2"); err != nil || item.ID != "a" {
+		t.Fatalf("ItemByKey(withdrawal-2) = %+v, %v", item, err)
+	}
+	if _, err := store.Item("unknown"); !errors.Is(err, queue.ErrNotFound) {
+		t.Fatalf("Item(unknown) = %v, want queue.ErrNotFound", err)
+	}
+	if _, err := store.ItemByKey("unknown"); !errors.Is(err, queue.ErrNotFound) {
+		t.Fatalf("ItemByKey(unknown) = %v, want queue.ErrNotFound", err)
+	}
+}
+
+func TestQueueDeduplicatesAfterReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.db")
+	intent := queue.Intent{Key: "withdrawal-1", WalletName: "hot", Address: "tb1qttn7vxzfh62ssm7asg6ycwwxxdxsjlj8qvd9ez", Amount: 100_000}
+	enqueue := func() queue.Item {
+		t.Helper()
+		store, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+		item, err := queue.New(wasabitest.NewMockClient(), store, queue.Options{}).Enqueue(intent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return item
+	}
+	first := enqueue()
+	if again := enqueue(); again.ID != first.ID {
+		t.Fatalf("Enqueue() after reopening = %s, want the item %s of the key", again.ID, first.ID)
+	}
+}
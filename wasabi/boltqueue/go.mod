@@ -0,0 +1,10 @@
+module github.com/acfnv/go-wasabi-rpc-client/wasabi/boltqueue
+
+go 1.21
+
+require (
+	github.com/acfnv/go-wasabi-rpc-client v0.0.0
+	go.etcd.io/bbolt v1.3.10
+)
+
+require golang.org/x/sys v0.4.0 // indirect
@@ -0,0 +1,91 @@
+package wasabi
+
+import (
+	"sync"
+	"time"
+)
+
+// FailureBurst describes a burst of failed calls of a method.
+type FailureBurst struct {
+	// Method is the method whose calls failed.
+	Method Method
+	// Failures is the number of failed calls in the burst.
+	Failures int
+	// Since is the time of the first failed call in the burst.
+	Since time.Time
+	// LastErr is the error of the last failed call.
+	LastErr error
+}
+
+// FailureBurstConfig configures the callback invoked on bursts of failed calls.
+type FailureBurstConfig struct {
+	// Threshold is the number of failed calls of a method which makes a burst. Zero disables the callback.
+	Threshold int
+	// Window is the period in which the failed calls must occur. If zero, the failed calls must be consecutive (any successful call resets the count).
+	Window time.Duration
+	// Methods limits the detection to the given methods. If empty, all methods are watched.
+	Methods []Method
+	// OnBurst is called synchronously after the call which completed the burst. The count starts again after each callback.
+	OnBurst func(burst FailureBurst)
+}
+
+// burstDetector detects bursts of failed calls per method.
+type burstDetector struct {
+	cfg      FailureBurstConfig
+	methods  map[Method]bool
+	mutex    sync.Mutex
+	failures map[Method][]time.Time
+}
+
+func newBurstDetector(cfg FailureBurstConfig) *burstDetector {
+	if cfg.Threshold <= 0 || cfg.OnBurst == nil {
+		return nil
+	}
+	d := &burstDetector{
+		cfg:      cfg,
+		failures: map[Method][]time.Time{},
+	}
+	if len(cfg.Methods) > 0 {
+		d.methods = map[Method]bool{}
+		for _, m := range cfg.Methods {
+			d.methods[m] = true
+		}
+	}
+	return d
+}
+
+func (d *burstDetector) record(method Method, at time.Time, err error) {
+	if d == nil || (d.methods != nil && !d.methods[method]) {
+		return
+	}
+	d.mutex.Lock()
+	if err == nil {
+		if d.cfg.Window == 0 {
+			delete(d.failures, method)
+		}
+		d.mutex.Unlock()
+		return
+	}
+	failures := append(d.failures[method], at)
+	if d.cfg.Window > 0 {
+		first := 0
+		for first < len(failures) && at.Sub(failures[first]) > d.cfg.Window {
+			first++
+		}
+		failures = failures[first:]
+	}
+	if len(failures) < d.cfg.Threshold {
+		d.failures[method] = failures
+		d.mutex.Unlock()
+		return
+	}
+	delete(d.failures, method)
+	d.mutex.Unlock()
+
+	d.cfg.OnBurst(FailureBurst{
+		Method:   method,
+		Failures: len(failures),
+		Since:    failures[0],
+		LastErr:  err,
+	})
+}
@@ -0,0 +1,11 @@
+package wasabi
+
+// Call calls the rpc method with the given params and decodes the result into a value of type T. It gives compile-time typed results for custom methods and methods not yet covered by the Client interface. The walletName may be empty for methods which do not target a wallet.
+func Call[T any](c Client, method Method, walletName string, params interface{}) (T, error) {
+	var result T
+	if err := c.RawCall(walletName, method.String(), params, &result); err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// baseDaemonVersion is the version of daemons routing with the url path which support none of the optional methods.
+const baseDaemonVersion = "2.0.0"
+
+// Capabilities describes the features supported by the daemon.
+type Capabilities struct {
+	// Version is the daemon version told by the optional methods it supports: the latest MethodSpec.Since of a supported method, or 2.0.0 if it supports none. It is empty for daemons routing with selectwallet, which predate the optional methods.
+	Version string
+	// Methods holds the optional methods (see MethodSpec.Optional) and whether the daemon supports them. Methods which are not optional are always supported.
+	Methods map[Method]bool
+}
+
+// Supports reports whether the daemon supports the method.
+func (c Capabilities) Supports(method Method) bool {
+	supported, ok := c.Methods[method]
+	return !ok || supported
+}
+
+// capabilityCache holds the capabilities of the daemon detected on first use.
+type capabilityCache struct {
+	mutex        sync.Mutex
+	detected     bool
+	capabilities Capabilities
+}
+
+func (c *client) Capabilities() (Capabilities, error) {
+	c.capabilities.mutex.Lock()
+	defer c.capabilities.mutex.Unlock()
+	if c.capabilities.detected {
+		return c.capabilities.capabilities, nil
+	}
+	capabilities, err := c.detectCapabilities(c.ctx)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	c.capabilities.capabilities = capabilities
+	c.capabilities.detected = true
+	return capabilities, nil
+}
+
+// detectCapabilities probes every optional method which is not mutating. A method is probed by calling it without params and without a wallet: a daemon which knows the method fails with a wallet or params error, a daemon which does not know it fails with E_NO_METHOD. The probes tell the daemon version, which tells whether the mutating optional methods are supported.
+// Only JSON-RPC answers tell whether a method is supported, so any other error (e.g. an http status of a proxy) fails the detection and nothing is cached.
+// Daemons using RoutingSelectWallet predate all optional methods, so they are not probed (a probe would run on the selected wallet).
+func (c *client) detectCapabilities(ctx context.Context) (Capabilities, error) {
+	capabilities := Capabilities{Methods: map[Method]bool{}}
+	if c.routing == RoutingSelectWallet {
+		for _, spec := range Methods() {
+			if spec.Optional {
+				capabilities.Methods[spec.Name] = false
+			}
+		}
+		return capabilities, nil
+	}
+	capabilities.Version = baseDaemonVersion
+	for _, spec := range Methods() {
+		if !spec.Optional || spec.Mutating {
+			continue
+		}
+		err := c.send(&CallInfo{
+			Context:       ctx,
+			Method:        spec.Name,
+			CorrelationID: newCorrelationID(),
+			Header:        http.Header{},
+		}, nil)
+		var rpcErr *RPCError
+		if err != nil && !errors.As(err, &rpcErr) {
+			return Capabilities{}, err
+		}
+		supported := !errors.Is(err, ErrUnsupportedMethod)
+		capabilities.Methods[spec.Name] = supported
+		if supported && spec.Since != "" && compareVersions(spec.Since, capabilities.Version) > 0 {
+			capabilities.Version = spec.Since
+		}
+	}
+	for _, spec := range Methods() {
+		if spec.Optional && spec.Mutating && spec.Since != "" {
+			capabilities.Methods[spec.Name] = compareVersions(spec.Since, capabilities.Version) <= 0
+		}
+	}
+	return capabilities, nil
+}
+
+// compareVersions compares two dotted versions (e.g. "2.1.0") number by number. Missing numbers count as zero.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// checkSupported returns ErrUnsupportedMethod if the method is optional and the daemon does not support it. If the capabilities cannot be detected, the call is not gated.
+func (c *client) checkSupported(method Method) error {
+	spec, ok := LookupMethod(method)
+	if !ok || !spec.Optional {
+		return nil
+	}
+	capabilities, err := c.Capabilities()
+	if err != nil || capabilities.Supports(method) {
+		return nil
+	}
+	return &ProtocolError{Err: ErrUnsupportedMethod}
+}
@@ -0,0 +1,127 @@
+package wasabi
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// newTestClient returns a client of a test server answering each request with the status and the body returned by respond for its method.
+func newTestClient(t *testing.T, respond func(method string) (int, string)) Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		status, body := respond(req.Method)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	host, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	c, err := NewClient(Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+const (
+	noMethodResponse     = `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`
+	badParamsResponse    = `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"wallet is not loaded"}}`
+	unauthorizedResponse = `unauthorized`
+)
+
+func TestCapabilitiesVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		known   map[Method]bool
+		version string
+	}{
+		{name: "2.0", known: map[Method]bool{}, version: "2.0.0"},
+		{name: "2.1", known: map[Method]bool{MethodBuildUnsafeTransaction: true}, version: "2.1.0"},
+		{name: "2.2", known: map[Method]bool{MethodBuildUnsafeTransaction: true, MethodListPaymentsInCoinJoin: true, MethodCancelTransaction: true, MethodSpeedUpTransaction: true}, version: "2.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mutex  sync.Mutex
+				probed []string
+			)
+			c := newTestClient(t, func(method string) (int, string) {
+				mutex.Lock()
+				probed = append(probed, method)
+				mutex.Unlock()
+				if tt.known[Method(method)] {
+					return http.StatusOK, badParamsResponse
+				}
+				return http.StatusOK, noMethodResponse
+			})
+			capabilities, err := c.Capabilities()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if capabilities.Version != tt.version {
+				t.Fatalf("Version = %q, want %q", capabilities.Version, tt.version)
+			}
+			for _, method := range probed {
+				if Method(method).IsMutating() {
+					t.Fatalf("mutating method %s was probed", method)
+				}
+			}
+			for _, spec := range Methods() {
+				if !spec.Optional {
+					continue
+				}
+				want := spec.Since != "" && compareVersions(spec.Since, tt.version) <= 0
+				if got := capabilities.Supports(spec.Name); got != want {
+					t.Fatalf("Supports(%s) = %v, want %v", spec.Name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCapabilitiesNotCachedOnHTTPError(t *testing.T) {
+	status := http.StatusUnauthorized
+	c := newTestClient(t, func(method string) (int, string) {
+		if status != http.StatusOK {
+			return status, unauthorizedResponse
+		}
+		return http.StatusOK, noMethodResponse
+	})
+	if _, err := c.Capabilities(); err == nil {
+		t.Fatal("Capabilities() = nil error, want the http error")
+	}
+	status = http.StatusOK
+	capabilities, err := c.Capabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capabilities.Supports(MethodCancelTransaction) {
+		t.Fatal("the http error was cached as a supported method")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.1.0", "2.1.0", 0},
+		{"2.1", "2.1.0", 0},
+		{"2.0.4", "2.1.0", -1},
+		{"2.10.0", "2.2.0", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
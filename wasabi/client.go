@@ -3,61 +3,101 @@ package wasabi
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
 	"math/big"
 	"net"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 )
 
-// Client is a wasabi-wallet-rpc client.
-type Client interface {
+// DaemonController controls the daemon itself: its status, the fee rates it reports and its shutdown.
+type DaemonController interface {
 	// IsWasabiWalletUp checks if Wasabi is running and reachable.
 	IsWasabiWalletUp() bool
 
+	// Ping performs a JSON-RPC round trip and returns its latency and whether the daemon accepted the credentials. Health checks should prefer it to IsWasabiWalletUp.
+	Ping(ctx context.Context) (PingResult, error)
+
 	// GetStatus returns information useful to understand Wasabi and its synchronization status.
 	GetStatus() (GetStatusResponse, error)
 
+	// GetFeeRates returns the fee rates (in satoshi per byte) for the given confirmation targets (in blocks).
+	GetFeeRates() (GetFeeRatesResponse, error)
+
+	// Stop stops and exits Wasabi. It returns as soon as the daemon accepted the request, before it exited; use StopAndWait to wait for the exit.
+	Stop() error
+}
+
+// WalletManager creates, recovers, loads and lists wallets.
+type WalletManager interface {
 	// CreateWallet creates a new wallet with the given name and password and returns the twelve recovery words of the freshly generated wallet in one string (space separated).
 	CreateWallet(walletName string, password string) (string, error)
 
+	// RecoverWallet recovers a wallet with the given name, mnemonic and password. The first parameter is the (new) wallet name, the second parameter is the mnemonic (recovery words), the third parameter is an optional passphrase (aka the password in Wasabi).
+	RecoverWallet(walletName string, mnemonic string, password string) error
+
 	// LoadWallet loads a wallet with the given name. Before accessing the wallet for the first time, it must be loaded.
 	LoadWallet(walletName string) error
 
+	// ListWallets returns the list of all wallets.
+	ListWallets() ([]ListWalletsResponseItem, error)
+}
+
+// WalletReader reads the coins, keys, history and information of a wallet and hands out its receive addresses.
+type WalletReader interface {
+	// GetWalletInfo returns information about the current loaded wallet.
+	GetWalletInfo(walletName string) (GetWalletInfoResponse, error)
+
 	// ListCoins returns the list of previously spent and currently unspent coins (confirmed and unconfirmed).
 	ListCoins(walletName string) ([]ListCoinsResponse, error)
 
 	// ListUnspentCoins returns the list of confirmed and unconfirmed coins that are unspent.
 	ListUnspentCoins(walletName string) ([]ListCoinsResponse, error)
 
-	// GetWalletInfo returns information about the current loaded wallet.
-	GetWalletInfo(walletName string) (GetWalletInfoResponse, error)
+	// GetHistory returns the list of all transactions sent and received.
+	GetHistory(walletName string) ([]Transaction, error)
+
+	// ListKeys returns the list of all the generated keys.
+	ListKeys(walletName string) ([]GeneratedKey, error)
 
 	// GetNewAddress creates an address and returns detailed information about it.
 	GetNewAddress(walletName string, label string) (GetNewAddressResponse, error)
+}
 
+// TransactionBuilder builds, sends, broadcasts and replaces transactions.
+type TransactionBuilder interface {
 	// Send builds and broadcasts a transaction.
-	Send(walletName string, payments []Payment, coins []Coin, feeTarget int, password string) (SendResponse, error)
+	Send(walletName string, req SendRequest) (SendResponse, error)
 
-	// Build builds a transaction. It is similar to the send method, except that it will not automatically broadcast the transaction. So it is also possible to send to many and to subtract the fee.
-	Build(walletName string, payments []Payment, coins []Coin, feeTarget int, password string) (string, error)
+	// Build builds a transaction. It is similar to the send method, except that it will not automatically broadcast the transaction. So it is also possible to send to many and to subtract the fee (see Payment.SubtractFee).
+	Build(walletName string, req BuildRequest) (string, error)
 
-	// Broadcast broadcasts a transaction. Enter the transaction hex in the params field. Returns the transaction id.
-	Broadcast(walletName string, hex string) (string, error)
+	// BuildUnsafeTransaction - constructs a transaction without checking fees and using unconfirmed coins. Unsafe, because no matter how big fee the user chooses, Wasabi will build the transaction. Potentially, the user can burn his money using this method, so be careful. The result is the transaction hex, waiting to be broadcast.
+	BuildUnsafeTransaction(walletName string, req BuildUnsafeRequest) (string, error)
 
-	// GetHistory returns the list of all transactions sent and received.
-	GetHistory(walletName string) ([]Transaction, error)
+	// Broadcast broadcasts a transaction. Enter the transaction hex in the params field. Returns the transaction id. The walletName may be empty to relay a transaction (e.g. signed externally) without targeting a loaded wallet.
+	Broadcast(walletName string, hex string) (TxID, error)
 
-	// ListKeys returns the list of all the generated keys.
-	ListKeys(walletName string) ([]GeneratedKey, error)
+	// CancelTransaction - cancels a transaction and returns the transaction hex, ready for broadcast. It expects the wallet name, transaction id and the password. It is similar to the SpeedUpTransaction method, except that it will create a transaction back to the wallet. The transaction is not automatically broadcast.
+	CancelTransaction(walletName string, txID TxID, password string) (string, error)
+
+	// SpeedUpTransaction - speeds up a transaction and returns the transaction hex, ready for broadcast. It expects the wallet name, transaction id and the password. It does not automatically broadcast the new transaction, so it still needs to be (manually) broadcast.
+	SpeedUpTransaction(walletName string, txID TxID, password string) (string, error)
+}
 
-	// StartCoinJoin starts a CoinJoin round. It expects the wallet name, the password, a boolean to stop when all mixed and a boolean to override the pleb stop.
-	StartCoinJoin(walletName string, password string, stopWhenAllMixed bool, overridePlebStop bool) error
+// CoinJoinController starts and stops coinjoins and manages the coins and payments taking part in them.
+type CoinJoinController interface {
+	// StartCoinJoin starts a CoinJoin round. It expects the wallet name, the password and the options of the coinjoin.
+	StartCoinJoin(walletName string, password string, opts StartCoinJoinOptions) error
 
 	// StartCoinJoinSweep starts a CoinJoin to another wallet.
 	StartCoinJoinSweep(walletName string, password string, outputWalletName string) error
@@ -65,38 +105,50 @@ type Client interface {
 	// StopCoinJoin stops a CoinJoin round.
 	StopCoinJoin(walletName string) error
 
-	// Stop stops and exits Wasabi.
-	Stop() error
-
-	// GetFeeRates returns the fee rates (in satoshi per byte) for the given confirmation targets (in blocks).
-	GetFeeRates() (GetFeeRatesResponse, error)
-
-	// ListWallets returns the list of all wallets.
-	ListWallets() ([]ListWalletsResponseItem, error)
-
 	// ExcludeFromCoinJoin excludes a coin from the CoinJoin or includes it again. It expects the wallet name, the transaction id and the index of the coin (vOut) and a boolean to exclude or include it.
-	ExcludeFromCoinJoin(walletName string, txID string, index int, exclude bool) error
-
-	// RecoverWallet recovers a wallet with the given name, mnemonic and password. The first parameter is the (new) wallet name, the second parameter is the mnemonic (recovery words), the third parameter is an optional passphrase (aka the password in Wasabi).
-	RecoverWallet(walletName string, mnemonic string, password string) error
+	ExcludeFromCoinJoin(walletName string, txID TxID, index int, exclude bool) error
 
-	// BuildUnsafeTransaction - constructs a transaction without checking fees and using unconfirmed coins. Unsafe, because no matter how big fee the user chooses, Wasabi will build the transaction. Potentially, the user can burn his money using this method, so be careful. The result is the transaction hex, waiting to be broadcast.
-	BuildUnsafeTransaction(walletName string, payments []Payment, coins []Coin, feeTarget int, password string) (string, error)
+	// ExcludeCoinsFromCoinJoin excludes many coins from the CoinJoin or includes them again. The calls are sent in JSON-RPC batches (or one by one if the daemon does not support batches).
+	ExcludeCoinsFromCoinJoin(walletName string, coins []Coin, exclude bool) error
 
 	// PayInCoinJoin - pays to the specified address the specified amount of money using CoinJoin. Returns hte paymentId (UUID). A PayInCoinJoin is written to the logs of WasabiWallet, and it's status can be seen by using the ListPaymentsInCoinJoin method. Currently, the default maximum is 4 payments per client per CoinJoin. PayInCoinJoin only registers a payment, so if CoinJoin is not running or the amount is lower than the wallet balance, the payment is queued. Pending payments can be removed by using the CancelPaymentInCoinJoin method. Pending payments are also removed if the Wasabi client restarts.
-	PayInCoinJoin(walletName string, address string, amount int, password string) (string, error)
+	PayInCoinJoin(walletName string, address Address, amount Amount, password string) (string, error)
 
 	// ListPaymentsInCoinJoin - returns the list of payments in the CoinJoin.
 	ListPaymentsInCoinJoin(walletName string) ([]ListPaymentsInCoinJoinResponseItem, error)
 
 	// CancelPaymentInCoinJoin - cancels a payment in the CoinJoin. It expects the wallet name and the payment id.
 	CancelPaymentInCoinJoin(walletName string, paymentID string) error
+}
 
-	// CancelTransaction - cancels a transaction and returns the transaction hex, ready for broadcast. It expects the wallet name, transaction id and the password. It is similar to the SpeedUpTransaction method, except that it will create a transaction back to the wallet. The transaction is not automatically broadcast.
-	CancelTransaction(walletName string, txID string, password string) (string, error)
+// Client is a wasabi-wallet-rpc client. It is the union of DaemonController, WalletManager, WalletReader, TransactionBuilder and CoinJoinController, so consumers can depend on (and mock) only the part they use.
+type Client interface {
+	DaemonController
+	WalletManager
+	WalletReader
+	TransactionBuilder
+	CoinJoinController
 
-	// SpeedUpTransaction - speeds up a transaction and returns the transaction hex, ready for broadcast. It expects the wallet name, transaction id and the password. It does not automatically broadcast the new transaction, so it still needs to be (manually) broadcast.
-	SpeedUpTransaction(walletName string, txID string, password string) (string, error)
+	// Capabilities returns the features supported by the daemon. They are detected on first use and cached.
+	Capabilities() (Capabilities, error)
+
+	// RawCall calls an rpc method which is not covered by the typed methods (or is only available in experimental daemon builds). The params are sent as JSON-RPC params and the result is decoded into out (which may be nil to discard it). The walletName may be empty for methods which do not target a wallet.
+	RawCall(walletName string, method string, params interface{}, out interface{}) error
+
+	// CallRaw calls an rpc method like RawCall, but copies the JSON of the result to w as it is received instead of decoding it, so huge results (listcoins or gethistory of large wallets) can be archived or proxied without holding them in memory. A null result is written as null. The call is not retried, as the result may be partially written when it fails.
+	CallRaw(walletName string, method string, params interface{}, w io.Writer) error
+
+	// Wallet returns a handle of the wallet with the given name, whose methods do not need the wallet name.
+	Wallet(walletName string) *Wallet
+
+	// WithContext returns a client that makes its calls with the given context. The context controls cancellation and deadlines of the calls and carries the trace of the caller.
+	WithContext(ctx context.Context) Client
+
+	// With returns a client derived with the options (see WithHeader, WithTimeout and WithEndpoint). It shares the http transport, the statistics and the rest of the configuration of the client.
+	With(opts ...ClientOption) (Client, error)
+
+	// Stats returns latency percentiles and error rates of the most recent calls per method.
+	Stats() map[Method]MethodStats
 }
 
 // NewClient creates a new Client.
@@ -105,62 +157,260 @@ func NewClient(cfg Config) (Client, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 	rpcClient := &client{
-		host:    cfg.Host,
-		port:    cfg.Port,
-		headers: cfg.CustomHeaders,
-	}
-	if cfg.Transport == nil {
+		host:                cfg.Host,
+		port:                cfg.Port,
+		headers:             cfg.CustomHeaders,
+		logger:              cfg.Logger,
+		tracer:              cfg.Tracer,
+		debugWriter:         cfg.DebugWriter,
+		interceptors:        cfg.Interceptors,
+		auditSink:           cfg.AuditSink,
+		correlationIDHeader: cfg.CorrelationIDHeader,
+		mutex:               &sync.Mutex{},
+		ctx:                 context.Background(),
+		stats:               newCallStats(),
+		bursts:              newBurstDetector(cfg.FailureBurst),
+		routing:             cfg.Routing,
+		selection:           &walletSelection{},
+		capabilities:        &capabilityCache{},
+		network:             &networkCache{},
+		disableNetworkGuard: cfg.DisableNetworkGuard,
+		feePolicy:           cfg.FeePolicy,
+		useNumber:           cfg.UseNumber,
+		retry:               cfg.Retry,
+	}
+	if rpcClient.correlationIDHeader == "" {
+		rpcClient.correlationIDHeader = DefaultCorrelationIDHeader
+	}
+	rpcClient.logLevel = slog.LevelDebug
+	if cfg.LogLevel != nil {
+		rpcClient.logLevel = cfg.LogLevel.Level()
+	}
+	rpcClient.logErrorLevel = slog.LevelError
+	if cfg.LogErrorLevel != nil {
+		rpcClient.logErrorLevel = cfg.LogErrorLevel.Level()
+	}
+	rpcClient.httpClient = cfg.HTTPClient
+	if rpcClient.httpClient == nil {
 		rpcClient.httpClient = http.DefaultClient
-	} else {
-		rpcClient.httpClient = &http.Client{
-			Transport: cfg.Transport,
-		}
 	}
+	if cfg.Transport != nil {
+		httpClient := *rpcClient.httpClient
+		httpClient.Transport = cfg.Transport
+		rpcClient.httpClient = &httpClient
+	}
+	hedges, err := hedgeClients(rpcClient, cfg.Hedge)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	rpcClient.hedge = cfg.Hedge
+	rpcClient.hedges = hedges
 	return rpcClient, nil
 }
 
 type client struct {
-	httpClient *http.Client
-	host       string
-	port       int
-	headers    map[string]string
-	mutex      sync.Mutex
+	httpClient   *http.Client
+	host         string
+	port         int
+	headers      map[string]string
+	mutex        *sync.Mutex
+	ctx          context.Context
+	stats        *callStats
+	bursts       *burstDetector
+	routing      RoutingMode
+	selection    *walletSelection
+	capabilities *capabilityCache
+	network      *networkCache
+
+	tracer              Tracer
+	debugWriter         io.Writer
+	interceptors        []Interceptor
+	auditSink           AuditSink
+	correlationIDHeader string
+	logger              Logger
+	logLevel            slog.Level
+	logErrorLevel       slog.Level
+	disableNetworkGuard bool
+	feePolicy           FeePolicy
+	useNumber           bool
+	retry               RetryPolicy
+	hedge               HedgePolicy
+	hedges              []*client
+	timeout             time.Duration
 }
 
 // Helper function
-func (c *client) do(method Method, targetWalletName string, in, out interface{}) error {
-	payload, err := encodeClientRequest(method.String(), in)
+func (c *client) do(method Method, targetWalletName string, in, out interface{}) (err error) {
+	start := time.Now()
+	correlationID := CorrelationIDFromContext(c.ctx)
+	if correlationID == "" {
+		correlationID = newCorrelationID()
+	}
+	defer func() {
+		if err != nil {
+			err = &CallError{Method: method, WalletName: targetWalletName, CorrelationID: correlationID, Err: err}
+		}
+		duration := time.Since(start)
+		c.stats.record(method, duration, err)
+		c.bursts.record(method, start, err)
+		c.logCall(method, targetWalletName, correlationID, in, duration, err)
+		c.audit(start, method, targetWalletName, correlationID, in, out, err)
+	}()
+
+	ctx, cancel := c.callContext()
+	defer cancel()
+	if c.tracer != nil {
+		var span Span
+		ctx, span = c.tracer.Start(ctx, "wasabi."+method.String(), map[string]string{
+			"rpc.system":            "jsonrpc",
+			"rpc.method":            method.String(),
+			"wasabi.wallet":         targetWalletName,
+			"wasabi.correlation_id": correlationID,
+			"server.address":        c.host,
+			"server.port":           strconv.Itoa(c.port),
+		})
+		defer func() {
+			span.End(err)
+		}()
+	}
+
+	if err = c.checkSupported(method); err != nil {
+		return err
+	}
+
+	if c.routing == RoutingSelectWallet && targetWalletName != "" {
+		c.selection.mutex.Lock()
+		defer c.selection.mutex.Unlock()
+		if err = c.selectWallet(ctx, targetWalletName); err != nil {
+			return err
+		}
+	}
+
+	call := &CallInfo{
+		Context:       ctx,
+		Method:        method,
+		WalletName:    targetWalletName,
+		CorrelationID: correlationID,
+		Params:        in,
+		Header:        http.Header{},
+	}
+	call.Header.Set(c.correlationIDHeader, correlationID)
+	for {
+		call.Attempt++
+		call.Retry = false
+		err = c.intercept(call, out)
+		if !c.retries(call, err) {
+			return err
+		}
+	}
+}
+
+// walletSelection holds the wallet selected with selectwallet (see RoutingSelectWallet).
+type walletSelection struct {
+	mutex    sync.Mutex
+	selected string
+}
+
+// selectWallet selects the wallet on the daemon unless it is already selected. The caller must hold the selection mutex.
+func (c *client) selectWallet(ctx context.Context, walletName string) error {
+	if c.selection.selected == walletName {
+		return nil
+	}
+	c.selection.selected = ""
+	call := &CallInfo{
+		Context: ctx,
+		Method:  MethodSelectWallet,
+		Params:  []interface{}{walletName},
+		Header:  http.Header{},
+	}
+	if err := c.send(call, nil); err != nil {
+		return fmt.Errorf("failed to select wallet %s: %w", walletName, err)
+	}
+	c.selection.selected = walletName
+	return nil
+}
+
+// send sends the call to the rpc server and decodes the result into out.
+func (c *client) send(call *CallInfo, out interface{}) error {
+	batch, isBatch := call.Params.(batchParams)
+	var (
+		payload  []byte
+		batchIDs []uint64
+		err      error
+	)
+	if isBatch {
+		payload, batchIDs, err = encodeBatchRequest(call.Method.String(), batch)
+	} else {
+		payload, err = encodeClientRequest(call.Method.String(), call.Params)
+	}
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s:%d/%s", c.host, c.port, targetWalletName), bytes.NewBuffer(payload))
+	path := call.WalletName
+	if c.routing == RoutingSelectWallet {
+		path = ""
+	}
+	req, err := http.NewRequestWithContext(call.Context, http.MethodPost, fmt.Sprintf("http://%s/%s", net.JoinHostPort(c.host, strconv.Itoa(c.port)), path), bytes.NewBuffer(payload))
 	if err != nil {
 		return err
 	}
+	if c.tracer != nil {
+		c.tracer.Inject(call.Context, req.Header)
+	}
 
 	if c.headers != nil {
 		for k, v := range c.headers {
 			req.Header.Set(k, v)
 		}
 	}
+	for k, v := range call.Header {
+		req.Header[k] = v
+	}
 
 	// Only one request at a time
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	if c.debugWriter != nil {
+		dumpRequest(c.debugWriter, req, call.Method, call.Params, payload)
+	}
+	sent := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return &ConnectionError{Err: err}
+	}
+	defer resp.Body.Close()
+	if c.debugWriter != nil {
+		if _, streamed := out.(*resultWriter); streamed {
+			dump := dumpStreamedResponse(c.debugWriter, resp, call.Method, time.Since(sent))
+			defer dump()
+		} else if err := dumpResponse(c.debugWriter, resp, call.Method, time.Since(sent)); err != nil {
+			return &ConnectionError{Err: err}
+		}
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http status %v", resp.StatusCode)
+		return &ProtocolError{Err: newHTTPError(resp)}
+	}
+
+	if isBatch {
+		return decodeBatchResponse(resp.Body, batchIDs)
+	}
+
+	if rw, ok := out.(*resultWriter); ok {
+		err := streamClientResponse(resp.Body, rw)
+		if rw.err != nil {
+			return rw.err
+		}
+		if err != nil {
+			return classifyResponseError(err)
+		}
+		return nil
 	}
-	defer resp.Body.Close()
 
 	// Some methods return null, which is not an error. (LoadWallet, StopCoinJoin, Stop)
-	if err := decodeClientResponse(resp.Body, out); err != nil && !errors.Is(err, RPCErrNullResult) {
-		return err
+	if err := decodeClientResponse(resp.Body, out, c.useNumber); err != nil && !errors.Is(err, RPCErrNullResult) {
+		return classifyResponseError(err)
 	}
 	return nil
 }
@@ -168,7 +418,7 @@ func (c *client) do(method Method, targetWalletName string, in, out interface{})
 // Method implementation
 
 func (c *client) IsWasabiWalletUp() bool {
-	conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", c.host, c.port))
+	conn, err := net.Dial("tcp", net.JoinHostPort(c.host, strconv.Itoa(c.port)))
 	if err != nil {
 		return false
 	}
@@ -176,100 +426,77 @@ func (c *client) IsWasabiWalletUp() bool {
 	return true
 }
 
-func (c *client) GetStatus() (resp GetStatusResponse, err error) {
-	err = c.do(MethodGetStatus, "", nil, &resp)
-	if err != nil {
-		return GetStatusResponse{}, err
-	}
-	return
+func (c *client) GetStatus() (GetStatusResponse, error) {
+	return Call[GetStatusResponse](c, MethodGetStatus, "", nil)
 }
 
-func (c *client) CreateWallet(walletName string, password string) (resp string, err error) {
-	err = c.do(MethodCreateWallet, "", []interface{}{walletName, password}, &resp)
-	if err != nil {
-		return "", err
-	}
-	return
+func (c *client) CreateWallet(walletName string, password string) (string, error) {
+	return Call[string](c, MethodCreateWallet, "", []interface{}{walletName, password})
 }
 
 func (c *client) LoadWallet(walletName string) error {
 	return c.do(MethodLoadWallet, "", []interface{}{walletName}, nil)
 }
 
-func (c *client) ListCoins(walletName string) (resp []ListCoinsResponse, err error) {
-	err = c.do(MethodListCoins, walletName, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-	return
+func (c *client) ListCoins(walletName string) ([]ListCoinsResponse, error) {
+	return Call[[]ListCoinsResponse](c, MethodListCoins, walletName, nil)
 }
 
-func (c *client) ListUnspentCoins(walletName string) (resp []ListCoinsResponse, err error) {
-	err = c.do(MethodListUnspentCoins, walletName, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-	return
+func (c *client) ListUnspentCoins(walletName string) ([]ListCoinsResponse, error) {
+	return Call[[]ListCoinsResponse](c, MethodListUnspentCoins, walletName, nil)
 }
 
-func (c *client) GetWalletInfo(walletName string) (resp GetWalletInfoResponse, err error) {
-	err = c.do(MethodGetWalletInfo, walletName, nil, &resp)
-	if err != nil {
-		return GetWalletInfoResponse{}, err
-	}
-	return resp, nil
+func (c *client) GetWalletInfo(walletName string) (GetWalletInfoResponse, error) {
+	return Call[GetWalletInfoResponse](c, MethodGetWalletInfo, walletName, nil)
 }
 
-func (c *client) GetNewAddress(walletName string, label string) (resp GetNewAddressResponse, err error) {
-	err = c.do(MethodGetNewAddress, walletName, []interface{}{label}, &resp)
-	if err != nil {
-		return GetNewAddressResponse{}, err
-	}
-	return resp, nil
+func (c *client) GetNewAddress(walletName string, label string) (GetNewAddressResponse, error) {
+	return Call[GetNewAddressResponse](c, MethodGetNewAddress, walletName, []interface{}{label})
 }
 
-func (c *client) Send(walletName string, payments []Payment, coins []Coin, feeTarget int, password string) (resp SendResponse, err error) {
-	err = c.do(MethodSend, walletName, map[string]interface{}{"payments": payments, "coins": coins, "feeTarget": feeTarget, "password": password}, &resp)
+func (c *client) Send(walletName string, req SendRequest) (SendResponse, error) {
+	params, err := req.params()
 	if err != nil {
 		return SendResponse{}, err
 	}
-	return resp, nil
+	if err := c.checkPaymentsNetwork(req.Payments); err != nil {
+		return SendResponse{}, err
+	}
+	return Call[SendResponse](c, MethodSend, walletName, params)
 }
 
-func (c *client) Build(walletName string, payments []Payment, coins []Coin, feeTarget int, password string) (resp string, err error) {
-	err = c.do(MethodBuild, walletName, map[string]interface{}{"payments": payments, "coins": coins, "feeTarget": feeTarget, "password": password}, &resp)
+func (c *client) Build(walletName string, req BuildRequest) (string, error) {
+	params, err := req.params()
 	if err != nil {
 		return "", err
 	}
-	return resp, nil
-}
-
-func (c *client) Broadcast(walletName string, hex string) (resp string, err error) {
-	err = c.do(MethodBroadcast, walletName, []interface{}{hex}, &resp)
+	if err := c.checkPaymentsNetwork(req.Payments); err != nil {
+		return "", err
+	}
+	txHex, err := Call[string](c, MethodBuild, walletName, params)
 	if err != nil {
 		return "", err
 	}
-	return resp, nil
+	if err := checkFeePolicy(c, walletName, txHex, req.Payments, c.feePolicy); err != nil {
+		return "", err
+	}
+	return txHex, nil
 }
 
-func (c *client) GetHistory(walletName string) (resp []Transaction, err error) {
-	err = c.do(MethodGetHistory, walletName, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+func (c *client) Broadcast(walletName string, hex string) (TxID, error) {
+	return Call[TxID](c, MethodBroadcast, walletName, []interface{}{hex})
 }
 
-func (c *client) ListKeys(walletName string) (resp []GeneratedKey, err error) {
-	err = c.do(MethodListKeys, walletName, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+func (c *client) GetHistory(walletName string) ([]Transaction, error) {
+	return Call[[]Transaction](c, MethodGetHistory, walletName, nil)
 }
 
-func (c *client) StartCoinJoin(walletName string, password string, stopWhenAllMixed bool, overridePlebStop bool) error {
-	return c.do(MethodStartCoinJoin, walletName, []interface{}{password, stopWhenAllMixed, overridePlebStop}, nil)
+func (c *client) ListKeys(walletName string) ([]GeneratedKey, error) {
+	return Call[[]GeneratedKey](c, MethodListKeys, walletName, nil)
+}
+
+func (c *client) StartCoinJoin(walletName string, password string, opts StartCoinJoinOptions) error {
+	return c.do(MethodStartCoinJoin, walletName, []interface{}{password, opts.StopWhenAllMixed, opts.OverridePlebStop}, nil)
 }
 
 func (c *client) StartCoinJoinSweep(walletName string, password string, outputWalletName string) error {
@@ -284,23 +511,15 @@ func (c *client) Stop() error {
 	return c.do(MethodStop, "", nil, nil)
 }
 
-func (c *client) GetFeeRates() (resp GetFeeRatesResponse, err error) {
-	err = c.do(MethodGetFeeRates, "", nil, &resp)
-	if err != nil {
-		return GetFeeRatesResponse{}, err
-	}
-	return resp, nil
+func (c *client) GetFeeRates() (GetFeeRatesResponse, error) {
+	return Call[GetFeeRatesResponse](c, MethodGetFeeRates, "", nil)
 }
 
-func (c *client) ListWallets() (resp []ListWalletsResponseItem, err error) {
-	err = c.do(MethodListWallets, "", nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+func (c *client) ListWallets() ([]ListWalletsResponseItem, error) {
+	return Call[[]ListWalletsResponseItem](c, MethodListWallets, "", nil)
 }
 
-func (c *client) ExcludeFromCoinJoin(walletName string, txID string, index int, exclude bool) error {
+func (c *client) ExcludeFromCoinJoin(walletName string, txID TxID, index int, exclude bool) error {
 	return c.do(MethodExcludeFromCoinJoin, walletName, []interface{}{txID, index, exclude}, nil)
 }
 
@@ -308,48 +527,63 @@ func (c *client) RecoverWallet(walletName string, mnemonic string, password stri
 	return c.do(MethodRecoverWallet, "", []interface{}{walletName, mnemonic, password}, nil)
 }
 
-func (c *client) BuildUnsafeTransaction(walletName string, payments []Payment, coins []Coin, feeTarget int, password string) (resp string, err error) {
-	err = c.do(MethodBuildUnsafeTransaction, walletName, map[string]interface{}{"payments": payments, "coins": coins, "feeTarget": feeTarget, "password": password}, &resp)
+func (c *client) BuildUnsafeTransaction(walletName string, req BuildUnsafeRequest) (string, error) {
+	params, err := req.params()
 	if err != nil {
 		return "", err
 	}
-	return resp, nil
-}
-
-func (c *client) PayInCoinJoin(walletName string, address string, amount int, password string) (resp string, err error) {
-	err = c.do(MethodPayInCoinJoin, walletName, []interface{}{address, amount, password}, &resp)
+	if err := c.checkPaymentsNetwork(req.Payments); err != nil {
+		return "", err
+	}
+	txHex, err := Call[string](c, MethodBuildUnsafeTransaction, walletName, params)
 	if err != nil {
 		return "", err
 	}
-	return resp, nil
+	policy := c.feePolicy
+	if req.MaxFee > 0 && (policy.MaxFee == 0 || req.MaxFee < policy.MaxFee) {
+		policy.MaxFee = req.MaxFee
+	}
+	if err := checkFeePolicy(c, walletName, txHex, req.Payments, policy); err != nil {
+		return "", err
+	}
+	return txHex, nil
 }
 
-func (c *client) ListPaymentsInCoinJoin(walletName string) (resp []ListPaymentsInCoinJoinResponseItem, err error) {
-	err = c.do(MethodListPaymentsInCoinJoin, walletName, nil, &resp)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+func (c *client) PayInCoinJoin(walletName string, address Address, amount Amount, password string) (string, error) {
+	return Call[string](c, MethodPayInCoinJoin, walletName, []interface{}{address, amount, password})
+}
+
+func (c *client) ListPaymentsInCoinJoin(walletName string) ([]ListPaymentsInCoinJoinResponseItem, error) {
+	return Call[[]ListPaymentsInCoinJoinResponseItem](c, MethodListPaymentsInCoinJoin, walletName, nil)
 }
 
 func (c *client) CancelPaymentInCoinJoin(walletName string, paymentID string) error {
 	return c.do(MethodCancelPaymentInCoinJoin, walletName, []interface{}{paymentID}, nil)
 }
 
-func (c *client) CancelTransaction(walletName string, txID string, password string) (resp string, err error) {
-	err = c.do(MethodCancelTransaction, walletName, []interface{}{txID, password}, &resp)
-	if err != nil {
-		return "", err
-	}
-	return resp, nil
+func (c *client) CancelTransaction(walletName string, txID TxID, password string) (string, error) {
+	return Call[string](c, MethodCancelTransaction, walletName, []interface{}{txID, password})
 }
 
-func (c *client) SpeedUpTransaction(walletName string, txID string, password string) (resp string, err error) {
-	err = c.do(MethodSpeedUpTransaction, walletName, []interface{}{txID, password}, &resp)
-	if err != nil {
-		return "", err
+func (c *client) SpeedUpTransaction(walletName string, txID TxID, password string) (string, error) {
+	return Call[string](c, MethodSpeedUpTransaction, walletName, []interface{}{txID, password})
+}
+
+func (c *client) RawCall(walletName string, method string, params interface{}, out interface{}) error {
+	if method == "" {
+		return fmt.Errorf("method must not be empty")
 	}
-	return resp, nil
+	return c.do(Method(method), walletName, params, out)
+}
+
+func (c *client) Wallet(walletName string) *Wallet {
+	return NewWallet(c, walletName)
+}
+
+func (c *client) WithContext(ctx context.Context) Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
 }
 
 // encodeClientRequest encodes parameters for a JSON-RPC client request.
@@ -368,8 +602,8 @@ func encodeClientRequest(method string, args interface{}) ([]byte, error) {
 	return json.Marshal(c)
 }
 
-// decodeClientResponse decodes the response body of a client request into the interface reply.
-func decodeClientResponse(r io.Reader, reply interface{}) error {
+// decodeClientResponse decodes the response body of a client request into the interface reply. With useNumber, the result is decoded by decodeNumbers.
+func decodeClientResponse(r io.Reader, reply interface{}, useNumber bool) error {
 	var c clientResponse
 	if err := json.NewDecoder(r).Decode(&c); err != nil {
 		return err
@@ -389,6 +623,9 @@ func decodeClientResponse(r io.Reader, reply interface{}) error {
 		return RPCErrNullResult
 	}
 
+	if useNumber {
+		return decodeNumbers(*c.Result, reply)
+	}
 	return json.Unmarshal(*c.Result, reply)
 }
 
@@ -411,6 +648,7 @@ type clientRequest struct {
 // clientResponse represents a JSON-RPC response returned to a client.
 type clientResponse struct {
 	Version string           `json:"jsonrpc"`
+	Id      uint64           `json:"id"`
 	Result  *json.RawMessage `json:"result"`
 	Error   *json.RawMessage `json:"error"`
 }
@@ -0,0 +1,70 @@
+package wasabi
+
+import "time"
+
+// Clock provides the time and the timers of the waiting helpers and watchers. Tests can inject a fake clock (see wasabitest.FakeClock) to advance the time instantly instead of sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a timer firing once after d.
+	NewTimer(d time.Duration) Timer
+	// NewTicker creates a ticker firing every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a timer created by a Clock.
+type Timer interface {
+	// C returns the channel receiving the time when the timer fires.
+	C() <-chan time.Time
+	// Stop stops the timer. It returns false if the timer already fired or was stopped.
+	Stop() bool
+}
+
+// Ticker is a ticker created by a Clock.
+type Ticker interface {
+	// C returns the channel receiving the time of each tick.
+	C() <-chan time.Time
+	// Stop stops the ticker.
+	Stop()
+}
+
+// SystemClock is the Clock of the time package. It is the default clock of the waiting helpers and watchers.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// clockOrDefault returns the clock, or SystemClock if it is nil.
+func clockOrDefault(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
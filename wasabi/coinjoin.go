@@ -0,0 +1,137 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCoinJoinStopped is returned by WaitUntilAllMixed when the coinjoin of the wallet stops before all funds are private.
+var ErrCoinJoinStopped = errors.New("coinjoin stopped before all funds were mixed")
+
+// IsIdle reports whether the wallet does not coinjoin.
+func (s CoinJoinStatus) IsIdle() bool {
+	return s == CoinJoinStatusIdle
+}
+
+// IsScheduled reports whether the coinjoin is started but waits for its schedule.
+func (s CoinJoinStatus) IsScheduled() bool {
+	return s == CoinJoinStatusInSchedule
+}
+
+// IsActive reports whether the wallet takes part in a coinjoin round.
+func (s CoinJoinStatus) IsActive() bool {
+	return s == CoinJoinStatusInProgress || s == CoinJoinStatusInCriticalPhase
+}
+
+// IsCritical reports whether the round is in its critical phase, in which stopping the coinjoin or spending its coins makes the round fail and may get the coins banned for a while.
+func (s CoinJoinStatus) IsCritical() bool {
+	return s == CoinJoinStatusInCriticalPhase
+}
+
+// CoinJoinProgress describes the mixing progress of a wallet.
+type CoinJoinProgress struct {
+	// Status is the coinjoin status of the wallet.
+	Status CoinJoinStatus
+	// Balance is the balance of the wallet including its private part.
+	Balance Balance
+	// Rounds is the number of coinjoin transactions of the wallet which appeared since the watch started.
+	Rounds int
+}
+
+// AllMixed reports whether all funds of the wallet reached the anonymity score target.
+func (p CoinJoinProgress) AllMixed() bool {
+	return p.Balance.Private == p.Balance.Total
+}
+
+// PrivateRatio returns the private part of the balance between 0 and 1. An empty wallet is fully private.
+func (p CoinJoinProgress) PrivateRatio() float64 {
+	if p.Balance.Total == 0 {
+		return 1
+	}
+	return float64(p.Balance.Private) / float64(p.Balance.Total)
+}
+
+// WatchCoinJoinOptions holds the options of WatchCoinJoin and WaitUntilAllMixed.
+type WatchCoinJoinOptions struct {
+	// Interval is the interval between the checks. Default is DefaultPollInterval.
+	Interval time.Duration
+	// OnProgress is called with the first progress and then each time the coinjoin status or the balance changes, i.e. round by round. Optional.
+	OnProgress func(progress CoinJoinProgress)
+	// Clock provides the timers of the checks. Default is SystemClock.
+	Clock Clock
+}
+
+// WatchCoinJoin tracks the coinjoin status and the anonymity scores of the coins of the wallet until done reports true for a progress, a call fails or the context is done. It returns the last progress.
+func WatchCoinJoin(ctx context.Context, c Client, walletName string, done func(progress CoinJoinProgress) bool, opts WatchCoinJoinOptions) (CoinJoinProgress, error) {
+	c = c.WithContext(ctx)
+	var (
+		progress CoinJoinProgress
+		known    map[TxID]bool
+		first    = true
+	)
+	err := poll(ctx, opts.Clock, opts.Interval, func() (bool, error) {
+		info, err := c.GetWalletInfo(walletName)
+		if err != nil {
+			return false, err
+		}
+		coins, err := c.ListUnspentCoins(walletName)
+		if err != nil {
+			return false, err
+		}
+		history, err := c.GetHistory(walletName)
+		if err != nil {
+			return false, err
+		}
+		current := CoinJoinProgress{
+			Status:  info.CoinJoinStatus,
+			Balance: ComputeBalance(coins, info.AnonScoreTarget),
+			Rounds:  progress.Rounds,
+		}
+		coinJoins := make(map[TxID]bool)
+		for _, tx := range history {
+			if !tx.IsLikelyCoinJoin {
+				continue
+			}
+			coinJoins[tx.TxID] = true
+			if known != nil && !known[tx.TxID] {
+				current.Rounds++
+			}
+		}
+		known = coinJoins
+		changed := first || current != progress
+		first = false
+		progress = current
+		if changed && opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		return done(progress), nil
+	})
+	return progress, err
+}
+
+// WaitUntilAllMixed waits until all funds of the wallet reached the anonymity score target. It returns ErrCoinJoinStopped if the coinjoin status goes back to idle before, so it has to be called after the coinjoin was started. It returns the last progress.
+func WaitUntilAllMixed(ctx context.Context, c Client, walletName string, opts WatchCoinJoinOptions) (CoinJoinProgress, error) {
+	active := false
+	stopped := false
+	progress, err := WatchCoinJoin(ctx, c, walletName, func(progress CoinJoinProgress) bool {
+		if progress.AllMixed() {
+			return true
+		}
+		if progress.Status != CoinJoinStatusIdle && progress.Status != "" {
+			active = true
+		} else if active {
+			stopped = true
+		}
+		return stopped
+	}, opts)
+	if err == nil && stopped {
+		err = ErrCoinJoinStopped
+	}
+	return progress, err
+}
+
+// WaitUntilAllMixed waits until all funds of the wallet reached the anonymity score target.
+func (w *Wallet) WaitUntilAllMixed(ctx context.Context, opts WatchCoinJoinOptions) (CoinJoinProgress, error) {
+	return WaitUntilAllMixed(ctx, w.client, w.name, opts)
+}
@@ -0,0 +1,155 @@
+package wasabi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultCoinPageSize is the number of coins of a page if no page size is given.
+const DefaultCoinPageSize = 500
+
+// CoinOrder is the order of the coins returned by ListCoinsPaged.
+type CoinOrder int
+
+const (
+	// CoinOrderOutpoint sorts the coins by transaction id and index.
+	CoinOrderOutpoint CoinOrder = iota
+	// CoinOrderAmountDesc sorts the largest coins first, then by outpoint.
+	CoinOrderAmountDesc
+)
+
+// CoinCursor marks the position after the last coin of a page. It stays valid when coins are added or spent between the pages: the next page starts with the first coin sorted after the marked one, even if that coin is gone.
+type CoinCursor string
+
+// CoinPage is a page of coins.
+type CoinPage struct {
+	Coins []ListCoinsResponse
+	// Next is the cursor of the next page. It is empty on the last page.
+	Next CoinCursor
+}
+
+// CoinPager returns the coins of a wallet page by page, so user interfaces can render wallets with tens of thousands of coins incrementally.
+type CoinPager struct {
+	coins    []ListCoinsResponse
+	pageSize int
+	order    CoinOrder
+	pos      int
+}
+
+// ListCoinsPaged fetches the coins of the wallet and returns a pager over them in the given order. The daemon has no paging parameters, so the coins are fetched once and paged on the client; pass the cursor of a page to Seek to resume from another snapshot.
+func ListCoinsPaged(c WalletReader, walletName string, pageSize int, order CoinOrder) (*CoinPager, error) {
+	if pageSize < 0 {
+		return nil, fmt.Errorf("page size must not be negative")
+	}
+	if order != CoinOrderOutpoint && order != CoinOrderAmountDesc {
+		return nil, fmt.Errorf("unknown coin order %d", order)
+	}
+	if pageSize == 0 {
+		pageSize = DefaultCoinPageSize
+	}
+	coins, err := c.ListCoins(walletName)
+	if err != nil {
+		return nil, err
+	}
+	p := &CoinPager{coins: coins, pageSize: pageSize, order: order}
+	sort.Slice(coins, func(i, j int) bool {
+		return p.less(coinKey(coins[i]), coinKey(coins[j]))
+	})
+	return p, nil
+}
+
+// ListCoinsPaged returns a pager over the coins of the wallet.
+func (w *Wallet) ListCoinsPaged(pageSize int, order CoinOrder) (*CoinPager, error) {
+	return ListCoinsPaged(w.client, w.name, pageSize, order)
+}
+
+// Len returns the number of coins of the snapshot.
+func (p *CoinPager) Len() int {
+	return len(p.coins)
+}
+
+// Next returns the next page. It returns false once all the coins were returned.
+func (p *CoinPager) Next() (CoinPage, bool) {
+	if p.pos >= len(p.coins) {
+		return CoinPage{}, false
+	}
+	end := p.pos + p.pageSize
+	if end > len(p.coins) {
+		end = len(p.coins)
+	}
+	page := CoinPage{Coins: p.coins[p.pos:end]}
+	p.pos = end
+	if end < len(p.coins) {
+		page.Next = p.cursor(coinKey(page.Coins[len(page.Coins)-1]))
+	}
+	return page, true
+}
+
+// Seek moves the pager to the page following the cursor, which may come from another pager over the same wallet and order. An empty cursor moves back to the first page.
+func (p *CoinPager) Seek(cursor CoinCursor) error {
+	if cursor == "" {
+		p.pos = 0
+		return nil
+	}
+	order, after, err := parseCoinCursor(cursor)
+	if err != nil {
+		return err
+	}
+	if order != p.order {
+		return fmt.Errorf("cursor of coin order %d used with coin order %d", order, p.order)
+	}
+	p.pos = sort.Search(len(p.coins), func(i int) bool {
+		return p.less(after, coinKey(p.coins[i]))
+	})
+	return nil
+}
+
+// coinSortKey holds the fields of a coin the orders sort by.
+type coinSortKey struct {
+	amount Amount
+	txID   TxID
+	index  int
+}
+
+func coinKey(coin ListCoinsResponse) coinSortKey {
+	return coinSortKey{amount: coin.Amount, txID: coin.TxID, index: coin.Index}
+}
+
+// less reports whether the coin of key a is sorted before the coin of key b.
+func (p *CoinPager) less(a, b coinSortKey) bool {
+	if p.order == CoinOrderAmountDesc && a.amount != b.amount {
+		return a.amount > b.amount
+	}
+	if a.txID != b.txID {
+		return a.txID < b.txID
+	}
+	return a.index < b.index
+}
+
+// cursor returns the cursor marking the coin of the key.
+func (p *CoinPager) cursor(key coinSortKey) CoinCursor {
+	return CoinCursor(fmt.Sprintf("%d:%d:%s:%d", p.order, int64(key.amount), key.txID, key.index))
+}
+
+// parseCoinCursor parses a cursor returned by CoinPager.cursor.
+func parseCoinCursor(cursor CoinCursor) (CoinOrder, coinSortKey, error) {
+	parts := strings.Split(string(cursor), ":")
+	if len(parts) != 4 {
+		return 0, coinSortKey{}, fmt.Errorf("invalid coin cursor %q", cursor)
+	}
+	order, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, coinSortKey{}, fmt.Errorf("invalid coin cursor %q", cursor)
+	}
+	amount, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, coinSortKey{}, fmt.Errorf("invalid coin cursor %q", cursor)
+	}
+	index, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return 0, coinSortKey{}, fmt.Errorf("invalid coin cursor %q", cursor)
+	}
+	return CoinOrder(order), coinSortKey{amount: Amount(amount), txID: TxID(parts[2]), index: index}, nil
+}
@@ -0,0 +1,128 @@
+// Package coinselect selects the coins (inputs) of a transaction from the coins of a wallet.
+//
+// The result of a selection is passed as Coins of a wasabi.SendRequest or wasabi.BuildRequest:
+//
+//	coins, err := c.ListUnspentCoins(walletName)
+//	selected, err := coinselect.Select(coinselect.PrivacyAware{MinAnonScore: 5}, coins, payments, feeRate)
+//	resp, err := c.Send(walletName, wasabi.SendRequest{Payments: payments, Coins: selected, FeeRate: feeRate})
+package coinselect
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// ErrInsufficientFunds is returned when the coins are not enough to pay the target.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Target is the amount a selection must cover.
+type Target struct {
+	// Amount is the sum of the payments.
+	Amount wasabi.Amount
+	// Outputs is the number of payment outputs (without change).
+	Outputs int
+	// FeeRate is the fee rate in satoshi per virtual byte.
+	FeeRate float64
+	// SubtractFee reports whether the fee is subtracted from a payment, so the coins only need to cover the amount.
+	SubtractFee bool
+}
+
+// NewTarget returns the target of the payments at the fee rate.
+func NewTarget(payments []wasabi.Payment, feeRate float64) Target {
+	target := Target{Outputs: len(payments), FeeRate: feeRate}
+	for _, payment := range payments {
+		target.Amount += payment.Amount
+		target.SubtractFee = target.SubtractFee || payment.SubtractFee
+	}
+	return target
+}
+
+// Fee returns the estimated fee of a transaction with the given number of inputs, with or without a change output.
+func (t Target) Fee(inputs int, change bool) wasabi.Amount {
+	if t.SubtractFee {
+		return 0
+	}
+	outputs := t.Outputs
+	if change {
+		outputs++
+	}
+	return wasabi.EstimateFee(t.FeeRate, inputs, outputs)
+}
+
+// Needed returns the amount the coins must cover with the given number of inputs, with or without a change output.
+func (t Target) Needed(inputs int, change bool) wasabi.Amount {
+	return t.Amount + t.Fee(inputs, change)
+}
+
+// CostOfChange returns the cost of creating (and later spending) a change output. Selections whose excess is below it are better without change.
+func (t Target) CostOfChange() wasabi.Amount {
+	return wasabi.Amount(math.Ceil(float64(wasabi.OutputVSize+wasabi.InputVSize) * t.FeeRate))
+}
+
+// Strategy selects coins covering a target.
+type Strategy interface {
+	// Select returns the coins covering the target. The coins passed to Select are unspent. It returns ErrInsufficientFunds if the coins are not enough.
+	Select(coins []wasabi.ListCoinsResponse, target Target) ([]wasabi.ListCoinsResponse, error)
+}
+
+// Select selects the coins paying the payments at the fee rate with the strategy, and returns them as outpoints. Spent coins are ignored.
+func Select(strategy Strategy, coins []wasabi.ListCoinsResponse, payments []wasabi.Payment, feeRate float64) ([]wasabi.Coin, error) {
+	selected, err := strategy.Select(Spendable(coins), NewTarget(payments, feeRate))
+	if err != nil {
+		return nil, err
+	}
+	return Outpoints(selected), nil
+}
+
+// Spendable returns the unspent coins.
+func Spendable(coins []wasabi.ListCoinsResponse) []wasabi.ListCoinsResponse {
+	spendable := make([]wasabi.ListCoinsResponse, 0, len(coins))
+	for _, coin := range coins {
+		if coin.SpentBy == nil || *coin.SpentBy == "" {
+			spendable = append(spendable, coin)
+		}
+	}
+	return spendable
+}
+
+// Outpoints returns the outpoints of the coins.
+func Outpoints(coins []wasabi.ListCoinsResponse) []wasabi.Coin {
+	outpoints := make([]wasabi.Coin, len(coins))
+	for i, coin := range coins {
+		outpoints[i] = wasabi.Coin{TransactionID: coin.TxID, Index: coin.Index}
+	}
+	return outpoints
+}
+
+// Sum returns the sum of the amounts of the coins.
+func Sum(coins []wasabi.ListCoinsResponse) wasabi.Amount {
+	var sum wasabi.Amount
+	for _, coin := range coins {
+		sum += coin.Amount
+	}
+	return sum
+}
+
+// HasChange reports whether the coins selected for the target leave enough to pay a change output. Otherwise the transaction is changeless and the excess is paid as fee.
+func HasChange(selected []wasabi.ListCoinsResponse, target Target) bool {
+	return Sum(selected) > target.Needed(len(selected), true)
+}
+
+// accumulate takes coins in the given order until they cover the target. Coins covering the target without change are enough: the excess too small to pay a change output is paid as fee (see HasChange).
+func accumulate(ordered []wasabi.ListCoinsResponse, target Target) ([]wasabi.ListCoinsResponse, error) {
+	var (
+		selected []wasabi.ListCoinsResponse
+		sum      wasabi.Amount
+	)
+	for _, coin := range ordered {
+		selected = append(selected, coin)
+		sum += coin.Amount
+		if sum >= target.Needed(len(selected), false) {
+			return selected, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: have %d sat, need %d sat", ErrInsufficientFunds, sum, target.Needed(len(ordered), false))
+}
@@ -0,0 +1,53 @@
+package coinselect
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+func TestAccumulateChangeless(t *testing.T) {
+	target := Target{Amount: 10000, Outputs: 1, FeeRate: 1}
+	// One input: 110 vB without change, 141 vB with change.
+	withoutChange, withChange := target.Needed(1, false), target.Needed(1, true)
+	tests := []struct {
+		name   string
+		amount wasabi.Amount
+		change bool
+	}{
+		{name: "exact", amount: withoutChange},
+		{name: "between the targets", amount: (withoutChange + withChange) / 2},
+		{name: "with change", amount: withChange + 1, change: true},
+	}
+	strategies := map[string]Strategy{
+		"LargestFirst":  LargestFirst{},
+		"SmallestFirst": SmallestFirst{},
+		"PrivacyAware":  PrivacyAware{},
+	}
+	for _, tt := range tests {
+		for name, strategy := range strategies {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				coins := []wasabi.ListCoinsResponse{{TxID: "a", Amount: tt.amount}}
+				selected, err := strategy.Select(coins, target)
+				if err != nil {
+					t.Fatalf("Select() = %v", err)
+				}
+				if len(selected) != 1 {
+					t.Fatalf("selected %d coins, want 1", len(selected))
+				}
+				if got := HasChange(selected, target); got != tt.change {
+					t.Fatalf("HasChange() = %v, want %v", got, tt.change)
+				}
+			})
+		}
+	}
+}
+
+func TestAccumulateInsufficient(t *testing.T) {
+	target := Target{Amount: 10000, Outputs: 1, FeeRate: 1}
+	coins := []wasabi.ListCoinsResponse{{TxID: "a", Amount: target.Needed(1, false) - 1}}
+	if _, err := (LargestFirst{}).Select(coins, target); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Select() = %v, want ErrInsufficientFunds", err)
+	}
+}
@@ -0,0 +1,110 @@
+package coinselect
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// InsufficientPrivateFundsError is returned by SendPrivate when the private coins of the wallet cannot pay the payments.
+type InsufficientPrivateFundsError struct {
+	// Needed is the amount (payments and estimated fee).
+	Needed wasabi.Amount
+	// Available is the private balance.
+	Available wasabi.Amount
+	// AnonScoreTarget is the anonymity score from which coins are private.
+	AnonScoreTarget int
+}
+
+// Shortfall returns the missing private amount.
+func (e *InsufficientPrivateFundsError) Shortfall() wasabi.Amount {
+	return e.Needed - e.Available
+}
+
+func (e *InsufficientPrivateFundsError) Error() string {
+	return fmt.Sprintf("insufficient private funds: need %d sat, have %d sat with anonymity score >= %d (short by %d sat)", e.Needed, e.Available, e.AnonScoreTarget, e.Shortfall())
+}
+
+func (e *InsufficientPrivateFundsError) Unwrap() error {
+	return ErrInsufficientFunds
+}
+
+// NonPrivateCoinsError is returned by SendPrivate when the request contains coins below the anonymity score target.
+type NonPrivateCoinsError struct {
+	Coins           []wasabi.Coin
+	AnonScoreTarget int
+}
+
+func (e *NonPrivateCoinsError) Error() string {
+	return fmt.Sprintf("%d coins have an anonymity score below %d", len(e.Coins), e.AnonScoreTarget)
+}
+
+// SendPrivate sends the payments spending only coins whose anonymity score reached the anonymity score target of the wallet, so non-private coins never leak into the transaction.
+// If the request has no coins, they are selected with the PrivacyAware strategy; if it has coins, they must all be private. If the private coins are not enough, an InsufficientPrivateFundsError is returned and nothing is sent.
+func SendPrivate(c wasabi.Client, walletName string, req wasabi.SendRequest) (wasabi.SendResponse, error) {
+	info, err := c.GetWalletInfo(walletName)
+	if err != nil {
+		return wasabi.SendResponse{}, err
+	}
+	minScore := float64(info.AnonScoreTarget)
+	coins, err := c.ListUnspentCoins(walletName)
+	if err != nil {
+		return wasabi.SendResponse{}, err
+	}
+	coins = Spendable(coins)
+
+	if len(req.Coins) > 0 {
+		scores := make(map[wasabi.Coin]float64, len(coins))
+		for _, coin := range coins {
+			scores[wasabi.Coin{TransactionID: coin.TxID, Index: coin.Index}] = coin.AnonymityScore
+		}
+		var nonPrivate []wasabi.Coin
+		for _, coin := range req.Coins {
+			if score, ok := scores[coin]; !ok || score < minScore {
+				nonPrivate = append(nonPrivate, coin)
+			}
+		}
+		if len(nonPrivate) > 0 {
+			return wasabi.SendResponse{}, &NonPrivateCoinsError{Coins: nonPrivate, AnonScoreTarget: info.AnonScoreTarget}
+		}
+		return c.Send(walletName, req)
+	}
+
+	feeRate, err := requestFeeRate(c, req.FeeTarget, req.FeeRate)
+	if err != nil {
+		return wasabi.SendResponse{}, err
+	}
+	target := NewTarget(req.Payments, feeRate)
+	selected, err := PrivacyAware{MinAnonScore: minScore, PrivateOnly: true}.Select(coins, target)
+	if errors.Is(err, ErrInsufficientFunds) {
+		var private []wasabi.ListCoinsResponse
+		for _, coin := range coins {
+			if coin.AnonymityScore >= minScore {
+				private = append(private, coin)
+			}
+		}
+		return wasabi.SendResponse{}, &InsufficientPrivateFundsError{
+			Needed:          target.Needed(max(len(private), 1), false),
+			Available:       Sum(private),
+			AnonScoreTarget: info.AnonScoreTarget,
+		}
+	}
+	if err != nil {
+		return wasabi.SendResponse{}, err
+	}
+	req.Coins = Outpoints(selected)
+	return c.Send(walletName, req)
+}
+
+// requestFeeRate returns the fee rate of a request: the fee rate if set, otherwise the daemon's estimation for the fee target.
+func requestFeeRate(c wasabi.Client, feeTarget wasabi.FeeTarget, feeRate float64) (float64, error) {
+	if feeRate > 0 {
+		return feeRate, nil
+	}
+	rate, err := wasabi.EstimateFeeRate(c, feeTarget)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate the fee rate: %w", err)
+	}
+	return rate, nil
+}
@@ -0,0 +1,146 @@
+package coinselect
+
+import (
+	"sort"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// LargestFirst selects the largest coins first. It minimizes the number of inputs (and the fee).
+type LargestFirst struct{}
+
+func (LargestFirst) Select(coins []wasabi.ListCoinsResponse, target Target) ([]wasabi.ListCoinsResponse, error) {
+	ordered := sorted(coins, func(a, b wasabi.ListCoinsResponse) bool { return a.Amount > b.Amount })
+	return accumulate(ordered, target)
+}
+
+// SmallestFirst selects the smallest coins first. It consolidates small coins at the price of a higher fee.
+type SmallestFirst struct{}
+
+func (SmallestFirst) Select(coins []wasabi.ListCoinsResponse, target Target) ([]wasabi.ListCoinsResponse, error) {
+	ordered := sorted(coins, func(a, b wasabi.ListCoinsResponse) bool { return a.Amount < b.Amount })
+	return accumulate(ordered, target)
+}
+
+// BranchAndBound searches a set of coins which covers the target without a change output (the excess is lower than the cost of a change output). If there is no such set, it falls back to the Fallback strategy (LargestFirst if nil).
+type BranchAndBound struct {
+	// MaxTries limits the number of explored branches. Default is 100000.
+	MaxTries int
+	// Fallback is used when no changeless set is found.
+	Fallback Strategy
+}
+
+func (s BranchAndBound) Select(coins []wasabi.ListCoinsResponse, target Target) ([]wasabi.ListCoinsResponse, error) {
+	maxTries := s.MaxTries
+	if maxTries <= 0 {
+		maxTries = 100000
+	}
+	ordered := sorted(coins, func(a, b wasabi.ListCoinsResponse) bool { return a.Amount > b.Amount })
+	// remaining[i] is the sum of the coins from i to the end, used to prune branches which cannot reach the target.
+	remaining := make([]wasabi.Amount, len(ordered)+1)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + ordered[i].Amount
+	}
+	costOfChange := target.CostOfChange()
+
+	var (
+		best      []int
+		bestWaste wasabi.Amount = -1
+		current   []int
+		tries     int
+	)
+	var search func(i int, sum wasabi.Amount)
+	search = func(i int, sum wasabi.Amount) {
+		if tries >= maxTries {
+			return
+		}
+		tries++
+		needed := target.Needed(len(current), false)
+		if sum >= needed {
+			if waste := sum - needed; waste <= costOfChange && (bestWaste < 0 || waste < bestWaste) {
+				best = append(best[:0], current...)
+				bestWaste = waste
+			}
+			return
+		}
+		if i >= len(ordered) || sum+remaining[i] < target.Needed(len(current)+1, false) {
+			return
+		}
+		current = append(current, i)
+		search(i+1, sum+ordered[i].Amount)
+		current = current[:len(current)-1]
+		search(i+1, sum)
+	}
+	search(0, 0)
+
+	if bestWaste < 0 {
+		fallback := s.Fallback
+		if fallback == nil {
+			fallback = LargestFirst{}
+		}
+		return fallback.Select(coins, target)
+	}
+	selected := make([]wasabi.ListCoinsResponse, len(best))
+	for i, index := range best {
+		selected[i] = ordered[index]
+	}
+	return selected, nil
+}
+
+// PrivacyAware prefers coins with a high anonymity score and avoids mixing coins of different label clusters, which would link them on chain.
+// It first tries to pay from a single cluster (coins with the same label), preferring the cluster with the highest anonymity score, and only then combines clusters.
+type PrivacyAware struct {
+	// MinAnonScore is the anonymity score from which coins are considered private. Private coins are always preferred.
+	MinAnonScore float64
+	// PrivateOnly restricts the selection to private coins.
+	PrivateOnly bool
+}
+
+func (s PrivacyAware) Select(coins []wasabi.ListCoinsResponse, target Target) ([]wasabi.ListCoinsResponse, error) {
+	if s.PrivateOnly {
+		private := make([]wasabi.ListCoinsResponse, 0, len(coins))
+		for _, coin := range coins {
+			if coin.AnonymityScore >= s.MinAnonScore {
+				private = append(private, coin)
+			}
+		}
+		coins = private
+	}
+	byPrivacy := func(a, b wasabi.ListCoinsResponse) bool {
+		aPrivate, bPrivate := a.AnonymityScore >= s.MinAnonScore, b.AnonymityScore >= s.MinAnonScore
+		if aPrivate != bPrivate {
+			return aPrivate
+		}
+		if a.AnonymityScore != b.AnonymityScore {
+			return a.AnonymityScore > b.AnonymityScore
+		}
+		return a.Amount > b.Amount
+	}
+
+	// Group the coins by label cluster, ordered by the privacy of their best coin.
+	clusters := map[string][]wasabi.ListCoinsResponse{}
+	var labels []string
+	for _, coin := range sorted(coins, byPrivacy) {
+		if _, ok := clusters[coin.Label]; !ok {
+			labels = append(labels, coin.Label)
+		}
+		clusters[coin.Label] = append(clusters[coin.Label], coin)
+	}
+	for _, label := range labels {
+		if selected, err := accumulate(clusters[label], target); err == nil {
+			return selected, nil
+		}
+	}
+	var ordered []wasabi.ListCoinsResponse
+	for _, label := range labels {
+		ordered = append(ordered, clusters[label]...)
+	}
+	return accumulate(ordered, target)
+}
+
+func sorted(coins []wasabi.ListCoinsResponse, less func(a, b wasabi.ListCoinsResponse) bool) []wasabi.ListCoinsResponse {
+	ordered := make([]wasabi.ListCoinsResponse, len(coins))
+	copy(ordered, coins)
+	sort.SliceStable(ordered, func(i, j int) bool { return less(ordered[i], ordered[j]) })
+	return ordered
+}
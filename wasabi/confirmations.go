@@ -0,0 +1,62 @@
+package wasabi
+
+import (
+	"context"
+	"time"
+)
+
+// WaitForConfirmationsOptions holds the options of WaitForConfirmations.
+type WaitForConfirmationsOptions struct {
+	// Interval is the interval between the checks. Default is DefaultPollInterval.
+	Interval time.Duration
+	// OnProgress is called after each check with the current number of confirmations of the transaction. Optional.
+	OnProgress func(confirmations int)
+	// Clock provides the timers of the checks. Default is SystemClock.
+	Clock Clock
+}
+
+// WaitForConfirmations waits until the transaction in the history of the wallet has at least the given number of confirmations. It returns when the transaction is confirmed, a call fails or the context is done. A transaction which is not in the history yet counts as unconfirmed.
+func WaitForConfirmations(ctx context.Context, c Client, walletName string, txID TxID, confirmations int, opts WaitForConfirmationsOptions) error {
+	c = c.WithContext(ctx)
+	return poll(ctx, opts.Clock, opts.Interval, func() (bool, error) {
+		current, err := TransactionConfirmations(c, walletName, txID)
+		if err != nil {
+			return false, err
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(current)
+		}
+		return current >= confirmations, nil
+	})
+}
+
+// TransactionConfirmations returns the number of confirmations of the transaction in the history of the wallet. It returns 0 for unconfirmed transactions and transactions which are not in the history.
+func TransactionConfirmations(c Client, walletName string, txID TxID) (int, error) {
+	history, err := c.GetHistory(walletName)
+	if err != nil {
+		return 0, err
+	}
+	height := 0
+	for _, tx := range history {
+		if tx.TxID == txID {
+			height = tx.Height
+			break
+		}
+	}
+	if height <= 0 {
+		return 0, nil
+	}
+	status, err := c.GetStatus()
+	if err != nil {
+		return 0, err
+	}
+	return confirmationsAt(height, status.BestBlockchainHeight), nil
+}
+
+// confirmationsAt returns the number of confirmations of a transaction mined at height when the best block is at bestHeight. Heights above the best height are used by the daemon for unconfirmed transactions.
+func confirmationsAt(height int, bestHeight uint64) int {
+	if height <= 0 || uint64(height) > bestHeight {
+		return 0
+	}
+	return int(bestHeight-uint64(height)) + 1
+}
@@ -0,0 +1,509 @@
+// Package conformance exercises every rpc method of the wasabi client against a running daemon (e.g. on regtest or testnet) and reports which methods and response fields the daemon supports. It helps to check a daemon version before upgrading, and daemon packagers to check their builds.
+//
+//	func TestDaemon(t *testing.T) {
+//		report := conformance.Run(t, client, conformance.Options{WalletName: "test", Password: "pw", Mutating: true})
+//		t.Log(report)
+//	}
+package conformance
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/fixtures"
+)
+
+// DefaultAmount is the amount of the payments made by the suite if no amount is given.
+const DefaultAmount wasabi.Amount = 10000
+
+// Options holds the options of the suite.
+type Options struct {
+	// WalletName is the wallet used by the wallet scoped methods. It must exist; the methods building transactions need confirmed coins.
+	WalletName string
+	// Password is the password of the wallet.
+	Password string
+	// Mutating enables the methods changing the state of the daemon: new addresses, new wallets (with random names), coin exclusion (reverted), payments in coinjoin (canceled) and starting and stopping the coinjoin.
+	Mutating bool
+	// Broadcast enables the methods broadcasting transactions: a payment of Amount to an address of the wallet itself, a transaction built by the suite, and fee bump and cancel transactions of the payment (built, not broadcast). It requires Mutating.
+	Broadcast bool
+	// Amount is the amount of the payments. Default is DefaultAmount.
+	Amount wasabi.Amount
+	// FeeTarget is the confirmation target of the transactions. Default is FeeTargetHour.
+	FeeTarget wasabi.FeeTarget
+	// Version labels the daemon in the report and in RecordDir, e.g. 2.2.1. Default is "live".
+	Version string
+	// RecordDir is the directory where the responses are saved as fixtures (see fixtures.Load), so they can be checked later without the daemon. Secret results are redacted. Optional.
+	RecordDir string
+}
+
+// Status is the outcome of the check of a method.
+type Status string
+
+const (
+	// StatusSupported is the status of methods which succeeded.
+	StatusSupported Status = "supported"
+	// StatusRejected is the status of methods which the daemon knows but rejected because of the state of the wallet, e.g. not enough coins. They count as supported.
+	StatusRejected Status = "rejected"
+	// StatusUnsupported is the status of optional methods which the daemon does not know.
+	StatusUnsupported Status = "unsupported"
+	// StatusFailed is the status of methods which failed, e.g. because the client could not decode the response.
+	StatusFailed Status = "failed"
+	// StatusSkipped is the status of methods which were not called.
+	StatusSkipped Status = "skipped"
+)
+
+// MethodResult is the result of the check of a method.
+type MethodResult struct {
+	Method wasabi.Method
+	Status Status
+	// Err is the error of rejected and failed methods.
+	Err error
+	// Reason explains why a method was skipped.
+	Reason string
+	// UnknownFields are the fields sent by the daemon which the client does not decode, e.g. fields added by a newer daemon.
+	UnknownFields []string
+	// MissingFields are the fields decoded by the client which the daemon did not send, e.g. fields added by a later daemon version.
+	MissingFields []string
+}
+
+// Report is the result of the suite.
+type Report struct {
+	// Version is the label of the daemon.
+	Version string
+	// Results are the results of all registered methods, in the order they were checked.
+	Results []MethodResult
+}
+
+// Result returns the result of the method.
+func (r Report) Result(method wasabi.Method) (MethodResult, bool) {
+	for _, result := range r.Results {
+		if result.Method == method {
+			return result, true
+		}
+	}
+	return MethodResult{}, false
+}
+
+// Supported reports whether the method succeeded or was rejected by the wallet.
+func (r Report) Supported(method wasabi.Method) bool {
+	result, ok := r.Result(method)
+	return ok && (result.Status == StatusSupported || result.Status == StatusRejected)
+}
+
+// String returns the report as a table, one line per method.
+func (r Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "daemon %s\n", r.Version)
+	for _, result := range r.Results {
+		fmt.Fprintf(&sb, "%-26s %-11s", result.Method, result.Status)
+		switch {
+		case result.Err != nil:
+			fmt.Fprintf(&sb, " %v", result.Err)
+		case result.Reason != "":
+			fmt.Fprintf(&sb, " %s", result.Reason)
+		}
+		if len(result.UnknownFields) > 0 {
+			fmt.Fprintf(&sb, " unknown fields: %s", strings.Join(result.UnknownFields, ", "))
+		}
+		if len(result.MissingFields) > 0 {
+			fmt.Fprintf(&sb, " missing fields: %s", strings.Join(result.MissingFields, ", "))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Run checks every registered method against the daemon in a subtest per method and returns the report. Methods the daemon does not support and methods disabled by the options are skipped; methods failing for another reason than a wallet rejection fail the test.
+func Run(t *testing.T, c wasabi.Client, opts Options) Report {
+	t.Helper()
+	if opts.WalletName == "" {
+		t.Fatal("conformance: wallet name must not be empty")
+	}
+	if opts.Amount <= 0 {
+		opts.Amount = DefaultAmount
+	}
+	if opts.FeeTarget <= 0 {
+		opts.FeeTarget = wasabi.FeeTargetHour
+	}
+	if opts.Version == "" {
+		opts.Version = "live"
+	}
+	s := &suite{client: c, opts: opts}
+	report := Report{Version: opts.Version}
+	checked := map[wasabi.Method]bool{}
+	for _, st := range steps {
+		checked[st.method] = true
+		report.Results = append(report.Results, s.check(t, st))
+	}
+	for _, spec := range wasabi.Methods() {
+		if !checked[spec.Name] {
+			report.Results = append(report.Results, s.check(t, step{method: spec.Name, skip: "no conformance step for the method"}))
+		}
+	}
+	return report
+}
+
+// level is the level of the options required by a step.
+type level int
+
+const (
+	readOnly level = iota
+	mutating
+	broadcast
+)
+
+// step checks a method. It returns the raw result of the call, or nil if the result is not checked field by field.
+type step struct {
+	method wasabi.Method
+	level  level
+	skip   string
+	run    func(s *suite) (json.RawMessage, error)
+}
+
+// suite holds the state shared by the steps.
+type suite struct {
+	client wasabi.Client
+	opts   Options
+
+	address   wasabi.Address
+	coins     []wasabi.ListCoinsResponse
+	built     string
+	txID      wasabi.TxID
+	paymentID string
+}
+
+func (s *suite) check(t *testing.T, st step) MethodResult {
+	result := MethodResult{Method: st.method}
+	t.Run(string(st.method), func(t *testing.T) {
+		switch {
+		case st.skip != "":
+			result.Status, result.Reason = StatusSkipped, st.skip
+		case st.level >= mutating && !s.opts.Mutating:
+			result.Status, result.Reason = StatusSkipped, "mutating methods are disabled"
+		case st.level >= broadcast && !s.opts.Broadcast:
+			result.Status, result.Reason = StatusSkipped, "broadcasting is disabled"
+		}
+		if result.Status == StatusSkipped {
+			t.Skip(result.Reason)
+		}
+
+		raw, err := st.run(s)
+		switch category := wasabi.Classify(err); {
+		case err == nil:
+			result.Status = StatusSupported
+		case errors.Is(err, wasabi.ErrUnsupportedMethod):
+			result.Status, result.Err = StatusUnsupported, err
+			t.Skipf("the daemon does not support %s", st.method)
+		case category == wasabi.ErrorCategoryWallet || category == wasabi.ErrorCategoryDaemon:
+			result.Status, result.Err = StatusRejected, err
+			t.Logf("%s was rejected: %v", st.method, err)
+			return
+		default:
+			result.Status, result.Err = StatusFailed, err
+			t.Errorf("%s failed: %v", st.method, err)
+			return
+		}
+		if raw == nil {
+			return
+		}
+		if err := s.checkFields(&result, raw); err != nil {
+			result.Status, result.Err = StatusFailed, err
+			t.Errorf("%s: %v", st.method, err)
+			return
+		}
+		if len(result.UnknownFields) > 0 {
+			t.Logf("fields not decoded by the client: %s", strings.Join(result.UnknownFields, ", "))
+		}
+		if len(result.MissingFields) > 0 {
+			t.Logf("fields not sent by the daemon: %s", strings.Join(result.MissingFields, ", "))
+		}
+	})
+	return result
+}
+
+// checkFields decodes the raw result as the client does, compares its fields with the fields of the result type and records it in RecordDir.
+func (s *suite) checkFields(result *MethodResult, raw json.RawMessage) error {
+	spec, _ := wasabi.LookupMethod(result.Method)
+	if spec.SecretResult {
+		raw, _ = json.Marshal("[REDACTED]")
+	}
+	body, err := json.MarshalIndent(map[string]interface{}{"jsonrpc": "2.0", "result": raw, "id": 1}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fixture := fixtures.Fixture{Version: s.opts.Version, Method: result.Method, Body: body}
+	if _, err := fixtures.Decode(fixture); err != nil {
+		return fmt.Errorf("client cannot decode the response: %w", err)
+	}
+	if result.UnknownFields, err = fixtures.UnknownFields(fixture); err != nil {
+		return err
+	}
+	if spec.Result != nil {
+		result.MissingFields = missingFields(raw, spec.Result)
+	}
+	if s.opts.RecordDir == "" {
+		return nil
+	}
+	dir := filepath.Join(s.opts.RecordDir, s.opts.Version)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, string(result.Method)+".json"), append(body, '\n'), 0o644)
+}
+
+// missingFields returns the JSON names of the fields of t which no object of raw (the object itself or the items of a list) has.
+func missingFields(raw json.RawMessage, t reflect.Type) []string {
+	var objects []map[string]json.RawMessage
+	switch t.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(raw, &object) != nil {
+			return nil
+		}
+		objects = append(objects, object)
+	case reflect.Slice:
+		if t = t.Elem(); t.Kind() != reflect.Struct || json.Unmarshal(raw, &objects) != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+	var missing []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		found := false
+		for _, object := range objects {
+			for key := range object {
+				if strings.EqualFold(key, name) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// rawCall calls the method with positional params and returns its raw result.
+func (s *suite) rawCall(walletName string, method wasabi.Method, params ...interface{}) (json.RawMessage, error) {
+	var raw json.RawMessage
+	var p interface{}
+	if len(params) > 0 {
+		p = params
+	}
+	if err := s.client.RawCall(walletName, string(method), p, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = json.RawMessage("null")
+	}
+	return raw, nil
+}
+
+// walletCall calls a method of the wallet with positional params and returns its raw result.
+func (s *suite) walletCall(method wasabi.Method, params ...interface{}) (json.RawMessage, error) {
+	return s.rawCall(s.opts.WalletName, method, params...)
+}
+
+// receiveAddress returns an address of the wallet: a new one if mutating methods are enabled, otherwise one of its keys.
+func (s *suite) receiveAddress() (wasabi.Address, error) {
+	if s.address != "" {
+		return s.address, nil
+	}
+	if s.opts.Mutating {
+		address, err := s.client.GetNewAddress(s.opts.WalletName, "conformance")
+		if err != nil {
+			return "", err
+		}
+		s.address = address.Address
+		return s.address, nil
+	}
+	keys, err := s.client.ListKeys(s.opts.WalletName)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range keys {
+		if !key.Internal && key.Address != "" {
+			s.address = key.Address
+			return s.address, nil
+		}
+	}
+	return "", fmt.Errorf("wallet %s has no receive key", s.opts.WalletName)
+}
+
+// payment returns a payment of Amount to an address of the wallet.
+func (s *suite) payment() ([]wasabi.Payment, error) {
+	address, err := s.receiveAddress()
+	if err != nil {
+		return nil, err
+	}
+	return []wasabi.Payment{{SendTo: address, Amount: s.opts.Amount, Label: "conformance"}}, nil
+}
+
+// randomName returns a wallet name which does not exist yet.
+func randomName() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return "conformance-" + hex.EncodeToString(b)
+}
+
+// recoveryMnemonic is the BIP39 test mnemonic used to recover a wallet.
+const recoveryMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// steps are the checks of the methods, in the order they run. Later steps use the state of earlier ones, e.g. the coins listed by listunspentcoins.
+var steps = []step{
+	{method: wasabi.MethodGetStatus, run: func(s *suite) (json.RawMessage, error) {
+		return s.rawCall("", wasabi.MethodGetStatus)
+	}},
+	{method: wasabi.MethodListWallets, run: func(s *suite) (json.RawMessage, error) {
+		return s.rawCall("", wasabi.MethodListWallets)
+	}},
+	{method: wasabi.MethodGetFeeRates, run: func(s *suite) (json.RawMessage, error) {
+		return s.rawCall("", wasabi.MethodGetFeeRates)
+	}},
+	{method: wasabi.MethodLoadWallet, run: func(s *suite) (json.RawMessage, error) {
+		return s.rawCall("", wasabi.MethodLoadWallet, s.opts.WalletName)
+	}},
+	{method: wasabi.MethodGetWalletInfo, run: func(s *suite) (json.RawMessage, error) {
+		return s.walletCall(wasabi.MethodGetWalletInfo)
+	}},
+	{method: wasabi.MethodListCoins, run: func(s *suite) (json.RawMessage, error) {
+		return s.walletCall(wasabi.MethodListCoins)
+	}},
+	{method: wasabi.MethodListUnspentCoins, run: func(s *suite) (json.RawMessage, error) {
+		raw, err := s.walletCall(wasabi.MethodListUnspentCoins)
+		if err == nil {
+			json.Unmarshal(raw, &s.coins)
+		}
+		return raw, err
+	}},
+	{method: wasabi.MethodGetHistory, run: func(s *suite) (json.RawMessage, error) {
+		return s.walletCall(wasabi.MethodGetHistory)
+	}},
+	{method: wasabi.MethodListKeys, run: func(s *suite) (json.RawMessage, error) {
+		return s.walletCall(wasabi.MethodListKeys)
+	}},
+	{method: wasabi.MethodListPaymentsInCoinJoin, run: func(s *suite) (json.RawMessage, error) {
+		return s.walletCall(wasabi.MethodListPaymentsInCoinJoin)
+	}},
+	{method: wasabi.MethodGetNewAddress, level: mutating, run: func(s *suite) (json.RawMessage, error) {
+		raw, err := s.walletCall(wasabi.MethodGetNewAddress, "conformance")
+		if err == nil {
+			var address wasabi.GetNewAddressResponse
+			if json.Unmarshal(raw, &address) == nil {
+				s.address = address.Address
+			}
+		}
+		return raw, err
+	}},
+	{method: wasabi.MethodBuild, run: func(s *suite) (json.RawMessage, error) {
+		payments, err := s.payment()
+		if err != nil {
+			return nil, err
+		}
+		s.built, err = s.client.Build(s.opts.WalletName, wasabi.BuildRequest{Payments: payments, FeeTarget: s.opts.FeeTarget, Password: s.opts.Password})
+		return nil, err
+	}},
+	{method: wasabi.MethodBroadcast, level: broadcast, run: func(s *suite) (json.RawMessage, error) {
+		if s.built == "" {
+			return nil, fmt.Errorf("no built transaction to broadcast")
+		}
+		return s.walletCall(wasabi.MethodBroadcast, s.built)
+	}},
+	{method: wasabi.MethodBuildUnsafeTransaction, run: func(s *suite) (json.RawMessage, error) {
+		payments, err := s.payment()
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.client.BuildUnsafeTransaction(s.opts.WalletName, wasabi.BuildUnsafeRequest{Payments: payments, FeeTarget: s.opts.FeeTarget, Password: s.opts.Password})
+		return nil, err
+	}},
+	{method: wasabi.MethodCreateWallet, level: mutating, run: func(s *suite) (json.RawMessage, error) {
+		return s.rawCall("", wasabi.MethodCreateWallet, randomName(), s.opts.Password)
+	}},
+	{method: wasabi.MethodRecoverWallet, level: mutating, run: func(s *suite) (json.RawMessage, error) {
+		return s.rawCall("", wasabi.MethodRecoverWallet, randomName(), recoveryMnemonic, s.opts.Password)
+	}},
+	{method: wasabi.MethodExcludeFromCoinJoin, level: mutating, run: func(s *suite) (json.RawMessage, error) {
+		if len(s.coins) == 0 {
+			return nil, fmt.Errorf("wallet %s has no unspent coins", s.opts.WalletName)
+		}
+		coin := s.coins[0]
+		raw, err := s.walletCall(wasabi.MethodExcludeFromCoinJoin, coin.TxID, coin.Index, !coin.ExcludedFromCoinJoin)
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.walletCall(wasabi.MethodExcludeFromCoinJoin, coin.TxID, coin.Index, coin.ExcludedFromCoinJoin)
+		return raw, err
+	}},
+	{method: wasabi.MethodPayInCoinJoin, level: mutating, run: func(s *suite) (json.RawMessage, error) {
+		address, err := s.receiveAddress()
+		if err != nil {
+			return nil, err
+		}
+		raw, err := s.walletCall(wasabi.MethodPayInCoinJoin, address, s.opts.Amount, s.opts.Password)
+		if err == nil {
+			json.Unmarshal(raw, &s.paymentID)
+		}
+		return raw, err
+	}},
+	{method: wasabi.MethodCancelPaymentInCoinJoin, level: mutating, run: func(s *suite) (json.RawMessage, error) {
+		if s.paymentID == "" {
+			return nil, fmt.Errorf("no payment in coinjoin to cancel")
+		}
+		return s.walletCall(wasabi.MethodCancelPaymentInCoinJoin, s.paymentID)
+	}},
+	{method: wasabi.MethodStartCoinJoin, level: mutating, run: func(s *suite) (json.RawMessage, error) {
+		return s.walletCall(wasabi.MethodStartCoinJoin, s.opts.Password, false, false)
+	}},
+	{method: wasabi.MethodStopCoinJoin, level: mutating, run: func(s *suite) (json.RawMessage, error) {
+		return s.walletCall(wasabi.MethodStopCoinJoin)
+	}},
+	{method: wasabi.MethodSend, level: broadcast, run: func(s *suite) (json.RawMessage, error) {
+		payments, err := s.payment()
+		if err != nil {
+			return nil, err
+		}
+		response, err := s.client.Send(s.opts.WalletName, wasabi.SendRequest{Payments: payments, FeeTarget: s.opts.FeeTarget, Password: s.opts.Password})
+		s.txID = response.TransactionID
+		return nil, err
+	}},
+	{method: wasabi.MethodSpeedUpTransaction, level: broadcast, run: func(s *suite) (json.RawMessage, error) {
+		if s.txID == "" {
+			return nil, fmt.Errorf("no unconfirmed transaction to speed up")
+		}
+		return s.walletCall(wasabi.MethodSpeedUpTransaction, s.txID, s.opts.Password)
+	}},
+	{method: wasabi.MethodCancelTransaction, level: broadcast, run: func(s *suite) (json.RawMessage, error) {
+		if s.txID == "" {
+			return nil, fmt.Errorf("no unconfirmed transaction to cancel")
+		}
+		return s.walletCall(wasabi.MethodCancelTransaction, s.txID, s.opts.Password)
+	}},
+	{method: wasabi.MethodStartCoinJoinSweep, skip: "sweeping moves all coins of the wallet"},
+	{method: wasabi.MethodStop, skip: "stopping the daemon ends the suite"},
+	{method: wasabi.MethodSelectWallet, skip: "only used by the select wallet routing"},
+}
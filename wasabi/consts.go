@@ -30,6 +30,9 @@ const (
 	MethodCancelPaymentInCoinJoin Method = "cancelpaymentincoinjoin"
 	MethodCancelTransaction       Method = "canceltransaction"
 	MethodSpeedUpTransaction      Method = "speeduptransaction"
+
+	// MethodSelectWallet selects the wallet used by subsequent calls. It is only used by older daemons, see RoutingSelectWallet.
+	MethodSelectWallet Method = "selectwallet"
 )
 
 // String returns the string representation of the method.
@@ -37,12 +40,29 @@ func (m Method) String() string {
 	return string(m)
 }
 
+// IsMutating reports whether the method changes the state of a wallet or the daemon (moves funds, creates wallets or keys, controls coinjoin, stops the daemon).
+func (m Method) IsMutating() bool {
+	spec, ok := LookupMethod(m)
+	return ok && spec.Mutating
+}
+
+// RoutingMode is the strategy used to route calls to a wallet.
+type RoutingMode int
+
+const (
+	// RoutingURLPath sends wallet calls to the url path of the wallet (http://host:port/walletName). It is used by current daemons.
+	RoutingURLPath RoutingMode = iota
+	// RoutingSelectWallet sends all calls to the root path and selects the wallet with the selectwallet method beforehand. It is used by older daemons.
+	RoutingSelectWallet
+)
+
 // BitcoinNetwork is a bitcoin network.
 type BitcoinNetwork string
 
 const (
 	BitcoinNetworkMainnet BitcoinNetwork = "Main"
 	BitcoinNetworkTestnet BitcoinNetwork = "TestNet"
+	BitcoinNetworkRegtest BitcoinNetwork = "RegTest"
 )
 
 // CoinJoinStatus is a coinjoin status.
@@ -94,6 +114,18 @@ const (
 	WalletStateStopped        WalletState = "Stopped"
 )
 
+// KeyState is a state of a generated key.
+type KeyState int
+
+const (
+	// KeyStateClean is the state of a key which was not used yet.
+	KeyStateClean KeyState = 0
+	// KeyStateLocked is the state of a key which is reserved (e.g. shown to the user as receive address) but has not received coins yet.
+	KeyStateLocked KeyState = 1
+	// KeyStateUsed is the state of a key which received coins.
+	KeyStateUsed KeyState = 2
+)
+
 // WalletError is a wallet error.
 type WalletError string
 
@@ -118,6 +150,28 @@ const (
 	ErrorCannotGetFeeEstimations          WalletError = "Cannot get fee estimations."
 )
 
+// walletErrors is the list of all known wallet errors.
+var walletErrors = []WalletError{
+	ErrorWalletIsNotFullyLoadedYet,
+	ErrorIndexFileInconsistency,
+	ErrorNegativeIssuerBalance,
+	ErrorNegativeBalance,
+	ErrorIncorrectPassword,
+	ErrorPaymentNotPending,
+	ErrorPaymentNotFound,
+	ErrorNotEnoughCoins,
+	ErrorNoSecretInTheWatchOnlyMode,
+	ErrorOutputWalletNameInvalid,
+	ErrorRPCMethodSpecial,
+	ErrorCoinJoinResultTypeNotHandled,
+	ErrorBlameRoundsNotSuccessful,
+	ErrorNotPossibleToSubtractTheFee,
+	ErrorOriginalPSBTShouldNotBeFinalized,
+	ErrorTransactionNotCancellable,
+	ErrorTransactionNotSpeedupable,
+	ErrorCannotGetFeeEstimations,
+}
+
 func (e WalletError) Error() string {
 	return string(e)
 }
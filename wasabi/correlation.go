@@ -0,0 +1,49 @@
+package wasabi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultCorrelationIDHeader is the default http header carrying the correlation id of a request.
+const DefaultCorrelationIDHeader = "X-Request-ID"
+
+type correlationIDContextKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation id to send with the calls made with it (see Client.WithContext). Calls without a correlation id get a generated one.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDContextKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation id set with WithCorrelationID.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDContextKey{}).(string)
+	return id
+}
+
+// newCorrelationID generates a random correlation id.
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// CallError wraps the errors of rpc calls with the called method and the correlation id of the request, so a failing call can be matched to proxy and daemon logs.
+type CallError struct {
+	Method        Method
+	WalletName    string
+	CorrelationID string
+	Err           error
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("%s (request id %s): %v", e.Method, e.CorrelationID, e.Err)
+}
+
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,229 @@
+// Package costbasis computes the cost basis and the realized gains of the disposals of a wallet from its history, using FIFO, LIFO or HIFO lot matching.
+package costbasis
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Method selects the lots matched with a disposal.
+type Method int
+
+const (
+	// FIFO matches the oldest lots first.
+	FIFO Method = iota
+	// LIFO matches the newest lots first.
+	LIFO
+	// HIFO matches the lots with the highest price first.
+	HIFO
+)
+
+// String returns the name of the method.
+func (m Method) String() string {
+	switch m {
+	case FIFO:
+		return "FIFO"
+	case LIFO:
+		return "LIFO"
+	case HIFO:
+		return "HIFO"
+	default:
+		return "unknown"
+	}
+}
+
+// PriceSource provides historical prices of bitcoin.
+type PriceSource interface {
+	// PriceAt returns the price of one bitcoin in the currency at the time.
+	PriceAt(ctx context.Context, currency string, t time.Time) (wasabi.Price, error)
+}
+
+// PriceSourceFunc adapts a function to a PriceSource.
+type PriceSourceFunc func(ctx context.Context, currency string, t time.Time) (wasabi.Price, error)
+
+// PriceAt implements PriceSource.
+func (f PriceSourceFunc) PriceAt(ctx context.Context, currency string, t time.Time) (wasabi.Price, error) {
+	return f(ctx, currency, t)
+}
+
+// Lot is an acquisition of bitcoin.
+type Lot struct {
+	TxID     wasabi.TxID
+	Acquired time.Time
+	// Amount is the amount of the lot not disposed of yet.
+	Amount wasabi.Amount
+	// Cost is the cost of the remaining amount in cents.
+	Cost int64
+	// Price is the price at the acquisition.
+	Price wasabi.Price
+}
+
+// LotUsage is the part of a lot matched with a disposal.
+type LotUsage struct {
+	TxID     wasabi.TxID
+	Acquired time.Time
+	Amount   wasabi.Amount
+	// Cost is the cost of the amount in cents.
+	Cost int64
+}
+
+// Disposal is an outgoing transaction with its cost basis and realized gain. Amounts are in cents of the report currency.
+type Disposal struct {
+	TxID   wasabi.TxID
+	Date   time.Time
+	Amount wasabi.Amount
+	// CoinJoin reports that the transaction is a coinjoin, whose outflow is the coinjoin fee.
+	CoinJoin bool
+	Proceeds int64
+	Cost     int64
+	Gain     int64
+	Lots     []LotUsage
+	// Unmatched is the amount which could not be matched with a lot (incomplete history). It has no cost.
+	Unmatched wasabi.Amount
+}
+
+// Report is the result of Compute.
+type Report struct {
+	Currency  string
+	Method    Method
+	Disposals []Disposal
+	// OpenLots are the lots not fully disposed of.
+	OpenLots []Lot
+	// Proceeds, Cost and Gain are the totals of the disposals.
+	Proceeds wasabi.FiatAmount
+	Cost     wasabi.FiatAmount
+	Gain     wasabi.FiatAmount
+}
+
+// Compute matches the outgoing transactions of the history with the incoming ones. Incoming transactions are acquisitions at the price of their time and outgoing transactions are disposals at the price of their time. The history only has the net amount of each transaction, so mining and coinjoin fees are part of the disposals.
+func Compute(ctx context.Context, history []wasabi.Transaction, prices PriceSource, currency string, method Method) (Report, error) {
+	txs := append([]wasabi.Transaction(nil), history...)
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].DateTime.Before(txs[j].DateTime) })
+
+	report := Report{Currency: currency, Method: method}
+	var lots []*Lot
+	for _, tx := range txs {
+		if tx.Amount == 0 {
+			continue
+		}
+		price, err := prices.PriceAt(ctx, currency, tx.DateTime)
+		if err != nil {
+			return Report{}, fmt.Errorf("failed to get the price of %s: %w", tx.TxID, err)
+		}
+		if price.Currency != "" && price.Currency != currency {
+			return Report{}, fmt.Errorf("price source returned %s instead of %s", price.Currency, currency)
+		}
+		if tx.Amount > 0 {
+			lots = append(lots, &Lot{TxID: tx.TxID, Acquired: tx.DateTime, Amount: tx.Amount, Cost: price.Value(tx.Amount).Cents, Price: price})
+			continue
+		}
+
+		disposal := Disposal{TxID: tx.TxID, Date: tx.DateTime, Amount: -tx.Amount, CoinJoin: tx.IsLikelyCoinJoin, Proceeds: price.Value(-tx.Amount).Cents}
+		remaining := disposal.Amount
+		for _, lot := range order(lots, method) {
+			if remaining == 0 {
+				break
+			}
+			take := min(remaining, lot.Amount)
+			cost := lot.Cost
+			if take < lot.Amount {
+				cost = proportionalCost(lot.Cost, take, lot.Amount)
+			}
+			disposal.Lots = append(disposal.Lots, LotUsage{TxID: lot.TxID, Acquired: lot.Acquired, Amount: take, Cost: cost})
+			disposal.Cost += cost
+			lot.Amount -= take
+			lot.Cost -= cost
+			remaining -= take
+		}
+		disposal.Unmatched = remaining
+		disposal.Gain = disposal.Proceeds - disposal.Cost
+		report.Disposals = append(report.Disposals, disposal)
+		report.Proceeds.Cents += disposal.Proceeds
+		report.Cost.Cents += disposal.Cost
+		lots = open(lots)
+	}
+	for _, lot := range lots {
+		report.OpenLots = append(report.OpenLots, *lot)
+	}
+	report.Gain.Cents = report.Proceeds.Cents - report.Cost.Cents
+	report.Proceeds.Currency, report.Cost.Currency, report.Gain.Currency = currency, currency, currency
+	return report, nil
+}
+
+// ComputeWallet computes the report of the history of the wallet.
+func ComputeWallet(ctx context.Context, c wasabi.Client, walletName string, prices PriceSource, currency string, method Method) (Report, error) {
+	history, err := c.WithContext(ctx).GetHistory(walletName)
+	if err != nil {
+		return Report{}, err
+	}
+	return Compute(ctx, history, prices, currency, method)
+}
+
+// order returns the lots in matching order. The lots are kept in acquisition order.
+func order(lots []*Lot, method Method) []*Lot {
+	ordered := append([]*Lot(nil), lots...)
+	switch method {
+	case LIFO:
+		for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		}
+	case HIFO:
+		sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Price.CentsPerBTC > ordered[j].Price.CentsPerBTC })
+	}
+	return ordered
+}
+
+// open returns the lots which are not fully disposed of.
+func open(lots []*Lot) []*Lot {
+	kept := lots[:0]
+	for _, lot := range lots {
+		if lot.Amount > 0 {
+			kept = append(kept, lot)
+		}
+	}
+	return kept
+}
+
+// proportionalCost returns cost * part / whole rounded half up to the cent.
+func proportionalCost(cost int64, part, whole wasabi.Amount) int64 {
+	product := new(big.Int).Mul(big.NewInt(cost), big.NewInt(int64(part)))
+	product.Add(product, big.NewInt(int64(whole)/2))
+	return product.Quo(product, big.NewInt(int64(whole))).Int64()
+}
+
+// WriteCSV writes one line per disposal: date, txid, amount, proceeds, cost, gain, unmatched amount and coinjoin flag.
+func (r Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	cents := func(c int64) string { return wasabi.FiatAmount{Cents: c}.Decimal() }
+	err := writer.Write([]string{"date", "txid", "amount_btc", "proceeds", "cost_basis", "gain", "unmatched_btc", "coinjoin", "currency", "method"})
+	if err != nil {
+		return err
+	}
+	for _, d := range r.Disposals {
+		err := writer.Write([]string{
+			d.Date.Format(time.RFC3339),
+			d.TxID.String(),
+			d.Amount.FormatBTC(),
+			cents(d.Proceeds),
+			cents(d.Cost),
+			cents(d.Gain),
+			d.Unmatched.FormatBTC(),
+			strconv.FormatBool(d.CoinJoin),
+			r.Currency,
+			r.Method.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
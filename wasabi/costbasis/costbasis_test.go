@@ -0,0 +1,134 @@
+package costbasis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+func day(n int) time.Time {
+	return time.Date(2024, 1, n, 12, 0, 0, 0, time.UTC)
+}
+
+// testPrices is 10000 USD/BTC on day 1, 30000 on day 2, 20000 on day 3 and 40000 on day 4.
+var testPrices = PriceSourceFunc(func(ctx context.Context, currency string, t time.Time) (wasabi.Price, error) {
+	perBTC := map[int]float64{1: 10000, 2: 30000, 3: 20000, 4: 40000}[t.Day()]
+	return wasabi.PriceFromFloat(currency, perBTC), nil
+})
+
+// testHistory acquires 1 BTC on each of days 1 to 3 and disposes of 1.5 BTC on day 4, listed newest first like the daemon does.
+var testHistory = []wasabi.Transaction{
+	{TxID: "d", DateTime: day(4), Amount: -150_000_000},
+	{TxID: "c", DateTime: day(3), Amount: 100_000_000},
+	{TxID: "b", DateTime: day(2), Amount: 100_000_000},
+	{TxID: "a", DateTime: day(1), Amount: 100_000_000},
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		method Method
+		// cost is the cost basis of the disposal in cents.
+		cost int64
+		lots []wasabi.TxID
+		open map[wasabi.TxID]wasabi.Amount
+	}{
+		{method: FIFO, cost: 2_500_000, lots: []wasabi.TxID{"a", "b"}, open: map[wasabi.TxID]wasabi.Amount{"b": 50_000_000, "c": 100_000_000}},
+		{method: LIFO, cost: 3_500_000, lots: []wasabi.TxID{"c", "b"}, open: map[wasabi.TxID]wasabi.Amount{"a": 100_000_000, "b": 50_000_000}},
+		{method: HIFO, cost: 4_000_000, lots: []wasabi.TxID{"b", "c"}, open: map[wasabi.TxID]wasabi.Amount{"a": 100_000_000, "c": 50_000_000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method.String(), func(t *testing.T) {
+			report, err := Compute(context.Background(), testHistory, testPrices, "USD", tt.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Disposals) != 1 {
+				t.Fatalf("%d disposals, want 1", len(report.Disposals))
+			}
+			disposal := report.Disposals[0]
+			if disposal.Proceeds != 6_000_000 || disposal.Cost != tt.cost || disposal.Gain != 6_000_000-tt.cost || disposal.Unmatched != 0 {
+				t.Fatalf("disposal = %+v, want proceeds 6000000, cost %d", disposal, tt.cost)
+			}
+			if len(disposal.Lots) != len(tt.lots) {
+				t.Fatalf("lots = %+v, want %v", disposal.Lots, tt.lots)
+			}
+			for i, lot := range disposal.Lots {
+				if lot.TxID != tt.lots[i] {
+					t.Fatalf("lots = %+v, want %v", disposal.Lots, tt.lots)
+				}
+			}
+			if len(report.OpenLots) != len(tt.open) {
+				t.Fatalf("open lots = %+v, want %v", report.OpenLots, tt.open)
+			}
+			for _, lot := range report.OpenLots {
+				if lot.Amount != tt.open[lot.TxID] {
+					t.Fatalf("open lots = %+v, want %v", report.OpenLots, tt.open)
+				}
+			}
+			if report.Gain != (wasabi.FiatAmount{Currency: "USD", Cents: 6_000_000 - tt.cost}) {
+				t.Fatalf("Gain = %v", report.Gain)
+			}
+		})
+	}
+}
+
+func TestComputeUnmatched(t *testing.T) {
+	history := []wasabi.Transaction{
+		{TxID: "a", DateTime: day(1), Amount: 100_000_000},
+		{TxID: "b", DateTime: day(4), Amount: -150_000_000},
+	}
+	report, err := Compute(context.Background(), history, testPrices, "USD", FIFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disposal := report.Disposals[0]
+	if disposal.Unmatched != 50_000_000 || disposal.Cost != 1_000_000 || len(report.OpenLots) != 0 {
+		t.Fatalf("disposal = %+v, open lots = %+v", disposal, report.OpenLots)
+	}
+}
+
+func TestComputeCurrencyMismatch(t *testing.T) {
+	prices := PriceSourceFunc(func(ctx context.Context, currency string, t time.Time) (wasabi.Price, error) {
+		return wasabi.PriceFromFloat("EUR", 10000), nil
+	})
+	if _, err := Compute(context.Background(), testHistory, prices, "USD", FIFO); err == nil {
+		t.Fatal("Compute() with prices in another currency = nil error")
+	}
+}
+
+func TestProportionalCost(t *testing.T) {
+	tests := []struct {
+		cost        int64
+		part, whole wasabi.Amount
+		want        int64
+	}{
+		{cost: 100, part: 1, whole: 2, want: 50},
+		{cost: 1, part: 1, whole: 2, want: 1},
+		{cost: 1, part: 1, whole: 3, want: 0},
+		{cost: 3_000_000, part: 50_000_000, whole: 100_000_000, want: 1_500_000},
+	}
+	for _, tt := range tests {
+		if got := proportionalCost(tt.cost, tt.part, tt.whole); got != tt.want {
+			t.Errorf("proportionalCost(%d, %d, %d) = %d, want %d", tt.cost, tt.part, tt.whole, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report, err := Compute(context.Background(), testHistory, testPrices, "USD", FIFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := report.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "date,txid,amount_btc,proceeds,cost_basis,gain,unmatched_btc,coinjoin,currency,method\n" +
+		"2024-01-04T12:00:00Z,d,1.50000000,60000.00,25000.00,35000.00,0.00000000,false,USD,FIFO\n"
+	if b.String() != want {
+		t.Fatalf("WriteCSV() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,146 @@
+package wasabi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// secretHeaders is the list of headers whose values are masked in debug dumps.
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// dumpRequest writes the request with its redacted JSON-RPC payload to w.
+func dumpRequest(w io.Writer, req *http.Request, method Method, params interface{}, payload []byte) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--> %s %s\n", req.Method, req.URL)
+	dumpHeaders(&buf, req.Header)
+	buf.Write(redactRequestPayload(method, params, payload))
+	buf.WriteString("\n\n")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// dumpResponse writes the response with its redacted body to w. The body of the response is buffered and replaced, so it can still be read by the caller. Streamed responses are dumped by dumpStreamedResponse instead.
+func dumpResponse(w io.Writer, resp *http.Response, method Method, duration time.Duration) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<-- %s (%s)\n", resp.Status, duration)
+	dumpHeaders(&buf, resp.Header)
+	buf.Write(redactResponseBody(method, body))
+	buf.WriteString("\n\n")
+	_, _ = w.Write(buf.Bytes())
+	return nil
+}
+
+// maxStreamDumpSize is the number of bytes of a streamed response body kept for the debug dump.
+const maxStreamDumpSize = 4096
+
+// dumpStreamedResponse dumps a response streamed by CallRaw without buffering its body: the body is replaced by a reader keeping the first maxStreamDumpSize bytes read by the caller. The returned function writes the dump once the body was read.
+func dumpStreamedResponse(w io.Writer, resp *http.Response, method Method, duration time.Duration) func() {
+	prefix := &prefixWriter{limit: maxStreamDumpSize}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(resp.Body, prefix), resp.Body}
+	return func() {
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "<-- %s (%s)\n", resp.Status, duration)
+		dumpHeaders(&buf, resp.Header)
+		if isSecretResult(method) {
+			buf.WriteString(redacted)
+		} else {
+			buf.Write(prefix.buf.Bytes())
+			if prefix.skipped > 0 {
+				fmt.Fprintf(&buf, "... (%d more bytes)", prefix.skipped)
+			}
+		}
+		buf.WriteString("\n\n")
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+// prefixWriter keeps the first limit bytes written to it and counts the others.
+type prefixWriter struct {
+	buf     bytes.Buffer
+	limit   int
+	skipped int64
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	n := len(b)
+	if room := p.limit - p.buf.Len(); room > 0 {
+		if room > len(b) {
+			room = len(b)
+		}
+		p.buf.Write(b[:room])
+		b = b[room:]
+	}
+	p.skipped += int64(len(b))
+	return n, nil
+}
+
+func dumpHeaders(buf *bytes.Buffer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range header[k] {
+			if secretHeaders[http.CanonicalHeaderKey(k)] {
+				v = redacted
+			}
+			fmt.Fprintf(buf, "%s: %s\n", k, v)
+		}
+	}
+}
+
+// redactRequestPayload replaces the params of the encoded request with their redacted version.
+func redactRequestPayload(method Method, params interface{}, payload []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload
+	}
+	redactedParams, err := json.Marshal(redactParams(method, params))
+	if err != nil {
+		return payload
+	}
+	fields["params"] = redactedParams
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+	return out
+}
+
+// redactResponseBody masks the result of methods which return secrets.
+func redactResponseBody(method Method, body []byte) []byte {
+	if !isSecretResult(method) {
+		return body
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return []byte(redacted)
+	}
+	if _, ok := fields["result"]; ok {
+		fields["result"], _ = json.Marshal(redacted)
+	}
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return []byte(redacted)
+	}
+	return out
+}
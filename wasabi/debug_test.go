@@ -0,0 +1,39 @@
+package wasabi
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCallRawDebugDumpIsBounded(t *testing.T) {
+	result := `"` + strings.Repeat("a", 3*maxStreamDumpSize) + `"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	defer srv.Close()
+	host, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	var dump bytes.Buffer
+	c, err := NewClient(Config{Host: host, Port: port, DebugWriter: &dump})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := c.CallRaw("", "getstatus", nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != result {
+		t.Fatalf("CallRaw wrote %d bytes, want the %d bytes of the result", out.Len(), len(result))
+	}
+	if dump.Len() > 2*maxStreamDumpSize {
+		t.Fatalf("dump is %d bytes, want at most a prefix of the body", dump.Len())
+	}
+	if !strings.Contains(dump.String(), "more bytes)") {
+		t.Fatalf("dump does not tell the body was truncated:\n%s", dump.String())
+	}
+}
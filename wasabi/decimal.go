@@ -0,0 +1,193 @@
+package wasabi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalScale is the highest number of decimals of a Decimal.
+const maxDecimalScale = 18
+
+// Decimal is a fixed-point decimal number decoded exactly from the JSON of the daemon, e.g. the exchange rate reported by GetStatus. It keeps the text it was decoded from. The zero value is 0.
+type Decimal struct {
+	raw string
+	// unscaled is the number multiplied by 10^scale.
+	unscaled int64
+	scale    int
+}
+
+// ParseDecimal parses a decimal number like 65432.1, -0.5 or 6.54321e4. It must fit 18 significant digits.
+func ParseDecimal(s string) (Decimal, error) {
+	trimmed := strings.TrimSpace(s)
+	mantissa, exponent := trimmed, 0
+	if i := strings.IndexAny(trimmed, "eE"); i >= 0 {
+		e, err := strconv.Atoi(trimmed[i+1:])
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		mantissa, exponent = trimmed[:i], e
+	}
+	negative := strings.HasPrefix(mantissa, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(mantissa, "-"), ".")
+	digits := whole + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	scale := len(fraction) - exponent
+	// Trailing zeros of the fraction do not change the value.
+	for scale > 0 && len(digits) > 1 && digits[len(digits)-1] == '0' {
+		digits, scale = digits[:len(digits)-1], scale-1
+	}
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return Decimal{raw: trimmed}, nil
+	}
+	for ; scale < 0; scale++ {
+		digits += "0"
+	}
+	if scale > maxDecimalScale || len(digits) > maxDecimalScale {
+		return Decimal{}, fmt.Errorf("invalid decimal %q: more than %d digits", s, maxDecimalScale)
+	}
+	unscaled, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if negative {
+		unscaled = -unscaled
+	}
+	return Decimal{raw: trimmed, unscaled: unscaled, scale: scale}, nil
+}
+
+// DecimalFromFloat returns the shortest decimal which converts back to the float.
+func DecimalFromFloat(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		// The float has more digits than a Decimal holds.
+		d, _ = ParseDecimal(strconv.FormatFloat(f, 'g', maxDecimalScale, 64))
+	}
+	return d
+}
+
+// Raw returns the text the decimal was parsed or decoded from, or its normalized form if it was computed.
+func (d Decimal) Raw() string {
+	if d.raw == "" {
+		return d.String()
+	}
+	return d.raw
+}
+
+// String returns the decimal without exponent and without trailing zeros, e.g. "65432.1".
+func (d Decimal) String() string {
+	s := strconv.FormatInt(d.unscaled, 10)
+	if d.scale == 0 {
+		return s
+	}
+	sign := ""
+	if d.unscaled < 0 {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= d.scale {
+		s = strings.Repeat("0", d.scale-len(s)+1) + s
+	}
+	return sign + s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+}
+
+// IsZero reports whether the decimal is 0.
+func (d Decimal) IsZero() bool {
+	return d.unscaled == 0
+}
+
+// Sign returns -1, 0 or 1 for negative, zero and positive decimals.
+func (d Decimal) Sign() int {
+	switch {
+	case d.unscaled < 0:
+		return -1
+	case d.unscaled > 0:
+		return 1
+	}
+	return 0
+}
+
+// Float64 returns the nearest float. Use it for display or metrics only.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Rat returns the exact value of the decimal.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(d.unscaled), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale)), nil))
+}
+
+// Round returns the decimal multiplied by 10^decimals and rounded half away from zero to an integer, e.g. Round(2) returns the cents of a fiat price.
+func (d Decimal) Round(decimals int) (int64, error) {
+	value := new(big.Rat).Mul(d.Rat(), new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	rounded, ok := roundRat(value)
+	if !ok {
+		return 0, fmt.Errorf("decimal %s is out of range", d)
+	}
+	return rounded, nil
+}
+
+// roundRat rounds the number half away from zero to an integer. It reports false if the integer does not fit an int64.
+func roundRat(r *big.Rat) (int64, bool) {
+	quotient, remainder := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(r.Sign())))
+	}
+	return quotient.Int64(), quotient.IsInt64()
+}
+
+// MarshalJSON encodes the decimal as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a string holding a number. Null is 0.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	var s string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	} else {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return err
+		}
+		s = number.String()
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// PriceFromDecimal returns the price of one bitcoin given as a decimal (e.g. the exchange rate reported by GetStatus), rounded half away from zero to the cent.
+func PriceFromDecimal(currency string, perBTC Decimal) (Price, error) {
+	cents, err := perBTC.Round(2)
+	if err != nil {
+		return Price{}, err
+	}
+	return Price{Currency: strings.ToUpper(currency), CentsPerBTC: cents}, nil
+}
+
+// Value returns the value of the amount at the decimal price per bitcoin in the currency, rounded half away from zero to the cent. Unlike Price.Value, the price is not rounded to the cent first.
+func (d Decimal) Value(currency string, amount Amount) (FiatAmount, error) {
+	cents, ok := roundRat(new(big.Rat).Mul(d.Rat(), big.NewRat(int64(amount)*100, SatoshiPerBitcoin)))
+	if !ok {
+		return FiatAmount{}, fmt.Errorf("value of %s at %s is out of range", amount, d)
+	}
+	return FiatAmount{Currency: strings.ToUpper(currency), Cents: cents}, nil
+}
@@ -0,0 +1,106 @@
+package wasabi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var (
+	torStatuses      = []TorStatus{TorStatusNotRunning, TorStatusRunning, TorStatusTurnedOff}
+	backendStatuses  = []BackendStatus{BackendStatusConnected, BackendStatusDisconnected}
+	walletStates     = []WalletState{WalletStateUninitialized, WalletStateWaitingForInit, WalletStateInitialized, WalletStateStarting, WalletStateStarted, WalletStateStopping, WalletStateStopped}
+	coinJoinStatuses = []CoinJoinStatus{CoinJoinStatusIdle, CoinJoinStatusInSchedule, CoinJoinStatusInProgress, CoinJoinStatusInCriticalPhase}
+	paymentStatuses  = []PaymentStatus{PaymentStatusPending, PaymentStatusInProgress, PaymentStatusFinished}
+)
+
+// parseEnum returns the known value matching s regardless of case, spaces, dashes and underscores (so "InProgress" and "in_progress" match "In progress"), or s itself and false.
+func parseEnum[T ~string](s string, known []T) (T, bool) {
+	normalized := normalizeEnum(s)
+	for _, value := range known {
+		if normalizeEnum(string(value)) == normalized {
+			return value, true
+		}
+	}
+	return T(s), false
+}
+
+func normalizeEnum(s string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(s))
+}
+
+// unmarshalEnum decodes a JSON string into the known value it matches, see parseEnum. Unknown values are kept verbatim. Null leaves the value unchanged.
+func unmarshalEnum[T ~string](data []byte, out *T, known []T) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid %T %s: %w", *out, data, err)
+	}
+	*out, _ = parseEnum(s, known)
+	return nil
+}
+
+// isUnknownEnum reports whether the value is neither empty nor known.
+func isUnknownEnum[T ~string](value T, known []T) bool {
+	if value == "" {
+		return false
+	}
+	for _, k := range known {
+		if value == k {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmarshalJSON decodes the status, mapping other spellings of the known statuses to their constant. Statuses the client does not know are kept verbatim, see IsUnknown.
+func (s *TorStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, torStatuses)
+}
+
+// IsUnknown reports whether the daemon reported a status the client does not know. Switch statements should handle it in their default case; the raw text is the value itself.
+func (s TorStatus) IsUnknown() bool {
+	return isUnknownEnum(s, torStatuses)
+}
+
+// UnmarshalJSON decodes the status, mapping other spellings of the known statuses to their constant. Statuses the client does not know are kept verbatim, see IsUnknown.
+func (s *BackendStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, backendStatuses)
+}
+
+// IsUnknown reports whether the daemon reported a status the client does not know. Switch statements should handle it in their default case; the raw text is the value itself.
+func (s BackendStatus) IsUnknown() bool {
+	return isUnknownEnum(s, backendStatuses)
+}
+
+// UnmarshalJSON decodes the state, mapping other spellings of the known states to their constant. States the client does not know are kept verbatim, see IsUnknown.
+func (s *WalletState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, walletStates)
+}
+
+// IsUnknown reports whether the daemon reported a state the client does not know. Switch statements should handle it in their default case; the raw text is the value itself.
+func (s WalletState) IsUnknown() bool {
+	return isUnknownEnum(s, walletStates)
+}
+
+// UnmarshalJSON decodes the status, mapping other spellings of the known statuses to their constant. Statuses the client does not know are kept verbatim, see IsUnknown.
+func (s *CoinJoinStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, coinJoinStatuses)
+}
+
+// IsUnknown reports whether the daemon reported a status the client does not know. Switch statements should handle it in their default case; the raw text is the value itself.
+func (s CoinJoinStatus) IsUnknown() bool {
+	return isUnknownEnum(s, coinJoinStatuses)
+}
+
+// UnmarshalJSON decodes the status, mapping other spellings of the known statuses to their constant. Statuses the client does not know are kept verbatim, see IsUnknown.
+func (s *PaymentStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, paymentStatuses)
+}
+
+// IsUnknown reports whether the daemon reported a status the client does not know. Switch statements should handle it in their default case; the raw text is the value itself.
+func (s PaymentStatus) IsUnknown() bool {
+	return isUnknownEnum(s, paymentStatuses)
+}
@@ -0,0 +1,213 @@
+package wasabi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// maxHTTPErrorBodySize is the maximum number of response body bytes kept in an HTTPError.
+const maxHTTPErrorBodySize = 4096
+
+// HTTPError is returned when the rpc server (or a proxy in front of it) responds with a non-200 http status.
+type HTTPError struct {
+	// Status is the http status code of the response.
+	Status int
+	// Body is an excerpt of the response body, truncated to at most 4096 bytes.
+	Body string
+	// Headers are the headers of the response.
+	Headers http.Header
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("http status %d", e.Status)
+	}
+	return fmt.Sprintf("http status %d: %s", e.Status, e.Body)
+}
+
+// newHTTPError creates an HTTPError from the response. The body is read up to the size limit and the rest is discarded, so the connection can be reused.
+func newHTTPError(resp *http.Response) *HTTPError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxHTTPErrorBodySize))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return &HTTPError{
+		Status:  resp.StatusCode,
+		Body:    string(body),
+		Headers: resp.Header,
+	}
+}
+
+// ErrUnsupportedMethod is matched (with errors.Is) by the errors of calls to methods the daemon does not know.
+var ErrUnsupportedMethod = errors.New("method is not supported by the daemon")
+
+// unsupportedMethodError is the error of a call rejected by the daemon with E_NO_METHOD.
+type unsupportedMethodError struct {
+	err *RPCError
+}
+
+func (e *unsupportedMethodError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrUnsupportedMethod, e.err)
+}
+
+func (e *unsupportedMethodError) Unwrap() []error {
+	return []error{ErrUnsupportedMethod, e.err}
+}
+
+// ErrorCategory is a category of an error returned by the client. It allows to tell apart an unreachable daemon, a broken response, a failing daemon and a request rejected by the wallet.
+type ErrorCategory int
+
+const (
+	// ErrorCategoryNone is the category of a nil error.
+	ErrorCategoryNone ErrorCategory = iota
+	// ErrorCategoryUnknown is the category of errors that do not belong to any other category.
+	ErrorCategoryUnknown
+	// ErrorCategoryConnection is the category of transport errors (dial failures, timeouts, resets).
+	ErrorCategoryConnection
+	// ErrorCategoryProtocol is the category of errors in http or JSON-RPC communication (unexpected http status, malformed response, unknown method).
+	ErrorCategoryProtocol
+	// ErrorCategoryDaemon is the category of errors reported by the daemon itself.
+	ErrorCategoryDaemon
+	// ErrorCategoryWallet is the category of requests rejected by the wallet (wrong password, not enough coins, etc.).
+	ErrorCategoryWallet
+)
+
+// String returns the string representation of the error category.
+func (c ErrorCategory) String() string {
+	switch c {
+	case ErrorCategoryNone:
+		return "none"
+	case ErrorCategoryConnection:
+		return "connection"
+	case ErrorCategoryProtocol:
+		return "protocol"
+	case ErrorCategoryDaemon:
+		return "daemon"
+	case ErrorCategoryWallet:
+		return "wallet"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionError is returned when the rpc server could not be reached.
+type ConnectionError struct {
+	Err error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("connection error: %v", e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// ProtocolError is returned when the response of the rpc server could not be understood. It wraps an HTTPError, an RPCError with a JSON-RPC protocol code or a decoding error.
+type ProtocolError struct {
+	Err error
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("protocol error: %v", e.Err)
+}
+
+func (e *ProtocolError) Unwrap() error {
+	return e.Err
+}
+
+// NumberError is returned, wrapped in a ProtocolError, when Config.UseNumber is set and a number of a result does not fit the field it is decoded into (e.g. a fractional or negative amount, or a height beyond uint64), or a numeric field holds something else.
+type NumberError struct {
+	// Field is the path of the field, e.g. "amount" or "peers.lastSeen". It is empty if the decoder does not tell.
+	Field string
+	// Value describes the offending value, e.g. "number 1.5", "string" or a quoted text which is not a number.
+	Value string
+	// Type is the type of the field. It is nil if the decoder does not tell.
+	Type reflect.Type
+	Err  error
+}
+
+func (e *NumberError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("invalid %s: %v", e.Value, e.Err)
+	}
+	return fmt.Sprintf("invalid %s in field %s: %v", e.Value, e.Field, e.Err)
+}
+
+func (e *NumberError) Unwrap() error {
+	return e.Err
+}
+
+// DaemonError is returned when the daemon reports an error which is not a wallet rejection.
+type DaemonError struct {
+	Err *RPCError
+}
+
+func (e *DaemonError) Error() string {
+	return fmt.Sprintf("daemon error: %v", e.Err)
+}
+
+func (e *DaemonError) Unwrap() error {
+	return e.Err
+}
+
+// WalletRejection is returned when the wallet rejects the request with one of the known WalletError reasons.
+type WalletRejection struct {
+	Reason WalletError
+	Err    *RPCError
+}
+
+func (e *WalletRejection) Error() string {
+	return fmt.Sprintf("wallet rejection: %v", e.Reason)
+}
+
+// Unwrap allows to match both the reason (errors.Is(err, ErrorIncorrectPassword)) and the underlying RPCError.
+func (e *WalletRejection) Unwrap() []error {
+	return []error{e.Reason, e.Err}
+}
+
+// Classify returns the category of the error returned by the client.
+func Classify(err error) ErrorCategory {
+	if err == nil {
+		return ErrorCategoryNone
+	}
+	var (
+		walletRejection *WalletRejection
+		daemonErr       *DaemonError
+		protocolErr     *ProtocolError
+		connectionErr   *ConnectionError
+	)
+	switch {
+	case errors.As(err, &walletRejection):
+		return ErrorCategoryWallet
+	case errors.As(err, &daemonErr):
+		return ErrorCategoryDaemon
+	case errors.As(err, &protocolErr):
+		return ErrorCategoryProtocol
+	case errors.As(err, &connectionErr):
+		return ErrorCategoryConnection
+	default:
+		return ErrorCategoryUnknown
+	}
+}
+
+// classifyResponseError wraps an error returned by decodeClientResponse into the error category it belongs to.
+func classifyResponseError(err error) error {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return &ProtocolError{Err: err}
+	}
+	switch rpcErr.Code {
+	case E_NO_METHOD:
+		return &ProtocolError{Err: &unsupportedMethodError{err: rpcErr}}
+	case E_PARSE, E_INVALID_REQ, E_BAD_PARAMS:
+		return &ProtocolError{Err: rpcErr}
+	}
+	for _, reason := range walletErrors {
+		if rpcErr.Message == string(reason) {
+			return &WalletRejection{Reason: reason, Err: rpcErr}
+		}
+	}
+	return &DaemonError{Err: rpcErr}
+}
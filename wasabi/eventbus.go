@@ -0,0 +1,371 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType is the type of an event published on an EventBus.
+type EventType string
+
+const (
+	// EventNewTransaction is the type of NewTransactionEvent.
+	EventNewTransaction EventType = "NewTransaction"
+	// EventConfirmationReached is the type of ConfirmationReachedEvent.
+	EventConfirmationReached EventType = "ConfirmationReached"
+	// EventCoinJoinStatusChanged is the type of CoinJoinStatusChangedEvent.
+	EventCoinJoinStatusChanged EventType = "CoinJoinStatusChanged"
+	// EventBackendDisconnected is the type of BackendDisconnectedEvent.
+	EventBackendDisconnected EventType = "BackendDisconnected"
+	// EventPaymentInCoinJoinFinished is the type of PaymentInCoinJoinFinishedEvent.
+	EventPaymentInCoinJoinFinished EventType = "PaymentInCoinJoinFinished"
+)
+
+// Event is an event published on an EventBus. Use a type switch to get the typed event.
+type Event interface {
+	Type() EventType
+}
+
+// NewTransactionEvent is published when a transaction appears in the history of a wallet.
+type NewTransactionEvent struct {
+	WalletName  string
+	Transaction Transaction
+}
+
+// Type implements Event.
+func (NewTransactionEvent) Type() EventType { return EventNewTransaction }
+
+// ConfirmationReachedEvent is published when a transaction of a wallet gets its first confirmation.
+type ConfirmationReachedEvent struct {
+	WalletName  string
+	Transaction Transaction
+	Height      int
+}
+
+// Type implements Event.
+func (ConfirmationReachedEvent) Type() EventType { return EventConfirmationReached }
+
+// CoinJoinStatusChangedEvent is published when the coinjoin status of a wallet changes.
+type CoinJoinStatusChangedEvent struct {
+	WalletName string
+	Previous   CoinJoinStatus
+	Current    CoinJoinStatus
+}
+
+// Type implements Event.
+func (CoinJoinStatusChangedEvent) Type() EventType { return EventCoinJoinStatusChanged }
+
+// BackendDisconnectedEvent is published when the daemon loses the connection to the backend or the daemon itself cannot be reached.
+type BackendDisconnectedEvent struct {
+	// Status is the backend status reported by the daemon. It is empty if the daemon cannot be reached.
+	Status BackendStatus
+	// Err is the error of the status call if the daemon cannot be reached.
+	Err error
+}
+
+// Type implements Event.
+func (BackendDisconnectedEvent) Type() EventType { return EventBackendDisconnected }
+
+// PaymentInCoinJoinFinishedEvent is published when a payment in coinjoin of a wallet is finished.
+type PaymentInCoinJoinFinishedEvent struct {
+	WalletName string
+	Payment    ListPaymentsInCoinJoinResponseItem
+}
+
+// Type implements Event.
+func (PaymentInCoinJoinFinishedEvent) Type() EventType { return EventPaymentInCoinJoinFinished }
+
+// SlowSubscriberPolicy decides what happens when the buffer of a subscription is full.
+type SlowSubscriberPolicy int
+
+const (
+	// DropNewest drops the published event.
+	DropNewest SlowSubscriberPolicy = iota
+	// DropOldest drops the oldest buffered event to make room for the published one.
+	DropOldest
+	// Block makes Publish wait until the subscriber receives the event, the subscription is closed or the context of Publish is done.
+	Block
+	// Disconnect closes the subscription.
+	Disconnect
+)
+
+// DefaultSubscriptionBuffer is the buffer size of a subscription if no buffer is given.
+const DefaultSubscriptionBuffer = 16
+
+// SubscribeOptions holds the options of a subscription.
+type SubscribeOptions struct {
+	// Buffer is the number of events buffered for the subscriber. Default is DefaultSubscriptionBuffer.
+	Buffer int
+	// Policy is applied when the buffer is full. Default is DropNewest.
+	Policy SlowSubscriberPolicy
+}
+
+// Subscription receives the events of an EventBus.
+type Subscription struct {
+	bus       *EventBus
+	types     map[EventType]bool
+	policy    SlowSubscriberPolicy
+	events    chan Event
+	done      chan struct{}
+	closeOnce sync.Once
+	// mutex is held for reading while events are sent and for writing while the events channel is closed.
+	mutex   sync.RWMutex
+	closed  bool
+	dropped atomic.Uint64
+}
+
+// Events returns the channel of the events. It is closed when the subscription is closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped returns the number of events dropped because the subscriber was too slow.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes from the bus and closes the events channel. Publish calls blocked on the subscription return.
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+	s.closeOnce.Do(func() { close(s.done) })
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
+
+// deliver hands the event to the subscriber according to the slow subscriber policy.
+func (s *Subscription) deliver(ctx context.Context, event Event) {
+	if !s.send(ctx, event) {
+		s.Close()
+	}
+}
+
+// send sends the event and reports false if the subscription has to be disconnected.
+func (s *Subscription) send(ctx context.Context, event Event) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.closed {
+		return true
+	}
+	if s.policy == Block {
+		select {
+		case s.events <- event:
+		case <-s.done:
+		case <-ctx.Done():
+			s.dropped.Add(1)
+		}
+		return true
+	}
+	for {
+		select {
+		case s.events <- event:
+			return true
+		default:
+		}
+		switch s.policy {
+		case DropOldest:
+			select {
+			case <-s.events:
+				s.dropped.Add(1)
+			default:
+			}
+		case Disconnect:
+			s.dropped.Add(1)
+			return false
+		default:
+			s.dropped.Add(1)
+			return true
+		}
+	}
+}
+
+// EventBus fans out typed events to subscribers. Events are published by Watch or by the application with Publish.
+type EventBus struct {
+	mutex         sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// NewEventBus creates an event bus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{subscriptions: map[*Subscription]struct{}{}}
+}
+
+// Subscribe registers a subscriber for the given event types, or for all events if no type is given.
+func (b *EventBus) Subscribe(opts SubscribeOptions, types ...EventType) *Subscription {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultSubscriptionBuffer
+	}
+	s := &Subscription{
+		bus:    b,
+		policy: opts.Policy,
+		events: make(chan Event, opts.Buffer),
+		done:   make(chan struct{}),
+	}
+	if len(types) > 0 {
+		s.types = make(map[EventType]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+	b.mutex.Lock()
+	b.subscriptions[s] = struct{}{}
+	b.mutex.Unlock()
+	return s
+}
+
+func (b *EventBus) unsubscribe(s *Subscription) {
+	b.mutex.Lock()
+	delete(b.subscriptions, s)
+	b.mutex.Unlock()
+}
+
+// Publish delivers the event to all subscribers of its type. It only waits for subscribers with the Block policy, at most until the context is done.
+func (b *EventBus) Publish(ctx context.Context, event Event) {
+	b.mutex.RLock()
+	subscriptions := make([]*Subscription, 0, len(b.subscriptions))
+	for s := range b.subscriptions {
+		if s.types == nil || s.types[event.Type()] {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	b.mutex.RUnlock()
+	for _, s := range subscriptions {
+		s.deliver(ctx, event)
+	}
+}
+
+// Close closes all subscriptions.
+func (b *EventBus) Close() {
+	b.mutex.Lock()
+	subscriptions := b.subscriptions
+	b.subscriptions = map[*Subscription]struct{}{}
+	b.mutex.Unlock()
+	for s := range subscriptions {
+		s.Close()
+	}
+}
+
+// EventWatchOptions holds the options of EventBus.Watch.
+type EventWatchOptions struct {
+	// Wallets are the wallets whose transactions, coinjoin status and payments in coinjoin are watched.
+	Wallets []string
+	// Interval is the interval between the checks. Default is DefaultPollInterval.
+	Interval time.Duration
+	// TxStateStore returns the store of the transaction watcher of a wallet. Optional; without a store, the transactions already in the history when Watch starts are not published.
+	TxStateStore func(walletName string) TxStateStore
+	// OnError is called with the errors of the checks. The watch keeps running after a failed check.
+	OnError func(err error)
+	// Clock provides the timers of the checks. Default is SystemClock.
+	Clock Clock
+}
+
+// Watch polls the daemon and publishes the events on the bus until the context is done.
+func (b *EventBus) Watch(ctx context.Context, c Client, opts EventWatchOptions) error {
+	c = c.WithContext(ctx)
+	onError := func(err error) {
+		if opts.OnError != nil && ctx.Err() == nil {
+			opts.OnError(err)
+		}
+	}
+	var wg sync.WaitGroup
+	for _, walletName := range opts.Wallets {
+		txOpts := TxWatcherOptions{Interval: opts.Interval, Clock: opts.Clock, IgnoreExisting: true, OnError: onError}
+		if opts.TxStateStore != nil {
+			txOpts.Store = opts.TxStateStore(walletName)
+		}
+		watcher := NewTxWatcher(c, walletName, txOpts)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := watcher.Run(ctx); err != nil && !errors.Is(err, ctx.Err()) {
+				onError(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for event := range watcher.Events() {
+				switch event.Type {
+				case TxDetected:
+					b.Publish(ctx, NewTransactionEvent{WalletName: event.WalletName, Transaction: event.Transaction})
+				case TxConfirmed:
+					b.Publish(ctx, ConfirmationReachedEvent{WalletName: event.WalletName, Transaction: event.Transaction, Height: event.Height})
+				}
+			}
+		}()
+	}
+
+	backendConnected := true
+	coinJoinStatus := make(map[string]CoinJoinStatus, len(opts.Wallets))
+	pendingPayments := make(map[string]map[string]bool, len(opts.Wallets))
+	paymentsUnsupported := false
+	err := poll(ctx, opts.Clock, opts.Interval, func() (bool, error) {
+		status, err := c.GetStatus()
+		switch {
+		case err != nil && Classify(err) == ErrorCategoryConnection:
+			if backendConnected {
+				backendConnected = false
+				b.Publish(ctx, BackendDisconnectedEvent{Err: err})
+			}
+			return false, nil
+		case err != nil:
+			onError(err)
+		case status.BackendStatus == BackendStatusDisconnected:
+			if backendConnected {
+				backendConnected = false
+				b.Publish(ctx, BackendDisconnectedEvent{Status: status.BackendStatus})
+			}
+		default:
+			backendConnected = true
+		}
+
+		for _, walletName := range opts.Wallets {
+			info, err := c.GetWalletInfo(walletName)
+			if err != nil {
+				onError(err)
+			} else {
+				previous, known := coinJoinStatus[walletName]
+				if known && previous != info.CoinJoinStatus {
+					b.Publish(ctx, CoinJoinStatusChangedEvent{WalletName: walletName, Previous: previous, Current: info.CoinJoinStatus})
+				}
+				coinJoinStatus[walletName] = info.CoinJoinStatus
+			}
+
+			if paymentsUnsupported {
+				continue
+			}
+			payments, err := c.ListPaymentsInCoinJoin(walletName)
+			if errors.Is(err, ErrUnsupportedMethod) {
+				paymentsUnsupported = true
+				continue
+			}
+			if err != nil {
+				onError(err)
+				continue
+			}
+			pending, known := pendingPayments[walletName]
+			current := make(map[string]bool, len(payments))
+			for _, payment := range payments {
+				if !paymentFinished(payment) {
+					current[payment.ID] = true
+				} else if known && pending[payment.ID] {
+					b.Publish(ctx, PaymentInCoinJoinFinishedEvent{WalletName: walletName, Payment: payment})
+				}
+			}
+			pendingPayments[walletName] = current
+		}
+		return false, nil
+	})
+	wg.Wait()
+	return err
+}
+
+// paymentFinished reports whether the last state of the payment is finished.
+func paymentFinished(payment ListPaymentsInCoinJoinResponseItem) bool {
+	return len(payment.State) > 0 && payment.State[len(payment.State)-1].Status == PaymentStatusFinished
+}
@@ -0,0 +1,205 @@
+package wasabi
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat is the file format of ExportHistory.
+type ExportFormat int
+
+const (
+	// ExportCSV writes a header line and one line per transaction.
+	ExportCSV ExportFormat = iota
+	// ExportJSON writes an array with one object per transaction, keyed by column.
+	ExportJSON
+)
+
+// ExportColumn is a column of ExportHistory.
+type ExportColumn string
+
+const (
+	ColumnDate       ExportColumn = "date"
+	ColumnTxID       ExportColumn = "txid"
+	ColumnAmountBTC  ExportColumn = "amount_btc"
+	ColumnAmountSats ExportColumn = "amount_sat"
+	// ColumnAmountFiat is the value of the amount at the price at the time of the export.
+	ColumnAmountFiat ExportColumn = "amount_fiat"
+	ColumnLabel      ExportColumn = "label"
+	ColumnCoinJoin   ExportColumn = "coinjoin"
+	ColumnHeight     ExportColumn = "height"
+)
+
+// DefaultExportColumns are the columns exported if no columns are given.
+var DefaultExportColumns = []ExportColumn{ColumnDate, ColumnTxID, ColumnAmountBTC, ColumnAmountFiat, ColumnLabel, ColumnCoinJoin}
+
+// ExportLocale controls the formatting of CSV exports.
+type ExportLocale struct {
+	// Comma is the field separator.
+	Comma rune
+	// DecimalSeparator separates the decimals of the amounts.
+	DecimalSeparator string
+	// DateFormat is the time layout of the dates.
+	DateFormat string
+}
+
+var (
+	// LocaleEnglish uses commas between fields, decimal points and RFC 3339 dates.
+	LocaleEnglish = ExportLocale{Comma: ',', DecimalSeparator: ".", DateFormat: time.RFC3339}
+	// LocaleEuropean uses semicolons between fields, decimal commas and day.month.year dates.
+	LocaleEuropean = ExportLocale{Comma: ';', DecimalSeparator: ",", DateFormat: "02.01.2006 15:04:05"}
+)
+
+// ExportOptions holds the options of ExportHistory.
+type ExportOptions struct {
+	// Columns are the exported columns in order. Default is DefaultExportColumns.
+	Columns []ExportColumn
+	// Locale formats the CSV export. Default is LocaleEnglish. JSON exports always use decimal points and RFC 3339 dates.
+	Locale *ExportLocale
+	// PriceSource values the amounts for ColumnAmountFiat. Default is StatusPriceSource.
+	PriceSource PriceSource
+	// Currency is the currency of ColumnAmountFiat. Default is USD.
+	Currency string
+	// Context is used for the calls and the price source. Default is context.Background().
+	Context context.Context
+}
+
+// ExportHistory writes the history of the wallet in an accountant-friendly format. The fiat values use the price at the time of the export, not the price at the time of each transaction; the price is fetched only if ColumnAmountFiat is exported.
+func ExportHistory(c Client, walletName string, w io.Writer, format ExportFormat, opts ExportOptions) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c = c.WithContext(ctx)
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = DefaultExportColumns
+	}
+	locale := LocaleEnglish
+	if opts.Locale != nil {
+		locale = *opts.Locale
+	}
+	currency := opts.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+
+	var price Price
+	for _, column := range columns {
+		if column != ColumnAmountFiat {
+			continue
+		}
+		source := opts.PriceSource
+		if source == nil {
+			source = StatusPriceSource{Client: c}
+		}
+		var err error
+		if price, err = source.Price(ctx, currency); err != nil {
+			return fmt.Errorf("failed to get the %s price: %w", currency, err)
+		}
+		break
+	}
+
+	history, err := c.GetHistory(walletName)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case ExportCSV:
+		return exportCSV(w, history, columns, locale, price)
+	case ExportJSON:
+		return exportJSON(w, history, columns, price)
+	default:
+		return fmt.Errorf("unknown export format %d", format)
+	}
+}
+
+func exportCSV(w io.Writer, history []Transaction, columns []ExportColumn, locale ExportLocale, price Price) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = locale.Comma
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = string(column)
+		if column == ColumnAmountFiat {
+			header[i] = "amount_" + strings.ToLower(price.Currency)
+		}
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	decimal := func(s string) string {
+		return strings.Replace(s, ".", locale.DecimalSeparator, 1)
+	}
+	record := make([]string, len(columns))
+	for _, tx := range history {
+		for i, column := range columns {
+			switch column {
+			case ColumnDate:
+				record[i] = tx.DateTime.Format(locale.DateFormat)
+			case ColumnTxID:
+				record[i] = string(tx.TxID)
+			case ColumnAmountBTC:
+				record[i] = decimal(tx.Amount.FormatBTC())
+			case ColumnAmountSats:
+				record[i] = strconv.FormatInt(tx.Amount.Sats(), 10)
+			case ColumnAmountFiat:
+				record[i] = decimal(price.Value(tx.Amount).Decimal())
+			case ColumnLabel:
+				record[i] = tx.Label
+			case ColumnCoinJoin:
+				record[i] = strconv.FormatBool(tx.IsLikelyCoinJoin)
+			case ColumnHeight:
+				record[i] = strconv.Itoa(tx.Height)
+			default:
+				return fmt.Errorf("unknown export column %q", column)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func exportJSON(w io.Writer, history []Transaction, columns []ExportColumn, price Price) error {
+	rows := make([]map[string]interface{}, 0, len(history))
+	for _, tx := range history {
+		row := make(map[string]interface{}, len(columns)+1)
+		for _, column := range columns {
+			switch column {
+			case ColumnDate:
+				row[string(column)] = tx.DateTime.Format(time.RFC3339)
+			case ColumnTxID:
+				row[string(column)] = tx.TxID
+			case ColumnAmountBTC:
+				// A string keeps the exact decimal value.
+				row[string(column)] = tx.Amount.FormatBTC()
+			case ColumnAmountSats:
+				row[string(column)] = tx.Amount.Sats()
+			case ColumnAmountFiat:
+				row[string(column)] = price.Value(tx.Amount).Decimal()
+				row["currency"] = price.Currency
+			case ColumnLabel:
+				row[string(column)] = tx.Label
+			case ColumnCoinJoin:
+				row[string(column)] = tx.IsLikelyCoinJoin
+			case ColumnHeight:
+				row[string(column)] = tx.Height
+			default:
+				return fmt.Errorf("unknown export column %q", column)
+			}
+		}
+		rows = append(rows, row)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
@@ -0,0 +1,101 @@
+package wasabi
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Estimated virtual sizes of the parts of a segwit v0 P2WPKH transaction, used to estimate fees before the daemon builds a transaction.
+const (
+	TxOverheadVSize = 11
+	InputVSize      = 68
+	OutputVSize     = 31
+)
+
+// EstimateFee returns the estimated fee of a transaction with the given numbers of inputs and outputs at the fee rate in satoshi per virtual byte.
+func EstimateFee(feeRate float64, inputs int, outputs int) Amount {
+	vsize := TxOverheadVSize + InputVSize*inputs + OutputVSize*outputs
+	return Amount(math.Ceil(float64(vsize) * feeRate))
+}
+
+// FeePolicy is a ceiling on the fees of built transactions. The zero value allows any fee.
+type FeePolicy struct {
+	// MaxFee is the highest absolute fee. Zero disables the check.
+	MaxFee Amount
+	// MaxFeePercent is the highest fee as a percentage of the sum of the payments, e.g. 1.5 for 1.5%. Zero disables the check.
+	MaxFeePercent float64
+}
+
+// enabled reports whether the policy limits the fee.
+func (p FeePolicy) enabled() bool {
+	return p.MaxFee > 0 || p.MaxFeePercent > 0
+}
+
+// Check returns a FeeCeilingError if the fee of a transaction paying amount exceeds the policy.
+func (p FeePolicy) Check(fee Amount, amount Amount) error {
+	exceeded := p.MaxFee > 0 && fee > p.MaxFee
+	if p.MaxFeePercent > 0 && (amount <= 0 || float64(fee)*100 > p.MaxFeePercent*float64(amount)) {
+		exceeded = true
+	}
+	if exceeded {
+		return &FeeCeilingError{Fee: fee, Amount: amount, MaxFee: p.MaxFee, MaxFeePercent: p.MaxFeePercent}
+	}
+	return nil
+}
+
+// FeeCeilingError is returned when a built transaction pays a higher fee than allowed.
+type FeeCeilingError struct {
+	// Fee is the fee of the transaction.
+	Fee Amount
+	// Amount is the sum of the payments of the transaction.
+	Amount Amount
+	// MaxFee is the highest allowed fee, zero if not limited.
+	MaxFee Amount
+	// MaxFeePercent is the highest allowed fee as a percentage of the amount, zero if not limited.
+	MaxFeePercent float64
+}
+
+func (e *FeeCeilingError) Error() string {
+	var limits []string
+	if e.MaxFee > 0 {
+		limits = append(limits, fmt.Sprintf("the maximum of %d sat", e.MaxFee))
+	}
+	if e.MaxFeePercent > 0 {
+		limits = append(limits, fmt.Sprintf("%g%% of the amount of %d sat", e.MaxFeePercent, e.Amount))
+	}
+	return fmt.Sprintf("transaction fee %d sat exceeds %s", e.Fee, strings.Join(limits, " or "))
+}
+
+// checkFeePolicy decodes the built transaction and returns a FeeCeilingError if its fee exceeds the policy.
+func checkFeePolicy(c Client, walletName string, txHex string, payments []Payment, policy FeePolicy) error {
+	if !policy.enabled() {
+		return nil
+	}
+	fee, err := builtTransactionFee(c, walletName, txHex)
+	if err != nil {
+		return err
+	}
+	var amount Amount
+	for _, payment := range payments {
+		amount += payment.Amount
+	}
+	return policy.Check(fee, amount)
+}
+
+// builtTransactionFee decodes a transaction built by the wallet and returns its fee computed from the coins of the wallet.
+func builtTransactionFee(c Client, walletName string, txHex string) (Amount, error) {
+	tx, err := DecodeTransaction(txHex)
+	if err != nil {
+		return 0, err
+	}
+	coins, err := c.ListCoins(walletName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list coins to check the fee: %w", err)
+	}
+	fee, err := TransactionFee(tx, coins)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check the fee: %w", err)
+	}
+	return fee, nil
+}
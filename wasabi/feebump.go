@@ -0,0 +1,244 @@
+package wasabi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FeeBumpPolicy decides when unconfirmed outgoing transactions are sped up.
+type FeeBumpPolicy struct {
+	// MaxWaitBlocks is the number of blocks a transaction may stay unconfirmed before it is sped up. The count restarts after each bump. Default is 1.
+	MaxWaitBlocks int
+	// MaxTotalFee is the highest fee a replacement may pay. A replacement paying more is not broadcast and the transaction is not bumped again. Zero means no limit.
+	MaxTotalFee Amount
+	// MaxBumps is the highest number of replacements of a transaction. Zero means no limit.
+	MaxBumps int
+}
+
+// FeeBump is a replacement broadcast by the FeeBumper.
+type FeeBump struct {
+	// Replaced is the id of the replaced transaction.
+	Replaced TxID
+	// TxID is the id of the replacement.
+	TxID TxID
+	// Fee is the fee of the replacement.
+	Fee Amount
+	// Height is the best block height when the replacement was broadcast.
+	Height uint64
+	Time   time.Time
+}
+
+// RBFChain is the chain of replacements of an outgoing transaction.
+type RBFChain struct {
+	// Original is the id of the first transaction of the chain.
+	Original TxID
+	// Bumps are the replacements in broadcast order.
+	Bumps []FeeBump
+	// Confirmed is the id of the transaction of the chain which got confirmed, empty while none is.
+	Confirmed TxID
+	// Exhausted reports that the policy does not allow further bumps.
+	Exhausted bool
+}
+
+// Current returns the id of the latest transaction of the chain.
+func (c RBFChain) Current() TxID {
+	if len(c.Bumps) == 0 {
+		return c.Original
+	}
+	return c.Bumps[len(c.Bumps)-1].TxID
+}
+
+// FeeBumperOptions holds the options of a FeeBumper.
+type FeeBumperOptions struct {
+	// Interval is the interval between the checks. Default is DefaultPollInterval.
+	Interval time.Duration
+	Policy   FeeBumpPolicy
+	// Password returns the password of the wallet. Without a provider, the empty password is used.
+	Password PasswordProvider
+	// OnBump is called after each broadcast replacement. Optional.
+	OnBump func(chain RBFChain, bump FeeBump)
+	// OnError is called with the errors of the checks and bumps. If set, the bumper keeps running after a failure; otherwise Run returns the error. A replacement refused because of MaxTotalFee is reported as a FeeCeilingError.
+	OnError func(err error)
+	// Clock provides the timers of the checks and the time of the bumps. Default is SystemClock.
+	Clock Clock
+}
+
+// pendingTx is an unconfirmed transaction watched by the FeeBumper.
+type pendingTx struct {
+	chain *RBFChain
+	// since is the best block height when the transaction was first seen or broadcast.
+	since uint64
+	// seen reports whether the transaction was in the history already. A replacement may take a moment to appear there.
+	seen bool
+}
+
+// FeeBumper monitors the unconfirmed outgoing transactions of a wallet and speeds them up according to a policy, broadcasting the replacements and recording the RBF chains.
+type FeeBumper struct {
+	client     Client
+	walletName string
+	opts       FeeBumperOptions
+	mutex      sync.Mutex
+	pending    map[TxID]*pendingTx
+	// replaced holds the chains of the transactions replaced by the bumper. They may stay in the history for a while and must not start a new chain.
+	replaced map[TxID]*RBFChain
+	chains   []*RBFChain
+}
+
+// NewFeeBumper creates a fee bumper for the wallet. Call Run to start it.
+func NewFeeBumper(c Client, walletName string, opts FeeBumperOptions) *FeeBumper {
+	if opts.Policy.MaxWaitBlocks <= 0 {
+		opts.Policy.MaxWaitBlocks = 1
+	}
+	return &FeeBumper{
+		client:     c,
+		walletName: walletName,
+		opts:       opts,
+		pending:    map[TxID]*pendingTx{},
+		replaced:   map[TxID]*RBFChain{},
+	}
+}
+
+// Chains returns the RBF chains of the transactions seen by the bumper.
+func (b *FeeBumper) Chains() []RBFChain {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	chains := make([]RBFChain, len(b.chains))
+	for i, chain := range b.chains {
+		chains[i] = *chain
+		chains[i].Bumps = append([]FeeBump(nil), chain.Bumps...)
+	}
+	return chains
+}
+
+// Run checks the wallet until the context is done or a check fails (unless OnError is set).
+func (b *FeeBumper) Run(ctx context.Context) error {
+	c := b.client.WithContext(ctx)
+	return poll(ctx, b.opts.Clock, b.opts.Interval, func() (bool, error) {
+		if err := b.check(c); err != nil {
+			if b.opts.OnError != nil {
+				b.opts.OnError(err)
+				return false, nil
+			}
+			return false, err
+		}
+		return false, nil
+	})
+}
+
+// check updates the watched transactions and bumps those which waited too long.
+func (b *FeeBumper) check(c Client) error {
+	history, err := c.GetHistory(b.walletName)
+	if err != nil {
+		return err
+	}
+	status, err := c.GetStatus()
+	if err != nil {
+		return err
+	}
+	best := status.BestBlockchainHeight
+
+	b.mutex.Lock()
+	unconfirmed := make(map[TxID]bool)
+	for _, tx := range history {
+		if chain, replaced := b.replaced[tx.TxID]; replaced {
+			if confirmationsAt(tx.Height, best) > 0 && chain.Confirmed == "" {
+				// The replacement lost the race.
+				chain.Confirmed = tx.TxID
+				delete(b.pending, chain.Current())
+			}
+			continue
+		}
+		pending, known := b.pending[tx.TxID]
+		if confirmationsAt(tx.Height, best) > 0 {
+			if known {
+				pending.chain.Confirmed = tx.TxID
+				delete(b.pending, tx.TxID)
+			}
+			continue
+		}
+		if tx.Amount >= 0 || tx.IsLikelyCoinJoin {
+			continue
+		}
+		unconfirmed[tx.TxID] = true
+		if !known {
+			chain := &RBFChain{Original: tx.TxID}
+			b.chains = append(b.chains, chain)
+			pending = &pendingTx{chain: chain, since: best}
+			b.pending[tx.TxID] = pending
+		}
+		pending.seen = true
+	}
+	var due []TxID
+	for txID, pending := range b.pending {
+		switch {
+		case !unconfirmed[txID] && pending.seen:
+			// Replaced or dropped from the mempool.
+			delete(b.pending, txID)
+		case pending.seen && !pending.chain.Exhausted && best-pending.since >= uint64(b.opts.Policy.MaxWaitBlocks):
+			due = append(due, txID)
+		}
+	}
+	b.mutex.Unlock()
+
+	for _, txID := range due {
+		if err := b.bump(c, txID, best); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bump speeds up the transaction and broadcasts the replacement if the policy allows it.
+func (b *FeeBumper) bump(c Client, txID TxID, best uint64) error {
+	b.mutex.Lock()
+	pending := b.pending[txID]
+	chain := pending.chain
+	if b.opts.Policy.MaxBumps > 0 && len(chain.Bumps) >= b.opts.Policy.MaxBumps {
+		chain.Exhausted = true
+		b.mutex.Unlock()
+		return nil
+	}
+	b.mutex.Unlock()
+
+	password := ""
+	if b.opts.Password != nil {
+		var err error
+		if password, err = b.opts.Password(); err != nil {
+			return err
+		}
+	}
+	txHex, err := c.SpeedUpTransaction(b.walletName, txID, password)
+	if err != nil {
+		return fmt.Errorf("failed to speed up transaction %s: %w", txID, err)
+	}
+	fee, err := builtTransactionFee(c, b.walletName, txHex)
+	if err != nil {
+		return err
+	}
+	if b.opts.Policy.MaxTotalFee > 0 && fee > b.opts.Policy.MaxTotalFee {
+		b.mutex.Lock()
+		chain.Exhausted = true
+		b.mutex.Unlock()
+		return &FeeCeilingError{Fee: fee, MaxFee: b.opts.Policy.MaxTotalFee}
+	}
+	newTxID, err := broadcastBuilt(c, b.walletName, txHex)
+	if err != nil {
+		return err
+	}
+
+	bump := FeeBump{Replaced: txID, TxID: newTxID, Fee: fee, Height: best, Time: clockOrDefault(b.opts.Clock).Now()}
+	b.mutex.Lock()
+	chain.Bumps = append(chain.Bumps, bump)
+	delete(b.pending, txID)
+	b.replaced[txID] = chain
+	b.pending[newTxID] = &pendingTx{chain: chain, since: best}
+	snapshot := *chain
+	snapshot.Bumps = append([]FeeBump(nil), chain.Bumps...)
+	b.mutex.Unlock()
+	if b.opts.OnBump != nil {
+		b.opts.OnBump(snapshot, bump)
+	}
+	return nil
+}
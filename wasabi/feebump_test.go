@@ -0,0 +1,161 @@
+package wasabi_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+// staleDaemon is a client of the fake daemon which keeps listing replaced transactions as unconfirmed, like a daemon whose mempool still holds them, and whose best height can be moved without mining.
+type staleDaemon struct {
+	wasabi.Client
+	state *staleState
+}
+
+type staleState struct {
+	mutex  sync.Mutex
+	blocks uint64
+	seen   map[wasabi.TxID]wasabi.Transaction
+}
+
+func (c staleDaemon) WithContext(ctx context.Context) wasabi.Client {
+	return c
+}
+
+// addBlocks moves the best height reported by the daemon without confirming anything.
+func (c staleDaemon) addBlocks(n uint64) {
+	c.state.mutex.Lock()
+	c.state.blocks += n
+	c.state.mutex.Unlock()
+}
+
+func (c staleDaemon) GetStatus() (wasabi.GetStatusResponse, error) {
+	status, err := c.Client.GetStatus()
+	c.state.mutex.Lock()
+	status.BestBlockchainHeight += c.state.blocks
+	c.state.mutex.Unlock()
+	return status, err
+}
+
+func (c staleDaemon) GetHistory(walletName string) ([]wasabi.Transaction, error) {
+	history, err := c.Client.GetHistory(walletName)
+	if err != nil {
+		return nil, err
+	}
+	c.state.mutex.Lock()
+	defer c.state.mutex.Unlock()
+	listed := map[wasabi.TxID]bool{}
+	for _, tx := range history {
+		listed[tx.TxID] = true
+		if tx.Height == 0 {
+			c.state.seen[tx.TxID] = tx
+		}
+	}
+	for txID, tx := range c.state.seen {
+		if !listed[txID] {
+			history = append(history, tx)
+		}
+	}
+	return history, nil
+}
+
+// newFeeBumpDaemon starts a fake daemon with a wallet holding an unconfirmed payment, and returns a client keeping replaced transactions in the history.
+func newFeeBumpDaemon(t *testing.T) (*wasabitest.Server, staleDaemon, wasabi.TxID) {
+	t.Helper()
+	s := wasabitest.NewServer(wasabitest.ServerOptions{})
+	t.Cleanup(s.Close)
+	for _, name := range []string{"w", "payee"} {
+		if err := s.AddWallet(name, "pw"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Fund("w", 1_000_000, "funding"); err != nil {
+		t.Fatal(err)
+	}
+	s.Mine(1)
+	c, err := wasabi.NewClient(s.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := c.GetNewAddress("payee", "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, err := c.Send("w", wasabi.SendRequest{
+		Payments:  []wasabi.Payment{{SendTo: address.Address, Amount: 100_000, Label: "payee"}},
+		FeeTarget: wasabi.FeeTargetHour,
+		Password:  "pw",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &staleState{seen: map[wasabi.TxID]wasabi.Transaction{}}
+	return s, staleDaemon{Client: c, state: state}, sent.TransactionID
+}
+
+// checkOnce runs one check of the bumper: the context of Run is done already, and the client ignores it, so Run returns after its first check.
+func checkOnce(t *testing.T, bumper *wasabi.FeeBumper) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := bumper.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}
+
+func TestFeeBumperSkipsReplacedTransactions(t *testing.T) {
+	s, c, original := newFeeBumpDaemon(t)
+	bumper := wasabi.NewFeeBumper(c, "w", wasabi.FeeBumperOptions{Password: wasabi.StaticPassword("pw")})
+	checkOnce(t, bumper)
+	c.addBlocks(1)
+	checkOnce(t, bumper) // the original is due and replaced
+	c.addBlocks(1)
+	checkOnce(t, bumper) // the replacement is due, the original is still listed
+	s.Mine(1)
+	checkOnce(t, bumper)
+
+	chains := bumper.Chains()
+	if len(chains) != 1 {
+		t.Fatalf("%d chains, want 1: %+v", len(chains), chains)
+	}
+	chain := chains[0]
+	if chain.Original != original {
+		t.Fatalf("Original = %s, want %s", chain.Original, original)
+	}
+	if len(chain.Bumps) != 2 {
+		t.Fatalf("%d bumps, want 2", len(chain.Bumps))
+	}
+	if chain.Bumps[0].Replaced != original || chain.Bumps[1].Replaced != chain.Bumps[0].TxID {
+		t.Fatalf("bumps do not form a chain: %+v", chain.Bumps)
+	}
+	if chain.Bumps[1].Fee <= chain.Bumps[0].Fee {
+		t.Fatalf("second bump fee %v is not higher than %v", chain.Bumps[1].Fee, chain.Bumps[0].Fee)
+	}
+	if chain.Confirmed != chain.Current() {
+		t.Fatalf("Confirmed = %q, want %s", chain.Confirmed, chain.Current())
+	}
+}
+
+func TestFeeBumperMaxBumps(t *testing.T) {
+	_, c, original := newFeeBumpDaemon(t)
+	bumper := wasabi.NewFeeBumper(c, "w", wasabi.FeeBumperOptions{
+		Policy:   wasabi.FeeBumpPolicy{MaxBumps: 1},
+		Password: wasabi.StaticPassword("pw"),
+	})
+	for i := 0; i < 3; i++ {
+		checkOnce(t, bumper)
+		c.addBlocks(1)
+	}
+
+	chains := bumper.Chains()
+	if len(chains) != 1 {
+		t.Fatalf("%d chains, want 1: %+v", len(chains), chains)
+	}
+	if chains[0].Original != original || len(chains[0].Bumps) != 1 || !chains[0].Exhausted {
+		t.Fatalf("chain = %+v, want one bump of %s and exhausted", chains[0], original)
+	}
+}
@@ -0,0 +1,69 @@
+package wasabi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FeeTarget is a confirmation target in blocks, used by the daemon to estimate the fee rate of a transaction.
+type FeeTarget int
+
+const (
+	// FeeTargetNextBlock is the shortest target accepted by the daemon, for a confirmation in the next blocks.
+	FeeTargetNextBlock FeeTarget = 2
+	// FeeTargetHalfHour aims at a confirmation within about 30 minutes.
+	FeeTargetHalfHour FeeTarget = 3
+	// FeeTargetHour aims at a confirmation within about an hour.
+	FeeTargetHour FeeTarget = 6
+	// FeeTargetEconomy aims at a confirmation within about a day.
+	FeeTargetEconomy FeeTarget = 144
+)
+
+const (
+	// MinFeeTarget and MaxFeeTarget bound the targets accepted by the daemon.
+	MinFeeTarget FeeTarget = 2
+	MaxFeeTarget FeeTarget = 1008
+)
+
+var feeTargetNames = map[string]FeeTarget{
+	"nextblock": FeeTargetNextBlock,
+	"halfhour":  FeeTargetHalfHour,
+	"hour":      FeeTargetHour,
+	"economy":   FeeTargetEconomy,
+}
+
+// ParseFeeTarget parses a number of blocks or a named target: next-block, half-hour, hour or economy.
+func ParseFeeTarget(s string) (FeeTarget, error) {
+	trimmed := strings.TrimSpace(s)
+	if target, ok := feeTargetNames[normalizeEnum(trimmed)]; ok {
+		return target, nil
+	}
+	blocks, err := strconv.Atoi(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fee target %q", s)
+	}
+	target := FeeTarget(blocks)
+	if err := target.Validate(); err != nil {
+		return 0, err
+	}
+	return target, nil
+}
+
+// Blocks returns the target in blocks.
+func (t FeeTarget) Blocks() int {
+	return int(t)
+}
+
+// Validate returns an error if the daemon does not accept the target.
+func (t FeeTarget) Validate() error {
+	if t < MinFeeTarget || t > MaxFeeTarget {
+		return fmt.Errorf("fee target must be between %d and %d blocks", MinFeeTarget, MaxFeeTarget)
+	}
+	return nil
+}
+
+// String returns the target in blocks, e.g. "6 blocks".
+func (t FeeTarget) String() string {
+	return strconv.Itoa(int(t)) + " blocks"
+}
@@ -0,0 +1,192 @@
+package wasabi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// FiatAmount is an amount of fiat money in hundredths of the currency unit (cents), so sums do not drift like floats.
+type FiatAmount struct {
+	Currency string
+	Cents    int64
+}
+
+// Decimal returns the amount with two decimals, without currency, e.g. "12.34".
+func (f FiatAmount) Decimal() string {
+	sign := ""
+	abs := uint64(f.Cents)
+	if f.Cents < 0 {
+		sign = "-"
+		abs = uint64(-f.Cents)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/100, abs%100)
+}
+
+// String returns the amount with two decimals and the currency, e.g. "12.34 USD".
+func (f FiatAmount) String() string {
+	return f.Decimal() + " " + f.Currency
+}
+
+// Add returns the sum of the amounts. Both must have the same currency.
+func (f FiatAmount) Add(other FiatAmount) (FiatAmount, error) {
+	if f.Currency != other.Currency {
+		return FiatAmount{}, fmt.Errorf("cannot add %s and %s amounts", f.Currency, other.Currency)
+	}
+	return FiatAmount{Currency: f.Currency, Cents: f.Cents + other.Cents}, nil
+}
+
+// Price is the price of one bitcoin in fiat money.
+type Price struct {
+	Currency string
+	// CentsPerBTC is the price of one bitcoin in hundredths of the currency unit.
+	CentsPerBTC int64
+	// Time is the time of the price. It is zero if the source does not tell.
+	Time time.Time
+}
+
+// PriceFromFloat returns the price of one bitcoin given as a float, rounded to the cent. Use PriceFromDecimal for the exchange rate reported by GetStatus.
+func PriceFromFloat(currency string, perBTC float64) Price {
+	return Price{Currency: strings.ToUpper(currency), CentsPerBTC: int64(math.Round(perBTC * 100))}
+}
+
+// Value returns the value of the amount at the price, rounded half away from zero to the cent.
+func (p Price) Value(amount Amount) FiatAmount {
+	product := new(big.Int).Mul(big.NewInt(int64(amount)), big.NewInt(p.CentsPerBTC))
+	quotient, remainder := new(big.Int).QuoRem(product, big.NewInt(SatoshiPerBitcoin), new(big.Int))
+	// Round half away from zero.
+	if new(big.Int).Abs(remainder).Cmp(big.NewInt(SatoshiPerBitcoin/2)) >= 0 {
+		if product.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return FiatAmount{Currency: p.Currency, Cents: quotient.Int64()}
+}
+
+// String returns the price, e.g. "65000.00 USD/BTC".
+func (p Price) String() string {
+	return FiatAmount{Currency: p.Currency, Cents: p.CentsPerBTC}.String() + "/BTC"
+}
+
+// PriceSource provides the current price of bitcoin in a currency.
+type PriceSource interface {
+	Price(ctx context.Context, currency string) (Price, error)
+}
+
+// PriceSourceFunc adapts a function to a PriceSource.
+type PriceSourceFunc func(ctx context.Context, currency string) (Price, error)
+
+// Price implements PriceSource.
+func (f PriceSourceFunc) Price(ctx context.Context, currency string) (Price, error) {
+	return f(ctx, currency)
+}
+
+// StatusPriceSource is a PriceSource using the exchange rate reported by GetStatus. The daemon only reports the USD price.
+type StatusPriceSource struct {
+	Client Client
+}
+
+// Price implements PriceSource.
+func (s StatusPriceSource) Price(ctx context.Context, currency string) (Price, error) {
+	if !strings.EqualFold(currency, "USD") {
+		return Price{}, fmt.Errorf("the daemon only reports the USD exchange rate, not %s", currency)
+	}
+	status, err := s.Client.WithContext(ctx).GetStatus()
+	if err != nil {
+		return Price{}, err
+	}
+	if status.ExchangeRate.Sign() <= 0 {
+		return Price{}, fmt.Errorf("the daemon reported no exchange rate")
+	}
+	price, err := PriceFromDecimal("USD", status.ExchangeRate)
+	if err != nil {
+		return Price{}, err
+	}
+	price.Time = time.Now()
+	return price, nil
+}
+
+// ValueInUSD returns the value of the amount at the exchange rate reported by the daemon.
+func ValueInUSD(ctx context.Context, c Client, amount Amount) (FiatAmount, error) {
+	price, err := StatusPriceSource{Client: c}.Price(ctx, "USD")
+	if err != nil {
+		return FiatAmount{}, err
+	}
+	return price.Value(amount), nil
+}
+
+// FiatBalance is a balance with the fiat value of each of its parts.
+type FiatBalance struct {
+	Balance
+	Price       Price
+	Total       FiatAmount
+	Confirmed   FiatAmount
+	Unconfirmed FiatAmount
+	Private     FiatAmount
+}
+
+// InFiat returns the balance valued at the price.
+func (b Balance) InFiat(price Price) FiatBalance {
+	return FiatBalance{
+		Balance:     b,
+		Price:       price,
+		Total:       price.Value(b.Total),
+		Confirmed:   price.Value(b.Confirmed),
+		Unconfirmed: price.Value(b.Unconfirmed),
+		Private:     price.Value(b.Private),
+	}
+}
+
+// FiatTransaction is a transaction with the fiat value of its amount.
+type FiatTransaction struct {
+	Transaction
+	Value FiatAmount
+}
+
+// HistoryInFiat returns the transactions valued at the price. The price is the current one, not the one at the time of each transaction.
+func HistoryInFiat(history []Transaction, price Price) []FiatTransaction {
+	valued := make([]FiatTransaction, len(history))
+	for i, tx := range history {
+		valued[i] = FiatTransaction{Transaction: tx, Value: price.Value(tx.Amount)}
+	}
+	return valued
+}
+
+// GetBalanceInFiat returns the balance of the wallet valued at the price of the source in the currency. If source is nil, StatusPriceSource is used.
+func GetBalanceInFiat(ctx context.Context, c Client, walletName string, source PriceSource, currency string) (FiatBalance, error) {
+	c = c.WithContext(ctx)
+	if source == nil {
+		source = StatusPriceSource{Client: c}
+	}
+	price, err := source.Price(ctx, currency)
+	if err != nil {
+		return FiatBalance{}, err
+	}
+	balance, err := GetBalance(c, walletName)
+	if err != nil {
+		return FiatBalance{}, err
+	}
+	return balance.InFiat(price), nil
+}
+
+// GetHistoryInFiat returns the history of the wallet valued at the price of the source in the currency. If source is nil, StatusPriceSource is used.
+func GetHistoryInFiat(ctx context.Context, c Client, walletName string, source PriceSource, currency string) ([]FiatTransaction, error) {
+	c = c.WithContext(ctx)
+	if source == nil {
+		source = StatusPriceSource{Client: c}
+	}
+	price, err := source.Price(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+	history, err := c.GetHistory(walletName)
+	if err != nil {
+		return nil, err
+	}
+	return HistoryInFiat(history, price), nil
+}
@@ -0,0 +1,27 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestValueInUSD(t *testing.T) {
+	c := newTestClient(t, func(method string) (int, string) {
+		return http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"exchangeRate":61840.5}}`
+	})
+	value, err := ValueInUSD(context.Background(), c, 50_000_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (FiatAmount{Currency: "USD", Cents: 3092025}); value != want {
+		t.Fatalf("ValueInUSD() = %v, want %v", value, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ValueInUSD(ctx, c, 50_000_000); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ValueInUSD() with a cancelled context = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,70 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// CaptureMethods are the methods called by Capture. They are read-only, so capturing does not change the wallet.
+var CaptureMethods = []wasabi.Method{
+	wasabi.MethodGetStatus,
+	wasabi.MethodListWallets,
+	wasabi.MethodGetFeeRates,
+	wasabi.MethodGetWalletInfo,
+	wasabi.MethodListCoins,
+	wasabi.MethodListUnspentCoins,
+	wasabi.MethodGetHistory,
+	wasabi.MethodListKeys,
+	wasabi.MethodListPaymentsInCoinJoin,
+}
+
+// Capture calls the CaptureMethods on the daemon (the wallet scoped ones on walletName) and returns their responses as fixtures of the daemon version. Methods the daemon does not support are skipped.
+// The responses hold the addresses, keys and history of the wallet, so capture from a regtest or testnet wallet before committing them.
+func Capture(c wasabi.Client, version string, walletName string) ([]Fixture, error) {
+	var fixtures []Fixture
+	for _, method := range CaptureMethods {
+		spec, ok := wasabi.LookupMethod(method)
+		if !ok {
+			return nil, fmt.Errorf("unknown method %s", method)
+		}
+		name := ""
+		if spec.WalletScoped {
+			name = walletName
+		}
+		var result bytes.Buffer
+		if err := c.CallRaw(name, string(method), nil, &result); err != nil {
+			if errors.Is(err, wasabi.ErrUnsupportedMethod) {
+				continue
+			}
+			return nil, fmt.Errorf("capture %s: %w", method, err)
+		}
+		var body bytes.Buffer
+		raw := []byte(`{"jsonrpc":"2.0","result":` + result.String() + `}`)
+		if err := json.Indent(&body, raw, "", "  "); err != nil {
+			return nil, fmt.Errorf("capture %s: %w", method, err)
+		}
+		body.WriteByte('\n')
+		fixtures = append(fixtures, Fixture{Version: version, Method: method, Body: body.Bytes()})
+	}
+	return fixtures, nil
+}
+
+// Write writes the fixtures to dir in the layout read by Load, replacing the files of the same version and method.
+func Write(dir string, fixtures []Fixture) error {
+	for _, f := range fixtures {
+		versionDir := filepath.Join(dir, f.Version)
+		if err := os.MkdirAll(versionDir, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(versionDir, string(f.Method)+".json"), f.Body, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
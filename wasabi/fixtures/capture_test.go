@@ -0,0 +1,49 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+func TestCapture(t *testing.T) {
+	s := wasabitest.NewServer(wasabitest.ServerOptions{Unsupported: []wasabi.Method{wasabi.MethodListPaymentsInCoinJoin}})
+	defer s.Close()
+	if err := s.AddWallet("w", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Fund("w", 100_000, "funding"); err != nil {
+		t.Fatal(err)
+	}
+	captured, err := Capture(s.Client(), "9.9.9", "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(captured) != len(CaptureMethods)-1 {
+		t.Fatalf("captured %v, want every method but the unsupported one", captured)
+	}
+	for _, f := range captured {
+		if f.Method == wasabi.MethodListPaymentsInCoinJoin {
+			t.Fatalf("captured the unsupported method: %v", captured)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := Write(dir, captured); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(captured) || loaded[0].Version != "9.9.9" {
+		t.Fatalf("Load() = %v, want the captured fixtures", loaded)
+	}
+	Run(t, loaded...)
+	for _, f := range loaded {
+		if unknown, err := UnknownFields(f); err != nil || len(unknown) > 0 {
+			t.Errorf("UnknownFields(%s) = %v, %v, want the fake daemon fields decoded", f, unknown, err)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Package fixtures provides JSON-RPC responses in the formats of several Wasabi daemon versions and a conformance runner checking that the wasabi client decodes them.
+// The embedded fixtures are synthetic: they were written from the rpc documentation and the release notes of each version, not captured from a daemon, so they may miss fields a real daemon sends. They cover the differences between the versions, e.g. the best blockchain height reported as a string by 2.0 daemons and the optional methods added by later versions. Responses captured from a daemon with Capture (or the wasabi-fixtures command) can be checked with Load and Run before upgrading, and replace the synthetic files once captured from the released daemons.
+package fixtures
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+//go:embed data
+var data embed.FS
+
+// Fixture is a JSON-RPC response in the format of a daemon version.
+type Fixture struct {
+	// Version is the daemon version whose format the response follows. For captured fixtures, it is the version of the daemon which sent the response.
+	Version string
+	// Method is the rpc method of the response.
+	Method wasabi.Method
+	// Body is the JSON-RPC response body.
+	Body []byte
+}
+
+// String returns the version and the method of the fixture.
+func (f Fixture) String() string {
+	return f.Version + "/" + string(f.Method)
+}
+
+// All returns the embedded (synthetic) fixtures, sorted by version and method.
+func All() []Fixture {
+	fixtures, err := load(data, "data")
+	if err != nil {
+		// The embedded files are checked by the conformance runner.
+		panic(err)
+	}
+	return fixtures
+}
+
+// Versions returns the daemon versions of the embedded fixtures.
+func Versions() []string {
+	var versions []string
+	for _, f := range All() {
+		if len(versions) == 0 || versions[len(versions)-1] != f.Version {
+			versions = append(versions, f.Version)
+		}
+	}
+	return versions
+}
+
+// ForVersion returns the embedded fixtures of the daemon version.
+func ForVersion(version string) []Fixture {
+	var fixtures []Fixture
+	for _, f := range All() {
+		if f.Version == version {
+			fixtures = append(fixtures, f)
+		}
+	}
+	return fixtures
+}
+
+// Load reads the fixtures in dir, which must have the layout of the embedded fixtures: a directory per daemon version holding a <method>.json file per response.
+func Load(dir string) ([]Fixture, error) {
+	return load(os.DirFS(dir), ".")
+}
+
+func load(fsys fs.FS, root string) ([]Fixture, error) {
+	versions, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	for _, version := range versions {
+		if !version.IsDir() {
+			continue
+		}
+		files, err := fs.ReadDir(fsys, path.Join(root, version.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if file.IsDir() || path.Ext(file.Name()) != ".json" {
+				continue
+			}
+			body, err := fs.ReadFile(fsys, path.Join(root, version.Name(), file.Name()))
+			if err != nil {
+				return nil, err
+			}
+			fixtures = append(fixtures, Fixture{
+				Version: version.Name(),
+				Method:  wasabi.Method(strings.TrimSuffix(file.Name(), ".json")),
+				Body:    body,
+			})
+		}
+	}
+	sort.SliceStable(fixtures, func(i, j int) bool {
+		if fixtures[i].Version != fixtures[j].Version {
+			return compareVersions(fixtures[i].Version, fixtures[j].Version) < 0
+		}
+		return fixtures[i].Method < fixtures[j].Method
+	})
+	return fixtures, nil
+}
+
+// compareVersions compares dotted versions numerically, so 2.10.0 sorts after 2.9.0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if len(as[i]) != len(bs[i]) {
+			return len(as[i]) - len(bs[i])
+		}
+		if c := strings.Compare(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// Decode decodes the fixture with the wasabi client, as the result of its method would be decoded when calling the daemon. It returns the decoded result (nil for methods without result).
+func Decode(f Fixture) (interface{}, error) {
+	spec, ok := wasabi.LookupMethod(f.Method)
+	if !ok {
+		return nil, fmt.Errorf("unknown method %s", f.Method)
+	}
+	c, err := wasabi.NewClient(wasabi.Config{
+		Host:                "fixtures.invalid",
+		Transport:           fixtureTransport{f},
+		DisableNetworkGuard: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	walletName := ""
+	if spec.WalletScoped {
+		walletName = "fixture"
+	}
+	if spec.Result == nil {
+		return nil, c.RawCall(walletName, string(f.Method), nil, nil)
+	}
+	out := reflect.New(spec.Result)
+	if err := c.RawCall(walletName, string(f.Method), nil, out.Interface()); err != nil {
+		return nil, err
+	}
+	return out.Elem().Interface(), nil
+}
+
+// UnknownFields returns the JSON names of the fields of the fixture result which the client does not decode, e.g. fields added by newer daemons. Unknown fields do not make decoding fail, but their information is lost.
+func UnknownFields(f Fixture) ([]string, error) {
+	spec, ok := wasabi.LookupMethod(f.Method)
+	if !ok {
+		return nil, fmt.Errorf("unknown method %s", f.Method)
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(f.Body, &response); err != nil {
+		return nil, err
+	}
+	if spec.Result == nil {
+		return nil, nil
+	}
+	fields := map[string]bool{}
+	collectUnknownFields(response.Result, spec.Result, "", fields)
+	unknown := make([]string, 0, len(fields))
+	for field := range fields {
+		unknown = append(unknown, field)
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// collectUnknownFields adds the keys of the JSON objects in raw which have no field in t, prefixed with their path (e.g. [].foo for the items of a list).
+func collectUnknownFields(raw json.RawMessage, t reflect.Type, prefix string, unknown map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) != nil {
+			return
+		}
+		for _, item := range items {
+			collectUnknownFields(item, t.Elem(), prefix+"[]", unknown)
+		}
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(raw, &object) != nil {
+			return
+		}
+		known := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			known[strings.ToLower(name)] = field.Type
+		}
+		for key, value := range object {
+			name := key
+			if prefix != "" {
+				name = prefix + "." + key
+			}
+			fieldType, ok := known[strings.ToLower(key)]
+			if !ok {
+				unknown[name] = true
+				continue
+			}
+			collectUnknownFields(value, fieldType, name, unknown)
+		}
+	}
+}
+
+// Run checks in a subtest per fixture that the client decodes the fixture. Unknown fields are logged. If no fixtures are given, the embedded fixtures are checked.
+func Run(t *testing.T, fixtures ...Fixture) {
+	t.Helper()
+	if len(fixtures) == 0 {
+		fixtures = All()
+	}
+	for _, f := range fixtures {
+		f := f
+		t.Run(f.String(), func(t *testing.T) {
+			if _, err := Decode(f); err != nil {
+				t.Fatalf("decode %s: %v", f, err)
+			}
+			unknown, err := UnknownFields(f)
+			if err != nil {
+				t.Fatalf("unknown fields of %s: %v", f, err)
+			}
+			if len(unknown) > 0 {
+				t.Logf("fields not decoded by the client: %s", strings.Join(unknown, ", "))
+			}
+		})
+	}
+}
+
+// fixtureTransport answers the call of the fixture method with the fixture body. Other calls, i.e. the capability probes, fail with an invalid params error, so the probed methods are considered supported.
+type fixtureTransport struct {
+	fixture Fixture
+}
+
+func (t fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var request struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return nil, fmt.Errorf("fixtures support single requests only: %w", err)
+		}
+	}
+	var body []byte
+	if wasabi.Method(request.Method) == t.fixture.Method {
+		var response map[string]json.RawMessage
+		if err := json.Unmarshal(t.fixture.Body, &response); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", t.fixture, err)
+		}
+		response["id"] = request.ID
+		body, _ = json.Marshal(response)
+	} else {
+		body, _ = json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"error":   map[string]interface{}{"code": -32602, "message": "invalid params"},
+			"id":      request.ID,
+		})
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
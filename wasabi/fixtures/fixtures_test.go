@@ -0,0 +1,7 @@
+package fixtures
+
+import "testing"
+
+func TestConformance(t *testing.T) {
+	Run(t)
+}
@@ -0,0 +1,197 @@
+// Package gateway exposes a Wasabi daemon as a REST API, so services written in any language can use it without speaking JSON-RPC.
+//
+//	GET  /status                          GET  /wallets/{name}/coins?unspent=true
+//	GET  /wallets                         POST /wallets/{name}/send
+//	POST /wallets/{name}/addresses        GET  /openapi.json
+//
+// Amounts are in satoshis. Errors are returned as {"error": "...", "category": "..."} with a status code matching the error category of the client (see wasabi.Classify). The OpenAPI description of all endpoints is served at /openapi.json and returned by Spec.
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// maxBodySize is the maximum size of a request body.
+const maxBodySize = 1 << 20
+
+// Options holds the options of a Gateway.
+type Options struct {
+	// Client calls the daemon.
+	Client wasabi.Client
+	// ReadOnly rejects the endpoints which change the state of a wallet or the daemon with 403.
+	ReadOnly bool
+	// Token is the bearer token required in the Authorization header. If empty, requests are not authenticated.
+	Token string
+}
+
+// Gateway is an http.Handler serving the REST API.
+type Gateway struct {
+	opts Options
+}
+
+// New creates a gateway.
+func New(opts Options) (*Gateway, error) {
+	if opts.Client == nil {
+		return nil, fmt.Errorf("client must not be nil")
+	}
+	return &Gateway{opts: opts}, nil
+}
+
+// request is a matched request passed to the handler of a route.
+type request struct {
+	http   *http.Request
+	client wasabi.Client
+	params map[string]string
+}
+
+// decode decodes the JSON body into v.
+func (r *request) decode(v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.http.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return &httpError{status: http.StatusBadRequest, err: fmt.Errorf("invalid body: %w", err)}
+	}
+	return nil
+}
+
+// httpError is an error with its http status.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &httpError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if g.opts.Token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+g.opts.Token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, &httpError{status: http.StatusUnauthorized, err: errors.New("unauthorized")})
+		return
+	}
+	if r.Method == http.MethodGet && r.URL.Path == "/openapi.json" {
+		writeJSON(w, http.StatusOK, Spec())
+		return
+	}
+	rt, params, allowed := match(r.Method, r.URL.Path)
+	if rt == nil {
+		if allowed != "" {
+			w.Header().Set("Allow", allowed)
+			writeError(w, &httpError{status: http.StatusMethodNotAllowed, err: errors.New("method not allowed")})
+			return
+		}
+		writeError(w, &httpError{status: http.StatusNotFound, err: errors.New("not found")})
+		return
+	}
+	if rt.mutating && g.opts.ReadOnly {
+		writeError(w, &httpError{status: http.StatusForbidden, err: errors.New("the gateway is read-only")})
+		return
+	}
+	result, err := rt.handle(&request{http: r, client: g.opts.Client.WithContext(r.Context()), params: params})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	status := http.StatusOK
+	if rt.method == http.MethodPost && rt.created {
+		status = http.StatusCreated
+	}
+	if result == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, status, result)
+}
+
+// match returns the route of the method and path and its path parameters. If only the method does not match, it returns the allowed methods.
+func match(method, path string) (*route, map[string]string, string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var allowed []string
+	for i := range routes {
+		rt := &routes[i]
+		params, ok := matchPattern(rt.pattern, segments)
+		if !ok {
+			continue
+		}
+		if rt.method != method {
+			allowed = append(allowed, rt.method)
+			continue
+		}
+		return rt, params, ""
+	}
+	return nil, nil, strings.Join(allowed, ", ")
+}
+
+// matchPattern matches the path segments with a pattern like /wallets/{name}/coins.
+func matchPattern(pattern string, segments []string) (map[string]string, bool) {
+	parts := strings.Split(strings.Trim(pattern, "/"), "/")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[strings.Trim(part, "{}")] = segments[i]
+		} else if part != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// errorBody is the body of an error response.
+type errorBody struct {
+	Error    string `json:"error"`
+	Category string `json:"category,omitempty"`
+}
+
+// writeError writes the error with the status of its category: 403 for calls rejected by a read-only client, 422 for rejections by the wallet, 501 for methods unsupported by the daemon, 502 for daemon and protocol errors and 503 if the daemon is unreachable.
+func writeError(w http.ResponseWriter, err error) {
+	var httpErr *httpError
+	if errors.As(err, &httpErr) {
+		writeJSON(w, httpErr.status, errorBody{Error: httpErr.Error()})
+		return
+	}
+	category := wasabi.Classify(err)
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, wasabi.ErrUnsupportedMethod):
+		status = http.StatusNotImplemented
+	case errors.Is(err, wasabi.ErrReadOnly):
+		status = http.StatusForbidden
+	case category == wasabi.ErrorCategoryWallet:
+		status = http.StatusUnprocessableEntity
+	case category == wasabi.ErrorCategoryDaemon || category == wasabi.ErrorCategoryProtocol:
+		status = http.StatusBadGateway
+	case category == wasabi.ErrorCategoryConnection:
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, errorBody{Error: err.Error(), Category: category.String()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// typeOf returns the type of v, for the bodies of the routes.
+func typeOf(v interface{}) reflect.Type {
+	return reflect.TypeOf(v)
+}
@@ -0,0 +1,175 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+// newTestGateway serves a gateway of a fake daemon with the wallet "w" (password "pw") holding 1000000 sat.
+func newTestGateway(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	s := wasabitest.NewServer(wasabitest.ServerOptions{Unsupported: []wasabi.Method{wasabi.MethodListKeys}})
+	t.Cleanup(s.Close)
+	if err := s.AddWallet("w", "pw"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Fund("w", 1_000_000, "salary"); err != nil {
+		t.Fatal(err)
+	}
+	s.Mine(1)
+	opts.Client = s.Client()
+	g, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// do sends the request to the gateway and returns the status and the body.
+func do(t *testing.T, server *httptest.Server, method, path, body string, header http.Header) (int, string, http.Header) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data), resp.Header
+}
+
+func TestGateway(t *testing.T) {
+	server := newTestGateway(t, Options{})
+	address := func() string {
+		status, body, _ := do(t, server, http.MethodPost, "/wallets/w/addresses", `{"label":"self"}`, nil)
+		if status != http.StatusCreated {
+			t.Fatalf("POST /wallets/w/addresses = %d %s", status, body)
+		}
+		var resp wasabi.GetNewAddressResponse
+		json.Unmarshal([]byte(body), &resp)
+		return string(resp.Address)
+	}()
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		wantStatus   int
+		wantBody     string
+	}{
+		{name: "status", method: http.MethodGet, path: "/status", wantStatus: http.StatusOK, wantBody: `"backendStatus":"Connected"`},
+		{name: "unspent coins", method: http.MethodGet, path: "/wallets/w/coins?unspent=true", wantStatus: http.StatusOK, wantBody: `"amount":1000000`},
+		{name: "invalid query", method: http.MethodGet, path: "/wallets/w/coins?unspent=maybe", wantStatus: http.StatusBadRequest, wantBody: `invalid unspent: \"maybe\"`},
+		{name: "balance", method: http.MethodGet, path: "/wallets/w/balance", wantStatus: http.StatusOK, wantBody: `"Confirmed":1000000`},
+		{name: "unknown field", method: http.MethodPost, path: "/wallets/w/send", body: `{"payments":[],"passwd":"pw"}`, wantStatus: http.StatusBadRequest, wantBody: `unknown field`},
+		{name: "wrong password", method: http.MethodPost, path: "/wallets/w/send", body: `{"payments":[{"sendto":"` + address + `","amount":1000}],"feeTarget":6,"password":"wrong"}`, wantStatus: http.StatusUnprocessableEntity, wantBody: `"category":"wallet"`},
+		{name: "send", method: http.MethodPost, path: "/wallets/w/send", body: `{"payments":[{"sendto":"` + address + `","amount":1000}],"feeTarget":6,"password":"pw"}`, wantStatus: http.StatusOK, wantBody: `"txid"`},
+		{name: "unsupported method", method: http.MethodGet, path: "/wallets/w/keys", wantStatus: http.StatusNotImplemented},
+		{name: "missing wallet", method: http.MethodGet, path: "/wallets/missing", wantStatus: http.StatusBadGateway, wantBody: `"category":"daemon"`},
+		{name: "no content", method: http.MethodPost, path: "/wallets/w/coinjoin/stop", wantStatus: http.StatusNoContent},
+		{name: "not found", method: http.MethodGet, path: "/wallets/w/unknown", wantStatus: http.StatusNotFound},
+		{name: "empty path parameter", method: http.MethodGet, path: "/wallets//coins", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body, header := do(t, server, tt.method, tt.path, tt.body, nil)
+			if status != tt.wantStatus || !strings.Contains(body, tt.wantBody) {
+				t.Fatalf("%s %s = %d %s, want %d with %s", tt.method, tt.path, status, body, tt.wantStatus, tt.wantBody)
+			}
+			if status != http.StatusNoContent && header.Get("Content-Type") != "application/json" {
+				t.Fatalf("Content-Type = %q", header.Get("Content-Type"))
+			}
+		})
+	}
+
+	status, _, header := do(t, server, http.MethodDelete, "/wallets/w", "", nil)
+	if status != http.StatusMethodNotAllowed || header.Get("Allow") != http.MethodGet {
+		t.Fatalf("DELETE /wallets/w = %d, Allow %q, want 405 allowing GET", status, header.Get("Allow"))
+	}
+}
+
+func TestGatewayAuth(t *testing.T) {
+	server := newTestGateway(t, Options{Token: "secret"})
+	status, _, header := do(t, server, http.MethodGet, "/status", "", nil)
+	if status != http.StatusUnauthorized || header.Get("WWW-Authenticate") != "Bearer" {
+		t.Fatalf("GET /status without token = %d, want 401 with a bearer challenge", status)
+	}
+	if status, _, _ := do(t, server, http.MethodGet, "/status", "", http.Header{"Authorization": {"Bearer wrong"}}); status != http.StatusUnauthorized {
+		t.Fatalf("GET /status with a wrong token = %d, want 401", status)
+	}
+	if status, body, _ := do(t, server, http.MethodGet, "/status", "", http.Header{"Authorization": {"Bearer secret"}}); status != http.StatusOK {
+		t.Fatalf("GET /status with the token = %d %s", status, body)
+	}
+}
+
+func TestGatewayReadOnly(t *testing.T) {
+	server := newTestGateway(t, Options{ReadOnly: true})
+	if status, _, _ := do(t, server, http.MethodPost, "/wallets/w/addresses", `{}`, nil); status != http.StatusForbidden {
+		t.Fatalf("POST /wallets/w/addresses of a read-only gateway = %d, want 403", status)
+	}
+	// The endpoints reading the wallet are allowed.
+	if status, body, _ := do(t, server, http.MethodGet, "/wallets/w/history", "", nil); status != http.StatusOK {
+		t.Fatalf("GET /wallets/w/history of a read-only gateway = %d %s", status, body)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Options{}); err == nil {
+		t.Fatal("New() without client = nil error")
+	}
+}
+
+func TestSpec(t *testing.T) {
+	server := newTestGateway(t, Options{})
+	status, body, _ := do(t, server, http.MethodGet, "/openapi.json", "", nil)
+	if status != http.StatusOK {
+		t.Fatalf("GET /openapi.json = %d", status)
+	}
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(body), &spec); err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	for _, rt := range routes {
+		op, ok := spec.Paths[rt.pattern][strings.ToLower(rt.method)]
+		if !ok {
+			t.Errorf("the spec misses %s %s", rt.method, rt.pattern)
+			continue
+		}
+		if ids[op.OperationID] {
+			t.Errorf("duplicate operation id %s", op.OperationID)
+		}
+		ids[op.OperationID] = true
+	}
+	if id := spec.Paths["/wallets/{name}/send"]["post"].OperationID; id != "postWalletsNameSend" {
+		t.Errorf("operation id = %s, want postWalletsNameSend", id)
+	}
+	if len(spec.Components.Schemas) == 0 {
+		t.Error("the spec has no component schemas")
+	}
+}
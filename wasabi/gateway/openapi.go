@@ -0,0 +1,178 @@
+package gateway
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Spec returns the OpenAPI 3 description of the gateway, generated from its routes and the types of their bodies. It marshals to the JSON served at /openapi.json.
+func Spec() map[string]interface{} {
+	g := &schemaGenerator{components: map[string]interface{}{}}
+	paths := map[string]map[string]interface{}{}
+	for _, rt := range routes {
+		op := map[string]interface{}{
+			"summary":     rt.summary,
+			"operationId": operationID(rt),
+			"responses":   g.responses(rt),
+		}
+		var params []interface{}
+		for _, part := range strings.Split(rt.pattern, "/") {
+			if strings.HasPrefix(part, "{") {
+				params = append(params, map[string]interface{}{"name": strings.Trim(part, "{}"), "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"}})
+			}
+		}
+		for _, q := range rt.query {
+			params = append(params, map[string]interface{}{"name": q.name, "in": "query", "description": q.description, "schema": map[string]interface{}{"type": q.kind}})
+		}
+		if params != nil {
+			op["parameters"] = params
+		}
+		if rt.body != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": g.schema(rt.body)}},
+			}
+		}
+		if paths[rt.pattern] == nil {
+			paths[rt.pattern] = map[string]interface{}{}
+		}
+		paths[rt.pattern][strings.ToLower(rt.method)] = op
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Wasabi Wallet REST gateway",
+			"version":     "1.0.0",
+			"description": "REST API of a Wasabi Wallet daemon. Amounts are in satoshis.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas":         g.components,
+			"securitySchemes": map[string]interface{}{"bearer": map[string]interface{}{"type": "http", "scheme": "bearer"}},
+		},
+	}
+}
+
+// operationID returns the operation id of the route, e.g. postWalletsNameSend.
+func operationID(rt route) string {
+	id := strings.ToLower(rt.method)
+	for _, part := range strings.FieldsFunc(rt.pattern, func(r rune) bool { return r == '/' || r == '{' || r == '}' || r == '-' }) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
+
+// schemaGenerator converts Go types into OpenAPI schemas. Named structs become components referenced by name.
+type schemaGenerator struct {
+	components map[string]interface{}
+}
+
+func (g *schemaGenerator) responses(rt route) map[string]interface{} {
+	errorResponse := map[string]interface{}{
+		"description": "Error",
+		"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": g.schema(reflect.TypeOf(errorBody{}))}},
+	}
+	responses := map[string]interface{}{"default": errorResponse}
+	switch {
+	case rt.result == nil:
+		responses["204"] = map[string]interface{}{"description": "Done"}
+	case rt.method == http.MethodPost && rt.created:
+		responses["201"] = g.response(rt.result)
+	default:
+		responses["200"] = g.response(rt.result)
+	}
+	return responses
+}
+
+func (g *schemaGenerator) response(t reflect.Type) map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Success",
+		"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": g.schema(t)}},
+	}
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	amountType = reflect.TypeOf(wasabi.Amount(0))
+)
+
+// schema returns the schema of the type.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case amountType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "Amount in satoshis"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		s["nullable"] = true
+		return s
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		name := componentName(t)
+		if _, ok := g.components[name]; !ok {
+			// Reserve the name before the fields, for recursive types.
+			g.components[name] = nil
+			g.components[name] = g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// object returns the schema of the fields of a struct, named like encoding/json names them.
+func (g *schemaGenerator) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schema(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+	s := map[string]interface{}{"type": "object", "properties": properties}
+	if required != nil {
+		s["required"] = required
+	}
+	return s
+}
+
+// componentName returns the name of the component of a struct type: the exported name of wasabi types, or the name of gateway bodies with an upper case first letter.
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Object"
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,271 @@
+package gateway
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// route is an endpoint of the gateway.
+type route struct {
+	method  string
+	pattern string
+	summary string
+	// query are the query parameters, described in the spec.
+	query []queryParam
+	// body and result are the types of the request and response bodies. A nil result means 204 No Content.
+	body   reflect.Type
+	result reflect.Type
+	// mutating routes are rejected by a read-only gateway.
+	mutating bool
+	// created routes answer 201.
+	created bool
+	handle  func(r *request) (interface{}, error)
+}
+
+// queryParam is a query parameter of a route.
+type queryParam struct {
+	name        string
+	kind        string
+	description string
+}
+
+// createWalletBody is the body of POST /wallets.
+type createWalletBody struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// recoverWalletBody is the body of POST /wallets/recover.
+type recoverWalletBody struct {
+	Name     string `json:"name"`
+	Mnemonic string `json:"mnemonic"`
+	Password string `json:"password"`
+}
+
+// mnemonicResult is the result of POST /wallets.
+type mnemonicResult struct {
+	Mnemonic string `json:"mnemonic"`
+}
+
+// addressBody is the body of POST /wallets/{name}/addresses.
+type addressBody struct {
+	Label string `json:"label"`
+}
+
+// transactionBody is the body of the endpoints building transactions.
+type transactionBody struct {
+	Payments  []wasabi.Payment `json:"payments"`
+	Coins     []wasabi.Coin    `json:"coins,omitempty"`
+	FeeTarget wasabi.FeeTarget `json:"feeTarget,omitempty"`
+	FeeRate   float64          `json:"feeRate,omitempty"`
+	Password  string           `json:"password"`
+}
+
+// txResult is the result of the endpoints returning a transaction.
+type txResult struct {
+	Tx string `json:"tx"`
+}
+
+// broadcastBody is the body of POST /broadcast.
+type broadcastBody struct {
+	Tx string `json:"tx"`
+}
+
+// txidResult is the result of POST /broadcast.
+type txidResult struct {
+	TxID wasabi.TxID `json:"txid"`
+}
+
+// passwordBody is the body of the endpoints only needing the password.
+type passwordBody struct {
+	Password string `json:"password"`
+}
+
+// startCoinJoinBody is the body of POST /wallets/{name}/coinjoin/start.
+type startCoinJoinBody struct {
+	Password         string `json:"password"`
+	StopWhenAllMixed bool   `json:"stopWhenAllMixed,omitempty"`
+	OverridePlebStop bool   `json:"overridePlebStop,omitempty"`
+}
+
+// excludeBody is the body of POST /wallets/{name}/coins/exclude.
+type excludeBody struct {
+	Coins   []wasabi.Coin `json:"coins"`
+	Exclude bool          `json:"exclude"`
+}
+
+// paymentBody is the body of POST /wallets/{name}/payments-in-coinjoin.
+type paymentBody struct {
+	Address  wasabi.Address `json:"address"`
+	Amount   wasabi.Amount  `json:"amount"`
+	Password string         `json:"password"`
+}
+
+// paymentResult is the result of POST /wallets/{name}/payments-in-coinjoin.
+type paymentResult struct {
+	ID string `json:"id"`
+}
+
+// routes are the endpoints of the gateway.
+var routes = []route{
+	{method: http.MethodGet, pattern: "/status", summary: "Status of the daemon", result: typeOf(wasabi.GetStatusResponse{}), handle: func(r *request) (interface{}, error) {
+		return r.client.GetStatus()
+	}},
+	{method: http.MethodGet, pattern: "/fee-rates", summary: "Fee rates in sat/vB by confirmation target", result: typeOf(wasabi.GetFeeRatesResponse{}), handle: func(r *request) (interface{}, error) {
+		return r.client.GetFeeRates()
+	}},
+	{method: http.MethodPost, pattern: "/broadcast", summary: "Broadcast a signed transaction", body: typeOf(broadcastBody{}), result: typeOf(txidResult{}), mutating: true, handle: func(r *request) (interface{}, error) {
+		var body broadcastBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		txid, err := r.client.Broadcast("", body.Tx)
+		if err != nil {
+			return nil, err
+		}
+		return txidResult{TxID: txid}, nil
+	}},
+	{method: http.MethodGet, pattern: "/wallets", summary: "List the wallets", result: typeOf([]wasabi.ListWalletsResponseItem{}), handle: func(r *request) (interface{}, error) {
+		return r.client.ListWallets()
+	}},
+	{method: http.MethodPost, pattern: "/wallets", summary: "Create a wallet and return its mnemonic", body: typeOf(createWalletBody{}), result: typeOf(mnemonicResult{}), mutating: true, created: true, handle: func(r *request) (interface{}, error) {
+		var body createWalletBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		mnemonic, err := r.client.CreateWallet(body.Name, body.Password)
+		if err != nil {
+			return nil, err
+		}
+		return mnemonicResult{Mnemonic: mnemonic}, nil
+	}},
+	{method: http.MethodPost, pattern: "/wallets/recover", summary: "Recover a wallet from its mnemonic", body: typeOf(recoverWalletBody{}), mutating: true, handle: func(r *request) (interface{}, error) {
+		var body recoverWalletBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		return nil, r.client.RecoverWallet(body.Name, body.Mnemonic, body.Password)
+	}},
+	{method: http.MethodGet, pattern: "/wallets/{name}", summary: "Information about the wallet", result: typeOf(wasabi.GetWalletInfoResponse{}), handle: func(r *request) (interface{}, error) {
+		return r.client.GetWalletInfo(r.params["name"])
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/load", summary: "Load the wallet", handle: func(r *request) (interface{}, error) {
+		return nil, r.client.LoadWallet(r.params["name"])
+	}},
+	{method: http.MethodGet, pattern: "/wallets/{name}/balance", summary: "Balance of the wallet", result: typeOf(wasabi.Balance{}), handle: func(r *request) (interface{}, error) {
+		return wasabi.GetBalance(r.client, r.params["name"])
+	}},
+	{method: http.MethodGet, pattern: "/wallets/{name}/coins", summary: "Coins of the wallet", query: []queryParam{{name: "unspent", kind: "boolean", description: "Only list the unspent coins"}}, result: typeOf([]wasabi.ListCoinsResponse{}), handle: func(r *request) (interface{}, error) {
+		unspent, err := boolQuery(r, "unspent")
+		if err != nil {
+			return nil, err
+		}
+		if unspent {
+			return r.client.ListUnspentCoins(r.params["name"])
+		}
+		return r.client.ListCoins(r.params["name"])
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/coins/exclude", summary: "Exclude coins from coinjoins or include them again", body: typeOf(excludeBody{}), mutating: true, handle: func(r *request) (interface{}, error) {
+		var body excludeBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		return nil, r.client.ExcludeCoinsFromCoinJoin(r.params["name"], body.Coins, body.Exclude)
+	}},
+	{method: http.MethodGet, pattern: "/wallets/{name}/history", summary: "Transactions of the wallet", result: typeOf([]wasabi.Transaction{}), handle: func(r *request) (interface{}, error) {
+		return r.client.GetHistory(r.params["name"])
+	}},
+	{method: http.MethodGet, pattern: "/wallets/{name}/keys", summary: "Keys generated by the wallet", result: typeOf([]wasabi.GeneratedKey{}), handle: func(r *request) (interface{}, error) {
+		return r.client.ListKeys(r.params["name"])
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/addresses", summary: "Generate a receiving address", body: typeOf(addressBody{}), result: typeOf(wasabi.GetNewAddressResponse{}), mutating: true, created: true, handle: func(r *request) (interface{}, error) {
+		var body addressBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		return r.client.GetNewAddress(r.params["name"], body.Label)
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/send", summary: "Build and broadcast a transaction", body: typeOf(transactionBody{}), result: typeOf(wasabi.SendResponse{}), mutating: true, handle: func(r *request) (interface{}, error) {
+		var body transactionBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		return r.client.Send(r.params["name"], wasabi.SendRequest{Payments: body.Payments, Coins: body.Coins, FeeTarget: body.FeeTarget, FeeRate: body.FeeRate, Password: body.Password})
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/build", summary: "Build a transaction without broadcasting it", body: typeOf(transactionBody{}), result: typeOf(txResult{}), handle: func(r *request) (interface{}, error) {
+		var body transactionBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		tx, err := r.client.Build(r.params["name"], wasabi.BuildRequest{Payments: body.Payments, Coins: body.Coins, FeeTarget: body.FeeTarget, FeeRate: body.FeeRate, Password: body.Password})
+		if err != nil {
+			return nil, err
+		}
+		return txResult{Tx: tx}, nil
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/transactions/{txid}/speedup", summary: "Build a transaction speeding up the transaction", body: typeOf(passwordBody{}), result: typeOf(txResult{}), handle: func(r *request) (interface{}, error) {
+		var body passwordBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		tx, err := r.client.SpeedUpTransaction(r.params["name"], wasabi.TxID(r.params["txid"]), body.Password)
+		if err != nil {
+			return nil, err
+		}
+		return txResult{Tx: tx}, nil
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/transactions/{txid}/cancel", summary: "Build a transaction cancelling the transaction", body: typeOf(passwordBody{}), result: typeOf(txResult{}), handle: func(r *request) (interface{}, error) {
+		var body passwordBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		tx, err := r.client.CancelTransaction(r.params["name"], wasabi.TxID(r.params["txid"]), body.Password)
+		if err != nil {
+			return nil, err
+		}
+		return txResult{Tx: tx}, nil
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/coinjoin/start", summary: "Start coinjoining", body: typeOf(startCoinJoinBody{}), mutating: true, handle: func(r *request) (interface{}, error) {
+		var body startCoinJoinBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		return nil, r.client.StartCoinJoin(r.params["name"], body.Password, wasabi.StartCoinJoinOptions{StopWhenAllMixed: body.StopWhenAllMixed, OverridePlebStop: body.OverridePlebStop})
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/coinjoin/stop", summary: "Stop coinjoining", mutating: true, handle: func(r *request) (interface{}, error) {
+		return nil, r.client.StopCoinJoin(r.params["name"])
+	}},
+	{method: http.MethodGet, pattern: "/wallets/{name}/payments-in-coinjoin", summary: "Payments in coinjoin of the wallet", result: typeOf([]wasabi.ListPaymentsInCoinJoinResponseItem{}), handle: func(r *request) (interface{}, error) {
+		return r.client.ListPaymentsInCoinJoin(r.params["name"])
+	}},
+	{method: http.MethodPost, pattern: "/wallets/{name}/payments-in-coinjoin", summary: "Pay in a coinjoin", body: typeOf(paymentBody{}), result: typeOf(paymentResult{}), mutating: true, created: true, handle: func(r *request) (interface{}, error) {
+		var body paymentBody
+		if err := r.decode(&body); err != nil {
+			return nil, err
+		}
+		id, err := r.client.PayInCoinJoin(r.params["name"], body.Address, body.Amount, body.Password)
+		if err != nil {
+			return nil, err
+		}
+		return paymentResult{ID: id}, nil
+	}},
+	{method: http.MethodDelete, pattern: "/wallets/{name}/payments-in-coinjoin/{id}", summary: "Cancel a payment in coinjoin", mutating: true, handle: func(r *request) (interface{}, error) {
+		return nil, r.client.CancelPaymentInCoinJoin(r.params["name"], r.params["id"])
+	}},
+}
+
+// boolQuery returns the boolean query parameter, false if it is missing.
+func boolQuery(r *request, name string) (bool, error) {
+	value := r.http.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, badRequest("invalid %s: %q", name, value)
+	}
+	return b, nil
+}
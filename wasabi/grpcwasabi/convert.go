@@ -0,0 +1,177 @@
+package grpcwasabi
+
+import (
+	"strconv"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/grpcwasabi/wasabipb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func statusToPB(st wasabi.GetStatusResponse) *wasabipb.Status {
+	pb := &wasabipb.Status{
+		TorStatus:            string(st.TorStatus),
+		BackendStatus:        string(st.BackendStatus),
+		BestBlockchainHeight: st.BestBlockchainHeight,
+		BestBlockchainHash:   st.BestBlockchainHash,
+		FiltersCount:         int64(st.FiltersCount),
+		FiltersLeft:          int64(st.FiltersLeft),
+		Network:              string(st.Network),
+		ExchangeRate:         st.ExchangeRate.Float64(),
+	}
+	for _, peer := range st.Peers {
+		pb.Peers = append(pb.Peers, &wasabipb.Peer{
+			IsConnected: peer.IsConnected,
+			LastSeen:    timestamppb.New(peer.LastSeen),
+			Endpoint:    peer.Endpoint,
+			UserAgent:   peer.UserAgent,
+		})
+	}
+	return pb
+}
+
+// feeRatesToPB converts the fee rates, skipping the confirmation targets which are not numbers.
+func feeRatesToPB(rates wasabi.GetFeeRatesResponse) *wasabipb.GetFeeRatesResponse {
+	pb := &wasabipb.GetFeeRatesResponse{FeeRates: map[int32]int64{}}
+	for target, rate := range rates {
+		blocks, err := strconv.ParseInt(target, 10, 32)
+		if err != nil {
+			continue
+		}
+		pb.FeeRates[int32(blocks)] = int64(rate)
+	}
+	return pb
+}
+
+func walletInfoToPB(info wasabi.GetWalletInfoResponse) *wasabipb.WalletInfo {
+	pb := &wasabipb.WalletInfo{
+		WalletName:           info.WalletName,
+		WalletFile:           info.WalletFile,
+		State:                string(info.State),
+		MasterKeyFingerprint: info.MasterKeyFingerprint,
+		AnonScoreTarget:      int32(info.AnonScoreTarget),
+		IsWatchOnly:          info.IsWatchOnly,
+		IsHardwareWallet:     info.IsHardwareWallet,
+		IsAutoCoinjoin:       info.IsAutoCoinJoin,
+		IsRedCoinIsolation:   info.IsRedCoinIsolation,
+		Balance:              int64(info.Balance),
+		CoinjoinStatus:       string(info.CoinJoinStatus),
+	}
+	for _, account := range info.Accounts {
+		pb.Accounts = append(pb.Accounts, &wasabipb.Account{Name: account.Name, PublicKey: account.PublicKey, KeyPath: account.KeyPath})
+	}
+	return pb
+}
+
+func coinToPB(coin wasabi.ListCoinsResponse) *wasabipb.Coin {
+	pb := &wasabipb.Coin{
+		Txid:                 string(coin.TxID),
+		Index:                int32(coin.Index),
+		Amount:               int64(coin.Amount),
+		AnonymityScore:       coin.AnonymityScore,
+		Confirmed:            coin.Confirmed,
+		Confirmations:        int32(coin.Confirmations),
+		KeyPath:              coin.KeyPath.String(),
+		Address:              string(coin.Address),
+		Label:                coin.Label,
+		ExcludedFromCoinjoin: coin.ExcludedFromCoinJoin,
+	}
+	if coin.SpentBy != nil {
+		pb.SpentBy = string(*coin.SpentBy)
+	}
+	return pb
+}
+
+func transactionToPB(tx wasabi.Transaction) *wasabipb.Transaction {
+	return &wasabipb.Transaction{
+		Datetime:         timestamppb.New(tx.DateTime),
+		Height:           int32(tx.Height),
+		Amount:           int64(tx.Amount),
+		Label:            tx.Label,
+		Txid:             string(tx.TxID),
+		IsLikelyCoinjoin: tx.IsLikelyCoinJoin,
+	}
+}
+
+func keyToPB(key wasabi.GeneratedKey) *wasabipb.Key {
+	return &wasabipb.Key{
+		FullKeyPath:  key.FullKeyPath.String(),
+		Internal:     key.Internal,
+		KeyState:     int32(key.KeyState),
+		Label:        key.Label,
+		ScriptPubKey: string(key.ScriptPubKey),
+		PubKey:       key.PubKey,
+		PubKeyHash:   key.PubKeyHash,
+		Address:      string(key.Address),
+	}
+}
+
+func paymentInCoinJoinToPB(payment wasabi.ListPaymentsInCoinJoinResponseItem) *wasabipb.PaymentInCoinJoin {
+	pb := &wasabipb.PaymentInCoinJoin{
+		Id:          payment.ID,
+		Amount:      int64(payment.Amount),
+		Destination: string(payment.Destination),
+		Address:     string(payment.Address),
+	}
+	for _, state := range payment.State {
+		pb.State = append(pb.State, &wasabipb.PaymentState{Status: string(state.Status), Round: int32(state.Round), Txid: string(state.TxID)})
+	}
+	return pb
+}
+
+func paymentsFromPB(payments []*wasabipb.Payment) []wasabi.Payment {
+	var result []wasabi.Payment
+	for _, payment := range payments {
+		result = append(result, wasabi.Payment{
+			SendTo:      wasabi.Address(payment.GetSendTo()),
+			Amount:      wasabi.Amount(payment.GetAmount()),
+			Label:       payment.GetLabel(),
+			SubtractFee: payment.GetSubtractFee(),
+		})
+	}
+	return result
+}
+
+func coinsFromPB(coins []*wasabipb.OutPoint) []wasabi.Coin {
+	var result []wasabi.Coin
+	for _, coin := range coins {
+		result = append(result, wasabi.Coin{TransactionID: wasabi.TxID(coin.GetTxid()), Index: int(coin.GetIndex())})
+	}
+	return result
+}
+
+// eventToPB converts an event of the bus. It returns nil for unknown events.
+func eventToPB(event wasabi.Event) *wasabipb.Event {
+	switch e := event.(type) {
+	case wasabi.NewTransactionEvent:
+		return &wasabipb.Event{Event: &wasabipb.Event_NewTransaction{NewTransaction: &wasabipb.NewTransactionEvent{
+			WalletName:  e.WalletName,
+			Transaction: transactionToPB(e.Transaction),
+		}}}
+	case wasabi.ConfirmationReachedEvent:
+		return &wasabipb.Event{Event: &wasabipb.Event_ConfirmationReached{ConfirmationReached: &wasabipb.ConfirmationReachedEvent{
+			WalletName:  e.WalletName,
+			Transaction: transactionToPB(e.Transaction),
+			Height:      int32(e.Height),
+		}}}
+	case wasabi.CoinJoinStatusChangedEvent:
+		return &wasabipb.Event{Event: &wasabipb.Event_CoinjoinStatusChanged{CoinjoinStatusChanged: &wasabipb.CoinJoinStatusChangedEvent{
+			WalletName: e.WalletName,
+			Previous:   string(e.Previous),
+			Current:    string(e.Current),
+		}}}
+	case wasabi.BackendDisconnectedEvent:
+		pb := &wasabipb.BackendDisconnectedEvent{Status: string(e.Status)}
+		if e.Err != nil {
+			pb.Error = e.Err.Error()
+		}
+		return &wasabipb.Event{Event: &wasabipb.Event_BackendDisconnected{BackendDisconnected: pb}}
+	case wasabi.PaymentInCoinJoinFinishedEvent:
+		return &wasabipb.Event{Event: &wasabipb.Event_PaymentInCoinjoinFinished{PaymentInCoinjoinFinished: &wasabipb.PaymentInCoinJoinFinishedEvent{
+			WalletName: e.WalletName,
+			Payment:    paymentInCoinJoinToPB(e.Payment),
+		}}}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,4 @@
+package grpcwasabi
+
+// The generated code is produced with protoc-gen-go and protoc-gen-go-grpc:
+//go:generate protoc -I proto --go_out=. --go_opt=module=github.com/acfnv/go-wasabi-rpc-client/wasabi/grpcwasabi --go-grpc_out=. --go-grpc_opt=module=github.com/acfnv/go-wasabi-rpc-client/wasabi/grpcwasabi wasabi/v1/wasabi.proto
@@ -0,0 +1,16 @@
+module github.com/acfnv/go-wasabi-rpc-client/wasabi/grpcwasabi
+
+go 1.21
+
+require (
+	github.com/acfnv/go-wasabi-rpc-client v0.0.0
+	google.golang.org/grpc v1.67.3
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+)
@@ -0,0 +1,405 @@
+// Package grpcwasabi serves a Wasabi daemon over gRPC, so polyglot consumers get typed stubs and streams of the status and the wallet events.
+//
+//	s := grpc.NewServer()
+//	wasabipb.RegisterWasabiServer(s, grpcwasabi.NewServer(client, grpcwasabi.ServerOptions{}))
+//
+// The service is defined in proto/wasabi/v1/wasabi.proto. It lives in its own module, so the wasabi package does not depend on gRPC.
+package grpcwasabi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/grpcwasabi/wasabipb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ServerOptions holds the options of a Server.
+type ServerOptions struct {
+	// Clock provides the timers of the streams. Default is wasabi.SystemClock.
+	Clock wasabi.Clock
+}
+
+// Server implements wasabipb.WasabiServer by calling the daemon with a client.
+type Server struct {
+	wasabipb.UnimplementedWasabiServer
+	client wasabi.Client
+	opts   ServerOptions
+}
+
+// NewServer creates a server calling the daemon with the client.
+func NewServer(c wasabi.Client, opts ServerOptions) *Server {
+	if opts.Clock == nil {
+		opts.Clock = wasabi.SystemClock
+	}
+	return &Server{client: c, opts: opts}
+}
+
+// toStatus converts an error of the client into a gRPC status: rejections by the wallet are FailedPrecondition, methods unsupported by the daemon Unimplemented, calls rejected by a read-only client PermissionDenied, an unreachable daemon Unavailable and other daemon or protocol errors Internal.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, wasabi.ErrUnsupportedMethod):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, wasabi.ErrReadOnly):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	switch wasabi.Classify(err) {
+	case wasabi.ErrorCategoryWallet:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case wasabi.ErrorCategoryConnection:
+		return status.Error(codes.Unavailable, err.Error())
+	case wasabi.ErrorCategoryDaemon, wasabi.ErrorCategoryProtocol:
+		return status.Error(codes.Internal, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
+
+// c returns the client for a call.
+func (s *Server) c(ctx context.Context) wasabi.Client {
+	return s.client.WithContext(ctx)
+}
+
+// GetStatus implements wasabipb.WasabiServer.
+func (s *Server) GetStatus(ctx context.Context, req *wasabipb.GetStatusRequest) (*wasabipb.Status, error) {
+	st, err := s.c(ctx).GetStatus()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return statusToPB(st), nil
+}
+
+// GetFeeRates implements wasabipb.WasabiServer.
+func (s *Server) GetFeeRates(ctx context.Context, req *wasabipb.GetFeeRatesRequest) (*wasabipb.GetFeeRatesResponse, error) {
+	rates, err := s.c(ctx).GetFeeRates()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return feeRatesToPB(rates), nil
+}
+
+// Broadcast implements wasabipb.WasabiServer.
+func (s *Server) Broadcast(ctx context.Context, req *wasabipb.BroadcastRequest) (*wasabipb.BroadcastResponse, error) {
+	txid, err := s.c(ctx).Broadcast(req.GetWalletName(), req.GetTx())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.BroadcastResponse{Txid: string(txid)}, nil
+}
+
+// Stop implements wasabipb.WasabiServer.
+func (s *Server) Stop(ctx context.Context, req *wasabipb.StopRequest) (*wasabipb.StopResponse, error) {
+	if err := s.c(ctx).Stop(); err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.StopResponse{}, nil
+}
+
+// ListWallets implements wasabipb.WasabiServer.
+func (s *Server) ListWallets(ctx context.Context, req *wasabipb.ListWalletsRequest) (*wasabipb.ListWalletsResponse, error) {
+	wallets, err := s.c(ctx).ListWallets()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &wasabipb.ListWalletsResponse{}
+	for _, w := range wallets {
+		resp.WalletNames = append(resp.WalletNames, w.Name)
+	}
+	return resp, nil
+}
+
+// CreateWallet implements wasabipb.WasabiServer.
+func (s *Server) CreateWallet(ctx context.Context, req *wasabipb.CreateWalletRequest) (*wasabipb.CreateWalletResponse, error) {
+	mnemonic, err := s.c(ctx).CreateWallet(req.GetWalletName(), req.GetPassword())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.CreateWalletResponse{Mnemonic: mnemonic}, nil
+}
+
+// RecoverWallet implements wasabipb.WasabiServer.
+func (s *Server) RecoverWallet(ctx context.Context, req *wasabipb.RecoverWalletRequest) (*wasabipb.RecoverWalletResponse, error) {
+	if err := s.c(ctx).RecoverWallet(req.GetWalletName(), req.GetMnemonic(), req.GetPassword()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.RecoverWalletResponse{}, nil
+}
+
+// LoadWallet implements wasabipb.WasabiServer.
+func (s *Server) LoadWallet(ctx context.Context, req *wasabipb.WalletRequest) (*wasabipb.LoadWalletResponse, error) {
+	if err := s.c(ctx).LoadWallet(req.GetWalletName()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.LoadWalletResponse{}, nil
+}
+
+// GetWalletInfo implements wasabipb.WasabiServer.
+func (s *Server) GetWalletInfo(ctx context.Context, req *wasabipb.WalletRequest) (*wasabipb.WalletInfo, error) {
+	info, err := s.c(ctx).GetWalletInfo(req.GetWalletName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return walletInfoToPB(info), nil
+}
+
+// ListCoins implements wasabipb.WasabiServer.
+func (s *Server) ListCoins(ctx context.Context, req *wasabipb.ListCoinsRequest) (*wasabipb.ListCoinsResponse, error) {
+	var (
+		coins []wasabi.ListCoinsResponse
+		err   error
+	)
+	if req.GetUnspentOnly() {
+		coins, err = s.c(ctx).ListUnspentCoins(req.GetWalletName())
+	} else {
+		coins, err = s.c(ctx).ListCoins(req.GetWalletName())
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &wasabipb.ListCoinsResponse{}
+	for _, coin := range coins {
+		resp.Coins = append(resp.Coins, coinToPB(coin))
+	}
+	return resp, nil
+}
+
+// GetHistory implements wasabipb.WasabiServer.
+func (s *Server) GetHistory(ctx context.Context, req *wasabipb.WalletRequest) (*wasabipb.GetHistoryResponse, error) {
+	txs, err := s.c(ctx).GetHistory(req.GetWalletName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &wasabipb.GetHistoryResponse{}
+	for _, tx := range txs {
+		resp.Transactions = append(resp.Transactions, transactionToPB(tx))
+	}
+	return resp, nil
+}
+
+// ListKeys implements wasabipb.WasabiServer.
+func (s *Server) ListKeys(ctx context.Context, req *wasabipb.WalletRequest) (*wasabipb.ListKeysResponse, error) {
+	keys, err := s.c(ctx).ListKeys(req.GetWalletName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &wasabipb.ListKeysResponse{}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, keyToPB(key))
+	}
+	return resp, nil
+}
+
+// GetNewAddress implements wasabipb.WasabiServer.
+func (s *Server) GetNewAddress(ctx context.Context, req *wasabipb.GetNewAddressRequest) (*wasabipb.Address, error) {
+	addr, err := s.c(ctx).GetNewAddress(req.GetWalletName(), req.GetLabel())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.Address{Address: string(addr.Address), KeyPath: addr.KeyPath.String(), Label: addr.Label, PublicKey: addr.PublicKey, ScriptPubKey: string(addr.ScriptPubKey)}, nil
+}
+
+// Send implements wasabipb.WasabiServer.
+func (s *Server) Send(ctx context.Context, req *wasabipb.SendRequest) (*wasabipb.SendResponse, error) {
+	resp, err := s.c(ctx).Send(req.GetWalletName(), wasabi.SendRequest{
+		Payments:        paymentsFromPB(req.GetPayments()),
+		Coins:           coinsFromPB(req.GetCoins()),
+		FeeTarget:       wasabi.FeeTarget(req.GetFeeTarget()),
+		FeeRate:         req.GetFeeRate(),
+		Password:        req.GetPassword(),
+		PayjoinEndpoint: req.GetPayjoinEndpoint(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.SendResponse{Txid: string(resp.TransactionID), Tx: resp.Transaction}, nil
+}
+
+// Build implements wasabipb.WasabiServer.
+func (s *Server) Build(ctx context.Context, req *wasabipb.BuildRequest) (*wasabipb.TransactionHex, error) {
+	tx, err := s.c(ctx).Build(req.GetWalletName(), wasabi.BuildRequest{
+		Payments:  paymentsFromPB(req.GetPayments()),
+		Coins:     coinsFromPB(req.GetCoins()),
+		FeeTarget: wasabi.FeeTarget(req.GetFeeTarget()),
+		FeeRate:   req.GetFeeRate(),
+		Password:  req.GetPassword(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.TransactionHex{Tx: tx}, nil
+}
+
+// BuildUnsafeTransaction implements wasabipb.WasabiServer.
+func (s *Server) BuildUnsafeTransaction(ctx context.Context, req *wasabipb.BuildRequest) (*wasabipb.TransactionHex, error) {
+	tx, err := s.c(ctx).BuildUnsafeTransaction(req.GetWalletName(), wasabi.BuildUnsafeRequest{
+		Payments:  paymentsFromPB(req.GetPayments()),
+		Coins:     coinsFromPB(req.GetCoins()),
+		FeeTarget: wasabi.FeeTarget(req.GetFeeTarget()),
+		FeeRate:   req.GetFeeRate(),
+		Password:  req.GetPassword(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.TransactionHex{Tx: tx}, nil
+}
+
+// SpeedUpTransaction implements wasabipb.WasabiServer.
+func (s *Server) SpeedUpTransaction(ctx context.Context, req *wasabipb.ReplaceTransactionRequest) (*wasabipb.TransactionHex, error) {
+	tx, err := s.c(ctx).SpeedUpTransaction(req.GetWalletName(), wasabi.TxID(req.GetTxid()), req.GetPassword())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.TransactionHex{Tx: tx}, nil
+}
+
+// CancelTransaction implements wasabipb.WasabiServer.
+func (s *Server) CancelTransaction(ctx context.Context, req *wasabipb.ReplaceTransactionRequest) (*wasabipb.TransactionHex, error) {
+	tx, err := s.c(ctx).CancelTransaction(req.GetWalletName(), wasabi.TxID(req.GetTxid()), req.GetPassword())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.TransactionHex{Tx: tx}, nil
+}
+
+// StartCoinJoin implements wasabipb.WasabiServer.
+func (s *Server) StartCoinJoin(ctx context.Context, req *wasabipb.StartCoinJoinRequest) (*wasabipb.StartCoinJoinResponse, error) {
+	opts := wasabi.StartCoinJoinOptions{StopWhenAllMixed: req.GetStopWhenAllMixed(), OverridePlebStop: req.GetOverridePlebStop()}
+	if err := s.c(ctx).StartCoinJoin(req.GetWalletName(), req.GetPassword(), opts); err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.StartCoinJoinResponse{}, nil
+}
+
+// StartCoinJoinSweep implements wasabipb.WasabiServer.
+func (s *Server) StartCoinJoinSweep(ctx context.Context, req *wasabipb.StartCoinJoinSweepRequest) (*wasabipb.StartCoinJoinResponse, error) {
+	if err := s.c(ctx).StartCoinJoinSweep(req.GetWalletName(), req.GetPassword(), req.GetOutputWalletName()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.StartCoinJoinResponse{}, nil
+}
+
+// StopCoinJoin implements wasabipb.WasabiServer.
+func (s *Server) StopCoinJoin(ctx context.Context, req *wasabipb.WalletRequest) (*wasabipb.StopCoinJoinResponse, error) {
+	if err := s.c(ctx).StopCoinJoin(req.GetWalletName()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.StopCoinJoinResponse{}, nil
+}
+
+// ExcludeFromCoinJoin implements wasabipb.WasabiServer.
+func (s *Server) ExcludeFromCoinJoin(ctx context.Context, req *wasabipb.ExcludeFromCoinJoinRequest) (*wasabipb.ExcludeFromCoinJoinResponse, error) {
+	if err := s.c(ctx).ExcludeCoinsFromCoinJoin(req.GetWalletName(), coinsFromPB(req.GetCoins()), req.GetExclude()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.ExcludeFromCoinJoinResponse{}, nil
+}
+
+// PayInCoinJoin implements wasabipb.WasabiServer.
+func (s *Server) PayInCoinJoin(ctx context.Context, req *wasabipb.PayInCoinJoinRequest) (*wasabipb.PayInCoinJoinResponse, error) {
+	id, err := s.c(ctx).PayInCoinJoin(req.GetWalletName(), wasabi.Address(req.GetAddress()), wasabi.Amount(req.GetAmount()), req.GetPassword())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.PayInCoinJoinResponse{PaymentId: id}, nil
+}
+
+// ListPaymentsInCoinJoin implements wasabipb.WasabiServer.
+func (s *Server) ListPaymentsInCoinJoin(ctx context.Context, req *wasabipb.WalletRequest) (*wasabipb.ListPaymentsInCoinJoinResponse, error) {
+	payments, err := s.c(ctx).ListPaymentsInCoinJoin(req.GetWalletName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &wasabipb.ListPaymentsInCoinJoinResponse{}
+	for _, payment := range payments {
+		resp.Payments = append(resp.Payments, paymentInCoinJoinToPB(payment))
+	}
+	return resp, nil
+}
+
+// CancelPaymentInCoinJoin implements wasabipb.WasabiServer.
+func (s *Server) CancelPaymentInCoinJoin(ctx context.Context, req *wasabipb.CancelPaymentInCoinJoinRequest) (*wasabipb.CancelPaymentInCoinJoinResponse, error) {
+	if err := s.c(ctx).CancelPaymentInCoinJoin(req.GetWalletName(), req.GetPaymentId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &wasabipb.CancelPaymentInCoinJoinResponse{}, nil
+}
+
+// interval returns the interval of a stream request, or wasabi.DefaultPollInterval if it is not set.
+func interval(d *durationpb.Duration) time.Duration {
+	if d.AsDuration() <= 0 {
+		return wasabi.DefaultPollInterval
+	}
+	return d.AsDuration()
+}
+
+// WatchStatus implements wasabipb.WasabiServer. The current status is sent first, then every change; failed checks end the stream.
+func (s *Server) WatchStatus(req *wasabipb.WatchStatusRequest, stream grpc.ServerStreamingServer[wasabipb.Status]) error {
+	ctx := stream.Context()
+	c := s.c(ctx)
+	ticker := s.opts.Clock.NewTicker(interval(req.GetInterval()))
+	defer ticker.Stop()
+	var last *wasabipb.Status
+	for {
+		st, err := c.GetStatus()
+		if err != nil {
+			return toStatus(err)
+		}
+		if current := statusToPB(st); last == nil || !proto.Equal(current, last) {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+			last = current
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// WatchEvents implements wasabipb.WasabiServer. The events are published by wasabi.EventBus.Watch; failed checks are reported as BackendDisconnectedEvent by the bus and do not end the stream.
+func (s *Server) WatchEvents(req *wasabipb.WatchEventsRequest, stream grpc.ServerStreamingServer[wasabipb.Event]) error {
+	if len(req.GetWalletNames()) == 0 {
+		return status.Error(codes.InvalidArgument, "wallet names must not be empty")
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	bus := wasabi.NewEventBus()
+	defer bus.Close()
+	sub := bus.Subscribe(wasabi.SubscribeOptions{Buffer: 256, Policy: wasabi.Block})
+	go bus.Watch(ctx, s.client, wasabi.EventWatchOptions{
+		Wallets:  req.GetWalletNames(),
+		Interval: interval(req.GetInterval()),
+		Clock:    s.opts.Clock,
+	})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			if pb := eventToPB(event); pb != nil {
+				if err := stream.Send(pb); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,3754 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: wasabi/v1/wasabi.proto
+
+package wasabipb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{0}
+}
+
+type Status struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TorStatus            string                 `protobuf:"bytes,1,opt,name=tor_status,json=torStatus,proto3" json:"tor_status,omitempty"`
+	BackendStatus        string                 `protobuf:"bytes,2,opt,name=backend_status,json=backendStatus,proto3" json:"backend_status,omitempty"`
+	BestBlockchainHeight uint64                 `protobuf:"varint,3,opt,name=best_blockchain_height,json=bestBlockchainHeight,proto3" json:"best_blockchain_height,omitempty"`
+	BestBlockchainHash   string                 `protobuf:"bytes,4,opt,name=best_blockchain_hash,json=bestBlockchainHash,proto3" json:"best_blockchain_hash,omitempty"`
+	FiltersCount         int64                  `protobuf:"varint,5,opt,name=filters_count,json=filtersCount,proto3" json:"filters_count,omitempty"`
+	FiltersLeft          int64                  `protobuf:"varint,6,opt,name=filters_left,json=filtersLeft,proto3" json:"filters_left,omitempty"`
+	Network              string                 `protobuf:"bytes,7,opt,name=network,proto3" json:"network,omitempty"`
+	ExchangeRate         float64                `protobuf:"fixed64,8,opt,name=exchange_rate,json=exchangeRate,proto3" json:"exchange_rate,omitempty"`
+	Peers                []*Peer                `protobuf:"bytes,9,rep,name=peers,proto3" json:"peers,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Status) Reset() {
+	*x = Status{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Status) ProtoMessage() {}
+
+func (x *Status) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Status.ProtoReflect.Descriptor instead.
+func (*Status) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Status) GetTorStatus() string {
+	if x != nil {
+		return x.TorStatus
+	}
+	return ""
+}
+
+func (x *Status) GetBackendStatus() string {
+	if x != nil {
+		return x.BackendStatus
+	}
+	return ""
+}
+
+func (x *Status) GetBestBlockchainHeight() uint64 {
+	if x != nil {
+		return x.BestBlockchainHeight
+	}
+	return 0
+}
+
+func (x *Status) GetBestBlockchainHash() string {
+	if x != nil {
+		return x.BestBlockchainHash
+	}
+	return ""
+}
+
+func (x *Status) GetFiltersCount() int64 {
+	if x != nil {
+		return x.FiltersCount
+	}
+	return 0
+}
+
+func (x *Status) GetFiltersLeft() int64 {
+	if x != nil {
+		return x.FiltersLeft
+	}
+	return 0
+}
+
+func (x *Status) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Status) GetExchangeRate() float64 {
+	if x != nil {
+		return x.ExchangeRate
+	}
+	return 0
+}
+
+func (x *Status) GetPeers() []*Peer {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+type Peer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsConnected   bool                   `protobuf:"varint,1,opt,name=is_connected,json=isConnected,proto3" json:"is_connected,omitempty"`
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Endpoint      string                 `protobuf:"bytes,3,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Peer) Reset() {
+	*x = Peer{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Peer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Peer) ProtoMessage() {}
+
+func (x *Peer) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Peer.ProtoReflect.Descriptor instead.
+func (*Peer) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Peer) GetIsConnected() bool {
+	if x != nil {
+		return x.IsConnected
+	}
+	return false
+}
+
+func (x *Peer) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *Peer) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *Peer) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+type GetFeeRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFeeRatesRequest) Reset() {
+	*x = GetFeeRatesRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFeeRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeeRatesRequest) ProtoMessage() {}
+
+func (x *GetFeeRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeeRatesRequest.ProtoReflect.Descriptor instead.
+func (*GetFeeRatesRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{3}
+}
+
+type GetFeeRatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Fee rates in sat/vB by confirmation target in blocks.
+	FeeRates      map[int32]int64 `protobuf:"bytes,1,rep,name=fee_rates,json=feeRates,proto3" json:"fee_rates,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFeeRatesResponse) Reset() {
+	*x = GetFeeRatesResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFeeRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeeRatesResponse) ProtoMessage() {}
+
+func (x *GetFeeRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeeRatesResponse.ProtoReflect.Descriptor instead.
+func (*GetFeeRatesResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetFeeRatesResponse) GetFeeRates() map[int32]int64 {
+	if x != nil {
+		return x.FeeRates
+	}
+	return nil
+}
+
+type BroadcastRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional.
+	WalletName    string `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Tx            string `protobuf:"bytes,2,opt,name=tx,proto3" json:"tx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastRequest) Reset() {
+	*x = BroadcastRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastRequest) ProtoMessage() {}
+
+func (x *BroadcastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastRequest.ProtoReflect.Descriptor instead.
+func (*BroadcastRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BroadcastRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *BroadcastRequest) GetTx() string {
+	if x != nil {
+		return x.Tx
+	}
+	return ""
+}
+
+type BroadcastResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastResponse) Reset() {
+	*x = BroadcastResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastResponse) ProtoMessage() {}
+
+func (x *BroadcastResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastResponse.ProtoReflect.Descriptor instead.
+func (*BroadcastResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BroadcastResponse) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+type StopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRequest) Reset() {
+	*x = StopRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRequest) ProtoMessage() {}
+
+func (x *StopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
+func (*StopRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{7}
+}
+
+type StopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopResponse) Reset() {
+	*x = StopResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopResponse) ProtoMessage() {}
+
+func (x *StopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopResponse.ProtoReflect.Descriptor instead.
+func (*StopResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{8}
+}
+
+type WalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletRequest) Reset() {
+	*x = WalletRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletRequest) ProtoMessage() {}
+
+func (x *WalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletRequest.ProtoReflect.Descriptor instead.
+func (*WalletRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WalletRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+type ListWalletsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWalletsRequest) Reset() {
+	*x = ListWalletsRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWalletsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWalletsRequest) ProtoMessage() {}
+
+func (x *ListWalletsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWalletsRequest.ProtoReflect.Descriptor instead.
+func (*ListWalletsRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{10}
+}
+
+type ListWalletsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletNames   []string               `protobuf:"bytes,1,rep,name=wallet_names,json=walletNames,proto3" json:"wallet_names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWalletsResponse) Reset() {
+	*x = ListWalletsResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWalletsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWalletsResponse) ProtoMessage() {}
+
+func (x *ListWalletsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWalletsResponse.ProtoReflect.Descriptor instead.
+func (*ListWalletsResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListWalletsResponse) GetWalletNames() []string {
+	if x != nil {
+		return x.WalletNames
+	}
+	return nil
+}
+
+type CreateWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWalletRequest) Reset() {
+	*x = CreateWalletRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWalletRequest) ProtoMessage() {}
+
+func (x *CreateWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWalletRequest.ProtoReflect.Descriptor instead.
+func (*CreateWalletRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateWalletRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *CreateWalletRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type CreateWalletResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mnemonic      string                 `protobuf:"bytes,1,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWalletResponse) Reset() {
+	*x = CreateWalletResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWalletResponse) ProtoMessage() {}
+
+func (x *CreateWalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWalletResponse.ProtoReflect.Descriptor instead.
+func (*CreateWalletResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateWalletResponse) GetMnemonic() string {
+	if x != nil {
+		return x.Mnemonic
+	}
+	return ""
+}
+
+type RecoverWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Mnemonic      string                 `protobuf:"bytes,2,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecoverWalletRequest) Reset() {
+	*x = RecoverWalletRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecoverWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecoverWalletRequest) ProtoMessage() {}
+
+func (x *RecoverWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecoverWalletRequest.ProtoReflect.Descriptor instead.
+func (*RecoverWalletRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RecoverWalletRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *RecoverWalletRequest) GetMnemonic() string {
+	if x != nil {
+		return x.Mnemonic
+	}
+	return ""
+}
+
+func (x *RecoverWalletRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RecoverWalletResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecoverWalletResponse) Reset() {
+	*x = RecoverWalletResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecoverWalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecoverWalletResponse) ProtoMessage() {}
+
+func (x *RecoverWalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecoverWalletResponse.ProtoReflect.Descriptor instead.
+func (*RecoverWalletResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{15}
+}
+
+type LoadWalletResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoadWalletResponse) Reset() {
+	*x = LoadWalletResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoadWalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadWalletResponse) ProtoMessage() {}
+
+func (x *LoadWalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadWalletResponse.ProtoReflect.Descriptor instead.
+func (*LoadWalletResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{16}
+}
+
+type WalletInfo struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	WalletName           string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	WalletFile           string                 `protobuf:"bytes,2,opt,name=wallet_file,json=walletFile,proto3" json:"wallet_file,omitempty"`
+	State                string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	MasterKeyFingerprint string                 `protobuf:"bytes,4,opt,name=master_key_fingerprint,json=masterKeyFingerprint,proto3" json:"master_key_fingerprint,omitempty"`
+	AnonScoreTarget      int32                  `protobuf:"varint,5,opt,name=anon_score_target,json=anonScoreTarget,proto3" json:"anon_score_target,omitempty"`
+	IsWatchOnly          bool                   `protobuf:"varint,6,opt,name=is_watch_only,json=isWatchOnly,proto3" json:"is_watch_only,omitempty"`
+	IsHardwareWallet     bool                   `protobuf:"varint,7,opt,name=is_hardware_wallet,json=isHardwareWallet,proto3" json:"is_hardware_wallet,omitempty"`
+	IsAutoCoinjoin       bool                   `protobuf:"varint,8,opt,name=is_auto_coinjoin,json=isAutoCoinjoin,proto3" json:"is_auto_coinjoin,omitempty"`
+	IsRedCoinIsolation   bool                   `protobuf:"varint,9,opt,name=is_red_coin_isolation,json=isRedCoinIsolation,proto3" json:"is_red_coin_isolation,omitempty"`
+	Accounts             []*Account             `protobuf:"bytes,10,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	Balance              int64                  `protobuf:"varint,11,opt,name=balance,proto3" json:"balance,omitempty"`
+	CoinjoinStatus       string                 `protobuf:"bytes,12,opt,name=coinjoin_status,json=coinjoinStatus,proto3" json:"coinjoin_status,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *WalletInfo) Reset() {
+	*x = WalletInfo{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletInfo) ProtoMessage() {}
+
+func (x *WalletInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletInfo.ProtoReflect.Descriptor instead.
+func (*WalletInfo) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WalletInfo) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *WalletInfo) GetWalletFile() string {
+	if x != nil {
+		return x.WalletFile
+	}
+	return ""
+}
+
+func (x *WalletInfo) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *WalletInfo) GetMasterKeyFingerprint() string {
+	if x != nil {
+		return x.MasterKeyFingerprint
+	}
+	return ""
+}
+
+func (x *WalletInfo) GetAnonScoreTarget() int32 {
+	if x != nil {
+		return x.AnonScoreTarget
+	}
+	return 0
+}
+
+func (x *WalletInfo) GetIsWatchOnly() bool {
+	if x != nil {
+		return x.IsWatchOnly
+	}
+	return false
+}
+
+func (x *WalletInfo) GetIsHardwareWallet() bool {
+	if x != nil {
+		return x.IsHardwareWallet
+	}
+	return false
+}
+
+func (x *WalletInfo) GetIsAutoCoinjoin() bool {
+	if x != nil {
+		return x.IsAutoCoinjoin
+	}
+	return false
+}
+
+func (x *WalletInfo) GetIsRedCoinIsolation() bool {
+	if x != nil {
+		return x.IsRedCoinIsolation
+	}
+	return false
+}
+
+func (x *WalletInfo) GetAccounts() []*Account {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+func (x *WalletInfo) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *WalletInfo) GetCoinjoinStatus() string {
+	if x != nil {
+		return x.CoinjoinStatus
+	}
+	return ""
+}
+
+type Account struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PublicKey     string                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	KeyPath       string                 `protobuf:"bytes,3,opt,name=key_path,json=keyPath,proto3" json:"key_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Account) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Account) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *Account) GetKeyPath() string {
+	if x != nil {
+		return x.KeyPath
+	}
+	return ""
+}
+
+type ListCoinsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	UnspentOnly   bool                   `protobuf:"varint,2,opt,name=unspent_only,json=unspentOnly,proto3" json:"unspent_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoinsRequest) Reset() {
+	*x = ListCoinsRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoinsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoinsRequest) ProtoMessage() {}
+
+func (x *ListCoinsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoinsRequest.ProtoReflect.Descriptor instead.
+func (*ListCoinsRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListCoinsRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *ListCoinsRequest) GetUnspentOnly() bool {
+	if x != nil {
+		return x.UnspentOnly
+	}
+	return false
+}
+
+type ListCoinsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coins         []*Coin                `protobuf:"bytes,1,rep,name=coins,proto3" json:"coins,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoinsResponse) Reset() {
+	*x = ListCoinsResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoinsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoinsResponse) ProtoMessage() {}
+
+func (x *ListCoinsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoinsResponse.ProtoReflect.Descriptor instead.
+func (*ListCoinsResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListCoinsResponse) GetCoins() []*Coin {
+	if x != nil {
+		return x.Coins
+	}
+	return nil
+}
+
+type Coin struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Txid           string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Index          int32                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Amount         int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	AnonymityScore float64                `protobuf:"fixed64,4,opt,name=anonymity_score,json=anonymityScore,proto3" json:"anonymity_score,omitempty"`
+	Confirmed      bool                   `protobuf:"varint,5,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	Confirmations  int32                  `protobuf:"varint,6,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	KeyPath        string                 `protobuf:"bytes,7,opt,name=key_path,json=keyPath,proto3" json:"key_path,omitempty"`
+	Address        string                 `protobuf:"bytes,8,opt,name=address,proto3" json:"address,omitempty"`
+	// Empty if the coin is unspent.
+	SpentBy              string `protobuf:"bytes,9,opt,name=spent_by,json=spentBy,proto3" json:"spent_by,omitempty"`
+	Label                string `protobuf:"bytes,10,opt,name=label,proto3" json:"label,omitempty"`
+	ExcludedFromCoinjoin bool   `protobuf:"varint,11,opt,name=excluded_from_coinjoin,json=excludedFromCoinjoin,proto3" json:"excluded_from_coinjoin,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Coin) Reset() {
+	*x = Coin{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coin) ProtoMessage() {}
+
+func (x *Coin) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coin.ProtoReflect.Descriptor instead.
+func (*Coin) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Coin) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *Coin) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Coin) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Coin) GetAnonymityScore() float64 {
+	if x != nil {
+		return x.AnonymityScore
+	}
+	return 0
+}
+
+func (x *Coin) GetConfirmed() bool {
+	if x != nil {
+		return x.Confirmed
+	}
+	return false
+}
+
+func (x *Coin) GetConfirmations() int32 {
+	if x != nil {
+		return x.Confirmations
+	}
+	return 0
+}
+
+func (x *Coin) GetKeyPath() string {
+	if x != nil {
+		return x.KeyPath
+	}
+	return ""
+}
+
+func (x *Coin) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Coin) GetSpentBy() string {
+	if x != nil {
+		return x.SpentBy
+	}
+	return ""
+}
+
+func (x *Coin) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Coin) GetExcludedFromCoinjoin() bool {
+	if x != nil {
+		return x.ExcludedFromCoinjoin
+	}
+	return false
+}
+
+type GetHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transactions  []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetHistoryResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+type Transaction struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Datetime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Height   int32                  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// Negative for outgoing transactions.
+	Amount           int64  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Label            string `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	Txid             string `protobuf:"bytes,5,opt,name=txid,proto3" json:"txid,omitempty"`
+	IsLikelyCoinjoin bool   `protobuf:"varint,6,opt,name=is_likely_coinjoin,json=isLikelyCoinjoin,proto3" json:"is_likely_coinjoin,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Transaction) GetDatetime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Datetime
+	}
+	return nil
+}
+
+func (x *Transaction) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Transaction) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Transaction) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *Transaction) GetIsLikelyCoinjoin() bool {
+	if x != nil {
+		return x.IsLikelyCoinjoin
+	}
+	return false
+}
+
+type ListKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*Key                 `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListKeysResponse) Reset() {
+	*x = ListKeysResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKeysResponse) ProtoMessage() {}
+
+func (x *ListKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListKeysResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListKeysResponse) GetKeys() []*Key {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type Key struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FullKeyPath   string                 `protobuf:"bytes,1,opt,name=full_key_path,json=fullKeyPath,proto3" json:"full_key_path,omitempty"`
+	Internal      bool                   `protobuf:"varint,2,opt,name=internal,proto3" json:"internal,omitempty"`
+	KeyState      int32                  `protobuf:"varint,3,opt,name=key_state,json=keyState,proto3" json:"key_state,omitempty"`
+	Label         string                 `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	ScriptPubKey  string                 `protobuf:"bytes,5,opt,name=script_pub_key,json=scriptPubKey,proto3" json:"script_pub_key,omitempty"`
+	PubKey        string                 `protobuf:"bytes,6,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	PubKeyHash    string                 `protobuf:"bytes,7,opt,name=pub_key_hash,json=pubKeyHash,proto3" json:"pub_key_hash,omitempty"`
+	Address       string                 `protobuf:"bytes,8,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Key) Reset() {
+	*x = Key{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Key) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Key) ProtoMessage() {}
+
+func (x *Key) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Key.ProtoReflect.Descriptor instead.
+func (*Key) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Key) GetFullKeyPath() string {
+	if x != nil {
+		return x.FullKeyPath
+	}
+	return ""
+}
+
+func (x *Key) GetInternal() bool {
+	if x != nil {
+		return x.Internal
+	}
+	return false
+}
+
+func (x *Key) GetKeyState() int32 {
+	if x != nil {
+		return x.KeyState
+	}
+	return 0
+}
+
+func (x *Key) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Key) GetScriptPubKey() string {
+	if x != nil {
+		return x.ScriptPubKey
+	}
+	return ""
+}
+
+func (x *Key) GetPubKey() string {
+	if x != nil {
+		return x.PubKey
+	}
+	return ""
+}
+
+func (x *Key) GetPubKeyHash() string {
+	if x != nil {
+		return x.PubKeyHash
+	}
+	return ""
+}
+
+func (x *Key) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type GetNewAddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNewAddressRequest) Reset() {
+	*x = GetNewAddressRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNewAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNewAddressRequest) ProtoMessage() {}
+
+func (x *GetNewAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNewAddressRequest.ProtoReflect.Descriptor instead.
+func (*GetNewAddressRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetNewAddressRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *GetNewAddressRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	KeyPath       string                 `protobuf:"bytes,2,opt,name=key_path,json=keyPath,proto3" json:"key_path,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	PublicKey     string                 `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	ScriptPubKey  string                 `protobuf:"bytes,5,opt,name=script_pub_key,json=scriptPubKey,proto3" json:"script_pub_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Address) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Address) GetKeyPath() string {
+	if x != nil {
+		return x.KeyPath
+	}
+	return ""
+}
+
+func (x *Address) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Address) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *Address) GetScriptPubKey() string {
+	if x != nil {
+		return x.ScriptPubKey
+	}
+	return ""
+}
+
+type Payment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SendTo        string                 `protobuf:"bytes,1,opt,name=send_to,json=sendTo,proto3" json:"send_to,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	SubtractFee   bool                   `protobuf:"varint,4,opt,name=subtract_fee,json=subtractFee,proto3" json:"subtract_fee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Payment) GetSendTo() string {
+	if x != nil {
+		return x.SendTo
+	}
+	return ""
+}
+
+func (x *Payment) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payment) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Payment) GetSubtractFee() bool {
+	if x != nil {
+		return x.SubtractFee
+	}
+	return false
+}
+
+type OutPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Index         int32                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutPoint) Reset() {
+	*x = OutPoint{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutPoint) ProtoMessage() {}
+
+func (x *OutPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutPoint.ProtoReflect.Descriptor instead.
+func (*OutPoint) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *OutPoint) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *OutPoint) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type SendRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	WalletName      string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Payments        []*Payment             `protobuf:"bytes,2,rep,name=payments,proto3" json:"payments,omitempty"`
+	Coins           []*OutPoint            `protobuf:"bytes,3,rep,name=coins,proto3" json:"coins,omitempty"`
+	FeeTarget       int32                  `protobuf:"varint,4,opt,name=fee_target,json=feeTarget,proto3" json:"fee_target,omitempty"`
+	FeeRate         float64                `protobuf:"fixed64,5,opt,name=fee_rate,json=feeRate,proto3" json:"fee_rate,omitempty"`
+	Password        string                 `protobuf:"bytes,6,opt,name=password,proto3" json:"password,omitempty"`
+	PayjoinEndpoint string                 `protobuf:"bytes,7,opt,name=payjoin_endpoint,json=payjoinEndpoint,proto3" json:"payjoin_endpoint,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SendRequest) Reset() {
+	*x = SendRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendRequest) ProtoMessage() {}
+
+func (x *SendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendRequest.ProtoReflect.Descriptor instead.
+func (*SendRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SendRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *SendRequest) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *SendRequest) GetCoins() []*OutPoint {
+	if x != nil {
+		return x.Coins
+	}
+	return nil
+}
+
+func (x *SendRequest) GetFeeTarget() int32 {
+	if x != nil {
+		return x.FeeTarget
+	}
+	return 0
+}
+
+func (x *SendRequest) GetFeeRate() float64 {
+	if x != nil {
+		return x.FeeRate
+	}
+	return 0
+}
+
+func (x *SendRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *SendRequest) GetPayjoinEndpoint() string {
+	if x != nil {
+		return x.PayjoinEndpoint
+	}
+	return ""
+}
+
+type SendResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Txid          string                 `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Tx            string                 `protobuf:"bytes,2,opt,name=tx,proto3" json:"tx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendResponse) Reset() {
+	*x = SendResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendResponse) ProtoMessage() {}
+
+func (x *SendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendResponse.ProtoReflect.Descriptor instead.
+func (*SendResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SendResponse) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *SendResponse) GetTx() string {
+	if x != nil {
+		return x.Tx
+	}
+	return ""
+}
+
+type BuildRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Payments      []*Payment             `protobuf:"bytes,2,rep,name=payments,proto3" json:"payments,omitempty"`
+	Coins         []*OutPoint            `protobuf:"bytes,3,rep,name=coins,proto3" json:"coins,omitempty"`
+	FeeTarget     int32                  `protobuf:"varint,4,opt,name=fee_target,json=feeTarget,proto3" json:"fee_target,omitempty"`
+	FeeRate       float64                `protobuf:"fixed64,5,opt,name=fee_rate,json=feeRate,proto3" json:"fee_rate,omitempty"`
+	Password      string                 `protobuf:"bytes,6,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildRequest) Reset() {
+	*x = BuildRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildRequest) ProtoMessage() {}
+
+func (x *BuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildRequest.ProtoReflect.Descriptor instead.
+func (*BuildRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BuildRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *BuildRequest) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *BuildRequest) GetCoins() []*OutPoint {
+	if x != nil {
+		return x.Coins
+	}
+	return nil
+}
+
+func (x *BuildRequest) GetFeeTarget() int32 {
+	if x != nil {
+		return x.FeeTarget
+	}
+	return 0
+}
+
+func (x *BuildRequest) GetFeeRate() float64 {
+	if x != nil {
+		return x.FeeRate
+	}
+	return 0
+}
+
+func (x *BuildRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type TransactionHex struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tx            string                 `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionHex) Reset() {
+	*x = TransactionHex{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionHex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionHex) ProtoMessage() {}
+
+func (x *TransactionHex) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionHex.ProtoReflect.Descriptor instead.
+func (*TransactionHex) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *TransactionHex) GetTx() string {
+	if x != nil {
+		return x.Tx
+	}
+	return ""
+}
+
+type ReplaceTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Txid          string                 `protobuf:"bytes,2,opt,name=txid,proto3" json:"txid,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplaceTransactionRequest) Reset() {
+	*x = ReplaceTransactionRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaceTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceTransactionRequest) ProtoMessage() {}
+
+func (x *ReplaceTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceTransactionRequest.ProtoReflect.Descriptor instead.
+func (*ReplaceTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ReplaceTransactionRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *ReplaceTransactionRequest) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+func (x *ReplaceTransactionRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type StartCoinJoinRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	WalletName       string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Password         string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	StopWhenAllMixed bool                   `protobuf:"varint,3,opt,name=stop_when_all_mixed,json=stopWhenAllMixed,proto3" json:"stop_when_all_mixed,omitempty"`
+	OverridePlebStop bool                   `protobuf:"varint,4,opt,name=override_pleb_stop,json=overridePlebStop,proto3" json:"override_pleb_stop,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StartCoinJoinRequest) Reset() {
+	*x = StartCoinJoinRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartCoinJoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartCoinJoinRequest) ProtoMessage() {}
+
+func (x *StartCoinJoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartCoinJoinRequest.ProtoReflect.Descriptor instead.
+func (*StartCoinJoinRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *StartCoinJoinRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *StartCoinJoinRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *StartCoinJoinRequest) GetStopWhenAllMixed() bool {
+	if x != nil {
+		return x.StopWhenAllMixed
+	}
+	return false
+}
+
+func (x *StartCoinJoinRequest) GetOverridePlebStop() bool {
+	if x != nil {
+		return x.OverridePlebStop
+	}
+	return false
+}
+
+type StartCoinJoinSweepRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	WalletName       string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Password         string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	OutputWalletName string                 `protobuf:"bytes,3,opt,name=output_wallet_name,json=outputWalletName,proto3" json:"output_wallet_name,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StartCoinJoinSweepRequest) Reset() {
+	*x = StartCoinJoinSweepRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartCoinJoinSweepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartCoinJoinSweepRequest) ProtoMessage() {}
+
+func (x *StartCoinJoinSweepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartCoinJoinSweepRequest.ProtoReflect.Descriptor instead.
+func (*StartCoinJoinSweepRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *StartCoinJoinSweepRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *StartCoinJoinSweepRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *StartCoinJoinSweepRequest) GetOutputWalletName() string {
+	if x != nil {
+		return x.OutputWalletName
+	}
+	return ""
+}
+
+type StartCoinJoinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartCoinJoinResponse) Reset() {
+	*x = StartCoinJoinResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartCoinJoinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartCoinJoinResponse) ProtoMessage() {}
+
+func (x *StartCoinJoinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartCoinJoinResponse.ProtoReflect.Descriptor instead.
+func (*StartCoinJoinResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{37}
+}
+
+type StopCoinJoinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopCoinJoinResponse) Reset() {
+	*x = StopCoinJoinResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopCoinJoinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopCoinJoinResponse) ProtoMessage() {}
+
+func (x *StopCoinJoinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopCoinJoinResponse.ProtoReflect.Descriptor instead.
+func (*StopCoinJoinResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{38}
+}
+
+type ExcludeFromCoinJoinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Coins         []*OutPoint            `protobuf:"bytes,2,rep,name=coins,proto3" json:"coins,omitempty"`
+	Exclude       bool                   `protobuf:"varint,3,opt,name=exclude,proto3" json:"exclude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExcludeFromCoinJoinRequest) Reset() {
+	*x = ExcludeFromCoinJoinRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExcludeFromCoinJoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExcludeFromCoinJoinRequest) ProtoMessage() {}
+
+func (x *ExcludeFromCoinJoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExcludeFromCoinJoinRequest.ProtoReflect.Descriptor instead.
+func (*ExcludeFromCoinJoinRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ExcludeFromCoinJoinRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *ExcludeFromCoinJoinRequest) GetCoins() []*OutPoint {
+	if x != nil {
+		return x.Coins
+	}
+	return nil
+}
+
+func (x *ExcludeFromCoinJoinRequest) GetExclude() bool {
+	if x != nil {
+		return x.Exclude
+	}
+	return false
+}
+
+type ExcludeFromCoinJoinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExcludeFromCoinJoinResponse) Reset() {
+	*x = ExcludeFromCoinJoinResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExcludeFromCoinJoinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExcludeFromCoinJoinResponse) ProtoMessage() {}
+
+func (x *ExcludeFromCoinJoinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExcludeFromCoinJoinResponse.ProtoReflect.Descriptor instead.
+func (*ExcludeFromCoinJoinResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{40}
+}
+
+type PayInCoinJoinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Password      string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayInCoinJoinRequest) Reset() {
+	*x = PayInCoinJoinRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayInCoinJoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayInCoinJoinRequest) ProtoMessage() {}
+
+func (x *PayInCoinJoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayInCoinJoinRequest.ProtoReflect.Descriptor instead.
+func (*PayInCoinJoinRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PayInCoinJoinRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *PayInCoinJoinRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *PayInCoinJoinRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PayInCoinJoinRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type PayInCoinJoinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayInCoinJoinResponse) Reset() {
+	*x = PayInCoinJoinResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayInCoinJoinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayInCoinJoinResponse) ProtoMessage() {}
+
+func (x *PayInCoinJoinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayInCoinJoinResponse.ProtoReflect.Descriptor instead.
+func (*PayInCoinJoinResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PayInCoinJoinResponse) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type ListPaymentsInCoinJoinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*PaymentInCoinJoin   `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsInCoinJoinResponse) Reset() {
+	*x = ListPaymentsInCoinJoinResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsInCoinJoinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsInCoinJoinResponse) ProtoMessage() {}
+
+func (x *ListPaymentsInCoinJoinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsInCoinJoinResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentsInCoinJoinResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListPaymentsInCoinJoinResponse) GetPayments() []*PaymentInCoinJoin {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+type PaymentInCoinJoin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	State         []*PaymentState        `protobuf:"bytes,4,rep,name=state,proto3" json:"state,omitempty"`
+	Address       string                 `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentInCoinJoin) Reset() {
+	*x = PaymentInCoinJoin{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentInCoinJoin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentInCoinJoin) ProtoMessage() {}
+
+func (x *PaymentInCoinJoin) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentInCoinJoin.ProtoReflect.Descriptor instead.
+func (*PaymentInCoinJoin) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *PaymentInCoinJoin) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PaymentInCoinJoin) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentInCoinJoin) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *PaymentInCoinJoin) GetState() []*PaymentState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *PaymentInCoinJoin) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type PaymentState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Round         int32                  `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Txid          string                 `protobuf:"bytes,3,opt,name=txid,proto3" json:"txid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentState) Reset() {
+	*x = PaymentState{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentState) ProtoMessage() {}
+
+func (x *PaymentState) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentState.ProtoReflect.Descriptor instead.
+func (*PaymentState) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *PaymentState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentState) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *PaymentState) GetTxid() string {
+	if x != nil {
+		return x.Txid
+	}
+	return ""
+}
+
+type CancelPaymentInCoinJoinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelPaymentInCoinJoinRequest) Reset() {
+	*x = CancelPaymentInCoinJoinRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelPaymentInCoinJoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelPaymentInCoinJoinRequest) ProtoMessage() {}
+
+func (x *CancelPaymentInCoinJoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelPaymentInCoinJoinRequest.ProtoReflect.Descriptor instead.
+func (*CancelPaymentInCoinJoinRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CancelPaymentInCoinJoinRequest) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *CancelPaymentInCoinJoinRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type CancelPaymentInCoinJoinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelPaymentInCoinJoinResponse) Reset() {
+	*x = CancelPaymentInCoinJoinResponse{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelPaymentInCoinJoinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelPaymentInCoinJoinResponse) ProtoMessage() {}
+
+func (x *CancelPaymentInCoinJoinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelPaymentInCoinJoinResponse.ProtoReflect.Descriptor instead.
+func (*CancelPaymentInCoinJoinResponse) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{47}
+}
+
+type WatchStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Interval between the checks. Default is 5 seconds.
+	Interval      *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStatusRequest) Reset() {
+	*x = WatchStatusRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStatusRequest) ProtoMessage() {}
+
+func (x *WatchStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchStatusRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *WatchStatusRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type WatchEventsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	WalletNames []string               `protobuf:"bytes,1,rep,name=wallet_names,json=walletNames,proto3" json:"wallet_names,omitempty"`
+	// Interval between the checks. Default is 5 seconds.
+	Interval      *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *WatchEventsRequest) GetWalletNames() []string {
+	if x != nil {
+		return x.WalletNames
+	}
+	return nil
+}
+
+func (x *WatchEventsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Event_NewTransaction
+	//	*Event_ConfirmationReached
+	//	*Event_CoinjoinStatusChanged
+	//	*Event_BackendDisconnected
+	//	*Event_PaymentInCoinjoinFinished
+	Event         isEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *Event) GetEvent() isEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Event) GetNewTransaction() *NewTransactionEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_NewTransaction); ok {
+			return x.NewTransaction
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetConfirmationReached() *ConfirmationReachedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_ConfirmationReached); ok {
+			return x.ConfirmationReached
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetCoinjoinStatusChanged() *CoinJoinStatusChangedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_CoinjoinStatusChanged); ok {
+			return x.CoinjoinStatusChanged
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetBackendDisconnected() *BackendDisconnectedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_BackendDisconnected); ok {
+			return x.BackendDisconnected
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetPaymentInCoinjoinFinished() *PaymentInCoinJoinFinishedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*Event_PaymentInCoinjoinFinished); ok {
+			return x.PaymentInCoinjoinFinished
+		}
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_NewTransaction struct {
+	NewTransaction *NewTransactionEvent `protobuf:"bytes,1,opt,name=new_transaction,json=newTransaction,proto3,oneof"`
+}
+
+type Event_ConfirmationReached struct {
+	ConfirmationReached *ConfirmationReachedEvent `protobuf:"bytes,2,opt,name=confirmation_reached,json=confirmationReached,proto3,oneof"`
+}
+
+type Event_CoinjoinStatusChanged struct {
+	CoinjoinStatusChanged *CoinJoinStatusChangedEvent `protobuf:"bytes,3,opt,name=coinjoin_status_changed,json=coinjoinStatusChanged,proto3,oneof"`
+}
+
+type Event_BackendDisconnected struct {
+	BackendDisconnected *BackendDisconnectedEvent `protobuf:"bytes,4,opt,name=backend_disconnected,json=backendDisconnected,proto3,oneof"`
+}
+
+type Event_PaymentInCoinjoinFinished struct {
+	PaymentInCoinjoinFinished *PaymentInCoinJoinFinishedEvent `protobuf:"bytes,5,opt,name=payment_in_coinjoin_finished,json=paymentInCoinjoinFinished,proto3,oneof"`
+}
+
+func (*Event_NewTransaction) isEvent_Event() {}
+
+func (*Event_ConfirmationReached) isEvent_Event() {}
+
+func (*Event_CoinjoinStatusChanged) isEvent_Event() {}
+
+func (*Event_BackendDisconnected) isEvent_Event() {}
+
+func (*Event_PaymentInCoinjoinFinished) isEvent_Event() {}
+
+type NewTransactionEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Transaction   *Transaction           `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewTransactionEvent) Reset() {
+	*x = NewTransactionEvent{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewTransactionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewTransactionEvent) ProtoMessage() {}
+
+func (x *NewTransactionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewTransactionEvent.ProtoReflect.Descriptor instead.
+func (*NewTransactionEvent) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *NewTransactionEvent) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *NewTransactionEvent) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type ConfirmationReachedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Transaction   *Transaction           `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	Height        int32                  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmationReachedEvent) Reset() {
+	*x = ConfirmationReachedEvent{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmationReachedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmationReachedEvent) ProtoMessage() {}
+
+func (x *ConfirmationReachedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmationReachedEvent.ProtoReflect.Descriptor instead.
+func (*ConfirmationReachedEvent) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ConfirmationReachedEvent) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *ConfirmationReachedEvent) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *ConfirmationReachedEvent) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type CoinJoinStatusChangedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Previous      string                 `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
+	Current       string                 `protobuf:"bytes,3,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoinJoinStatusChangedEvent) Reset() {
+	*x = CoinJoinStatusChangedEvent{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoinJoinStatusChangedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoinJoinStatusChangedEvent) ProtoMessage() {}
+
+func (x *CoinJoinStatusChangedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoinJoinStatusChangedEvent.ProtoReflect.Descriptor instead.
+func (*CoinJoinStatusChangedEvent) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *CoinJoinStatusChangedEvent) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *CoinJoinStatusChangedEvent) GetPrevious() string {
+	if x != nil {
+		return x.Previous
+	}
+	return ""
+}
+
+func (x *CoinJoinStatusChangedEvent) GetCurrent() string {
+	if x != nil {
+		return x.Current
+	}
+	return ""
+}
+
+type BackendDisconnectedEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty if the daemon cannot be reached.
+	Status string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// Set if the daemon cannot be reached.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackendDisconnectedEvent) Reset() {
+	*x = BackendDisconnectedEvent{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackendDisconnectedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendDisconnectedEvent) ProtoMessage() {}
+
+func (x *BackendDisconnectedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendDisconnectedEvent.ProtoReflect.Descriptor instead.
+func (*BackendDisconnectedEvent) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *BackendDisconnectedEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BackendDisconnectedEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PaymentInCoinJoinFinishedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletName    string                 `protobuf:"bytes,1,opt,name=wallet_name,json=walletName,proto3" json:"wallet_name,omitempty"`
+	Payment       *PaymentInCoinJoin     `protobuf:"bytes,2,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentInCoinJoinFinishedEvent) Reset() {
+	*x = PaymentInCoinJoinFinishedEvent{}
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentInCoinJoinFinishedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentInCoinJoinFinishedEvent) ProtoMessage() {}
+
+func (x *PaymentInCoinJoinFinishedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wasabi_v1_wasabi_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentInCoinJoinFinishedEvent.ProtoReflect.Descriptor instead.
+func (*PaymentInCoinJoinFinishedEvent) Descriptor() ([]byte, []int) {
+	return file_wasabi_v1_wasabi_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *PaymentInCoinJoinFinishedEvent) GetWalletName() string {
+	if x != nil {
+		return x.WalletName
+	}
+	return ""
+}
+
+func (x *PaymentInCoinJoinFinishedEvent) GetPayment() *PaymentInCoinJoin {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+var File_wasabi_v1_wasabi_proto protoreflect.FileDescriptor
+
+const file_wasabi_v1_wasabi_proto_rawDesc = "" +
+	"\n" +
+	"\x16wasabi/v1/wasabi.proto\x12\twasabi.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x12\n" +
+	"\x10GetStatusRequest\"\xe4\x02\n" +
+	"\x06Status\x12\x1d\n" +
+	"\n" +
+	"tor_status\x18\x01 \x01(\tR\ttorStatus\x12%\n" +
+	"\x0ebackend_status\x18\x02 \x01(\tR\rbackendStatus\x124\n" +
+	"\x16best_blockchain_height\x18\x03 \x01(\x04R\x14bestBlockchainHeight\x120\n" +
+	"\x14best_blockchain_hash\x18\x04 \x01(\tR\x12bestBlockchainHash\x12#\n" +
+	"\rfilters_count\x18\x05 \x01(\x03R\ffiltersCount\x12!\n" +
+	"\ffilters_left\x18\x06 \x01(\x03R\vfiltersLeft\x12\x18\n" +
+	"\anetwork\x18\a \x01(\tR\anetwork\x12#\n" +
+	"\rexchange_rate\x18\b \x01(\x01R\fexchangeRate\x12%\n" +
+	"\x05peers\x18\t \x03(\v2\x0f.wasabi.v1.PeerR\x05peers\"\x9d\x01\n" +
+	"\x04Peer\x12!\n" +
+	"\fis_connected\x18\x01 \x01(\bR\visConnected\x127\n" +
+	"\tlast_seen\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12\x1a\n" +
+	"\bendpoint\x18\x03 \x01(\tR\bendpoint\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\tuserAgent\"\x14\n" +
+	"\x12GetFeeRatesRequest\"\x9d\x01\n" +
+	"\x13GetFeeRatesResponse\x12I\n" +
+	"\tfee_rates\x18\x01 \x03(\v2,.wasabi.v1.GetFeeRatesResponse.FeeRatesEntryR\bfeeRates\x1a;\n" +
+	"\rFeeRatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"C\n" +
+	"\x10BroadcastRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x0e\n" +
+	"\x02tx\x18\x02 \x01(\tR\x02tx\"'\n" +
+	"\x11BroadcastResponse\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\"\r\n" +
+	"\vStopRequest\"\x0e\n" +
+	"\fStopResponse\"0\n" +
+	"\rWalletRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\"\x14\n" +
+	"\x12ListWalletsRequest\"8\n" +
+	"\x13ListWalletsResponse\x12!\n" +
+	"\fwallet_names\x18\x01 \x03(\tR\vwalletNames\"R\n" +
+	"\x13CreateWalletRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"2\n" +
+	"\x14CreateWalletResponse\x12\x1a\n" +
+	"\bmnemonic\x18\x01 \x01(\tR\bmnemonic\"o\n" +
+	"\x14RecoverWalletRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x1a\n" +
+	"\bmnemonic\x18\x02 \x01(\tR\bmnemonic\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\"\x17\n" +
+	"\x15RecoverWalletResponse\"\x14\n" +
+	"\x12LoadWalletResponse\"\xe8\x03\n" +
+	"\n" +
+	"WalletInfo\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x1f\n" +
+	"\vwallet_file\x18\x02 \x01(\tR\n" +
+	"walletFile\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x124\n" +
+	"\x16master_key_fingerprint\x18\x04 \x01(\tR\x14masterKeyFingerprint\x12*\n" +
+	"\x11anon_score_target\x18\x05 \x01(\x05R\x0fanonScoreTarget\x12\"\n" +
+	"\ris_watch_only\x18\x06 \x01(\bR\visWatchOnly\x12,\n" +
+	"\x12is_hardware_wallet\x18\a \x01(\bR\x10isHardwareWallet\x12(\n" +
+	"\x10is_auto_coinjoin\x18\b \x01(\bR\x0eisAutoCoinjoin\x121\n" +
+	"\x15is_red_coin_isolation\x18\t \x01(\bR\x12isRedCoinIsolation\x12.\n" +
+	"\baccounts\x18\n" +
+	" \x03(\v2\x12.wasabi.v1.AccountR\baccounts\x12\x18\n" +
+	"\abalance\x18\v \x01(\x03R\abalance\x12'\n" +
+	"\x0fcoinjoin_status\x18\f \x01(\tR\x0ecoinjoinStatus\"W\n" +
+	"\aAccount\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\tR\tpublicKey\x12\x19\n" +
+	"\bkey_path\x18\x03 \x01(\tR\akeyPath\"V\n" +
+	"\x10ListCoinsRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12!\n" +
+	"\funspent_only\x18\x02 \x01(\bR\vunspentOnly\":\n" +
+	"\x11ListCoinsResponse\x12%\n" +
+	"\x05coins\x18\x01 \x03(\v2\x0f.wasabi.v1.CoinR\x05coins\"\xd1\x02\n" +
+	"\x04Coin\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x05R\x05index\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fanonymity_score\x18\x04 \x01(\x01R\x0eanonymityScore\x12\x1c\n" +
+	"\tconfirmed\x18\x05 \x01(\bR\tconfirmed\x12$\n" +
+	"\rconfirmations\x18\x06 \x01(\x05R\rconfirmations\x12\x19\n" +
+	"\bkey_path\x18\a \x01(\tR\akeyPath\x12\x18\n" +
+	"\aaddress\x18\b \x01(\tR\aaddress\x12\x19\n" +
+	"\bspent_by\x18\t \x01(\tR\aspentBy\x12\x14\n" +
+	"\x05label\x18\n" +
+	" \x01(\tR\x05label\x124\n" +
+	"\x16excluded_from_coinjoin\x18\v \x01(\bR\x14excludedFromCoinjoin\"P\n" +
+	"\x12GetHistoryResponse\x12:\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x16.wasabi.v1.TransactionR\ftransactions\"\xcd\x01\n" +
+	"\vTransaction\x126\n" +
+	"\bdatetime\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\bdatetime\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x05R\x06height\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x14\n" +
+	"\x05label\x18\x04 \x01(\tR\x05label\x12\x12\n" +
+	"\x04txid\x18\x05 \x01(\tR\x04txid\x12,\n" +
+	"\x12is_likely_coinjoin\x18\x06 \x01(\bR\x10isLikelyCoinjoin\"6\n" +
+	"\x10ListKeysResponse\x12\"\n" +
+	"\x04keys\x18\x01 \x03(\v2\x0e.wasabi.v1.KeyR\x04keys\"\xf3\x01\n" +
+	"\x03Key\x12\"\n" +
+	"\rfull_key_path\x18\x01 \x01(\tR\vfullKeyPath\x12\x1a\n" +
+	"\binternal\x18\x02 \x01(\bR\binternal\x12\x1b\n" +
+	"\tkey_state\x18\x03 \x01(\x05R\bkeyState\x12\x14\n" +
+	"\x05label\x18\x04 \x01(\tR\x05label\x12$\n" +
+	"\x0escript_pub_key\x18\x05 \x01(\tR\fscriptPubKey\x12\x17\n" +
+	"\apub_key\x18\x06 \x01(\tR\x06pubKey\x12 \n" +
+	"\fpub_key_hash\x18\a \x01(\tR\n" +
+	"pubKeyHash\x12\x18\n" +
+	"\aaddress\x18\b \x01(\tR\aaddress\"M\n" +
+	"\x14GetNewAddressRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\"\x99\x01\n" +
+	"\aAddress\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x19\n" +
+	"\bkey_path\x18\x02 \x01(\tR\akeyPath\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x04 \x01(\tR\tpublicKey\x12$\n" +
+	"\x0escript_pub_key\x18\x05 \x01(\tR\fscriptPubKey\"s\n" +
+	"\aPayment\x12\x17\n" +
+	"\asend_to\x18\x01 \x01(\tR\x06sendTo\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12!\n" +
+	"\fsubtract_fee\x18\x04 \x01(\bR\vsubtractFee\"4\n" +
+	"\bOutPoint\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x05R\x05index\"\x8a\x02\n" +
+	"\vSendRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12.\n" +
+	"\bpayments\x18\x02 \x03(\v2\x12.wasabi.v1.PaymentR\bpayments\x12)\n" +
+	"\x05coins\x18\x03 \x03(\v2\x13.wasabi.v1.OutPointR\x05coins\x12\x1d\n" +
+	"\n" +
+	"fee_target\x18\x04 \x01(\x05R\tfeeTarget\x12\x19\n" +
+	"\bfee_rate\x18\x05 \x01(\x01R\afeeRate\x12\x1a\n" +
+	"\bpassword\x18\x06 \x01(\tR\bpassword\x12)\n" +
+	"\x10payjoin_endpoint\x18\a \x01(\tR\x0fpayjoinEndpoint\"2\n" +
+	"\fSendResponse\x12\x12\n" +
+	"\x04txid\x18\x01 \x01(\tR\x04txid\x12\x0e\n" +
+	"\x02tx\x18\x02 \x01(\tR\x02tx\"\xe0\x01\n" +
+	"\fBuildRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12.\n" +
+	"\bpayments\x18\x02 \x03(\v2\x12.wasabi.v1.PaymentR\bpayments\x12)\n" +
+	"\x05coins\x18\x03 \x03(\v2\x13.wasabi.v1.OutPointR\x05coins\x12\x1d\n" +
+	"\n" +
+	"fee_target\x18\x04 \x01(\x05R\tfeeTarget\x12\x19\n" +
+	"\bfee_rate\x18\x05 \x01(\x01R\afeeRate\x12\x1a\n" +
+	"\bpassword\x18\x06 \x01(\tR\bpassword\" \n" +
+	"\x0eTransactionHex\x12\x0e\n" +
+	"\x02tx\x18\x01 \x01(\tR\x02tx\"l\n" +
+	"\x19ReplaceTransactionRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x12\n" +
+	"\x04txid\x18\x02 \x01(\tR\x04txid\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\"\xb0\x01\n" +
+	"\x14StartCoinJoinRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12-\n" +
+	"\x13stop_when_all_mixed\x18\x03 \x01(\bR\x10stopWhenAllMixed\x12,\n" +
+	"\x12override_pleb_stop\x18\x04 \x01(\bR\x10overridePlebStop\"\x86\x01\n" +
+	"\x19StartCoinJoinSweepRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12,\n" +
+	"\x12output_wallet_name\x18\x03 \x01(\tR\x10outputWalletName\"\x17\n" +
+	"\x15StartCoinJoinResponse\"\x16\n" +
+	"\x14StopCoinJoinResponse\"\x82\x01\n" +
+	"\x1aExcludeFromCoinJoinRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12)\n" +
+	"\x05coins\x18\x02 \x03(\v2\x13.wasabi.v1.OutPointR\x05coins\x12\x18\n" +
+	"\aexclude\x18\x03 \x01(\bR\aexclude\"\x1d\n" +
+	"\x1bExcludeFromCoinJoinResponse\"\x85\x01\n" +
+	"\x14PayInCoinJoinRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bpassword\x18\x04 \x01(\tR\bpassword\"6\n" +
+	"\x15PayInCoinJoinResponse\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\"Z\n" +
+	"\x1eListPaymentsInCoinJoinResponse\x128\n" +
+	"\bpayments\x18\x01 \x03(\v2\x1c.wasabi.v1.PaymentInCoinJoinR\bpayments\"\xa6\x01\n" +
+	"\x11PaymentInCoinJoin\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12-\n" +
+	"\x05state\x18\x04 \x03(\v2\x17.wasabi.v1.PaymentStateR\x05state\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\tR\aaddress\"P\n" +
+	"\fPaymentState\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05round\x18\x02 \x01(\x05R\x05round\x12\x12\n" +
+	"\x04txid\x18\x03 \x01(\tR\x04txid\"`\n" +
+	"\x1eCancelPaymentInCoinJoinRequest\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\"!\n" +
+	"\x1fCancelPaymentInCoinJoinResponse\"K\n" +
+	"\x12WatchStatusRequest\x125\n" +
+	"\binterval\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\binterval\"n\n" +
+	"\x12WatchEventsRequest\x12!\n" +
+	"\fwallet_names\x18\x01 \x03(\tR\vwalletNames\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\xde\x03\n" +
+	"\x05Event\x12I\n" +
+	"\x0fnew_transaction\x18\x01 \x01(\v2\x1e.wasabi.v1.NewTransactionEventH\x00R\x0enewTransaction\x12X\n" +
+	"\x14confirmation_reached\x18\x02 \x01(\v2#.wasabi.v1.ConfirmationReachedEventH\x00R\x13confirmationReached\x12_\n" +
+	"\x17coinjoin_status_changed\x18\x03 \x01(\v2%.wasabi.v1.CoinJoinStatusChangedEventH\x00R\x15coinjoinStatusChanged\x12X\n" +
+	"\x14backend_disconnected\x18\x04 \x01(\v2#.wasabi.v1.BackendDisconnectedEventH\x00R\x13backendDisconnected\x12l\n" +
+	"\x1cpayment_in_coinjoin_finished\x18\x05 \x01(\v2).wasabi.v1.PaymentInCoinJoinFinishedEventH\x00R\x19paymentInCoinjoinFinishedB\a\n" +
+	"\x05event\"p\n" +
+	"\x13NewTransactionEvent\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x128\n" +
+	"\vtransaction\x18\x02 \x01(\v2\x16.wasabi.v1.TransactionR\vtransaction\"\x8d\x01\n" +
+	"\x18ConfirmationReachedEvent\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x128\n" +
+	"\vtransaction\x18\x02 \x01(\v2\x16.wasabi.v1.TransactionR\vtransaction\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x05R\x06height\"s\n" +
+	"\x1aCoinJoinStatusChangedEvent\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x12\x1a\n" +
+	"\bprevious\x18\x02 \x01(\tR\bprevious\x12\x18\n" +
+	"\acurrent\x18\x03 \x01(\tR\acurrent\"H\n" +
+	"\x18BackendDisconnectedEvent\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"y\n" +
+	"\x1ePaymentInCoinJoinFinishedEvent\x12\x1f\n" +
+	"\vwallet_name\x18\x01 \x01(\tR\n" +
+	"walletName\x126\n" +
+	"\apayment\x18\x02 \x01(\v2\x1c.wasabi.v1.PaymentInCoinJoinR\apayment2\xa6\x10\n" +
+	"\x06Wasabi\x12;\n" +
+	"\tGetStatus\x12\x1b.wasabi.v1.GetStatusRequest\x1a\x11.wasabi.v1.Status\x12L\n" +
+	"\vGetFeeRates\x12\x1d.wasabi.v1.GetFeeRatesRequest\x1a\x1e.wasabi.v1.GetFeeRatesResponse\x12F\n" +
+	"\tBroadcast\x12\x1b.wasabi.v1.BroadcastRequest\x1a\x1c.wasabi.v1.BroadcastResponse\x127\n" +
+	"\x04Stop\x12\x16.wasabi.v1.StopRequest\x1a\x17.wasabi.v1.StopResponse\x12L\n" +
+	"\vListWallets\x12\x1d.wasabi.v1.ListWalletsRequest\x1a\x1e.wasabi.v1.ListWalletsResponse\x12O\n" +
+	"\fCreateWallet\x12\x1e.wasabi.v1.CreateWalletRequest\x1a\x1f.wasabi.v1.CreateWalletResponse\x12R\n" +
+	"\rRecoverWallet\x12\x1f.wasabi.v1.RecoverWalletRequest\x1a .wasabi.v1.RecoverWalletResponse\x12E\n" +
+	"\n" +
+	"LoadWallet\x12\x18.wasabi.v1.WalletRequest\x1a\x1d.wasabi.v1.LoadWalletResponse\x12@\n" +
+	"\rGetWalletInfo\x12\x18.wasabi.v1.WalletRequest\x1a\x15.wasabi.v1.WalletInfo\x12F\n" +
+	"\tListCoins\x12\x1b.wasabi.v1.ListCoinsRequest\x1a\x1c.wasabi.v1.ListCoinsResponse\x12E\n" +
+	"\n" +
+	"GetHistory\x12\x18.wasabi.v1.WalletRequest\x1a\x1d.wasabi.v1.GetHistoryResponse\x12A\n" +
+	"\bListKeys\x12\x18.wasabi.v1.WalletRequest\x1a\x1b.wasabi.v1.ListKeysResponse\x12D\n" +
+	"\rGetNewAddress\x12\x1f.wasabi.v1.GetNewAddressRequest\x1a\x12.wasabi.v1.Address\x127\n" +
+	"\x04Send\x12\x16.wasabi.v1.SendRequest\x1a\x17.wasabi.v1.SendResponse\x12;\n" +
+	"\x05Build\x12\x17.wasabi.v1.BuildRequest\x1a\x19.wasabi.v1.TransactionHex\x12L\n" +
+	"\x16BuildUnsafeTransaction\x12\x17.wasabi.v1.BuildRequest\x1a\x19.wasabi.v1.TransactionHex\x12U\n" +
+	"\x12SpeedUpTransaction\x12$.wasabi.v1.ReplaceTransactionRequest\x1a\x19.wasabi.v1.TransactionHex\x12T\n" +
+	"\x11CancelTransaction\x12$.wasabi.v1.ReplaceTransactionRequest\x1a\x19.wasabi.v1.TransactionHex\x12R\n" +
+	"\rStartCoinJoin\x12\x1f.wasabi.v1.StartCoinJoinRequest\x1a .wasabi.v1.StartCoinJoinResponse\x12\\\n" +
+	"\x12StartCoinJoinSweep\x12$.wasabi.v1.StartCoinJoinSweepRequest\x1a .wasabi.v1.StartCoinJoinResponse\x12I\n" +
+	"\fStopCoinJoin\x12\x18.wasabi.v1.WalletRequest\x1a\x1f.wasabi.v1.StopCoinJoinResponse\x12d\n" +
+	"\x13ExcludeFromCoinJoin\x12%.wasabi.v1.ExcludeFromCoinJoinRequest\x1a&.wasabi.v1.ExcludeFromCoinJoinResponse\x12R\n" +
+	"\rPayInCoinJoin\x12\x1f.wasabi.v1.PayInCoinJoinRequest\x1a .wasabi.v1.PayInCoinJoinResponse\x12]\n" +
+	"\x16ListPaymentsInCoinJoin\x12\x18.wasabi.v1.WalletRequest\x1a).wasabi.v1.ListPaymentsInCoinJoinResponse\x12p\n" +
+	"\x17CancelPaymentInCoinJoin\x12).wasabi.v1.CancelPaymentInCoinJoinRequest\x1a*.wasabi.v1.CancelPaymentInCoinJoinResponse\x12A\n" +
+	"\vWatchStatus\x12\x1d.wasabi.v1.WatchStatusRequest\x1a\x11.wasabi.v1.Status0\x01\x12@\n" +
+	"\vWatchEvents\x12\x1d.wasabi.v1.WatchEventsRequest\x1a\x10.wasabi.v1.Event0\x01BBZ@github.com/acfnv/go-wasabi-rpc-client/wasabi/grpcwasabi/wasabipbb\x06proto3"
+
+var (
+	file_wasabi_v1_wasabi_proto_rawDescOnce sync.Once
+	file_wasabi_v1_wasabi_proto_rawDescData []byte
+)
+
+func file_wasabi_v1_wasabi_proto_rawDescGZIP() []byte {
+	file_wasabi_v1_wasabi_proto_rawDescOnce.Do(func() {
+		file_wasabi_v1_wasabi_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_wasabi_v1_wasabi_proto_rawDesc), len(file_wasabi_v1_wasabi_proto_rawDesc)))
+	})
+	return file_wasabi_v1_wasabi_proto_rawDescData
+}
+
+var file_wasabi_v1_wasabi_proto_msgTypes = make([]protoimpl.MessageInfo, 57)
+var file_wasabi_v1_wasabi_proto_goTypes = []any{
+	(*GetStatusRequest)(nil),                // 0: wasabi.v1.GetStatusRequest
+	(*Status)(nil),                          // 1: wasabi.v1.Status
+	(*Peer)(nil),                            // 2: wasabi.v1.Peer
+	(*GetFeeRatesRequest)(nil),              // 3: wasabi.v1.GetFeeRatesRequest
+	(*GetFeeRatesResponse)(nil),             // 4: wasabi.v1.GetFeeRatesResponse
+	(*BroadcastRequest)(nil),                // 5: wasabi.v1.BroadcastRequest
+	(*BroadcastResponse)(nil),               // 6: wasabi.v1.BroadcastResponse
+	(*StopRequest)(nil),                     // 7: wasabi.v1.StopRequest
+	(*StopResponse)(nil),                    // 8: wasabi.v1.StopResponse
+	(*WalletRequest)(nil),                   // 9: wasabi.v1.WalletRequest
+	(*ListWalletsRequest)(nil),              // 10: wasabi.v1.ListWalletsRequest
+	(*ListWalletsResponse)(nil),             // 11: wasabi.v1.ListWalletsResponse
+	(*CreateWalletRequest)(nil),             // 12: wasabi.v1.CreateWalletRequest
+	(*CreateWalletResponse)(nil),            // 13: wasabi.v1.CreateWalletResponse
+	(*RecoverWalletRequest)(nil),            // 14: wasabi.v1.RecoverWalletRequest
+	(*RecoverWalletResponse)(nil),           // 15: wasabi.v1.RecoverWalletResponse
+	(*LoadWalletResponse)(nil),              // 16: wasabi.v1.LoadWalletResponse
+	(*WalletInfo)(nil),                      // 17: wasabi.v1.WalletInfo
+	(*Account)(nil),                         // 18: wasabi.v1.Account
+	(*ListCoinsRequest)(nil),                // 19: wasabi.v1.ListCoinsRequest
+	(*ListCoinsResponse)(nil),               // 20: wasabi.v1.ListCoinsResponse
+	(*Coin)(nil),                            // 21: wasabi.v1.Coin
+	(*GetHistoryResponse)(nil),              // 22: wasabi.v1.GetHistoryResponse
+	(*Transaction)(nil),                     // 23: wasabi.v1.Transaction
+	(*ListKeysResponse)(nil),                // 24: wasabi.v1.ListKeysResponse
+	(*Key)(nil),                             // 25: wasabi.v1.Key
+	(*GetNewAddressRequest)(nil),            // 26: wasabi.v1.GetNewAddressRequest
+	(*Address)(nil),                         // 27: wasabi.v1.Address
+	(*Payment)(nil),                         // 28: wasabi.v1.Payment
+	(*OutPoint)(nil),                        // 29: wasabi.v1.OutPoint
+	(*SendRequest)(nil),                     // 30: wasabi.v1.SendRequest
+	(*SendResponse)(nil),                    // 31: wasabi.v1.SendResponse
+	(*BuildRequest)(nil),                    // 32: wasabi.v1.BuildRequest
+	(*TransactionHex)(nil),                  // 33: wasabi.v1.TransactionHex
+	(*ReplaceTransactionRequest)(nil),       // 34: wasabi.v1.ReplaceTransactionRequest
+	(*StartCoinJoinRequest)(nil),            // 35: wasabi.v1.StartCoinJoinRequest
+	(*StartCoinJoinSweepRequest)(nil),       // 36: wasabi.v1.StartCoinJoinSweepRequest
+	(*StartCoinJoinResponse)(nil),           // 37: wasabi.v1.StartCoinJoinResponse
+	(*StopCoinJoinResponse)(nil),            // 38: wasabi.v1.StopCoinJoinResponse
+	(*ExcludeFromCoinJoinRequest)(nil),      // 39: wasabi.v1.ExcludeFromCoinJoinRequest
+	(*ExcludeFromCoinJoinResponse)(nil),     // 40: wasabi.v1.ExcludeFromCoinJoinResponse
+	(*PayInCoinJoinRequest)(nil),            // 41: wasabi.v1.PayInCoinJoinRequest
+	(*PayInCoinJoinResponse)(nil),           // 42: wasabi.v1.PayInCoinJoinResponse
+	(*ListPaymentsInCoinJoinResponse)(nil),  // 43: wasabi.v1.ListPaymentsInCoinJoinResponse
+	(*PaymentInCoinJoin)(nil),               // 44: wasabi.v1.PaymentInCoinJoin
+	(*PaymentState)(nil),                    // 45: wasabi.v1.PaymentState
+	(*CancelPaymentInCoinJoinRequest)(nil),  // 46: wasabi.v1.CancelPaymentInCoinJoinRequest
+	(*CancelPaymentInCoinJoinResponse)(nil), // 47: wasabi.v1.CancelPaymentInCoinJoinResponse
+	(*WatchStatusRequest)(nil),              // 48: wasabi.v1.WatchStatusRequest
+	(*WatchEventsRequest)(nil),              // 49: wasabi.v1.WatchEventsRequest
+	(*Event)(nil),                           // 50: wasabi.v1.Event
+	(*NewTransactionEvent)(nil),             // 51: wasabi.v1.NewTransactionEvent
+	(*ConfirmationReachedEvent)(nil),        // 52: wasabi.v1.ConfirmationReachedEvent
+	(*CoinJoinStatusChangedEvent)(nil),      // 53: wasabi.v1.CoinJoinStatusChangedEvent
+	(*BackendDisconnectedEvent)(nil),        // 54: wasabi.v1.BackendDisconnectedEvent
+	(*PaymentInCoinJoinFinishedEvent)(nil),  // 55: wasabi.v1.PaymentInCoinJoinFinishedEvent
+	nil,                                     // 56: wasabi.v1.GetFeeRatesResponse.FeeRatesEntry
+	(*timestamppb.Timestamp)(nil),           // 57: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),             // 58: google.protobuf.Duration
+}
+var file_wasabi_v1_wasabi_proto_depIdxs = []int32{
+	2,  // 0: wasabi.v1.Status.peers:type_name -> wasabi.v1.Peer
+	57, // 1: wasabi.v1.Peer.last_seen:type_name -> google.protobuf.Timestamp
+	56, // 2: wasabi.v1.GetFeeRatesResponse.fee_rates:type_name -> wasabi.v1.GetFeeRatesResponse.FeeRatesEntry
+	18, // 3: wasabi.v1.WalletInfo.accounts:type_name -> wasabi.v1.Account
+	21, // 4: wasabi.v1.ListCoinsResponse.coins:type_name -> wasabi.v1.Coin
+	23, // 5: wasabi.v1.GetHistoryResponse.transactions:type_name -> wasabi.v1.Transaction
+	57, // 6: wasabi.v1.Transaction.datetime:type_name -> google.protobuf.Timestamp
+	25, // 7: wasabi.v1.ListKeysResponse.keys:type_name -> wasabi.v1.Key
+	28, // 8: wasabi.v1.SendRequest.payments:type_name -> wasabi.v1.Payment
+	29, // 9: wasabi.v1.SendRequest.coins:type_name -> wasabi.v1.OutPoint
+	28, // 10: wasabi.v1.BuildRequest.payments:type_name -> wasabi.v1.Payment
+	29, // 11: wasabi.v1.BuildRequest.coins:type_name -> wasabi.v1.OutPoint
+	29, // 12: wasabi.v1.ExcludeFromCoinJoinRequest.coins:type_name -> wasabi.v1.OutPoint
+	44, // 13: wasabi.v1.ListPaymentsInCoinJoinResponse.payments:type_name -> wasabi.v1.PaymentInCoinJoin
+	45, // 14: wasabi.v1.PaymentInCoinJoin.state:type_name -> wasabi.v1.PaymentState
+	58, // 15: wasabi.v1.WatchStatusRequest.interval:type_name -> google.protobuf.Duration
+	58, // 16: wasabi.v1.WatchEventsRequest.interval:type_name -> google.protobuf.Duration
+	51, // 17: wasabi.v1.Event.new_transaction:type_name -> wasabi.v1.NewTransactionEvent
+	52, // 18: wasabi.v1.Event.confirmation_reached:type_name -> wasabi.v1.ConfirmationReachedEvent
+	53, // 19: wasabi.v1.Event.coinjoin_status_changed:type_name -> wasabi.v1.CoinJoinStatusChangedEvent
+	54, // 20: wasabi.v1.Event.backend_disconnected:type_name -> wasabi.v1.BackendDisconnectedEvent
+	55, // 21: wasabi.v1.Event.payment_in_coinjoin_finished:type_name -> wasabi.v1.PaymentInCoinJoinFinishedEvent
+	23, // 22: wasabi.v1.NewTransactionEvent.transaction:type_name -> wasabi.v1.Transaction
+	23, // 23: wasabi.v1.ConfirmationReachedEvent.transaction:type_name -> wasabi.v1.Transaction
+	44, // 24: wasabi.v1.PaymentInCoinJoinFinishedEvent.payment:type_name -> wasabi.v1.PaymentInCoinJoin
+	0,  // 25: wasabi.v1.Wasabi.GetStatus:input_type -> wasabi.v1.GetStatusRequest
+	3,  // 26: wasabi.v1.Wasabi.GetFeeRates:input_type -> wasabi.v1.GetFeeRatesRequest
+	5,  // 27: wasabi.v1.Wasabi.Broadcast:input_type -> wasabi.v1.BroadcastRequest
+	7,  // 28: wasabi.v1.Wasabi.Stop:input_type -> wasabi.v1.StopRequest
+	10, // 29: wasabi.v1.Wasabi.ListWallets:input_type -> wasabi.v1.ListWalletsRequest
+	12, // 30: wasabi.v1.Wasabi.CreateWallet:input_type -> wasabi.v1.CreateWalletRequest
+	14, // 31: wasabi.v1.Wasabi.RecoverWallet:input_type -> wasabi.v1.RecoverWalletRequest
+	9,  // 32: wasabi.v1.Wasabi.LoadWallet:input_type -> wasabi.v1.WalletRequest
+	9,  // 33: wasabi.v1.Wasabi.GetWalletInfo:input_type -> wasabi.v1.WalletRequest
+	19, // 34: wasabi.v1.Wasabi.ListCoins:input_type -> wasabi.v1.ListCoinsRequest
+	9,  // 35: wasabi.v1.Wasabi.GetHistory:input_type -> wasabi.v1.WalletRequest
+	9,  // 36: wasabi.v1.Wasabi.ListKeys:input_type -> wasabi.v1.WalletRequest
+	26, // 37: wasabi.v1.Wasabi.GetNewAddress:input_type -> wasabi.v1.GetNewAddressRequest
+	30, // 38: wasabi.v1.Wasabi.Send:input_type -> wasabi.v1.SendRequest
+	32, // 39: wasabi.v1.Wasabi.Build:input_type -> wasabi.v1.BuildRequest
+	32, // 40: wasabi.v1.Wasabi.BuildUnsafeTransaction:input_type -> wasabi.v1.BuildRequest
+	34, // 41: wasabi.v1.Wasabi.SpeedUpTransaction:input_type -> wasabi.v1.ReplaceTransactionRequest
+	34, // 42: wasabi.v1.Wasabi.CancelTransaction:input_type -> wasabi.v1.ReplaceTransactionRequest
+	35, // 43: wasabi.v1.Wasabi.StartCoinJoin:input_type -> wasabi.v1.StartCoinJoinRequest
+	36, // 44: wasabi.v1.Wasabi.StartCoinJoinSweep:input_type -> wasabi.v1.StartCoinJoinSweepRequest
+	9,  // 45: wasabi.v1.Wasabi.StopCoinJoin:input_type -> wasabi.v1.WalletRequest
+	39, // 46: wasabi.v1.Wasabi.ExcludeFromCoinJoin:input_type -> wasabi.v1.ExcludeFromCoinJoinRequest
+	41, // 47: wasabi.v1.Wasabi.PayInCoinJoin:input_type -> wasabi.v1.PayInCoinJoinRequest
+	9,  // 48: wasabi.v1.Wasabi.ListPaymentsInCoinJoin:input_type -> wasabi.v1.WalletRequest
+	46, // 49: wasabi.v1.Wasabi.CancelPaymentInCoinJoin:input_type -> wasabi.v1.CancelPaymentInCoinJoinRequest
+	48, // 50: wasabi.v1.Wasabi.WatchStatus:input_type -> wasabi.v1.WatchStatusRequest
+	49, // 51: wasabi.v1.Wasabi.WatchEvents:input_type -> wasabi.v1.WatchEventsRequest
+	1,  // 52: wasabi.v1.Wasabi.GetStatus:output_type -> wasabi.v1.Status
+	4,  // 53: wasabi.v1.Wasabi.GetFeeRates:output_type -> wasabi.v1.GetFeeRatesResponse
+	6,  // 54: wasabi.v1.Wasabi.Broadcast:output_type -> wasabi.v1.BroadcastResponse
+	8,  // 55: wasabi.v1.Wasabi.Stop:output_type -> wasabi.v1.StopResponse
+	11, // 56: wasabi.v1.Wasabi.ListWallets:output_type -> wasabi.v1.ListWalletsResponse
+	13, // 57: wasabi.v1.Wasabi.CreateWallet:output_type -> wasabi.v1.CreateWalletResponse
+	15, // 58: wasabi.v1.Wasabi.RecoverWallet:output_type -> wasabi.v1.RecoverWalletResponse
+	16, // 59: wasabi.v1.Wasabi.LoadWallet:output_type -> wasabi.v1.LoadWalletResponse
+	17, // 60: wasabi.v1.Wasabi.GetWalletInfo:output_type -> wasabi.v1.WalletInfo
+	20, // 61: wasabi.v1.Wasabi.ListCoins:output_type -> wasabi.v1.ListCoinsResponse
+	22, // 62: wasabi.v1.Wasabi.GetHistory:output_type -> wasabi.v1.GetHistoryResponse
+	24, // 63: wasabi.v1.Wasabi.ListKeys:output_type -> wasabi.v1.ListKeysResponse
+	27, // 64: wasabi.v1.Wasabi.GetNewAddress:output_type -> wasabi.v1.Address
+	31, // 65: wasabi.v1.Wasabi.Send:output_type -> wasabi.v1.SendResponse
+	33, // 66: wasabi.v1.Wasabi.Build:output_type -> wasabi.v1.TransactionHex
+	33, // 67: wasabi.v1.Wasabi.BuildUnsafeTransaction:output_type -> wasabi.v1.TransactionHex
+	33, // 68: wasabi.v1.Wasabi.SpeedUpTransaction:output_type -> wasabi.v1.TransactionHex
+	33, // 69: wasabi.v1.Wasabi.CancelTransaction:output_type -> wasabi.v1.TransactionHex
+	37, // 70: wasabi.v1.Wasabi.StartCoinJoin:output_type -> wasabi.v1.StartCoinJoinResponse
+	37, // 71: wasabi.v1.Wasabi.StartCoinJoinSweep:output_type -> wasabi.v1.StartCoinJoinResponse
+	38, // 72: wasabi.v1.Wasabi.StopCoinJoin:output_type -> wasabi.v1.StopCoinJoinResponse
+	40, // 73: wasabi.v1.Wasabi.ExcludeFromCoinJoin:output_type -> wasabi.v1.ExcludeFromCoinJoinResponse
+	42, // 74: wasabi.v1.Wasabi.PayInCoinJoin:output_type -> wasabi.v1.PayInCoinJoinResponse
+	43, // 75: wasabi.v1.Wasabi.ListPaymentsInCoinJoin:output_type -> wasabi.v1.ListPaymentsInCoinJoinResponse
+	47, // 76: wasabi.v1.Wasabi.CancelPaymentInCoinJoin:output_type -> wasabi.v1.CancelPaymentInCoinJoinResponse
+	1,  // 77: wasabi.v1.Wasabi.WatchStatus:output_type -> wasabi.v1.Status
+	50, // 78: wasabi.v1.Wasabi.WatchEvents:output_type -> wasabi.v1.Event
+	52, // [52:79] is the sub-list for method output_type
+	25, // [25:52] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_wasabi_v1_wasabi_proto_init() }
+func file_wasabi_v1_wasabi_proto_init() {
+	if File_wasabi_v1_wasabi_proto != nil {
+		return
+	}
+	file_wasabi_v1_wasabi_proto_msgTypes[50].OneofWrappers = []any{
+		(*Event_NewTransaction)(nil),
+		(*Event_ConfirmationReached)(nil),
+		(*Event_CoinjoinStatusChanged)(nil),
+		(*Event_BackendDisconnected)(nil),
+		(*Event_PaymentInCoinjoinFinished)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wasabi_v1_wasabi_proto_rawDesc), len(file_wasabi_v1_wasabi_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   57,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_wasabi_v1_wasabi_proto_goTypes,
+		DependencyIndexes: file_wasabi_v1_wasabi_proto_depIdxs,
+		MessageInfos:      file_wasabi_v1_wasabi_proto_msgTypes,
+	}.Build()
+	File_wasabi_v1_wasabi_proto = out.File
+	file_wasabi_v1_wasabi_proto_goTypes = nil
+	file_wasabi_v1_wasabi_proto_depIdxs = nil
+}
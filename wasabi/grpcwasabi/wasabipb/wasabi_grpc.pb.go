@@ -0,0 +1,1134 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: wasabi/v1/wasabi.proto
+
+package wasabipb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Wasabi_GetStatus_FullMethodName               = "/wasabi.v1.Wasabi/GetStatus"
+	Wasabi_GetFeeRates_FullMethodName             = "/wasabi.v1.Wasabi/GetFeeRates"
+	Wasabi_Broadcast_FullMethodName               = "/wasabi.v1.Wasabi/Broadcast"
+	Wasabi_Stop_FullMethodName                    = "/wasabi.v1.Wasabi/Stop"
+	Wasabi_ListWallets_FullMethodName             = "/wasabi.v1.Wasabi/ListWallets"
+	Wasabi_CreateWallet_FullMethodName            = "/wasabi.v1.Wasabi/CreateWallet"
+	Wasabi_RecoverWallet_FullMethodName           = "/wasabi.v1.Wasabi/RecoverWallet"
+	Wasabi_LoadWallet_FullMethodName              = "/wasabi.v1.Wasabi/LoadWallet"
+	Wasabi_GetWalletInfo_FullMethodName           = "/wasabi.v1.Wasabi/GetWalletInfo"
+	Wasabi_ListCoins_FullMethodName               = "/wasabi.v1.Wasabi/ListCoins"
+	Wasabi_GetHistory_FullMethodName              = "/wasabi.v1.Wasabi/GetHistory"
+	Wasabi_ListKeys_FullMethodName                = "/wasabi.v1.Wasabi/ListKeys"
+	Wasabi_GetNewAddress_FullMethodName           = "/wasabi.v1.Wasabi/GetNewAddress"
+	Wasabi_Send_FullMethodName                    = "/wasabi.v1.Wasabi/Send"
+	Wasabi_Build_FullMethodName                   = "/wasabi.v1.Wasabi/Build"
+	Wasabi_BuildUnsafeTransaction_FullMethodName  = "/wasabi.v1.Wasabi/BuildUnsafeTransaction"
+	Wasabi_SpeedUpTransaction_FullMethodName      = "/wasabi.v1.Wasabi/SpeedUpTransaction"
+	Wasabi_CancelTransaction_FullMethodName       = "/wasabi.v1.Wasabi/CancelTransaction"
+	Wasabi_StartCoinJoin_FullMethodName           = "/wasabi.v1.Wasabi/StartCoinJoin"
+	Wasabi_StartCoinJoinSweep_FullMethodName      = "/wasabi.v1.Wasabi/StartCoinJoinSweep"
+	Wasabi_StopCoinJoin_FullMethodName            = "/wasabi.v1.Wasabi/StopCoinJoin"
+	Wasabi_ExcludeFromCoinJoin_FullMethodName     = "/wasabi.v1.Wasabi/ExcludeFromCoinJoin"
+	Wasabi_PayInCoinJoin_FullMethodName           = "/wasabi.v1.Wasabi/PayInCoinJoin"
+	Wasabi_ListPaymentsInCoinJoin_FullMethodName  = "/wasabi.v1.Wasabi/ListPaymentsInCoinJoin"
+	Wasabi_CancelPaymentInCoinJoin_FullMethodName = "/wasabi.v1.Wasabi/CancelPaymentInCoinJoin"
+	Wasabi_WatchStatus_FullMethodName             = "/wasabi.v1.Wasabi/WatchStatus"
+	Wasabi_WatchEvents_FullMethodName             = "/wasabi.v1.Wasabi/WatchEvents"
+)
+
+// WasabiClient is the client API for Wasabi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Wasabi is the gRPC surface of a Wasabi Wallet daemon. The rpcs mirror the JSON-RPC methods of the daemon; amounts are in satoshis and statuses are the strings reported by the daemon.
+type WasabiClient interface {
+	// Daemon.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error)
+	GetFeeRates(ctx context.Context, in *GetFeeRatesRequest, opts ...grpc.CallOption) (*GetFeeRatesResponse, error)
+	Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastResponse, error)
+	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	// Wallets.
+	ListWallets(ctx context.Context, in *ListWalletsRequest, opts ...grpc.CallOption) (*ListWalletsResponse, error)
+	CreateWallet(ctx context.Context, in *CreateWalletRequest, opts ...grpc.CallOption) (*CreateWalletResponse, error)
+	RecoverWallet(ctx context.Context, in *RecoverWalletRequest, opts ...grpc.CallOption) (*RecoverWalletResponse, error)
+	LoadWallet(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*LoadWalletResponse, error)
+	GetWalletInfo(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*WalletInfo, error)
+	ListCoins(ctx context.Context, in *ListCoinsRequest, opts ...grpc.CallOption) (*ListCoinsResponse, error)
+	GetHistory(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+	ListKeys(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*ListKeysResponse, error)
+	GetNewAddress(ctx context.Context, in *GetNewAddressRequest, opts ...grpc.CallOption) (*Address, error)
+	// Transactions.
+	Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*TransactionHex, error)
+	BuildUnsafeTransaction(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*TransactionHex, error)
+	SpeedUpTransaction(ctx context.Context, in *ReplaceTransactionRequest, opts ...grpc.CallOption) (*TransactionHex, error)
+	CancelTransaction(ctx context.Context, in *ReplaceTransactionRequest, opts ...grpc.CallOption) (*TransactionHex, error)
+	// Coinjoin.
+	StartCoinJoin(ctx context.Context, in *StartCoinJoinRequest, opts ...grpc.CallOption) (*StartCoinJoinResponse, error)
+	StartCoinJoinSweep(ctx context.Context, in *StartCoinJoinSweepRequest, opts ...grpc.CallOption) (*StartCoinJoinResponse, error)
+	StopCoinJoin(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*StopCoinJoinResponse, error)
+	ExcludeFromCoinJoin(ctx context.Context, in *ExcludeFromCoinJoinRequest, opts ...grpc.CallOption) (*ExcludeFromCoinJoinResponse, error)
+	PayInCoinJoin(ctx context.Context, in *PayInCoinJoinRequest, opts ...grpc.CallOption) (*PayInCoinJoinResponse, error)
+	ListPaymentsInCoinJoin(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*ListPaymentsInCoinJoinResponse, error)
+	CancelPaymentInCoinJoin(ctx context.Context, in *CancelPaymentInCoinJoinRequest, opts ...grpc.CallOption) (*CancelPaymentInCoinJoinResponse, error)
+	// Streams.
+	// WatchStatus sends the status of the daemon when it changes.
+	WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Status], error)
+	// WatchEvents sends the events of the wallets: new transactions, confirmations, coinjoin status changes, backend disconnections and finished payments in coinjoin.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type wasabiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWasabiClient(cc grpc.ClientConnInterface) WasabiClient {
+	return &wasabiClient{cc}
+}
+
+func (c *wasabiClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Status)
+	err := c.cc.Invoke(ctx, Wasabi_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) GetFeeRates(ctx context.Context, in *GetFeeRatesRequest, opts ...grpc.CallOption) (*GetFeeRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFeeRatesResponse)
+	err := c.cc.Invoke(ctx, Wasabi_GetFeeRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BroadcastResponse)
+	err := c.cc.Invoke(ctx, Wasabi_Broadcast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopResponse)
+	err := c.cc.Invoke(ctx, Wasabi_Stop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) ListWallets(ctx context.Context, in *ListWalletsRequest, opts ...grpc.CallOption) (*ListWalletsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWalletsResponse)
+	err := c.cc.Invoke(ctx, Wasabi_ListWallets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) CreateWallet(ctx context.Context, in *CreateWalletRequest, opts ...grpc.CallOption) (*CreateWalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWalletResponse)
+	err := c.cc.Invoke(ctx, Wasabi_CreateWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) RecoverWallet(ctx context.Context, in *RecoverWalletRequest, opts ...grpc.CallOption) (*RecoverWalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecoverWalletResponse)
+	err := c.cc.Invoke(ctx, Wasabi_RecoverWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) LoadWallet(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*LoadWalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoadWalletResponse)
+	err := c.cc.Invoke(ctx, Wasabi_LoadWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) GetWalletInfo(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*WalletInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WalletInfo)
+	err := c.cc.Invoke(ctx, Wasabi_GetWalletInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) ListCoins(ctx context.Context, in *ListCoinsRequest, opts ...grpc.CallOption) (*ListCoinsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCoinsResponse)
+	err := c.cc.Invoke(ctx, Wasabi_ListCoins_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) GetHistory(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHistoryResponse)
+	err := c.cc.Invoke(ctx, Wasabi_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) ListKeys(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*ListKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListKeysResponse)
+	err := c.cc.Invoke(ctx, Wasabi_ListKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) GetNewAddress(ctx context.Context, in *GetNewAddressRequest, opts ...grpc.CallOption) (*Address, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Address)
+	err := c.cc.Invoke(ctx, Wasabi_GetNewAddress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, Wasabi_Send_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) Build(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*TransactionHex, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionHex)
+	err := c.cc.Invoke(ctx, Wasabi_Build_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) BuildUnsafeTransaction(ctx context.Context, in *BuildRequest, opts ...grpc.CallOption) (*TransactionHex, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionHex)
+	err := c.cc.Invoke(ctx, Wasabi_BuildUnsafeTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) SpeedUpTransaction(ctx context.Context, in *ReplaceTransactionRequest, opts ...grpc.CallOption) (*TransactionHex, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionHex)
+	err := c.cc.Invoke(ctx, Wasabi_SpeedUpTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) CancelTransaction(ctx context.Context, in *ReplaceTransactionRequest, opts ...grpc.CallOption) (*TransactionHex, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionHex)
+	err := c.cc.Invoke(ctx, Wasabi_CancelTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) StartCoinJoin(ctx context.Context, in *StartCoinJoinRequest, opts ...grpc.CallOption) (*StartCoinJoinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartCoinJoinResponse)
+	err := c.cc.Invoke(ctx, Wasabi_StartCoinJoin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) StartCoinJoinSweep(ctx context.Context, in *StartCoinJoinSweepRequest, opts ...grpc.CallOption) (*StartCoinJoinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartCoinJoinResponse)
+	err := c.cc.Invoke(ctx, Wasabi_StartCoinJoinSweep_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) StopCoinJoin(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*StopCoinJoinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopCoinJoinResponse)
+	err := c.cc.Invoke(ctx, Wasabi_StopCoinJoin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) ExcludeFromCoinJoin(ctx context.Context, in *ExcludeFromCoinJoinRequest, opts ...grpc.CallOption) (*ExcludeFromCoinJoinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExcludeFromCoinJoinResponse)
+	err := c.cc.Invoke(ctx, Wasabi_ExcludeFromCoinJoin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) PayInCoinJoin(ctx context.Context, in *PayInCoinJoinRequest, opts ...grpc.CallOption) (*PayInCoinJoinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PayInCoinJoinResponse)
+	err := c.cc.Invoke(ctx, Wasabi_PayInCoinJoin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) ListPaymentsInCoinJoin(ctx context.Context, in *WalletRequest, opts ...grpc.CallOption) (*ListPaymentsInCoinJoinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPaymentsInCoinJoinResponse)
+	err := c.cc.Invoke(ctx, Wasabi_ListPaymentsInCoinJoin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) CancelPaymentInCoinJoin(ctx context.Context, in *CancelPaymentInCoinJoinRequest, opts ...grpc.CallOption) (*CancelPaymentInCoinJoinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelPaymentInCoinJoinResponse)
+	err := c.cc.Invoke(ctx, Wasabi_CancelPaymentInCoinJoin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wasabiClient) WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Status], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Wasabi_ServiceDesc.Streams[0], Wasabi_WatchStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchStatusRequest, Status]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Wasabi_WatchStatusClient = grpc.ServerStreamingClient[Status]
+
+func (c *wasabiClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Wasabi_ServiceDesc.Streams[1], Wasabi_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Wasabi_WatchEventsClient = grpc.ServerStreamingClient[Event]
+
+// WasabiServer is the server API for Wasabi service.
+// All implementations must embed UnimplementedWasabiServer
+// for forward compatibility.
+//
+// Wasabi is the gRPC surface of a Wasabi Wallet daemon. The rpcs mirror the JSON-RPC methods of the daemon; amounts are in satoshis and statuses are the strings reported by the daemon.
+type WasabiServer interface {
+	// Daemon.
+	GetStatus(context.Context, *GetStatusRequest) (*Status, error)
+	GetFeeRates(context.Context, *GetFeeRatesRequest) (*GetFeeRatesResponse, error)
+	Broadcast(context.Context, *BroadcastRequest) (*BroadcastResponse, error)
+	Stop(context.Context, *StopRequest) (*StopResponse, error)
+	// Wallets.
+	ListWallets(context.Context, *ListWalletsRequest) (*ListWalletsResponse, error)
+	CreateWallet(context.Context, *CreateWalletRequest) (*CreateWalletResponse, error)
+	RecoverWallet(context.Context, *RecoverWalletRequest) (*RecoverWalletResponse, error)
+	LoadWallet(context.Context, *WalletRequest) (*LoadWalletResponse, error)
+	GetWalletInfo(context.Context, *WalletRequest) (*WalletInfo, error)
+	ListCoins(context.Context, *ListCoinsRequest) (*ListCoinsResponse, error)
+	GetHistory(context.Context, *WalletRequest) (*GetHistoryResponse, error)
+	ListKeys(context.Context, *WalletRequest) (*ListKeysResponse, error)
+	GetNewAddress(context.Context, *GetNewAddressRequest) (*Address, error)
+	// Transactions.
+	Send(context.Context, *SendRequest) (*SendResponse, error)
+	Build(context.Context, *BuildRequest) (*TransactionHex, error)
+	BuildUnsafeTransaction(context.Context, *BuildRequest) (*TransactionHex, error)
+	SpeedUpTransaction(context.Context, *ReplaceTransactionRequest) (*TransactionHex, error)
+	CancelTransaction(context.Context, *ReplaceTransactionRequest) (*TransactionHex, error)
+	// Coinjoin.
+	StartCoinJoin(context.Context, *StartCoinJoinRequest) (*StartCoinJoinResponse, error)
+	StartCoinJoinSweep(context.Context, *StartCoinJoinSweepRequest) (*StartCoinJoinResponse, error)
+	StopCoinJoin(context.Context, *WalletRequest) (*StopCoinJoinResponse, error)
+	ExcludeFromCoinJoin(context.Context, *ExcludeFromCoinJoinRequest) (*ExcludeFromCoinJoinResponse, error)
+	PayInCoinJoin(context.Context, *PayInCoinJoinRequest) (*PayInCoinJoinResponse, error)
+	ListPaymentsInCoinJoin(context.Context, *WalletRequest) (*ListPaymentsInCoinJoinResponse, error)
+	CancelPaymentInCoinJoin(context.Context, *CancelPaymentInCoinJoinRequest) (*CancelPaymentInCoinJoinResponse, error)
+	// Streams.
+	// WatchStatus sends the status of the daemon when it changes.
+	WatchStatus(*WatchStatusRequest, grpc.ServerStreamingServer[Status]) error
+	// WatchEvents sends the events of the wallets: new transactions, confirmations, coinjoin status changes, backend disconnections and finished payments in coinjoin.
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedWasabiServer()
+}
+
+// UnimplementedWasabiServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWasabiServer struct{}
+
+func (UnimplementedWasabiServer) GetStatus(context.Context, *GetStatusRequest) (*Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedWasabiServer) GetFeeRates(context.Context, *GetFeeRatesRequest) (*GetFeeRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeeRates not implemented")
+}
+func (UnimplementedWasabiServer) Broadcast(context.Context, *BroadcastRequest) (*BroadcastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Broadcast not implemented")
+}
+func (UnimplementedWasabiServer) Stop(context.Context, *StopRequest) (*StopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (UnimplementedWasabiServer) ListWallets(context.Context, *ListWalletsRequest) (*ListWalletsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWallets not implemented")
+}
+func (UnimplementedWasabiServer) CreateWallet(context.Context, *CreateWalletRequest) (*CreateWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWallet not implemented")
+}
+func (UnimplementedWasabiServer) RecoverWallet(context.Context, *RecoverWalletRequest) (*RecoverWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecoverWallet not implemented")
+}
+func (UnimplementedWasabiServer) LoadWallet(context.Context, *WalletRequest) (*LoadWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadWallet not implemented")
+}
+func (UnimplementedWasabiServer) GetWalletInfo(context.Context, *WalletRequest) (*WalletInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWalletInfo not implemented")
+}
+func (UnimplementedWasabiServer) ListCoins(context.Context, *ListCoinsRequest) (*ListCoinsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCoins not implemented")
+}
+func (UnimplementedWasabiServer) GetHistory(context.Context, *WalletRequest) (*GetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedWasabiServer) ListKeys(context.Context, *WalletRequest) (*ListKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (UnimplementedWasabiServer) GetNewAddress(context.Context, *GetNewAddressRequest) (*Address, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNewAddress not implemented")
+}
+func (UnimplementedWasabiServer) Send(context.Context, *SendRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Send not implemented")
+}
+func (UnimplementedWasabiServer) Build(context.Context, *BuildRequest) (*TransactionHex, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Build not implemented")
+}
+func (UnimplementedWasabiServer) BuildUnsafeTransaction(context.Context, *BuildRequest) (*TransactionHex, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuildUnsafeTransaction not implemented")
+}
+func (UnimplementedWasabiServer) SpeedUpTransaction(context.Context, *ReplaceTransactionRequest) (*TransactionHex, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpeedUpTransaction not implemented")
+}
+func (UnimplementedWasabiServer) CancelTransaction(context.Context, *ReplaceTransactionRequest) (*TransactionHex, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTransaction not implemented")
+}
+func (UnimplementedWasabiServer) StartCoinJoin(context.Context, *StartCoinJoinRequest) (*StartCoinJoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartCoinJoin not implemented")
+}
+func (UnimplementedWasabiServer) StartCoinJoinSweep(context.Context, *StartCoinJoinSweepRequest) (*StartCoinJoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartCoinJoinSweep not implemented")
+}
+func (UnimplementedWasabiServer) StopCoinJoin(context.Context, *WalletRequest) (*StopCoinJoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopCoinJoin not implemented")
+}
+func (UnimplementedWasabiServer) ExcludeFromCoinJoin(context.Context, *ExcludeFromCoinJoinRequest) (*ExcludeFromCoinJoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExcludeFromCoinJoin not implemented")
+}
+func (UnimplementedWasabiServer) PayInCoinJoin(context.Context, *PayInCoinJoinRequest) (*PayInCoinJoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PayInCoinJoin not implemented")
+}
+func (UnimplementedWasabiServer) ListPaymentsInCoinJoin(context.Context, *WalletRequest) (*ListPaymentsInCoinJoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPaymentsInCoinJoin not implemented")
+}
+func (UnimplementedWasabiServer) CancelPaymentInCoinJoin(context.Context, *CancelPaymentInCoinJoinRequest) (*CancelPaymentInCoinJoinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelPaymentInCoinJoin not implemented")
+}
+func (UnimplementedWasabiServer) WatchStatus(*WatchStatusRequest, grpc.ServerStreamingServer[Status]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
+func (UnimplementedWasabiServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedWasabiServer) mustEmbedUnimplementedWasabiServer() {}
+func (UnimplementedWasabiServer) testEmbeddedByValue()                {}
+
+// UnsafeWasabiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WasabiServer will
+// result in compilation errors.
+type UnsafeWasabiServer interface {
+	mustEmbedUnimplementedWasabiServer()
+}
+
+func RegisterWasabiServer(s grpc.ServiceRegistrar, srv WasabiServer) {
+	// If the following call pancis, it indicates UnimplementedWasabiServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Wasabi_ServiceDesc, srv)
+}
+
+func _Wasabi_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_GetFeeRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFeeRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).GetFeeRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_GetFeeRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).GetFeeRates(ctx, req.(*GetFeeRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_Broadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).Broadcast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_Broadcast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).Broadcast(ctx, req.(*BroadcastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_Stop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).Stop(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_ListWallets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWalletsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).ListWallets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_ListWallets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).ListWallets(ctx, req.(*ListWalletsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_CreateWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).CreateWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_CreateWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).CreateWallet(ctx, req.(*CreateWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_RecoverWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecoverWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).RecoverWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_RecoverWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).RecoverWallet(ctx, req.(*RecoverWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_LoadWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).LoadWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_LoadWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).LoadWallet(ctx, req.(*WalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_GetWalletInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).GetWalletInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_GetWalletInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).GetWalletInfo(ctx, req.(*WalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_ListCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCoinsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).ListCoins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_ListCoins_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).ListCoins(ctx, req.(*ListCoinsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).GetHistory(ctx, req.(*WalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_ListKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).ListKeys(ctx, req.(*WalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_GetNewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNewAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).GetNewAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_GetNewAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).GetNewAddress(ctx, req.(*GetNewAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_Send_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).Send(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_Send_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).Send(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_Build_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).Build(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_Build_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).Build(ctx, req.(*BuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_BuildUnsafeTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).BuildUnsafeTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_BuildUnsafeTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).BuildUnsafeTransaction(ctx, req.(*BuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_SpeedUpTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).SpeedUpTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_SpeedUpTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).SpeedUpTransaction(ctx, req.(*ReplaceTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_CancelTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).CancelTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_CancelTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).CancelTransaction(ctx, req.(*ReplaceTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_StartCoinJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartCoinJoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).StartCoinJoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_StartCoinJoin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).StartCoinJoin(ctx, req.(*StartCoinJoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_StartCoinJoinSweep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartCoinJoinSweepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).StartCoinJoinSweep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_StartCoinJoinSweep_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).StartCoinJoinSweep(ctx, req.(*StartCoinJoinSweepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_StopCoinJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).StopCoinJoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_StopCoinJoin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).StopCoinJoin(ctx, req.(*WalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_ExcludeFromCoinJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExcludeFromCoinJoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).ExcludeFromCoinJoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_ExcludeFromCoinJoin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).ExcludeFromCoinJoin(ctx, req.(*ExcludeFromCoinJoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_PayInCoinJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayInCoinJoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).PayInCoinJoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_PayInCoinJoin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).PayInCoinJoin(ctx, req.(*PayInCoinJoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_ListPaymentsInCoinJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).ListPaymentsInCoinJoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_ListPaymentsInCoinJoin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).ListPaymentsInCoinJoin(ctx, req.(*WalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_CancelPaymentInCoinJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelPaymentInCoinJoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WasabiServer).CancelPaymentInCoinJoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wasabi_CancelPaymentInCoinJoin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WasabiServer).CancelPaymentInCoinJoin(ctx, req.(*CancelPaymentInCoinJoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Wasabi_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WasabiServer).WatchStatus(m, &grpc.GenericServerStream[WatchStatusRequest, Status]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Wasabi_WatchStatusServer = grpc.ServerStreamingServer[Status]
+
+func _Wasabi_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WasabiServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Wasabi_WatchEventsServer = grpc.ServerStreamingServer[Event]
+
+// Wasabi_ServiceDesc is the grpc.ServiceDesc for Wasabi service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Wasabi_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wasabi.v1.Wasabi",
+	HandlerType: (*WasabiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _Wasabi_GetStatus_Handler,
+		},
+		{
+			MethodName: "GetFeeRates",
+			Handler:    _Wasabi_GetFeeRates_Handler,
+		},
+		{
+			MethodName: "Broadcast",
+			Handler:    _Wasabi_Broadcast_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _Wasabi_Stop_Handler,
+		},
+		{
+			MethodName: "ListWallets",
+			Handler:    _Wasabi_ListWallets_Handler,
+		},
+		{
+			MethodName: "CreateWallet",
+			Handler:    _Wasabi_CreateWallet_Handler,
+		},
+		{
+			MethodName: "RecoverWallet",
+			Handler:    _Wasabi_RecoverWallet_Handler,
+		},
+		{
+			MethodName: "LoadWallet",
+			Handler:    _Wasabi_LoadWallet_Handler,
+		},
+		{
+			MethodName: "GetWalletInfo",
+			Handler:    _Wasabi_GetWalletInfo_Handler,
+		},
+		{
+			MethodName: "ListCoins",
+			Handler:    _Wasabi_ListCoins_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _Wasabi_GetHistory_Handler,
+		},
+		{
+			MethodName: "ListKeys",
+			Handler:    _Wasabi_ListKeys_Handler,
+		},
+		{
+			MethodName: "GetNewAddress",
+			Handler:    _Wasabi_GetNewAddress_Handler,
+		},
+		{
+			MethodName: "Send",
+			Handler:    _Wasabi_Send_Handler,
+		},
+		{
+			MethodName: "Build",
+			Handler:    _Wasabi_Build_Handler,
+		},
+		{
+			MethodName: "BuildUnsafeTransaction",
+			Handler:    _Wasabi_BuildUnsafeTransaction_Handler,
+		},
+		{
+			MethodName: "SpeedUpTransaction",
+			Handler:    _Wasabi_SpeedUpTransaction_Handler,
+		},
+		{
+			MethodName: "CancelTransaction",
+			Handler:    _Wasabi_CancelTransaction_Handler,
+		},
+		{
+			MethodName: "StartCoinJoin",
+			Handler:    _Wasabi_StartCoinJoin_Handler,
+		},
+		{
+			MethodName: "StartCoinJoinSweep",
+			Handler:    _Wasabi_StartCoinJoinSweep_Handler,
+		},
+		{
+			MethodName: "StopCoinJoin",
+			Handler:    _Wasabi_StopCoinJoin_Handler,
+		},
+		{
+			MethodName: "ExcludeFromCoinJoin",
+			Handler:    _Wasabi_ExcludeFromCoinJoin_Handler,
+		},
+		{
+			MethodName: "PayInCoinJoin",
+			Handler:    _Wasabi_PayInCoinJoin_Handler,
+		},
+		{
+			MethodName: "ListPaymentsInCoinJoin",
+			Handler:    _Wasabi_ListPaymentsInCoinJoin_Handler,
+		},
+		{
+			MethodName: "CancelPaymentInCoinJoin",
+			Handler:    _Wasabi_CancelPaymentInCoinJoin_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _Wasabi_WatchStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _Wasabi_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "wasabi/v1/wasabi.proto",
+}
@@ -0,0 +1,121 @@
+package wasabi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// HedgePolicy sends read calls which are slow to answer to other endpoints of the daemon too and takes the first response, to cut the tail latency of flaky links such as Tor circuits. The zero value does not hedge.
+type HedgePolicy struct {
+	// Delay is how long a request may take before the call is sent to the next endpoint. Zero disables hedging.
+	Delay time.Duration
+	// Endpoints are the other endpoints ("host:port", or "[host]:port" for IPv6 addresses) serving the same daemon, e.g. its onion service reached through other proxies, tried in order.
+	Endpoints []string
+	// Clock provides the timers of the delay. Default is SystemClock.
+	Clock Clock
+}
+
+// hedgeClients returns a client per hedging endpoint of the policy, derived from c.
+func hedgeClients(c *client, policy HedgePolicy) ([]*client, error) {
+	if policy.Delay < 0 {
+		return nil, fmt.Errorf("hedge delay must not be negative")
+	}
+	if policy.Delay == 0 {
+		return nil, nil
+	}
+	hedges := make([]*client, 0, len(policy.Endpoints))
+	for _, endpoint := range policy.Endpoints {
+		host, portStr, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hedge endpoint %q: %w", endpoint, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hedge endpoint %q: port must be a number", endpoint)
+		}
+		hedge, err := c.With(WithEndpoint(host, port))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hedge endpoint %q: %w", endpoint, err)
+		}
+		hedges = append(hedges, hedge.(*client))
+	}
+	return hedges, nil
+}
+
+// hedged reports whether the call is hedged. Mutating calls are never hedged, nor are batches and calls of clients routing with selectwallet, whose selection is not shared by the endpoints.
+func (c *client) hedged(call *CallInfo) bool {
+	if len(c.hedges) == 0 || c.routing == RoutingSelectWallet || call.Method.IsMutating() {
+		return false
+	}
+	_, isBatch := call.Params.(batchParams)
+	return !isBatch
+}
+
+type hedgeResult struct {
+	result json.RawMessage
+	err    error
+}
+
+// sendHedged sends the call like send, and to the next hedging endpoint whenever the pending requests are slower than the delay or fail to connect. The first response wins and the other requests are canceled.
+func (c *client) sendHedged(call *CallInfo, out interface{}) error {
+	if _, streamed := out.(*resultWriter); streamed || !c.hedged(call) {
+		return c.send(call, out)
+	}
+	ctx, cancel := context.WithCancel(call.Context)
+	defer cancel()
+	targets := append([]*client{c}, c.hedges...)
+	results := make(chan hedgeResult, len(targets))
+	next, pending := 0, 0
+	launch := func() {
+		target := targets[next]
+		next++
+		pending++
+		hedgedCall := *call
+		hedgedCall.Context = ctx
+		hedgedCall.Header = call.Header.Clone()
+		go func() {
+			var result json.RawMessage
+			err := target.send(&hedgedCall, &result)
+			results <- hedgeResult{result: result, err: err}
+		}()
+	}
+	clock := clockOrDefault(c.hedge.Clock)
+	launch()
+	timer := clock.NewTimer(c.hedge.Delay)
+	defer func() {
+		timer.Stop()
+	}()
+	var lastErr error
+	for pending > 0 {
+		select {
+		case <-timer.C():
+			if next < len(targets) {
+				launch()
+				timer = clock.NewTimer(c.hedge.Delay)
+			}
+		case r := <-results:
+			pending--
+			var connectionErr *ConnectionError
+			if r.err != nil && errors.As(r.err, &connectionErr) {
+				lastErr = r.err
+				if next < len(targets) && ctx.Err() == nil {
+					launch()
+				}
+				continue
+			}
+			if r.err != nil {
+				return r.err
+			}
+			if len(r.result) == 0 {
+				return nil
+			}
+			return decodeResult(r.result, out, c.useNumber)
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,77 @@
+package wasabi
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWithAppliesOptionsToHedges(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server notices the canceled request once the body is read.
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer slow.Close()
+	tenants := make(chan string, 1)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants <- r.Header.Get("X-Tenant")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	}))
+	defer fast.Close()
+
+	host, portStr, _ := net.SplitHostPort(slow.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	c, err := NewClient(Config{Host: host, Port: port, Hedge: HedgePolicy{Delay: 10 * time.Millisecond, Endpoints: []string{fast.Listener.Addr().String()}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenant, err := c.With(WithHeader("X-Tenant", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tenant.GetStatus(); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-tenants; got != "a" {
+		t.Fatalf("hedged request header X-Tenant = %q, want %q", got, "a")
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		host  string
+		valid bool
+	}{
+		{host: "localhost", valid: true},
+		{host: "127.0.0.1", valid: true},
+		{host: "::1", valid: true},
+		{host: "2001:db8::1", valid: true},
+		{host: ""},
+		{host: "[::1]"},
+		{host: "localhost:37128"},
+		{host: "localhost/wallet"},
+	}
+	for _, tt := range tests {
+		if err := checkHost(tt.host); (err == nil) != tt.valid {
+			t.Errorf("checkHost(%q) = %v, want valid %v", tt.host, err, tt.valid)
+		}
+	}
+}
+
+func TestWithEndpointIPv6(t *testing.T) {
+	c, err := NewClient(Config{Host: "localhost", Hedge: HedgePolicy{Delay: time.Second, Endpoints: []string{"[::1]:37129"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.With(WithEndpoint("::1", 37128)); err != nil {
+		t.Fatalf("WithEndpoint(::1) = %v", err)
+	}
+}
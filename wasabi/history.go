@@ -0,0 +1,87 @@
+package wasabi
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// HistoryFilter selects and orders the transactions returned by GetHistoryFiltered. The zero value keeps the whole history, oldest first.
+type HistoryFilter struct {
+	// SinceHeight keeps the transactions confirmed at or above the height, and the unconfirmed ones. Zero keeps all.
+	SinceHeight int
+	// From and To keep the transactions dated at or after From and before To. Zero times do not bound.
+	From time.Time
+	To   time.Time
+	// ExcludeCoinJoins drops the transactions which are likely coinjoins.
+	ExcludeCoinJoins bool
+	// Limit is the maximum number of transactions returned, after sorting. Zero returns all.
+	Limit int
+	// SortDesc returns the newest transactions first.
+	SortDesc bool
+}
+
+// Validate validates the filter.
+func (f HistoryFilter) Validate() error {
+	switch {
+	case f.SinceHeight < 0:
+		return fmt.Errorf("since height must not be negative")
+	case f.Limit < 0:
+		return fmt.Errorf("limit must not be negative")
+	case !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From):
+		return fmt.Errorf("to must not be before from")
+	}
+	return nil
+}
+
+// matches reports whether the transaction passes the filter.
+func (f HistoryFilter) matches(tx Transaction) bool {
+	switch {
+	case f.SinceHeight > 0 && tx.Height > 0 && tx.Height < f.SinceHeight:
+		return false
+	case !f.From.IsZero() && tx.DateTime.Before(f.From):
+		return false
+	case !f.To.IsZero() && !tx.DateTime.Before(f.To):
+		return false
+	case f.ExcludeCoinJoins && tx.IsLikelyCoinJoin:
+		return false
+	}
+	return true
+}
+
+// FilterHistory returns the transactions of the history passing the filter, sorted by date and cut to its limit. The history is not modified.
+func FilterHistory(history []Transaction, filter HistoryFilter) []Transaction {
+	filtered := make([]Transaction, 0, len(history))
+	for _, tx := range history {
+		if filter.matches(tx) {
+			filtered = append(filtered, tx)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if filter.SortDesc {
+			return filtered[i].DateTime.After(filtered[j].DateTime)
+		}
+		return filtered[i].DateTime.Before(filtered[j].DateTime)
+	})
+	if filter.Limit > 0 && len(filtered) > filter.Limit {
+		filtered = filtered[:filter.Limit]
+	}
+	return filtered
+}
+
+// GetHistoryFiltered returns the history of the wallet passing the filter. The daemon has no filtering parameters, so the full history is fetched and filtered by FilterHistory.
+func GetHistoryFiltered(c WalletReader, walletName string, filter HistoryFilter) ([]Transaction, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	history, err := c.GetHistory(walletName)
+	if err != nil {
+		return nil, err
+	}
+	return FilterHistory(history, filter), nil
+}
+
+// HistoryFiltered returns the history of the wallet passing the filter.
+func (w *Wallet) HistoryFiltered(filter HistoryFilter) ([]Transaction, error) {
+	return GetHistoryFiltered(w.client, w.name, filter)
+}
@@ -0,0 +1,108 @@
+package wasabi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TxID is a transaction id: the hex encoding of the byte-reversed transaction hash.
+type TxID string
+
+// ParseTxID validates a transaction id and normalizes it to lowercase.
+func ParseTxID(s string) (TxID, error) {
+	if len(s) != 64 {
+		return "", fmt.Errorf("txid %q must have 64 hex characters", s)
+	}
+	if _, err := hex.DecodeString(s); err != nil {
+		return "", fmt.Errorf("txid %q must be hex: %w", s, err)
+	}
+	return TxID(strings.ToLower(s)), nil
+}
+
+// String returns the transaction id.
+func (id TxID) String() string {
+	return string(id)
+}
+
+// Validate checks that the transaction id is 64 hex characters.
+func (id TxID) Validate() error {
+	_, err := ParseTxID(string(id))
+	return err
+}
+
+// Address is a bitcoin address.
+type Address string
+
+// ParseAddress validates an address (see DecodeAddress) and normalizes segwit addresses to lowercase, since bech32 addresses may be written in uppercase (e.g. in QR codes).
+func ParseAddress(s string) (Address, error) {
+	info, err := DecodeAddress(s)
+	if err != nil {
+		return "", err
+	}
+	if info.WitnessVersion >= 0 {
+		s = strings.ToLower(s)
+	}
+	return Address(s), nil
+}
+
+// String returns the address.
+func (a Address) String() string {
+	return string(a)
+}
+
+// Decode decodes the address (see DecodeAddress).
+func (a Address) Decode() (AddressInfo, error) {
+	return DecodeAddress(string(a))
+}
+
+// ScriptPubKey returns the output script paying to the address.
+func (a Address) ScriptPubKey() (ScriptPubKey, error) {
+	info, err := a.Decode()
+	if err != nil {
+		return "", err
+	}
+	return info.ScriptPubKey(), nil
+}
+
+// ScriptPubKey returns the output script paying to the decoded address.
+func (info AddressInfo) ScriptPubKey() ScriptPubKey {
+	var script []byte
+	switch info.Type {
+	case AddressTypeP2PKH:
+		script = append(append([]byte{0x76, 0xa9, 0x14}, info.Program...), 0x88, 0xac)
+	case AddressTypeP2SH:
+		script = append(append([]byte{0xa9, 0x14}, info.Program...), 0x87)
+	default:
+		version := byte(0)
+		if info.WitnessVersion > 0 {
+			version = byte(0x50 + info.WitnessVersion)
+		}
+		script = append([]byte{version, byte(len(info.Program))}, info.Program...)
+	}
+	return ScriptPubKey(hex.EncodeToString(script))
+}
+
+// ScriptPubKey is a hex encoded output script.
+type ScriptPubKey string
+
+// ParseScriptPubKey validates a hex encoded output script and normalizes it to lowercase.
+func ParseScriptPubKey(s string) (ScriptPubKey, error) {
+	if s == "" || len(s)%2 != 0 {
+		return "", fmt.Errorf("script pubkey %q must have an even, non-zero number of hex characters", s)
+	}
+	if _, err := hex.DecodeString(s); err != nil {
+		return "", fmt.Errorf("script pubkey %q must be hex: %w", s, err)
+	}
+	return ScriptPubKey(strings.ToLower(s)), nil
+}
+
+// String returns the hex encoded script.
+func (s ScriptPubKey) String() string {
+	return string(s)
+}
+
+// Bytes returns the decoded script.
+func (s ScriptPubKey) Bytes() ([]byte, error) {
+	return hex.DecodeString(string(s))
+}
@@ -0,0 +1,101 @@
+package wasabi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// CallInfo describes an rpc call passed to interceptors.
+type CallInfo struct {
+	// Context is the context of the call.
+	Context context.Context
+	// Method is the called rpc method.
+	Method Method
+	// WalletName is the name of the target wallet. It is empty for methods that do not target a wallet.
+	WalletName string
+	// CorrelationID is the correlation id of the call, sent in the correlation id header.
+	CorrelationID string
+	// Params are the parameters of the call. They contain secrets (passwords, mnemonics) in clear text.
+	Params interface{}
+	// Header holds additional http headers of the request. Interceptors may modify it in BeforeRequest.
+	Header http.Header
+	// Result short-circuits the call when set by an interceptor in BeforeRequest: the request is not sent and Result is decoded as the result of the call.
+	Result json.RawMessage
+	// Attempt is the number of the current attempt, starting with 1.
+	Attempt int
+	// Retry can be set by an interceptor in AfterResponse to send the call again. All interceptors are called again for the new attempt. It is ignored for calls made with a WithoutRetry context.
+	Retry bool
+}
+
+// Interceptor hooks into every rpc call of the client. Retries, metrics, auth refresh and auditing can be layered with interceptors.
+type Interceptor interface {
+	// BeforeRequest is called before the request is sent. If it returns an error, the request is not sent and the error is returned to the caller (after the AfterResponse hooks of the preceding interceptors).
+	BeforeRequest(call *CallInfo) error
+	// AfterResponse is called when the call finished with the error of the call (nil on success). The returned error replaces the error of the call.
+	AfterResponse(call *CallInfo, err error) error
+}
+
+// InterceptorFuncs is an Interceptor made of functions. Nil functions are skipped.
+type InterceptorFuncs struct {
+	Before func(call *CallInfo) error
+	After  func(call *CallInfo, err error) error
+}
+
+func (f InterceptorFuncs) BeforeRequest(call *CallInfo) error {
+	if f.Before == nil {
+		return nil
+	}
+	return f.Before(call)
+}
+
+func (f InterceptorFuncs) AfterResponse(call *CallInfo, err error) error {
+	if f.After == nil {
+		return err
+	}
+	return f.After(call, err)
+}
+
+// intercept runs the call through the interceptor chain.
+func (c *client) intercept(call *CallInfo, out interface{}) (err error) {
+	call.Result = nil
+	called := 0
+	for _, interceptor := range c.interceptors {
+		if err = interceptor.BeforeRequest(call); err != nil {
+			break
+		}
+		called++
+	}
+	if err == nil {
+		if call.Result != nil {
+			err = decodeResult(call.Result, out, c.useNumber)
+		} else {
+			err = c.sendHedged(call, out)
+		}
+	}
+	for i := called - 1; i >= 0; i-- {
+		err = c.interceptors[i].AfterResponse(call, err)
+	}
+	return err
+}
+
+// decodeResult decodes a short-circuited result into out.
+func decodeResult(result json.RawMessage, out interface{}, useNumber bool) error {
+	if rw, ok := out.(*resultWriter); ok {
+		if _, err := rw.Write(result); err != nil {
+			return err
+		}
+		return nil
+	}
+	if out == nil || string(result) == "null" {
+		return nil
+	}
+	decode := json.Unmarshal
+	if useNumber {
+		decode = decodeNumbers
+	}
+	if err := decode(result, out); err != nil {
+		return &ProtocolError{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package wasabi
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultGapLimit is the number of consecutive unused receive keys wallets scan ahead when they are recovered.
+const DefaultGapLimit = 20
+
+// AddressIssuerOptions holds the options of an AddressIssuer.
+type AddressIssuerOptions struct {
+	// GapLimit is the highest acceptable number of consecutive unused receive keys at the end of a derivation chain. Default is DefaultGapLimit.
+	GapLimit int
+	// OnGapWarning is called after an address is issued if the gap of a chain exceeds GapLimit. Funds received past the gap limit may be missed when the wallet is recovered from its mnemonic. Optional.
+	OnGapWarning func(walletName string, chain string, gap int)
+}
+
+// IssuedAddress is an address handed out by an AddressIssuer.
+type IssuedAddress struct {
+	GetNewAddressResponse
+	// Reused reports that the address is an unused key generated before, not a new one.
+	Reused bool
+}
+
+// AddressIssuer hands out receive addresses, preferring keys which were generated but never used or labeled over new keys, so the gap of unused keys stays small.
+type AddressIssuer struct {
+	client     Client
+	walletName string
+	opts       AddressIssuerOptions
+	mutex      sync.Mutex
+	// issued holds the reused addresses handed out. The daemon does not know about them, so they must not be handed out twice.
+	issued map[Address]bool
+}
+
+// NewAddressIssuer creates an address issuer for the wallet. Reused addresses are remembered in memory only, so use one issuer per wallet.
+func NewAddressIssuer(c Client, walletName string, opts AddressIssuerOptions) *AddressIssuer {
+	if opts.GapLimit <= 0 {
+		opts.GapLimit = DefaultGapLimit
+	}
+	return &AddressIssuer{client: c, walletName: walletName, opts: opts, issued: map[Address]bool{}}
+}
+
+// Next returns a receive address. Without label, the clean external key with the lowest index is reused if there is one; otherwise, and always if a label is given (labels can only be set by GetNewAddress), a new address is created.
+func (i *AddressIssuer) Next(label string) (IssuedAddress, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	keys, err := i.client.ListKeys(i.walletName)
+	if err != nil {
+		return IssuedAddress{}, err
+	}
+	var issued IssuedAddress
+	if label == "" {
+		if key, ok := i.cleanKey(keys); ok {
+			i.issued[key.Address] = true
+			issued = IssuedAddress{
+				GetNewAddressResponse: GetNewAddressResponse{
+					Address:      key.Address,
+					KeyPath:      key.FullKeyPath,
+					PublicKey:    key.PubKey,
+					ScriptPubKey: key.ScriptPubKey,
+				},
+				Reused: true,
+			}
+		}
+	}
+	if issued.Address == "" {
+		resp, err := i.client.GetNewAddress(i.walletName, label)
+		if err != nil {
+			return IssuedAddress{}, err
+		}
+		issued = IssuedAddress{GetNewAddressResponse: resp}
+		if keys, err = i.client.ListKeys(i.walletName); err != nil {
+			// The address is valid even if the gap cannot be checked.
+			return issued, nil
+		}
+	}
+	if i.opts.OnGapWarning != nil {
+		for chain, gap := range i.gaps(keys) {
+			if gap > i.opts.GapLimit {
+				i.opts.OnGapWarning(i.walletName, chain, gap)
+			}
+		}
+	}
+	return issued, nil
+}
+
+// cleanKey returns the clean, unlabeled external key with the lowest index which was not handed out yet.
+func (i *AddressIssuer) cleanKey(keys []GeneratedKey) (GeneratedKey, bool) {
+	var best GeneratedKey
+	bestIndex := -1
+	for _, key := range keys {
+		if key.Internal || key.KeyState != KeyStateClean || key.Label != "" || key.Address == "" || i.issued[key.Address] {
+			continue
+		}
+		_, index, ok := splitKeyPath(key.FullKeyPath)
+		if ok && (bestIndex < 0 || index < bestIndex) {
+			best, bestIndex = key, index
+		}
+	}
+	return best, bestIndex >= 0
+}
+
+// gaps returns the number of consecutive unused external keys at the end of each derivation chain. Keys handed out by the issuer count as used.
+func (i *AddressIssuer) gaps(keys []GeneratedKey) map[string]int {
+	chains := map[string][]GeneratedKey{}
+	for _, key := range keys {
+		if key.Internal {
+			continue
+		}
+		chain, _, ok := splitKeyPath(key.FullKeyPath)
+		if !ok {
+			continue
+		}
+		chains[chain] = append(chains[chain], key)
+	}
+	gaps := make(map[string]int, len(chains))
+	for chain, chainKeys := range chains {
+		sort.Slice(chainKeys, func(a, b int) bool {
+			return chainKeys[a].FullKeyPath[len(chainKeys[a].FullKeyPath)-1] < chainKeys[b].FullKeyPath[len(chainKeys[b].FullKeyPath)-1]
+		})
+		gap := 0
+		for k := len(chainKeys) - 1; k >= 0; k-- {
+			key := chainKeys[k]
+			if key.KeyState != KeyStateClean || key.Label != "" || i.issued[key.Address] {
+				break
+			}
+			gap++
+		}
+		gaps[chain] = gap
+	}
+	return gaps
+}
+
+// splitKeyPath splits a key path like 84'/0'/0'/0/5 into its chain (84'/0'/0'/0) and its unhardened index (5).
+func splitKeyPath(path DerivationPath) (string, int, bool) {
+	if len(path) < 2 || path[len(path)-1] >= HardenedKeyStart {
+		return "", 0, false
+	}
+	return path[:len(path)-1].String(), int(path[len(path)-1]), true
+}
@@ -0,0 +1,63 @@
+package wasabi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// decodeNumbers decodes data into out like json.Unmarshal, but the numbers of interface{} values are decoded as json.Number. Numbers which do not fit their field, and values of numeric fields which are not numbers, are returned as NumberError.
+func decodeNumbers(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(out)
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+	switch {
+	case errors.As(err, &typeErr) && (strings.HasPrefix(typeErr.Value, "number") || isNumericKind(typeErr.Type)):
+		return &NumberError{Field: typeErr.Field, Value: typeErr.Value, Type: typeErr.Type, Err: err}
+	case errors.As(err, &numErr):
+		return &NumberError{Value: strconv.Quote(numErr.Num), Err: err}
+	}
+	return err
+}
+
+// isNumericKind reports whether t is an integer or float type.
+func isNumericKind(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// unknownJSONFields returns the fields of the JSON object which do not map to a field of the struct type t. It returns nil if there are none.
+func unknownJSONFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		for key := range fields {
+			if strings.EqualFold(key, name) {
+				delete(fields, key)
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
@@ -0,0 +1,138 @@
+package wasabi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HardenedKeyStart is added to the index of hardened derivation steps.
+const HardenedKeyStart uint32 = 1 << 31
+
+// DerivationPath is a BIP32 derivation path, e.g. 84'/0'/0'/1/5, with HardenedKeyStart added to the hardened steps. It is decoded from and encoded to the text form of the daemon. The standard paths of BIP44, BIP49, BIP84 and BIP86 have five steps: purpose', coin type', account', chain and index.
+type DerivationPath []uint32
+
+// ParseDerivationPath parses a derivation path. The m/ prefix is optional and hardened steps may be marked with ', h or H. The empty string is the empty path.
+func ParseDerivationPath(s string) (DerivationPath, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "m"), "/")
+	if trimmed == "" {
+		return nil, nil
+	}
+	steps := strings.Split(trimmed, "/")
+	path := make(DerivationPath, len(steps))
+	for i, step := range steps {
+		hardened := false
+		if n := len(step); n > 0 && (step[n-1] == '\'' || step[n-1] == 'h' || step[n-1] == 'H') {
+			step, hardened = step[:n-1], true
+		}
+		index, err := strconv.ParseUint(step, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid derivation path %q: invalid step %q", s, steps[i])
+		}
+		path[i] = uint32(index)
+		if hardened {
+			path[i] += HardenedKeyStart
+		}
+	}
+	return path, nil
+}
+
+// String returns the path like the daemon, e.g. 84'/0'/0'/1/5.
+func (p DerivationPath) String() string {
+	steps := make([]string, len(p))
+	for i, step := range p {
+		if step >= HardenedKeyStart {
+			steps[i] = strconv.FormatUint(uint64(step-HardenedKeyStart), 10) + "'"
+		} else {
+			steps[i] = strconv.FormatUint(uint64(step), 10)
+		}
+	}
+	return strings.Join(steps, "/")
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p DerivationPath) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *DerivationPath) UnmarshalText(text []byte) error {
+	path, err := ParseDerivationPath(string(text))
+	if err != nil {
+		return err
+	}
+	*p = path
+	return nil
+}
+
+// Equal reports whether both paths have the same steps.
+func (p DerivationPath) Equal(other DerivationPath) bool {
+	if len(p) != len(other) {
+		return false
+	}
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsStandard reports whether the path has the five steps of BIP44 and its successors, the first three hardened.
+func (p DerivationPath) IsStandard() bool {
+	return len(p) == 5 && p[0] >= HardenedKeyStart && p[1] >= HardenedKeyStart && p[2] >= HardenedKeyStart && p[3] < HardenedKeyStart && p[4] < HardenedKeyStart
+}
+
+// Purpose returns the purpose of a standard path (e.g. 84 for native segwit), or 0.
+func (p DerivationPath) Purpose() uint32 {
+	return p.standardStep(0)
+}
+
+// CoinType returns the coin type of a standard path: 0 for mainnet, 1 for the test networks.
+func (p DerivationPath) CoinType() uint32 {
+	return p.standardStep(1)
+}
+
+// Account returns the account of a standard path.
+func (p DerivationPath) Account() uint32 {
+	return p.standardStep(2)
+}
+
+// Chain returns the chain of a standard path: 0 for receiving keys, 1 for change keys.
+func (p DerivationPath) Chain() uint32 {
+	return p.standardStep(3)
+}
+
+// Index returns the index of the key of a standard path in its chain.
+func (p DerivationPath) Index() uint32 {
+	return p.standardStep(4)
+}
+
+// IsInternal reports whether the path is a standard path of a change key.
+func (p DerivationPath) IsInternal() bool {
+	return p.IsStandard() && p.Chain() == 1
+}
+
+// AccountPath returns the first three steps of a standard path, e.g. 84'/0'/0', or nil.
+func (p DerivationPath) AccountPath() DerivationPath {
+	if !p.IsStandard() {
+		return nil
+	}
+	return append(DerivationPath(nil), p[:3]...)
+}
+
+// ChainPath returns the path of the chain of the key of a standard path, e.g. 84'/0'/0'/1, or nil.
+func (p DerivationPath) ChainPath() DerivationPath {
+	if !p.IsStandard() {
+		return nil
+	}
+	return append(DerivationPath(nil), p[:4]...)
+}
+
+// standardStep returns the step of a standard path without the hardened offset, or 0 for other paths.
+func (p DerivationPath) standardStep(i int) uint32 {
+	if !p.IsStandard() {
+		return 0
+	}
+	return p[i] &^ HardenedKeyStart
+}
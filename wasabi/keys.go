@@ -0,0 +1,43 @@
+package wasabi
+
+// KeyFilter selects generated keys. The zero value selects all keys.
+type KeyFilter struct {
+	// ExternalOnly selects only external (receive) keys.
+	ExternalOnly bool
+	// InternalOnly selects only internal (change) keys.
+	InternalOnly bool
+	// UnusedOnly selects only keys which have not received coins.
+	UnusedOnly bool
+	// Label selects only keys with the given label. Empty selects keys with any label.
+	Label string
+}
+
+// Match reports whether the key is selected by the filter.
+func (f KeyFilter) Match(key GeneratedKey) bool {
+	switch {
+	case f.ExternalOnly && key.Internal:
+		return false
+	case f.InternalOnly && !key.Internal:
+		return false
+	case f.UnusedOnly && key.KeyState == KeyStateUsed:
+		return false
+	case f.Label != "" && key.Label != f.Label:
+		return false
+	}
+	return true
+}
+
+// ListKeysFiltered returns the generated keys of the wallet selected by the filter. The daemon does not support filtering, so the keys are filtered on the client side.
+func ListKeysFiltered(c Client, walletName string, filter KeyFilter) ([]GeneratedKey, error) {
+	keys, err := c.ListKeys(walletName)
+	if err != nil {
+		return nil, err
+	}
+	filtered := keys[:0]
+	for _, key := range keys {
+		if filter.Match(key) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered, nil
+}
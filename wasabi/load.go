@@ -0,0 +1,78 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ListLoadedWallets returns the wallets which are loaded. Daemons which do not report the loaded flag or the state of the wallets are asked for the info of each wallet, and wallets which are not fully loaded yet are left out.
+func ListLoadedWallets(c Client) ([]ListWalletsResponseItem, error) {
+	wallets, err := c.ListWallets()
+	if err != nil {
+		return nil, err
+	}
+	var loaded []ListWalletsResponseItem
+	for _, wallet := range wallets {
+		isLoaded, known := wallet.IsLoaded()
+		if !known {
+			_, err := c.GetWalletInfo(wallet.Name)
+			switch {
+			case err == nil:
+				isLoaded = true
+			case Classify(err) == ErrorCategoryConnection:
+				return nil, err
+			}
+		}
+		if isLoaded {
+			loaded = append(loaded, wallet)
+		}
+	}
+	return loaded, nil
+}
+
+// EnsureWalletLoadedOptions holds the options of EnsureWalletLoaded.
+type EnsureWalletLoadedOptions struct {
+	// InitialBackoff is the first interval between the checks of the wallet state. Default is 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff is the longest interval between the checks. Default is DefaultPollInterval.
+	MaxBackoff time.Duration
+	// Clock provides the timers of the checks. Default is SystemClock.
+	Clock Clock
+}
+
+// EnsureWalletLoaded loads the wallet if it is not loaded yet or stopped and waits until it is started, so the following calls do not fail with ErrorWalletIsNotFullyLoadedYet. It returns when the wallet is started, a call fails or the context is done.
+func EnsureWalletLoaded(ctx context.Context, c Client, walletName string, opts EnsureWalletLoadedOptions) error {
+	c = c.WithContext(ctx)
+	info, err := c.GetWalletInfo(walletName)
+	switch {
+	case err == nil && info.State.IsRunning():
+		return nil
+	case err == nil && !info.State.IsStopped(), errors.Is(err, ErrorWalletIsNotFullyLoadedYet):
+		// The wallet is loading already.
+	case Classify(err) == ErrorCategoryConnection:
+		return err
+	default:
+		// The wallet is not loaded, or it is stopped and does not start by itself.
+		if err := c.LoadWallet(walletName); err != nil {
+			return err
+		}
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultPollInterval
+	}
+	return pollWithBackoff(ctx, opts.Clock, opts.InitialBackoff, opts.MaxBackoff, func() (bool, error) {
+		info, err := c.GetWalletInfo(walletName)
+		if errors.Is(err, ErrorWalletIsNotFullyLoadedYet) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if info.State.IsTerminal() {
+			// The wallet was stopping when it was checked.
+			return false, c.LoadWallet(walletName)
+		}
+		return info.State.IsRunning(), nil
+	})
+}
@@ -0,0 +1,47 @@
+package wasabi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEnsureWalletLoadedStartsStoppedWallet(t *testing.T) {
+	for _, state := range []WalletState{WalletStateStopped, WalletStateUninitialized, WalletStateStopping} {
+		t.Run(string(state), func(t *testing.T) {
+			var (
+				mutex   sync.Mutex
+				current = state
+				loads   int
+			)
+			c := newTestClient(t, func(method string) (int, string) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				switch Method(method) {
+				case MethodLoadWallet:
+					loads++
+					current = WalletStateStarted
+					return http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":null}`
+				case MethodGetWalletInfo:
+					result := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":{"walletName":"w","state":%q}}`, current)
+					if current == WalletStateStopping {
+						current = WalletStateStopped
+					}
+					return http.StatusOK, result
+				}
+				return http.StatusOK, noMethodResponse
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := EnsureWalletLoaded(ctx, c, "w", EnsureWalletLoadedOptions{InitialBackoff: time.Millisecond}); err != nil {
+				t.Fatal(err)
+			}
+			if loads != 1 {
+				t.Fatalf("loadwallet called %d times, want 1", loads)
+			}
+		})
+	}
+}
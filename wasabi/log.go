@@ -0,0 +1,124 @@
+package wasabi
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Logger is a minimal leveled, structured logger. The keysAndValues are alternating keys (strings) and values.
+// *slog.Logger implements it directly, other logging libraries can be plugged in with NewZapLogger and NewLogrusLogger.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// ZapSugaredLogger is the subset of *zap.SugaredLogger used by NewZapLogger.
+type ZapSugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// NewZapLogger adapts a zap sugared logger (zap.L().Sugar()) to the Logger interface.
+func NewZapLogger(l ZapSugaredLogger) Logger {
+	return zapLogger{l: l}
+}
+
+type zapLogger struct {
+	l ZapSugaredLogger
+}
+
+func (z zapLogger) Debug(msg string, keysAndValues ...interface{}) { z.l.Debugw(msg, keysAndValues...) }
+func (z zapLogger) Info(msg string, keysAndValues ...interface{})  { z.l.Infow(msg, keysAndValues...) }
+func (z zapLogger) Warn(msg string, keysAndValues ...interface{})  { z.l.Warnw(msg, keysAndValues...) }
+func (z zapLogger) Error(msg string, keysAndValues ...interface{}) { z.l.Errorw(msg, keysAndValues...) }
+
+// LogrusEntry is the subset of *logrus.Entry used by NewLogrusLogger.
+type LogrusEntry[E any] interface {
+	WithField(key string, value interface{}) E
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+}
+
+// NewLogrusLogger adapts a logrus entry (logrus.NewEntry(logger)) to the Logger interface. Key-value pairs are attached as logrus fields.
+func NewLogrusLogger[E LogrusEntry[E]](entry E) Logger {
+	return logrusLogger[E]{entry: entry}
+}
+
+type logrusLogger[E LogrusEntry[E]] struct {
+	entry E
+}
+
+func (l logrusLogger[E]) withFields(keysAndValues []interface{}) E {
+	e := l.entry
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			continue
+		}
+		e = e.WithField(key, keysAndValues[i+1])
+	}
+	return e
+}
+
+func (l logrusLogger[E]) Debug(msg string, keysAndValues ...interface{}) {
+	l.withFields(keysAndValues).Debug(msg)
+}
+
+func (l logrusLogger[E]) Info(msg string, keysAndValues ...interface{}) {
+	l.withFields(keysAndValues).Info(msg)
+}
+
+func (l logrusLogger[E]) Warn(msg string, keysAndValues ...interface{}) {
+	l.withFields(keysAndValues).Warn(msg)
+}
+
+func (l logrusLogger[E]) Error(msg string, keysAndValues ...interface{}) {
+	l.withFields(keysAndValues).Error(msg)
+}
+
+// logAt logs the message with the method of the logger matching the level.
+func logAt(l Logger, level slog.Level, msg string, keysAndValues ...interface{}) {
+	switch {
+	case level < slog.LevelInfo:
+		l.Debug(msg, keysAndValues...)
+	case level < slog.LevelWarn:
+		l.Info(msg, keysAndValues...)
+	case level < slog.LevelError:
+		l.Warn(msg, keysAndValues...)
+	default:
+		l.Error(msg, keysAndValues...)
+	}
+}
+
+// logCall logs a finished call. Secret parameters are redacted.
+func (c *client) logCall(method Method, walletName string, correlationID string, params interface{}, duration time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+	level := c.logLevel
+	keysAndValues := []interface{}{
+		"method", method.String(),
+		"wallet", walletName,
+		"correlation_id", correlationID,
+		"duration", duration,
+	}
+	if params != nil {
+		keysAndValues = append(keysAndValues, "params", redactParams(method, params))
+	}
+	if err != nil {
+		level = c.logErrorLevel
+		keysAndValues = append(keysAndValues,
+			"outcome", Classify(err).String(),
+			"error", err.Error(),
+		)
+	} else {
+		keysAndValues = append(keysAndValues, "outcome", "ok")
+	}
+	logAt(c.logger, level, "wasabi rpc call", keysAndValues...)
+}
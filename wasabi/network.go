@@ -0,0 +1,57 @@
+package wasabi
+
+import (
+	"fmt"
+	"sync"
+)
+
+// NetworkMismatchError is returned when an address does not belong to the expected network, e.g. a mainnet payment sent through a testnet daemon.
+type NetworkMismatchError struct {
+	Address string
+	// Network is the network of the address.
+	Network BitcoinNetwork
+	// Expected is the network of the daemon or the network given to ValidateAddress.
+	Expected BitcoinNetwork
+}
+
+func (e *NetworkMismatchError) Error() string {
+	return fmt.Sprintf("address %q belongs to network %s, expected %s", e.Address, e.Network, e.Expected)
+}
+
+// networkCache holds the network of the daemon fetched on first use.
+type networkCache struct {
+	mutex   sync.Mutex
+	network BitcoinNetwork
+}
+
+// daemonNetwork returns the network of the daemon from GetStatus. The network of a running daemon does not change, so it is cached.
+func (c *client) daemonNetwork() (BitcoinNetwork, error) {
+	c.network.mutex.Lock()
+	defer c.network.mutex.Unlock()
+	if c.network.network != "" {
+		return c.network.network, nil
+	}
+	status, err := c.GetStatus()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the network of the daemon: %w", err)
+	}
+	c.network.network = status.Network
+	return status.Network, nil
+}
+
+// checkPaymentsNetwork returns a NetworkMismatchError if a payment address does not belong to the network of the daemon. It does nothing if the guard is disabled in the config.
+func (c *client) checkPaymentsNetwork(payments []Payment) error {
+	if c.disableNetworkGuard || len(payments) == 0 {
+		return nil
+	}
+	network, err := c.daemonNetwork()
+	if err != nil {
+		return err
+	}
+	for _, payment := range payments {
+		if err := ValidateAddress(string(payment.SendTo), network); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package notify sends the events of a wasabi.EventBus to notification sinks: Telegram, Slack, email or a command.
+//
+//	bus := wasabi.NewEventBus()
+//	go bus.Watch(ctx, client, wasabi.EventWatchOptions{Wallets: []string{"wallet"}})
+//	sub := bus.Subscribe(wasabi.SubscribeOptions{}, wasabi.EventNewTransaction)
+//	go notify.Attach(ctx, sub, &notify.Slack{WebhookURL: url}, nil)
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Notifier sends a notification of an event.
+type Notifier interface {
+	Notify(ctx context.Context, event wasabi.Event) error
+}
+
+// NotifierFunc adapts a function to a Notifier.
+type NotifierFunc func(ctx context.Context, event wasabi.Event) error
+
+// Notify implements Notifier.
+func (f NotifierFunc) Notify(ctx context.Context, event wasabi.Event) error {
+	return f(ctx, event)
+}
+
+// Multi returns a notifier sending the notification to all notifiers. It returns the errors of all failed notifiers.
+func Multi(notifiers ...Notifier) Notifier {
+	return NotifierFunc(func(ctx context.Context, event wasabi.Event) error {
+		var errs []error
+		for _, n := range notifiers {
+			if err := n.Notify(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// Attach sends the events of the subscription to the notifier until the subscription is closed or the context is done. The errors of the notifier are passed to onError, which may be nil.
+func Attach(ctx context.Context, sub *wasabi.Subscription, notifier Notifier, onError func(event wasabi.Event, err error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := notifier.Notify(ctx, event); err != nil && onError != nil {
+				onError(event, err)
+			}
+		}
+	}
+}
+
+// Title returns a short title of the event, e.g. the subject of an email.
+func Title(event wasabi.Event) string {
+	switch e := event.(type) {
+	case wasabi.NewTransactionEvent:
+		if e.Transaction.Amount < 0 {
+			return fmt.Sprintf("%s: sent %s", e.WalletName, -e.Transaction.Amount)
+		}
+		return fmt.Sprintf("%s: received %s", e.WalletName, e.Transaction.Amount)
+	case wasabi.ConfirmationReachedEvent:
+		return fmt.Sprintf("%s: transaction confirmed", e.WalletName)
+	case wasabi.CoinJoinStatusChangedEvent:
+		return fmt.Sprintf("%s: coinjoin %s", e.WalletName, e.Current)
+	case wasabi.BackendDisconnectedEvent:
+		if e.Err != nil {
+			return "Wasabi daemon unreachable"
+		}
+		return "Wasabi backend disconnected"
+	case wasabi.PaymentInCoinJoinFinishedEvent:
+		return fmt.Sprintf("%s: payment in coinjoin finished", e.WalletName)
+	default:
+		return string(event.Type())
+	}
+}
+
+// Message returns a human readable description of the event.
+func Message(event wasabi.Event) string {
+	switch e := event.(type) {
+	case wasabi.NewTransactionEvent:
+		return fmt.Sprintf("%s\nTransaction %s\nLabel: %s", Title(e), e.Transaction.TxID, e.Transaction.Label)
+	case wasabi.ConfirmationReachedEvent:
+		return fmt.Sprintf("%s\nTransaction %s of %s confirmed at height %d", Title(e), e.Transaction.TxID, e.Transaction.Amount, e.Height)
+	case wasabi.CoinJoinStatusChangedEvent:
+		return fmt.Sprintf("%s\nCoinjoin status changed from %s to %s", Title(e), e.Previous, e.Current)
+	case wasabi.BackendDisconnectedEvent:
+		if e.Err != nil {
+			return fmt.Sprintf("%s\n%v", Title(e), e.Err)
+		}
+		return fmt.Sprintf("%s\nBackend status: %s", Title(e), e.Status)
+	case wasabi.PaymentInCoinJoinFinishedEvent:
+		return fmt.Sprintf("%s\nPayment %s of %s to %s", Title(e), e.Payment.ID, e.Payment.Amount, e.Payment.Address)
+	default:
+		return Title(event)
+	}
+}
+
+// eventJSON is the JSON representation of an event given to commands.
+type eventJSON struct {
+	Type    wasabi.EventType `json:"type"`
+	Title   string           `json:"title"`
+	Message string           `json:"message"`
+	Event   wasabi.Event     `json:"event"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// marshalEvent returns the JSON representation of the event.
+func marshalEvent(event wasabi.Event) ([]byte, error) {
+	v := eventJSON{Type: event.Type(), Title: Title(event), Message: Message(event), Event: event}
+	if e, ok := event.(wasabi.BackendDisconnectedEvent); ok && e.Err != nil {
+		v.Error = e.Err.Error()
+		e.Err = nil
+		v.Event = e
+	}
+	return json.Marshal(v)
+}
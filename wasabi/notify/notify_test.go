@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+var received = wasabi.NewTransactionEvent{
+	WalletName:  "w",
+	Transaction: wasabi.Transaction{TxID: "tx", Amount: 150_000, Label: "salary"},
+}
+
+func TestTitleAndMessage(t *testing.T) {
+	tests := []struct {
+		event   wasabi.Event
+		title   string
+		message string
+	}{
+		{event: received, title: "w: received 0.00150000 BTC", message: "w: received 0.00150000 BTC\nTransaction tx\nLabel: salary"},
+		{event: wasabi.NewTransactionEvent{WalletName: "w", Transaction: wasabi.Transaction{TxID: "tx", Amount: -2000}}, title: "w: sent 0.00002000 BTC"},
+		{event: wasabi.ConfirmationReachedEvent{WalletName: "w", Transaction: wasabi.Transaction{TxID: "tx", Amount: 2000}, Height: 12}, title: "w: transaction confirmed", message: "w: transaction confirmed\nTransaction tx of 0.00002000 BTC confirmed at height 12"},
+		{event: wasabi.CoinJoinStatusChangedEvent{WalletName: "w", Previous: wasabi.CoinJoinStatusIdle, Current: wasabi.CoinJoinStatusInProgress}, title: "w: coinjoin In progress", message: "w: coinjoin In progress\nCoinjoin status changed from Idle to In progress"},
+		{event: wasabi.BackendDisconnectedEvent{Status: wasabi.BackendStatusDisconnected}, title: "Wasabi backend disconnected"},
+		{event: wasabi.BackendDisconnectedEvent{Err: errors.New("connection refused")}, title: "Wasabi daemon unreachable", message: "Wasabi daemon unreachable\nconnection refused"},
+	}
+	for _, tt := range tests {
+		if got := Title(tt.event); got != tt.title {
+			t.Errorf("Title(%+v) = %q, want %q", tt.event, got, tt.title)
+		}
+		if tt.message == "" {
+			continue
+		}
+		if got := Message(tt.event); got != tt.message {
+			t.Errorf("Message(%+v) = %q, want %q", tt.event, got, tt.message)
+		}
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	var form url.Values
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		r.ParseForm()
+		form = r.PostForm
+	}))
+	defer server.Close()
+
+	telegram := &Telegram{Token: "123:secret", ChatID: "42", BaseURL: server.URL + "/"}
+	if err := telegram.Notify(context.Background(), received); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/bot123:secret/sendMessage" {
+		t.Errorf("path = %s", path)
+	}
+	if form.Get("chat_id") != "42" || form.Get("text") != Message(received) {
+		t.Errorf("form = %v", form)
+	}
+	if err := (&Telegram{Token: "123:secret"}).Notify(context.Background(), received); err == nil {
+		t.Error("Notify() without chat id = nil error")
+	}
+}
+
+func TestSlack(t *testing.T) {
+	var body struct {
+		Text string `json:"text"`
+	}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(status)
+		io.WriteString(w, "invalid_token\n")
+	}))
+	defer server.Close()
+
+	slack := &Slack{WebhookURL: server.URL + "/services/secret"}
+	if err := slack.Notify(context.Background(), received); err != nil {
+		t.Fatal(err)
+	}
+	if body.Text != Message(received) {
+		t.Errorf("text = %q", body.Text)
+	}
+
+	status = http.StatusForbidden
+	err := slack.Notify(context.Background(), received)
+	if err == nil || err.Error() != "slack: http status 403: invalid_token" {
+		t.Errorf("Notify() = %v, want the status and body of the response", err)
+	}
+}
+
+func TestNetworkErrorHidesURL(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	err := (&Telegram{Token: "123:secret", ChatID: "42", BaseURL: server.URL}).Notify(context.Background(), received)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("Notify() = %v, want an error without the token", err)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	var calls int
+	ok := NotifierFunc(func(ctx context.Context, event wasabi.Event) error {
+		calls++
+		return nil
+	})
+	failed := errors.New("failed")
+	failing := NotifierFunc(func(ctx context.Context, event wasabi.Event) error {
+		calls++
+		return failed
+	})
+	err := Multi(failing, ok, failing).Notify(context.Background(), received)
+	if !errors.Is(err, failed) || calls != 3 {
+		t.Fatalf("Notify() = %v after %d calls, want the errors after 3 calls", err, calls)
+	}
+	if err := Multi(ok).Notify(context.Background(), received); err != nil {
+		t.Fatalf("Notify() = %v", err)
+	}
+}
+
+func TestAttach(t *testing.T) {
+	bus := wasabi.NewEventBus()
+	sub := bus.Subscribe(wasabi.SubscribeOptions{}, wasabi.EventNewTransaction)
+	var notified []wasabi.Event
+	var failures []wasabi.Event
+	notifier := NotifierFunc(func(ctx context.Context, event wasabi.Event) error {
+		notified = append(notified, event)
+		if len(notified) == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	done := make(chan struct{})
+	go func() {
+		Attach(context.Background(), sub, notifier, func(event wasabi.Event, err error) { failures = append(failures, event) })
+		close(done)
+	}()
+	bus.Publish(context.Background(), received)
+	bus.Publish(context.Background(), wasabi.CoinJoinStatusChangedEvent{WalletName: "w"})
+	bus.Publish(context.Background(), received)
+	sub.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Attach did not return when the subscription was closed")
+	}
+	if len(notified) != 2 || len(failures) != 1 {
+		t.Fatalf("notified %d events with %d failures, want 2 events of the subscribed type and 1 failure", len(notified), len(failures))
+	}
+}
+
+func TestExec(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	out := filepath.Join(t.TempDir(), "event.json")
+	x := &Exec{Path: "sh", Args: []string{"-c", `cat > "$OUT"; echo >> "$OUT"; echo "$WASABI_EVENT_TYPE|$WASABI_EVENT_TITLE" >> "$OUT"`}, Env: []string{"OUT=" + out}}
+	if err := x.Notify(context.Background(), received); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, env, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	var event struct {
+		Type  wasabi.EventType `json:"type"`
+		Title string           `json:"title"`
+		Event struct {
+			WalletName string
+		} `json:"event"`
+	}
+	if err := json.Unmarshal([]byte(input), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != wasabi.EventNewTransaction || event.Title != Title(received) || event.Event.WalletName != "w" {
+		t.Errorf("command input = %s", input)
+	}
+	if env != "NewTransaction|"+Title(received) {
+		t.Errorf("command environment = %s", env)
+	}
+
+	failing := &Exec{Path: "sh", Args: []string{"-c", "echo broken >&2; exit 3"}}
+	if err := failing.Notify(context.Background(), received); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Notify() = %v, want the output of the failed command", err)
+	}
+}
+
+func TestMarshalEventError(t *testing.T) {
+	data, err := marshalEvent(wasabi.BackendDisconnectedEvent{Err: errors.New("connection refused")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil || event.Error != "connection refused" {
+		t.Fatalf("marshalEvent() = %s, %v", data, err)
+	}
+}
@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Telegram sends the notifications as messages of a Telegram bot.
+type Telegram struct {
+	// Token is the token of the bot.
+	Token string
+	// ChatID is the chat receiving the messages.
+	ChatID string
+	// BaseURL is the URL of the bot API. Default is https://api.telegram.org.
+	BaseURL string
+	// HTTPClient sends the requests. Default is http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Notify implements Notifier.
+func (t *Telegram) Notify(ctx context.Context, event wasabi.Event) error {
+	if t.Token == "" || t.ChatID == "" {
+		return fmt.Errorf("telegram token and chat id must not be empty")
+	}
+	baseURL := t.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.telegram.org"
+	}
+	form := url.Values{"chat_id": {t.ChatID}, "text": {Message(event)}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(baseURL, "/")+"/bot"+t.Token+"/sendMessage", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return do(t.HTTPClient, req, "telegram")
+}
+
+// Slack sends the notifications to a Slack incoming webhook.
+type Slack struct {
+	// WebhookURL is the URL of the incoming webhook.
+	WebhookURL string
+	// HTTPClient sends the requests. Default is http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Notify implements Notifier.
+func (s *Slack) Notify(ctx context.Context, event wasabi.Event) error {
+	if s.WebhookURL == "" {
+		return fmt.Errorf("slack webhook url must not be empty")
+	}
+	body, err := json.Marshal(map[string]string{"text": Message(event)})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return do(s.HTTPClient, req, "slack")
+}
+
+// do sends the request and fails on non 2xx responses.
+func do(client *http.Client, req *http.Request, sink string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error contains the URL, which may contain a token.
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s: %w", sink, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: http status %d: %s", sink, resp.StatusCode, bytes.TrimSpace(data))
+	}
+	return nil
+}
+
+// Email sends the notifications as emails through an SMTP server.
+type Email struct {
+	// Addr is the address of the SMTP server, e.g. "smtp.example.com:587".
+	Addr string
+	// Auth authenticates to the server. Optional.
+	Auth smtp.Auth
+	// From is the sender address.
+	From string
+	// To are the recipient addresses.
+	To []string
+}
+
+// Notify implements Notifier. The context is not used: net/smtp does not support cancellation.
+func (e *Email) Notify(ctx context.Context, event wasabi.Event) error {
+	if e.Addr == "" || e.From == "" || len(e.To) == 0 {
+		return fmt.Errorf("email server, sender and recipients must not be empty")
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", Title(event))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(Message(event), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	if err := smtp.SendMail(e.Addr, e.Auth, e.From, e.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
+
+// Exec runs a command for each notification. The command gets the event as JSON on its standard input, with the fields type, title, message and event, and the environment variables WASABI_EVENT_TYPE, WASABI_EVENT_TITLE and WASABI_EVENT_MESSAGE.
+type Exec struct {
+	// Path is the command to run.
+	Path string
+	// Args are the arguments of the command.
+	Args []string
+	// Env is added to the environment of the current process.
+	Env []string
+}
+
+// Notify implements Notifier. The command is killed when the context is done.
+func (x *Exec) Notify(ctx context.Context, event wasabi.Event) error {
+	if x.Path == "" {
+		return fmt.Errorf("command must not be empty")
+	}
+	data, err := marshalEvent(event)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, x.Path, x.Args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = append(append(os.Environ(), x.Env...),
+		"WASABI_EVENT_TYPE="+string(event.Type()),
+		"WASABI_EVENT_TITLE="+Title(event),
+		"WASABI_EVENT_MESSAGE="+Message(event),
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", x.Path, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package otelwasabi traces wasabi rpc calls with OpenTelemetry.
+//
+// It lives in its own module, so the wasabi package does not depend on OpenTelemetry.
+package otelwasabi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer.
+const instrumentationName = "github.com/acfnv/go-wasabi-rpc-client/wasabi/otelwasabi"
+
+// Option configures the tracer.
+type Option func(*tracer)
+
+// WithTracerProvider sets the tracer provider. Default is the global tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(t *tracer) {
+		t.provider = tp
+	}
+}
+
+// WithPropagator sets the propagator used to inject the trace context into the request headers. Default is the global propagator.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(t *tracer) {
+		t.propagator = p
+	}
+}
+
+// NewTracer creates a wasabi.Tracer which starts an OpenTelemetry client span for each rpc call.
+func NewTracer(opts ...Option) wasabi.Tracer {
+	t := &tracer{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.tracer = t.provider.Tracer(instrumentationName)
+	return t
+}
+
+type tracer struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+	tracer     trace.Tracer
+}
+
+func (t *tracer) Start(ctx context.Context, spanName string, attributes map[string]string) (context.Context, wasabi.Span) {
+	attrs := make([]attribute.KeyValue, 0, len(attributes))
+	for k, v := range attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	ctx, s := t.tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, span{s}
+}
+
+func (t *tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type span struct {
+	trace.Span
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.SetAttributes(attribute.String("wasabi.error.category", wasabi.Classify(err).String()))
+		s.RecordError(err)
+		s.SetStatus(codes.Error, err.Error())
+	}
+	s.Span.End()
+}
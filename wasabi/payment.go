@@ -0,0 +1,146 @@
+package wasabi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PaymentBuilder builds the payments of a transaction. The methods can be chained; the first error is kept and returned by Build.
+//
+//	payments, err := wasabi.NewPaymentBuilder().
+//		PayBTC("bc1q...", "0.0015").Label("invoice 42").
+//		PayURI("bitcoin:bc1q...?amount=0.01&label=rent").SubtractFee().
+//		Build()
+type PaymentBuilder struct {
+	payments []Payment
+	network  BitcoinNetwork
+	err      error
+}
+
+// NewPaymentBuilder creates an empty payment builder.
+func NewPaymentBuilder() *PaymentBuilder {
+	return &PaymentBuilder{}
+}
+
+// ForNetwork makes Build check that all addresses belong to the network.
+func (b *PaymentBuilder) ForNetwork(network BitcoinNetwork) *PaymentBuilder {
+	b.network = network
+	return b
+}
+
+// Pay adds a payment of the amount.
+func (b *PaymentBuilder) Pay(address string, amount Amount) *PaymentBuilder {
+	b.payments = append(b.payments, Payment{SendTo: Address(address), Amount: amount})
+	return b
+}
+
+// PayBTC adds a payment of the decimal bitcoin amount, e.g. "0.0015".
+func (b *PaymentBuilder) PayBTC(address string, btc string) *PaymentBuilder {
+	amount, err := ParseBTC(btc)
+	if err != nil {
+		b.setErr(err)
+	}
+	return b.Pay(address, amount)
+}
+
+// PayURI adds the payment of a BIP21 URI. The URI must have an amount; its label is used as the label of the payment, or its message if there is no label.
+func (b *PaymentBuilder) PayURI(uri string) *PaymentBuilder {
+	parsed, err := ParseBIP21(uri)
+	if err != nil {
+		b.setErr(err)
+		return b
+	}
+	payment, err := parsed.Payment()
+	if err != nil {
+		b.setErr(err)
+	}
+	b.payments = append(b.payments, payment)
+	return b
+}
+
+// Label sets the label of the last added payment.
+func (b *PaymentBuilder) Label(label string) *PaymentBuilder {
+	if p := b.last("Label"); p != nil {
+		p.Label = label
+	}
+	return b
+}
+
+// SubtractFee makes the last added payment pay the transaction fee.
+func (b *PaymentBuilder) SubtractFee() *PaymentBuilder {
+	if p := b.last("SubtractFee"); p != nil {
+		p.SubtractFee = true
+	}
+	return b
+}
+
+func (b *PaymentBuilder) last(method string) *Payment {
+	if len(b.payments) == 0 {
+		b.setErr(fmt.Errorf("%s called before any payment was added", method))
+		return nil
+	}
+	return &b.payments[len(b.payments)-1]
+}
+
+func (b *PaymentBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Build validates and returns the payments: there must be at least one payment, amounts must be positive, addresses must be valid (and belong to the network given to ForNetwork) and at most one payment may subtract the fee.
+func (b *PaymentBuilder) Build() ([]Payment, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.payments) == 0 {
+		return nil, errors.New("no payments")
+	}
+	subtractFee := 0
+	for _, p := range b.payments {
+		if p.Amount <= 0 {
+			return nil, fmt.Errorf("amount of the payment to %s must be positive", p.SendTo)
+		}
+		var err error
+		if b.network != "" {
+			err = ValidateAddress(string(p.SendTo), b.network)
+		} else {
+			_, err = p.SendTo.Decode()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if p.SubtractFee {
+			subtractFee++
+		}
+	}
+	if subtractFee > 1 {
+		return nil, errors.New("only one payment may subtract the fee")
+	}
+	return append([]Payment(nil), b.payments...), nil
+}
+
+// Summary returns a human-readable description of the payments, one line per payment and the total.
+func (b *PaymentBuilder) Summary() string {
+	return PaymentsSummary(b.payments)
+}
+
+// PaymentsSummary returns a human-readable description of the payments, one line per payment and the total.
+func PaymentsSummary(payments []Payment) string {
+	var sb strings.Builder
+	var total Amount
+	for _, p := range payments {
+		total += p.Amount
+		fmt.Fprintf(&sb, "%s (%d sat) to %s", p.Amount, p.Amount.Sats(), p.SendTo)
+		if p.Label != "" {
+			fmt.Fprintf(&sb, " %q", p.Label)
+		}
+		if p.SubtractFee {
+			sb.WriteString(", fee subtracted")
+		}
+		sb.WriteByte('\n')
+	}
+	fmt.Fprintf(&sb, "total %s (%d sat) in %d payments", total, total.Sats(), len(payments))
+	return sb.String()
+}
@@ -0,0 +1,39 @@
+package wasabi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// PingResult is the outcome of a Ping.
+type PingResult struct {
+	// Latency is the duration of the round trip.
+	Latency time.Duration
+	// Authenticated reports whether the daemon accepted the credentials of the client, i.e. answered with a JSON-RPC response.
+	Authenticated bool
+}
+
+// Ping sends a getstatus request and waits for its JSON-RPC response, so unlike IsWasabiWalletUp it checks the credentials and that the daemon answers calls, not only that its port accepts connections. The call is not retried. If the daemon answers with an error, e.g. rejects the credentials, the result holds the latency along with the error.
+func (c *client) Ping(ctx context.Context) (PingResult, error) {
+	clone := *c
+	clone.ctx = WithoutRetry(ctx)
+	var status json.RawMessage
+	start := time.Now()
+	err := clone.do(MethodGetStatus, "", nil, &status)
+	result := PingResult{Latency: time.Since(start)}
+	var httpErr *HTTPError
+	switch {
+	case err == nil:
+		result.Authenticated = true
+	case errors.As(err, &httpErr):
+		// The daemon (or a proxy in front of it) answered without a JSON-RPC response, e.g. 401 for wrong credentials.
+	case Classify(err) == ErrorCategoryConnection || Classify(err) == ErrorCategoryUnknown:
+		return PingResult{}, err
+	default:
+		// The daemon answered with a JSON-RPC error, so the request got past the authentication.
+		result.Authenticated = true
+	}
+	return result, err
+}
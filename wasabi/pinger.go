@@ -0,0 +1,94 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPingInterval is the interval between the pings of a Pinger if no interval is given. It is shorter than the idle timeout of the http transport and the lifetime of unused Tor circuits.
+const DefaultPingInterval = 30 * time.Second
+
+// PingerOptions holds the options of a Pinger.
+type PingerOptions struct {
+	// Interval is the interval between the pings. Default is DefaultPingInterval.
+	Interval time.Duration
+	// Timeout bounds each ping. Default is the interval.
+	Timeout time.Duration
+	// OnError is called with the errors of the pings. The pinger keeps running after a failed ping.
+	OnError func(err error)
+	// Clock provides the timers of the pings and the time of LastHealthy. Default is SystemClock.
+	Clock Clock
+}
+
+// Pinger pings the daemon in the background to keep the connections and Tor circuits of the client warm, so the first call of a rarely used service does not pay for their setup. It records when the daemon last answered, for health checks which must not call the daemon themselves.
+type Pinger struct {
+	client      Client
+	opts        PingerOptions
+	lastHealthy atomic.Int64
+	lastLatency atomic.Int64
+	runOnce     sync.Once
+}
+
+// NewPinger creates a pinger of the daemon of the client. Call Run, usually in its own goroutine, to start it.
+func NewPinger(c Client, opts PingerOptions) *Pinger {
+	if opts.Clock == nil {
+		opts.Clock = SystemClock
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultPingInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = opts.Interval
+	}
+	return &Pinger{client: c, opts: opts}
+}
+
+// Run pings the daemon immediately and then every interval until the context is done, and returns the error of the context. Run can be called only once.
+func (p *Pinger) Run(ctx context.Context) error {
+	err := errors.New("the pinger can be run only once")
+	p.runOnce.Do(func() {
+		err = poll(ctx, p.opts.Clock, p.opts.Interval, func() (bool, error) {
+			p.ping(ctx)
+			return false, nil
+		})
+	})
+	return err
+}
+
+// ping pings the daemon once and records the outcome.
+func (p *Pinger) ping(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+	defer cancel()
+	result, err := p.client.Ping(pingCtx)
+	if err != nil {
+		if p.opts.OnError != nil && ctx.Err() == nil {
+			p.opts.OnError(err)
+		}
+		return
+	}
+	p.lastLatency.Store(int64(result.Latency))
+	p.lastHealthy.Store(p.opts.Clock.Now().UnixNano())
+}
+
+// LastHealthy returns when the daemon last answered a ping, or the zero time if it never did. It is safe to call concurrently with Run.
+func (p *Pinger) LastHealthy() time.Time {
+	nanos := p.lastHealthy.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// LastLatency returns the latency of the last successful ping, or zero if no ping succeeded.
+func (p *Pinger) LastLatency() time.Duration {
+	return time.Duration(p.lastLatency.Load())
+}
+
+// HealthyWithin reports whether the daemon answered a ping within the given duration.
+func (p *Pinger) HealthyWithin(d time.Duration) bool {
+	last := p.LastHealthy()
+	return !last.IsZero() && p.opts.Clock.Now().Sub(last) <= d
+}
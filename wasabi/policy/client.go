@@ -0,0 +1,288 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// client checks the spends of the wrapped client with the rules of an engine.
+type client struct {
+	wasabi.Client
+	engine *Engine
+}
+
+// Client returns a client whose spends are checked by the rules of the engine. Sends without coins are built first, so the rules see the inputs selected by the daemon, and then sent with exactly these coins. Payments in coinjoin are recorded when they are registered, even if they are cancelled later.
+func (e *Engine) Client(c wasabi.Client) wasabi.Client {
+	return &client{Client: c, engine: e}
+}
+
+func (c *client) Send(walletName string, req wasabi.SendRequest) (wasabi.SendResponse, error) {
+	outputs, err := paymentOutputs(req.Payments)
+	if err != nil {
+		return wasabi.SendResponse{}, err
+	}
+	var inputs []wasabi.ListCoinsResponse
+	if len(req.Coins) == 0 {
+		tx, err := c.Client.Build(walletName, wasabi.BuildRequest{Payments: req.Payments, FeeTarget: req.FeeTarget, FeeRate: req.FeeRate, Password: req.Password})
+		if err != nil {
+			return wasabi.SendResponse{}, err
+		}
+		if inputs, err = c.builtInputs(walletName, tx); err != nil {
+			return wasabi.SendResponse{}, err
+		}
+		for _, input := range inputs {
+			req.Coins = append(req.Coins, wasabi.Coin{TransactionID: input.TxID, Index: input.Index})
+		}
+	} else if inputs, err = c.coins(walletName, req.Coins); err != nil {
+		return wasabi.SendResponse{}, err
+	}
+	var resp wasabi.SendResponse
+	err = c.engine.execute(Spend{Method: wasabi.MethodSend, WalletName: walletName, Outputs: outputs, Inputs: inputs}, true, func() error {
+		resp, err = c.Client.Send(walletName, req)
+		return err
+	})
+	return resp, err
+}
+
+func (c *client) Build(walletName string, req wasabi.BuildRequest) (string, error) {
+	return c.build(wasabi.MethodBuild, walletName, req.Payments, func() (string, error) {
+		return c.Client.Build(walletName, req)
+	})
+}
+
+func (c *client) BuildUnsafeTransaction(walletName string, req wasabi.BuildUnsafeRequest) (string, error) {
+	return c.build(wasabi.MethodBuildUnsafeTransaction, walletName, req.Payments, func() (string, error) {
+		return c.Client.BuildUnsafeTransaction(walletName, req)
+	})
+}
+
+// build builds the transaction and returns it if the rules allow it as a spend of its inputs.
+func (c *client) build(method wasabi.Method, walletName string, payments []wasabi.Payment, build func() (string, error)) (string, error) {
+	outputs, err := paymentOutputs(payments)
+	if err != nil {
+		return "", err
+	}
+	tx, err := build()
+	if err != nil {
+		return "", err
+	}
+	inputs, err := c.builtInputs(walletName, tx)
+	if err != nil {
+		return "", err
+	}
+	err = c.engine.execute(Spend{Method: method, WalletName: walletName, Outputs: outputs, Inputs: inputs}, false, func() error { return nil })
+	if err != nil {
+		return "", err
+	}
+	return tx, nil
+}
+
+func (c *client) Broadcast(walletName string, txHex string) (wasabi.TxID, error) {
+	spend, err := c.transactionSpend(wasabi.MethodBroadcast, walletName, txHex)
+	if err != nil {
+		return "", err
+	}
+	var txID wasabi.TxID
+	err = c.engine.execute(spend, true, func() error {
+		txID, err = c.Client.Broadcast(walletName, txHex)
+		return err
+	})
+	return txID, err
+}
+
+// SpeedUpTransaction returns the replacement transaction if the rules allow it, like Build. It is recorded when it is broadcast.
+func (c *client) SpeedUpTransaction(walletName string, txID wasabi.TxID, password string) (string, error) {
+	return c.replacement(wasabi.MethodSpeedUpTransaction, walletName, func() (string, error) {
+		return c.Client.SpeedUpTransaction(walletName, txID, password)
+	})
+}
+
+// CancelTransaction returns the replacement transaction if the rules allow it, like Build. It is recorded when it is broadcast.
+func (c *client) CancelTransaction(walletName string, txID wasabi.TxID, password string) (string, error) {
+	return c.replacement(wasabi.MethodCancelTransaction, walletName, func() (string, error) {
+		return c.Client.CancelTransaction(walletName, txID, password)
+	})
+}
+
+// replacement builds a transaction replacing another and returns it if the rules allow it as a spend of its inputs and of its outputs to others.
+func (c *client) replacement(method wasabi.Method, walletName string, build func() (string, error)) (string, error) {
+	tx, err := build()
+	if err != nil {
+		return "", err
+	}
+	spend, err := c.transactionSpend(method, walletName, tx)
+	if err != nil {
+		return "", err
+	}
+	if err := c.engine.execute(spend, false, func() error { return nil }); err != nil {
+		return "", err
+	}
+	return tx, nil
+}
+
+// StartCoinJoinSweep is denied: the coins are sent to another wallet in coinjoins whose outputs cannot be checked by the rules.
+func (c *client) StartCoinJoinSweep(walletName string, password string, outputWalletName string) error {
+	return notChecked(wasabi.MethodStartCoinJoinSweep, walletName, "coinjoin sweeps to another wallet cannot be checked by the rules")
+}
+
+// transactionSpend returns the spend of the signed transaction: the coins of the wallet it spends and its outputs not paying to the wallet. Without wallet name, all outputs are part of the spend.
+func (c *client) transactionSpend(method wasabi.Method, walletName string, txHex string) (Spend, error) {
+	tx, err := wasabi.DecodeTransaction(txHex)
+	if err != nil {
+		return Spend{}, err
+	}
+	spend := Spend{Method: method, WalletName: walletName}
+	own := map[wasabi.ScriptPubKey]bool{}
+	if walletName != "" {
+		keys, err := c.Client.ListKeys(walletName)
+		if err != nil {
+			return Spend{}, err
+		}
+		for _, key := range keys {
+			own[key.ScriptPubKey] = true
+		}
+		if spend.Inputs, err = c.inputs(walletName, tx); err != nil {
+			return Spend{}, err
+		}
+	}
+	for _, output := range tx.Outputs {
+		if !own[output.ScriptPubKey] {
+			spend.Outputs = append(spend.Outputs, Output{ScriptPubKey: output.ScriptPubKey, Amount: output.Amount})
+		}
+	}
+	return spend, nil
+}
+
+func (c *client) PayInCoinJoin(walletName string, address wasabi.Address, amount wasabi.Amount, password string) (string, error) {
+	outputs, err := paymentOutputs([]wasabi.Payment{{SendTo: address, Amount: amount}})
+	if err != nil {
+		return "", err
+	}
+	var id string
+	err = c.engine.execute(Spend{Method: wasabi.MethodPayInCoinJoin, WalletName: walletName, Outputs: outputs}, true, func() error {
+		id, err = c.Client.PayInCoinJoin(walletName, address, amount, password)
+		return err
+	})
+	return id, err
+}
+
+// RawCall rejects the spending methods, which cannot be checked by the rules.
+func (c *client) RawCall(walletName string, method string, params interface{}, out interface{}) error {
+	if err := checkRawCall(walletName, method); err != nil {
+		return err
+	}
+	return c.Client.RawCall(walletName, method, params, out)
+}
+
+// CallRaw rejects the spending methods, which cannot be checked by the rules.
+func (c *client) CallRaw(walletName string, method string, params interface{}, w io.Writer) error {
+	if err := checkRawCall(walletName, method); err != nil {
+		return err
+	}
+	return c.Client.CallRaw(walletName, method, params, w)
+}
+
+// checkRawCall returns a DeniedError for the raw calls of spending methods.
+func checkRawCall(walletName string, method string) error {
+	switch wasabi.Method(method) {
+	case wasabi.MethodSend, wasabi.MethodBuild, wasabi.MethodBuildUnsafeTransaction, wasabi.MethodBroadcast, wasabi.MethodPayInCoinJoin,
+		wasabi.MethodSpeedUpTransaction, wasabi.MethodCancelTransaction, wasabi.MethodStartCoinJoinSweep:
+		return notChecked(wasabi.Method(method), walletName, "raw calls of spending methods are not allowed")
+	}
+	return nil
+}
+
+// notChecked returns the DeniedError of a spend which cannot be checked by the rules.
+func notChecked(method wasabi.Method, walletName string, message string) error {
+	return &DeniedError{Method: method, WalletName: walletName, Denials: []Denial{{
+		Rule:    "policy",
+		Reason:  ReasonNotChecked,
+		Message: message,
+	}}}
+}
+
+func (c *client) Wallet(walletName string) *wasabi.Wallet {
+	return wasabi.NewWallet(c, walletName)
+}
+
+func (c *client) WithContext(ctx context.Context) wasabi.Client {
+	return &client{Client: c.Client.WithContext(ctx), engine: c.engine}
+}
+
+func (c *client) With(opts ...wasabi.ClientOption) (wasabi.Client, error) {
+	derived, err := c.Client.With(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &client{Client: derived, engine: c.engine}, nil
+}
+
+// builtInputs returns the coins of the wallet spent by the built transaction.
+func (c *client) builtInputs(walletName string, txHex string) ([]wasabi.ListCoinsResponse, error) {
+	tx, err := wasabi.DecodeTransaction(txHex)
+	if err != nil {
+		return nil, err
+	}
+	return c.inputs(walletName, tx)
+}
+
+// inputs returns the coins of the wallet spent by the transaction. Inputs which are not coins of the wallet (e.g. of a payjoin receiver) are skipped.
+func (c *client) inputs(walletName string, tx *wasabi.RawTransaction) ([]wasabi.ListCoinsResponse, error) {
+	byOutPoint, err := c.unspentCoins(walletName)
+	if err != nil {
+		return nil, err
+	}
+	inputs := []wasabi.ListCoinsResponse{}
+	for _, input := range tx.Inputs {
+		if coin, ok := byOutPoint[wasabi.Coin{TransactionID: input.PrevTxID, Index: int(input.PrevIndex)}]; ok {
+			inputs = append(inputs, coin)
+		}
+	}
+	return inputs, nil
+}
+
+// coins returns the unspent coins of the wallet with the outpoints.
+func (c *client) coins(walletName string, outPoints []wasabi.Coin) ([]wasabi.ListCoinsResponse, error) {
+	byOutPoint, err := c.unspentCoins(walletName)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]wasabi.ListCoinsResponse, 0, len(outPoints))
+	for _, outPoint := range outPoints {
+		coin, ok := byOutPoint[outPoint]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s:%d", wasabi.ErrUnknownInput, outPoint.TransactionID, outPoint.Index)
+		}
+		inputs = append(inputs, coin)
+	}
+	return inputs, nil
+}
+
+// unspentCoins returns the unspent coins of the wallet by outpoint.
+func (c *client) unspentCoins(walletName string) (map[wasabi.Coin]wasabi.ListCoinsResponse, error) {
+	coins, err := c.Client.ListUnspentCoins(walletName)
+	if err != nil {
+		return nil, err
+	}
+	byOutPoint := make(map[wasabi.Coin]wasabi.ListCoinsResponse, len(coins))
+	for _, coin := range coins {
+		byOutPoint[wasabi.Coin{TransactionID: coin.TxID, Index: coin.Index}] = coin
+	}
+	return byOutPoint, nil
+}
+
+// paymentOutputs returns the outputs of the payments.
+func paymentOutputs(payments []wasabi.Payment) ([]Output, error) {
+	outputs := make([]Output, len(payments))
+	for i, payment := range payments {
+		script, err := payment.SendTo.ScriptPubKey()
+		if err != nil {
+			return nil, err
+		}
+		outputs[i] = Output{Address: payment.SendTo, ScriptPubKey: script, Amount: payment.Amount}
+	}
+	return outputs, nil
+}
@@ -0,0 +1,147 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Counters persist the amounts spent, for the limits.
+type Counters interface {
+	// Add records an amount spent at the time under the key.
+	Add(key string, at time.Time, amount wasabi.Amount) error
+	// Sum returns the total amount recorded under the key since the time.
+	Sum(key string, since time.Time) (wasabi.Amount, error)
+}
+
+// entry is an amount spent.
+type entry struct {
+	Time   time.Time     `json:"time"`
+	Amount wasabi.Amount `json:"amount"`
+}
+
+// MemoryCounters are Counters kept in memory.
+type MemoryCounters struct {
+	mutex   sync.RWMutex
+	entries map[string][]entry
+}
+
+// NewMemoryCounters creates empty memory counters.
+func NewMemoryCounters() *MemoryCounters {
+	return &MemoryCounters{entries: map[string][]entry{}}
+}
+
+// Add implements Counters.
+func (c *MemoryCounters) Add(key string, at time.Time, amount wasabi.Amount) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[key] = append(c.entries[key], entry{Time: at, Amount: amount})
+	return nil
+}
+
+// Sum implements Counters.
+func (c *MemoryCounters) Sum(key string, since time.Time) (wasabi.Amount, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	var sum wasabi.Amount
+	for _, e := range c.entries[key] {
+		if !e.Time.Before(since) {
+			sum += e.Amount
+		}
+	}
+	return sum, nil
+}
+
+// Prune drops the amounts spent before the time. Call it with the start of the longest window of the limits to keep the counters small.
+func (c *MemoryCounters) Prune(before time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key, entries := range c.entries {
+		kept := entries[:0]
+		for _, e := range entries {
+			if !e.Time.Before(before) {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) == 0 {
+			delete(c.entries, key)
+		} else {
+			c.entries[key] = kept
+		}
+	}
+	return nil
+}
+
+// FileCounters are Counters kept as JSON in a file. The file is rewritten atomically after each change.
+type FileCounters struct {
+	path   string
+	memory *MemoryCounters
+	mutex  sync.Mutex
+}
+
+// OpenFileCounters opens the counters in the file, which is created on the first change if it does not exist.
+func OpenFileCounters(path string) (*FileCounters, error) {
+	c := &FileCounters{path: path, memory: NewMemoryCounters()}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &c.memory.entries); err != nil {
+		return nil, err
+	}
+	if c.memory.entries == nil {
+		c.memory.entries = map[string][]entry{}
+	}
+	return c, nil
+}
+
+// Add implements Counters.
+func (c *FileCounters) Add(key string, at time.Time, amount wasabi.Amount) error {
+	return c.change(func() error { return c.memory.Add(key, at, amount) })
+}
+
+// Sum implements Counters.
+func (c *FileCounters) Sum(key string, since time.Time) (wasabi.Amount, error) {
+	return c.memory.Sum(key, since)
+}
+
+// Prune drops the amounts spent before the time (see MemoryCounters.Prune).
+func (c *FileCounters) Prune(before time.Time) error {
+	return c.change(func() error { return c.memory.Prune(before) })
+}
+
+// change applies the change in memory and writes the file.
+func (c *FileCounters) change(apply func() error) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := apply(); err != nil {
+		return err
+	}
+	c.memory.mutex.RLock()
+	content, err := json.MarshalIndent(c.memory.entries, "", "  ")
+	c.memory.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -0,0 +1,198 @@
+// Package policy enforces spending rules on a client: amount limits per period, address allowlists and denylists, a minimum anonymity score of the inputs and business hours.
+//
+//	counters, err := policy.OpenFileCounters("spent.json")
+//	engine := policy.New(policy.Options{Rules: []policy.Rule{
+//		policy.DailyLimit(1_000_000, counters),
+//		policy.MinAnonScore{Score: 5},
+//	}})
+//	c := engine.Client(client)
+//
+// Send, Build, BuildUnsafeTransaction, Broadcast and PayInCoinJoin of the client are checked by all rules before the daemon is asked to spend; denied calls fail with a DeniedError listing the reasons of all rules which denied the spend. The replacements of SpeedUpTransaction and CancelTransaction are checked like built transactions, while StartCoinJoinSweep and the raw calls of spending methods are always denied.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Output is an output of a spend paying someone else than the wallet. Change outputs are not part of a spend.
+type Output struct {
+	// Address is the address of the output. It is empty for the outputs of broadcast transactions, which are only known by their script.
+	Address wasabi.Address
+	// ScriptPubKey is the hex encoded script of the output.
+	ScriptPubKey wasabi.ScriptPubKey
+	Amount       wasabi.Amount
+}
+
+// Spend is a spend checked by the rules.
+type Spend struct {
+	// Method is the method spending: send, build, buildunsafetransaction, broadcast, payincoinjoin, speeduptransaction or canceltransaction.
+	Method     wasabi.Method
+	WalletName string
+	Outputs    []Output
+	// Inputs are the coins of the wallet spent. They are nil if they are not known: for payments in coinjoin and for transactions broadcast without a wallet name.
+	Inputs []wasabi.ListCoinsResponse
+	// Time is the time of the spend.
+	Time time.Time
+}
+
+// Amount returns the total amount of the outputs.
+func (s Spend) Amount() wasabi.Amount {
+	var amount wasabi.Amount
+	for _, output := range s.Outputs {
+		amount += output.Amount
+	}
+	return amount
+}
+
+// Reason is the machine readable reason of a denial.
+type Reason string
+
+const (
+	ReasonLimitExceeded        Reason = "limit_exceeded"
+	ReasonAddressNotAllowed    Reason = "address_not_allowed"
+	ReasonAddressDenied        Reason = "address_denied"
+	ReasonAnonScoreTooLow      Reason = "anon_score_too_low"
+	ReasonInputsUnknown        Reason = "inputs_unknown"
+	ReasonOutsideBusinessHours Reason = "outside_business_hours"
+	// ReasonNotChecked is the reason of raw calls of spending methods, which cannot be checked by the rules.
+	ReasonNotChecked Reason = "not_checked"
+)
+
+// Denial is a reason a rule denied a spend.
+type Denial struct {
+	// Rule is the name of the rule.
+	Rule    string `json:"rule"`
+	Reason  Reason `json:"reason"`
+	Message string `json:"message"`
+}
+
+// Rule decides whether a spend is allowed.
+type Rule interface {
+	// Name returns the name of the rule, reported in its denials.
+	Name() string
+	// Check returns a denial if the spend is not allowed, or nil. An error denies the spend too.
+	Check(spend Spend) (*Denial, error)
+}
+
+// Recorder is implemented by rules keeping track of the executed spends (see Limit). Record is called after each successful spend, except builds, and after each failed spend the daemon may have executed.
+type Recorder interface {
+	Record(spend Spend) error
+}
+
+// ErrDenied is matched (with errors.Is) by the errors of spends denied by the rules.
+var ErrDenied = errors.New("spend denied by policy")
+
+// DeniedError is returned when rules deny a spend.
+type DeniedError struct {
+	Method     wasabi.Method
+	WalletName string
+	Denials    []Denial
+}
+
+func (e *DeniedError) Error() string {
+	messages := make([]string, len(e.Denials))
+	for i, denial := range e.Denials {
+		messages[i] = denial.Rule + ": " + denial.Message
+	}
+	return fmt.Sprintf("%s of wallet %q: %v: %s", e.Method, e.WalletName, ErrDenied, strings.Join(messages, "; "))
+}
+
+func (e *DeniedError) Unwrap() error {
+	return ErrDenied
+}
+
+// Options holds the options of an Engine.
+type Options struct {
+	// Rules are the rules checking each spend. A spend is allowed if no rule denies it.
+	Rules []Rule
+	// OnRecordError is called if a rule fails to record a successful spend. The spend is not reported as failed, since it was executed.
+	OnRecordError func(spend Spend, err error)
+	// Clock provides the time of the spends. Default is wasabi.SystemClock.
+	Clock wasabi.Clock
+}
+
+// Engine checks spends with its rules. Spends of the clients of an engine are serialized, so limits cannot be exceeded by concurrent spends.
+type Engine struct {
+	opts  Options
+	mutex sync.Mutex
+}
+
+// New creates an engine.
+func New(opts Options) *Engine {
+	if opts.Clock == nil {
+		opts.Clock = wasabi.SystemClock
+	}
+	return &Engine{opts: opts}
+}
+
+// Evaluate returns the denials of the rules for the spend without executing it. The time of the spend is set to the current time if it is zero.
+func (e *Engine) Evaluate(spend Spend) ([]Denial, error) {
+	if spend.Time.IsZero() {
+		spend.Time = e.opts.Clock.Now()
+	}
+	var denials []Denial
+	for _, rule := range e.opts.Rules {
+		denial, err := rule.Check(spend)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name(), err)
+		}
+		if denial != nil {
+			denial.Rule = rule.Name()
+			denials = append(denials, *denial)
+		}
+	}
+	return denials, nil
+}
+
+// execute checks the spend and calls execute if it is allowed. The spend is recorded by the rules if record is set and execute succeeds, or fails in a way the daemon may still have executed it (see mayHaveExecuted), so the limits fail closed.
+func (e *Engine) execute(spend Spend, record bool, execute func() error) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	spend.Time = e.opts.Clock.Now()
+	denials, err := e.Evaluate(spend)
+	if err != nil {
+		return err
+	}
+	if len(denials) > 0 {
+		return &DeniedError{Method: spend.Method, WalletName: spend.WalletName, Denials: denials}
+	}
+	err = execute()
+	if err != nil && !mayHaveExecuted(err) {
+		return err
+	}
+	if !record {
+		return err
+	}
+	for _, rule := range e.opts.Rules {
+		recorder, ok := rule.(Recorder)
+		if !ok {
+			continue
+		}
+		if recordErr := recorder.Record(spend); recordErr != nil && e.opts.OnRecordError != nil {
+			e.opts.OnRecordError(spend, fmt.Errorf("rule %s: %w", rule.Name(), recordErr))
+		}
+	}
+	return err
+}
+
+// mayHaveExecuted reports whether a spend failing with err may have been executed by the daemon anyway: the connection failed, e.g. after the request was sent, a proxy answered with a 5xx status or the response could not be decoded. Errors answered by the daemon mean the spend was not executed.
+func mayHaveExecuted(err error) bool {
+	switch wasabi.Classify(err) {
+	case wasabi.ErrorCategoryConnection:
+		return true
+	case wasabi.ErrorCategoryProtocol:
+		var rpcErr *wasabi.RPCError
+		var httpErr *wasabi.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr.Status >= 500
+		}
+		return !errors.As(err, &rpcErr)
+	}
+	return false
+}
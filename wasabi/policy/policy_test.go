@@ -0,0 +1,208 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+// newTestServer starts a fake daemon with the wallet "w" (password "pw") holding 1000000 sat and returns the address of the wallet "payee".
+func newTestServer(t *testing.T) (*wasabitest.Server, wasabi.Address) {
+	t.Helper()
+	s := wasabitest.NewServer(wasabitest.ServerOptions{})
+	t.Cleanup(s.Close)
+	if err := s.AddWallet("w", "pw"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddWallet("payee", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Fund("w", 1_000_000, "salary"); err != nil {
+		t.Fatal(err)
+	}
+	s.Mine(1)
+	address, err := s.Client().GetNewAddress("payee", "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, address.Address
+}
+
+func send(c wasabi.Client, address wasabi.Address, amount wasabi.Amount) error {
+	_, err := c.Send("w", wasabi.SendRequest{
+		Payments:  []wasabi.Payment{{SendTo: address, Amount: amount, Label: "payee"}},
+		FeeTarget: wasabi.FeeTargetHour,
+		Password:  "pw",
+	})
+	return err
+}
+
+func deniedFor(err error, reason Reason) bool {
+	var denied *DeniedError
+	return errors.As(err, &denied) && len(denied.Denials) == 1 && denied.Denials[0].Reason == reason
+}
+
+func TestLimit(t *testing.T) {
+	s, payee := newTestServer(t)
+	clock := wasabitest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := New(Options{Rules: []Rule{DailyLimit(150_000, NewMemoryCounters())}, Clock: clock}).Client(s.Client())
+
+	if err := send(c, payee, 100_000); err != nil {
+		t.Fatal(err)
+	}
+	if err := send(c, payee, 100_000); !errors.Is(err, ErrDenied) || !deniedFor(err, ReasonLimitExceeded) {
+		t.Fatalf("Send() above the limit = %v, want a denial", err)
+	}
+	// Builds are checked, but not recorded.
+	if _, err := c.Build("w", wasabi.BuildRequest{Payments: []wasabi.Payment{{SendTo: payee, Amount: 100_000, Label: "payee"}}, FeeTarget: wasabi.FeeTargetHour, Password: "pw"}); !deniedFor(err, ReasonLimitExceeded) {
+		t.Fatalf("Build() above the limit = %v, want a denial", err)
+	}
+	if _, err := c.Build("w", wasabi.BuildRequest{Payments: []wasabi.Payment{{SendTo: payee, Amount: 50_000, Label: "payee"}}, FeeTarget: wasabi.FeeTargetHour, Password: "pw"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := send(c, payee, 50_000); err != nil {
+		t.Fatalf("Send() within the limit after a build = %v", err)
+	}
+
+	clock.Advance(25 * time.Hour)
+	if err := send(c, payee, 100_000); err != nil {
+		t.Fatalf("Send() a day later = %v", err)
+	}
+}
+
+func TestLimitFailedSend(t *testing.T) {
+	tests := []struct {
+		name     string
+		fail     func(r *wasabitest.Rule)
+		category wasabi.ErrorCategory
+		recorded bool
+	}{
+		{name: "wallet rejection", fail: func(r *wasabitest.Rule) { r.FailWallet(wasabi.ErrorIncorrectPassword) }, category: wasabi.ErrorCategoryWallet},
+		{name: "bad request", fail: func(r *wasabitest.Rule) { r.FailHTTP(http.StatusBadRequest) }, category: wasabi.ErrorCategoryProtocol},
+		{name: "bad gateway", fail: func(r *wasabitest.Rule) { r.FailHTTP(http.StatusBadGateway) }, category: wasabi.ErrorCategoryProtocol, recorded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, payee := newTestServer(t)
+			c := New(Options{Rules: []Rule{DailyLimit(150_000, NewMemoryCounters())}}).Client(s.Client())
+			tt.fail(s.On(wasabi.MethodSend).Once())
+
+			if err := send(c, payee, 100_000); wasabi.Classify(err) != tt.category {
+				t.Fatalf("Send() = %v, want a %v error", err, tt.category)
+			}
+			err := send(c, payee, 100_000)
+			if tt.recorded && !deniedFor(err, ReasonLimitExceeded) {
+				t.Fatalf("Send() after a failure the daemon may have executed = %v, want a denial", err)
+			}
+			if !tt.recorded && err != nil {
+				t.Fatalf("Send() after a rejected send = %v", err)
+			}
+		})
+	}
+}
+
+// roundTripperFunc implements http.RoundTripper.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestLimitConnectionError(t *testing.T) {
+	s, payee := newTestServer(t)
+	cfg := s.Config()
+	// The sends reach the daemon, but their responses are lost.
+	cfg.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil || !bytes.Contains(body, []byte(`"method":"send"`)) {
+			return resp, err
+		}
+		resp.Body.Close()
+		return nil, errors.New("connection reset by peer")
+	})
+	inner, err := wasabi.NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counters := NewMemoryCounters()
+	c := New(Options{Rules: []Rule{DailyLimit(150_000, counters)}}).Client(inner)
+
+	if err := send(c, payee, 100_000); wasabi.Classify(err) != wasabi.ErrorCategoryConnection {
+		t.Fatalf("Send() = %v, want a connection error", err)
+	}
+	history, err := s.Client().GetHistory("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history = %+v, want the send executed by the daemon", history)
+	}
+	if spent, _ := counters.Sum("daily/w", time.Time{}); spent != 100_000 {
+		t.Fatalf("recorded %v, want the send recorded", spent)
+	}
+	if err := send(c, payee, 100_000); !deniedFor(err, ReasonLimitExceeded) {
+		t.Fatalf("Send() after a lost response = %v, want a denial", err)
+	}
+}
+
+func TestReplacements(t *testing.T) {
+	s, payee := newTestServer(t)
+	sent, err := s.Client().Send("w", wasabi.SendRequest{
+		Payments:  []wasabi.Payment{{SendTo: payee, Amount: 100_000, Label: "payee"}},
+		FeeTarget: wasabi.FeeTargetHour,
+		Password:  "pw",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := New(Options{Rules: []Rule{DenyList{Addresses: []wasabi.Address{payee}}}}).Client(s.Client())
+
+	if _, err := c.SpeedUpTransaction("w", sent.TransactionID, "pw"); !deniedFor(err, ReasonAddressDenied) {
+		t.Fatalf("SpeedUpTransaction() of a payment to a denied address = %v, want a denial", err)
+	}
+	// The cancellation pays back to the wallet.
+	if tx, err := c.CancelTransaction("w", sent.TransactionID, "pw"); err != nil || tx == "" {
+		t.Fatalf("CancelTransaction() = %q, %v", tx, err)
+	}
+	if _, err := c.CancelTransaction("w", sent.TransactionID, "wrong"); wasabi.Classify(err) != wasabi.ErrorCategoryWallet {
+		t.Fatalf("CancelTransaction() with a wrong password = %v, want a wallet rejection", err)
+	}
+}
+
+func TestDeniedCalls(t *testing.T) {
+	s, _ := newTestServer(t)
+	c := New(Options{}).Client(s.Client())
+	before := len(s.Requests())
+
+	if err := c.StartCoinJoinSweep("w", "pw", "payee"); !deniedFor(err, ReasonNotChecked) {
+		t.Errorf("StartCoinJoinSweep() = %v, want a denial", err)
+	}
+	for _, method := range []wasabi.Method{wasabi.MethodSend, wasabi.MethodBroadcast, wasabi.MethodSpeedUpTransaction, wasabi.MethodCancelTransaction, wasabi.MethodStartCoinJoinSweep} {
+		if err := c.RawCall("w", string(method), nil, nil); !deniedFor(err, ReasonNotChecked) {
+			t.Errorf("RawCall(%s) = %v, want a denial", method, err)
+		}
+		if err := c.CallRaw("w", string(method), nil, io.Discard); !deniedFor(err, ReasonNotChecked) {
+			t.Errorf("CallRaw(%s) = %v, want a denial", method, err)
+		}
+	}
+	var status wasabi.GetStatusResponse
+	if err := c.RawCall("", string(wasabi.MethodGetStatus), nil, &status); err != nil {
+		t.Errorf("RawCall(getstatus) = %v", err)
+	}
+	for _, request := range s.Requests()[before:] {
+		if request.Method != wasabi.MethodGetStatus {
+			t.Errorf("the daemon was called with %s", request.Method)
+		}
+	}
+}
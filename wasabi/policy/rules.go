@@ -0,0 +1,195 @@
+package policy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Limit denies spends which would bring the amount spent by a wallet within the window above the maximum. The spent amounts are kept in the counters under the label of the limit and the wallet name.
+type Limit struct {
+	// Label names the limit, e.g. daily. Limits sharing counters must have different labels.
+	Label    string
+	Window   time.Duration
+	Max      wasabi.Amount
+	Counters Counters
+}
+
+// DailyLimit returns a limit of the amount spent by a wallet within 24 hours.
+func DailyLimit(max wasabi.Amount, counters Counters) Limit {
+	return Limit{Label: "daily", Window: 24 * time.Hour, Max: max, Counters: counters}
+}
+
+// WeeklyLimit returns a limit of the amount spent by a wallet within 7 days.
+func WeeklyLimit(max wasabi.Amount, counters Counters) Limit {
+	return Limit{Label: "weekly", Window: 7 * 24 * time.Hour, Max: max, Counters: counters}
+}
+
+// Name implements Rule.
+func (l Limit) Name() string {
+	if l.Label == "" {
+		return "limit"
+	}
+	return l.Label + " limit"
+}
+
+func (l Limit) key(walletName string) string {
+	return l.Label + "/" + walletName
+}
+
+// Check implements Rule.
+func (l Limit) Check(spend Spend) (*Denial, error) {
+	if l.Window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+	if l.Counters == nil {
+		return nil, fmt.Errorf("counters must not be nil")
+	}
+	spent, err := l.Counters.Sum(l.key(spend.WalletName), spend.Time.Add(-l.Window))
+	if err != nil {
+		return nil, err
+	}
+	if spent+spend.Amount() > l.Max {
+		return &Denial{
+			Reason:  ReasonLimitExceeded,
+			Message: fmt.Sprintf("spending %v would exceed the limit of %v within %v (%v already spent)", spend.Amount(), l.Max, l.Window, spent),
+		}, nil
+	}
+	return nil, nil
+}
+
+// Record implements Recorder.
+func (l Limit) Record(spend Spend) error {
+	return l.Counters.Add(l.key(spend.WalletName), spend.Time, spend.Amount())
+}
+
+// AllowList denies spends paying to addresses which are not in the list.
+type AllowList struct {
+	Addresses []wasabi.Address
+}
+
+// Name implements Rule.
+func (AllowList) Name() string { return "allowlist" }
+
+// Check implements Rule.
+func (l AllowList) Check(spend Spend) (*Denial, error) {
+	scripts, err := scriptSet(l.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	for _, output := range spend.Outputs {
+		if !scripts[output.ScriptPubKey] {
+			return &Denial{Reason: ReasonAddressNotAllowed, Message: fmt.Sprintf("%s is not in the allowlist", describeOutput(output))}, nil
+		}
+	}
+	return nil, nil
+}
+
+// DenyList denies spends paying to addresses in the list.
+type DenyList struct {
+	Addresses []wasabi.Address
+}
+
+// Name implements Rule.
+func (DenyList) Name() string { return "denylist" }
+
+// Check implements Rule.
+func (l DenyList) Check(spend Spend) (*Denial, error) {
+	scripts, err := scriptSet(l.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	for _, output := range spend.Outputs {
+		if scripts[output.ScriptPubKey] {
+			return &Denial{Reason: ReasonAddressDenied, Message: fmt.Sprintf("%s is in the denylist", describeOutput(output))}, nil
+		}
+	}
+	return nil, nil
+}
+
+// scriptSet returns the scripts of the addresses, so addresses are compared whatever their case.
+func scriptSet(addresses []wasabi.Address) (map[wasabi.ScriptPubKey]bool, error) {
+	scripts := make(map[wasabi.ScriptPubKey]bool, len(addresses))
+	for _, address := range addresses {
+		script, err := address.ScriptPubKey()
+		if err != nil {
+			return nil, err
+		}
+		scripts[script] = true
+	}
+	return scripts, nil
+}
+
+func describeOutput(output Output) string {
+	if output.Address != "" {
+		return "address " + output.Address.String()
+	}
+	return "script " + output.ScriptPubKey.String()
+}
+
+// MinAnonScore denies spends of coins whose anonymity score is below the minimum. Payments in coinjoin are allowed, since they are paid from coinjoin outputs; spends whose inputs are not known are denied.
+type MinAnonScore struct {
+	Score float64
+}
+
+// Name implements Rule.
+func (MinAnonScore) Name() string { return "min anon score" }
+
+// Check implements Rule.
+func (r MinAnonScore) Check(spend Spend) (*Denial, error) {
+	if spend.Method == wasabi.MethodPayInCoinJoin {
+		return nil, nil
+	}
+	if spend.Inputs == nil {
+		return &Denial{Reason: ReasonInputsUnknown, Message: "the inputs of the spend are not known"}, nil
+	}
+	var low int
+	for _, coin := range spend.Inputs {
+		if coin.AnonymityScore < r.Score {
+			low++
+		}
+	}
+	if low > 0 {
+		return &Denial{Reason: ReasonAnonScoreTooLow, Message: fmt.Sprintf("%d of %d inputs have an anonymity score below %v", low, len(spend.Inputs), r.Score)}, nil
+	}
+	return nil, nil
+}
+
+// BusinessHours denies spends outside the business hours.
+type BusinessHours struct {
+	// Location is the time zone of the business hours. Default is time.Local.
+	Location *time.Location
+	// Days are the business days. Default is Monday to Friday.
+	Days []time.Weekday
+	// Start and End are the times of day (since midnight) the business hours start and end, e.g. 9 * time.Hour and 17 * time.Hour.
+	Start, End time.Duration
+}
+
+// Name implements Rule.
+func (BusinessHours) Name() string { return "business hours" }
+
+// Check implements Rule.
+func (r BusinessHours) Check(spend Spend) (*Denial, error) {
+	if r.Start < 0 || r.End > 24*time.Hour || r.End <= r.Start {
+		return nil, fmt.Errorf("business hours must be within a day and end after they start")
+	}
+	location := r.Location
+	if location == nil {
+		location = time.Local
+	}
+	days := r.Days
+	if days == nil {
+		days = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	}
+	t := spend.Time.In(location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+	if offset := t.Sub(midnight); offset >= r.Start && offset < r.End {
+		for _, day := range days {
+			if t.Weekday() == day {
+				return nil, nil
+			}
+		}
+	}
+	return &Denial{Reason: ReasonOutsideBusinessHours, Message: fmt.Sprintf("%s is outside the business hours", t.Format("Mon 15:04 MST"))}, nil
+}
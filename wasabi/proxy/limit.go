@@ -0,0 +1,53 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// limiter is a token bucket rate limiter per key.
+type limiter struct {
+	rate  float64
+	burst float64
+	clock wasabi.Clock
+
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+// bucket holds the tokens of a key.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int, clock wasabi.Clock) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{rate: rate, burst: float64(burst), clock: clock, buckets: map[string]*bucket{}}
+}
+
+// take takes a token of the key. It returns zero if a token was available, or the time until the next token.
+func (l *limiter) take(key string) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := l.clock.Now()
+	b := l.buckets[key]
+	if b == nil {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
@@ -0,0 +1,440 @@
+// Package proxy serves several Wasabi daemons behind one JSON-RPC endpoint.
+//
+// Wallet calls (http://proxy/walletName) are routed to the daemon holding the wallet, daemon calls (http://proxy/) to the default daemon, and listwallets returns the wallets of all daemons. The proxy authenticates its users, limits their request rate and can reject the methods which change the state of a wallet or a daemon. Existing clients work unchanged: point wasabi.Config at the proxy. Wallets are reached with URL path routing: daemons routing with selectwallet only serve daemon calls, since their selected wallet would be shared by all users.
+package proxy
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// DefaultDiscoveryInterval is the minimum interval between two discoveries of the wallets of the daemons.
+const DefaultDiscoveryInterval = 30 * time.Second
+
+// maxBodySize is the maximum size of a request body.
+const maxBodySize = 10 << 20
+
+// Backend is a daemon behind the proxy.
+type Backend struct {
+	// Name identifies the daemon in errors and logs.
+	Name string
+	// Host is the address of the rpc server of the daemon (without protocol and port).
+	Host string
+	// Port is the rpc port of the daemon. Default is 37128.
+	Port int
+	// RpcUser and RpcPassword authenticate the proxy to the daemon.
+	RpcUser     string
+	RpcPassword string
+	// Transport sends the requests to the daemon. Default is http.DefaultTransport.
+	Transport http.RoundTripper
+	// Wallets are the wallets of the daemon. If empty, the wallets are discovered with listwallets.
+	Wallets []string
+	// Routing is the routing mode of the daemon. Default is wasabi.RoutingURLPath. The wallets of daemons routing with selectwallet cannot be used through the proxy.
+	Routing wasabi.RoutingMode
+}
+
+// User is a user of the proxy.
+type User struct {
+	Name     string
+	Password string
+	// ReadOnly rejects the mutating methods for the user.
+	ReadOnly bool
+	// Wallets restricts the user to these wallets. If empty, the user can use all wallets. Restricted users cannot call mutating daemon methods (createwallet, recoverwallet, broadcast, stop), nor wallet methods, selectwallet and loadwallet on the root path.
+	Wallets []string
+}
+
+// Options holds the options of a Proxy.
+type Options struct {
+	// Backends are the daemons behind the proxy.
+	Backends []Backend
+	// DefaultBackend is the name of the daemon receiving the daemon calls. Default is the first backend.
+	DefaultBackend string
+	// Users are the users allowed to use the proxy with basic authentication. If empty, the proxy does not authenticate its clients.
+	Users []User
+	// ReadOnly rejects the mutating methods for all users.
+	ReadOnly bool
+	// RateLimit is the number of requests per second allowed per user, or per remote address without users. Zero means no limit.
+	RateLimit float64
+	// Burst is the number of requests allowed at once above the rate limit. Default is 1.
+	Burst int
+	// DiscoveryInterval is the minimum interval between two discoveries of the wallets. Default is DefaultDiscoveryInterval.
+	DiscoveryInterval time.Duration
+	// Clock provides the time of the rate limiter and the discovery. Default is wasabi.SystemClock.
+	Clock wasabi.Clock
+}
+
+// Proxy is an http.Handler serving the daemons behind one JSON-RPC endpoint.
+type Proxy struct {
+	opts     Options
+	backends []*backend
+	byName   map[string]*backend
+	fallback *backend
+	users    map[string]User
+	limiter  *limiter
+
+	mutex      sync.Mutex
+	routes     map[string]*backend
+	discovered time.Time
+}
+
+// backend is a daemon and its http client.
+type backend struct {
+	Backend
+	url    string
+	client *http.Client
+}
+
+// New creates a proxy for the backends.
+func New(opts Options) (*Proxy, error) {
+	if len(opts.Backends) == 0 {
+		return nil, fmt.Errorf("backends must not be empty")
+	}
+	if opts.RateLimit < 0 || opts.Burst < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative")
+	}
+	if opts.DiscoveryInterval == 0 {
+		opts.DiscoveryInterval = DefaultDiscoveryInterval
+	}
+	if opts.Clock == nil {
+		opts.Clock = wasabi.SystemClock
+	}
+	p := &Proxy{opts: opts, byName: map[string]*backend{}, users: map[string]User{}, routes: map[string]*backend{}}
+	for _, b := range opts.Backends {
+		switch {
+		case b.Name == "":
+			return nil, fmt.Errorf("backend name must not be empty")
+		case p.byName[b.Name] != nil:
+			return nil, fmt.Errorf("duplicate backend %s", b.Name)
+		case b.Host == "" || strings.ContainsAny(b.Host, "/:"):
+			return nil, fmt.Errorf("backend %s: host must not be empty or contain / or :", b.Name)
+		case (b.RpcUser == "") != (b.RpcPassword == ""):
+			return nil, fmt.Errorf("backend %s: rpc user and password must be set together", b.Name)
+		case b.Routing != wasabi.RoutingURLPath && b.Routing != wasabi.RoutingSelectWallet:
+			return nil, fmt.Errorf("backend %s: unknown routing mode %d", b.Name, b.Routing)
+		}
+		port := b.Port
+		if port == 0 {
+			port = 37128
+		}
+		be := &backend{Backend: b, url: "http://" + net.JoinHostPort(b.Host, strconv.Itoa(port)) + "/", client: &http.Client{Transport: b.Transport}}
+		p.backends = append(p.backends, be)
+		p.byName[b.Name] = be
+		for _, w := range b.Wallets {
+			p.routes[w] = be
+		}
+	}
+	p.fallback = p.backends[0]
+	if opts.DefaultBackend != "" {
+		if p.fallback = p.byName[opts.DefaultBackend]; p.fallback == nil {
+			return nil, fmt.Errorf("unknown default backend %s", opts.DefaultBackend)
+		}
+	}
+	for _, u := range opts.Users {
+		if u.Name == "" || u.Password == "" {
+			return nil, fmt.Errorf("user name and password must not be empty")
+		}
+		p.users[u.Name] = u
+	}
+	if opts.RateLimit > 0 {
+		p.limiter = newLimiter(opts.RateLimit, opts.Burst, opts.Clock)
+	}
+	return p, nil
+}
+
+// rpcRequest is the part of a JSON-RPC request read by the proxy.
+type rpcRequest struct {
+	Method string          `json:"method"`
+	ID     json.RawMessage `json:"id"`
+}
+
+// rpcResponse is a JSON-RPC response written by the proxy.
+type rpcResponse struct {
+	Version string           `json:"jsonrpc"`
+	ID      json.RawMessage  `json:"id,omitempty"`
+	Result  interface{}      `json:"result"`
+	Error   *wasabi.RPCError `json:"error,omitempty"`
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user, ok := p.authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="wasabi"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if p.limiter != nil {
+		key := user.Name
+		if key == "" {
+			key, _, _ = net.SplitHostPort(r.RemoteAddr)
+		}
+		if wait := p.limiter.take(key); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait/time.Second)+1))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	walletName := strings.Trim(r.URL.Path, "/")
+
+	trimmed := bytes.TrimSpace(body)
+	batch := len(trimmed) > 0 && trimmed[0] == '['
+	var requests []rpcRequest
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		var req rpcRequest
+		err = json.Unmarshal(body, &req)
+		requests = []rpcRequest{req}
+	}
+	if err != nil {
+		writeJSON(w, rpcResponse{Version: "2.0", Error: &wasabi.RPCError{Code: wasabi.E_PARSE, Message: err.Error()}})
+		return
+	}
+	for _, req := range requests {
+		if err := p.authorize(user, walletName, wasabi.Method(req.Method)); err != nil {
+			writeErrors(w, requests, batch, err)
+			return
+		}
+	}
+
+	if walletName == "" && !batch && wasabi.Method(requests[0].Method) == wasabi.MethodListWallets {
+		p.listWallets(w, user, requests[0])
+		return
+	}
+	target := p.fallback
+	if walletName != "" {
+		if target = p.route(walletName); target == nil {
+			writeErrors(w, requests, batch, &wasabi.RPCError{Code: wasabi.E_SERVER, Message: fmt.Sprintf("unknown wallet %s", walletName)})
+			return
+		}
+		if target.Routing == wasabi.RoutingSelectWallet {
+			writeErrors(w, requests, batch, &wasabi.RPCError{Code: wasabi.E_SERVER, Message: fmt.Sprintf("wallet %s is on backend %s, which routes with selectwallet", walletName, target.Name)})
+			return
+		}
+	}
+	p.forward(w, r, target, walletName, body)
+	for _, req := range requests {
+		if m := wasabi.Method(req.Method); m == wasabi.MethodCreateWallet || m == wasabi.MethodRecoverWallet {
+			p.invalidate()
+		}
+	}
+}
+
+// authenticate returns the user of the request. Without users, every request is allowed as the anonymous user.
+func (p *Proxy) authenticate(r *http.Request) (User, bool) {
+	if len(p.users) == 0 {
+		return User{}, true
+	}
+	name, password, ok := r.BasicAuth()
+	if !ok {
+		return User{}, false
+	}
+	user, ok := p.users[name]
+	if !ok || subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) != 1 {
+		return User{}, false
+	}
+	return user, true
+}
+
+// authorize checks that the user may call the method on the wallet.
+// On the root path, a wallet method runs on the wallet selected on the daemon, which may belong to another user, so wallet methods, selectwallet and loadwallet are rejected there for restricted users, and for all users if the default daemon routes with selectwallet.
+func (p *Proxy) authorize(user User, walletName string, method wasabi.Method) *wasabi.RPCError {
+	spec, known := wasabi.LookupMethod(method)
+	mutating := !known || spec.Mutating
+	if mutating && (p.opts.ReadOnly || user.ReadOnly) {
+		return &wasabi.RPCError{Code: wasabi.E_SERVER, Message: fmt.Sprintf("method %s is not allowed by the read-only proxy", method)}
+	}
+	walletMethod := !known || spec.WalletScoped || method == wasabi.MethodSelectWallet || method == wasabi.MethodLoadWallet
+	if walletName == "" && walletMethod && (len(user.Wallets) > 0 || p.fallback.Routing == wasabi.RoutingSelectWallet) {
+		return &wasabi.RPCError{Code: wasabi.E_SERVER, Message: fmt.Sprintf("method %s is not allowed on the root path, call it on the path of the wallet", method)}
+	}
+	if len(user.Wallets) == 0 {
+		return nil
+	}
+	if walletName == "" {
+		if mutating {
+			return &wasabi.RPCError{Code: wasabi.E_SERVER, Message: fmt.Sprintf("method %s is not allowed for user %s", method, user.Name)}
+		}
+		return nil
+	}
+	if !contains(user.Wallets, walletName) {
+		return &wasabi.RPCError{Code: wasabi.E_SERVER, Message: fmt.Sprintf("wallet %s is not allowed for user %s", walletName, user.Name)}
+	}
+	return nil
+}
+
+// route returns the backend holding the wallet, or nil. Unknown wallets trigger a discovery, at most once per discovery interval.
+func (p *Proxy) route(walletName string) *backend {
+	p.mutex.Lock()
+	b := p.routes[walletName]
+	stale := p.opts.Clock.Now().Sub(p.discovered) >= p.opts.DiscoveryInterval
+	p.mutex.Unlock()
+	if b != nil || !stale {
+		return b
+	}
+	p.discover()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.routes[walletName]
+}
+
+// invalidate makes the next unknown wallet trigger a discovery.
+func (p *Proxy) invalidate() {
+	p.mutex.Lock()
+	p.discovered = time.Time{}
+	p.mutex.Unlock()
+}
+
+// discover lists the wallets of the backends without static wallets.
+func (p *Proxy) discover() {
+	routes := map[string]*backend{}
+	for _, b := range p.backends {
+		wallets := b.Wallets
+		if len(wallets) == 0 {
+			items, err := b.listWallets()
+			if err != nil {
+				continue
+			}
+			for _, item := range items {
+				wallets = append(wallets, item.Name)
+			}
+		}
+		for _, w := range wallets {
+			if routes[w] == nil {
+				routes[w] = b
+			}
+		}
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.routes = routes
+	p.discovered = p.opts.Clock.Now()
+}
+
+// listWallets answers listwallets with the wallets of all backends allowed for the user.
+func (p *Proxy) listWallets(w http.ResponseWriter, user User, req rpcRequest) {
+	p.discover()
+	p.mutex.Lock()
+	var wallets []wasabi.ListWalletsResponseItem
+	for _, b := range p.backends {
+		for name, route := range p.routes {
+			if route == b && (len(user.Wallets) == 0 || contains(user.Wallets, name)) {
+				wallets = append(wallets, wasabi.ListWalletsResponseItem{Name: name})
+			}
+		}
+	}
+	p.mutex.Unlock()
+	if wallets == nil {
+		wallets = []wasabi.ListWalletsResponseItem{}
+	}
+	writeJSON(w, rpcResponse{Version: "2.0", ID: req.ID, Result: wallets})
+}
+
+// forward sends the request to the backend and copies its response.
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request, b *backend, walletName string, body []byte) {
+	resp, err := b.post(r, walletName, body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("backend %s: %v", b.Name, err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for _, h := range []string{"Content-Type", "Retry-After"} {
+		if v := resp.Header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+// post sends a JSON-RPC body to the wallet of the backend. The headers of the original request, if any, are passed on except the credentials.
+func (b *backend) post(r *http.Request, walletName string, body []byte) (*http.Response, error) {
+	ctx := r.Context()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url+walletName, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.Header {
+		if k != "Authorization" && k != "Content-Length" {
+			req.Header[k] = v
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.RpcUser != "" {
+		req.SetBasicAuth(b.RpcUser, b.RpcPassword)
+	}
+	return b.client.Do(req)
+}
+
+// listWallets calls listwallets on the backend.
+func (b *backend) listWallets() ([]wasabi.ListWalletsResponseItem, error) {
+	r, err := http.NewRequest(http.MethodPost, b.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.post(r, "", []byte(`{"jsonrpc":"2.0","id":"1","method":"listwallets"}`))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("backend %s: http status %d", b.Name, resp.StatusCode)
+	}
+	var result struct {
+		Result []wasabi.ListWalletsResponseItem `json:"result"`
+		Error  *wasabi.RPCError                 `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// writeErrors answers every request with the error.
+func writeErrors(w http.ResponseWriter, requests []rpcRequest, batch bool, rpcErr *wasabi.RPCError) {
+	if !batch {
+		writeJSON(w, rpcResponse{Version: "2.0", ID: requests[0].ID, Error: rpcErr})
+		return
+	}
+	responses := make([]rpcResponse, len(requests))
+	for i, req := range requests {
+		responses[i] = rpcResponse{Version: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	writeJSON(w, responses)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+func TestAuthorizeRootPath(t *testing.T) {
+	restricted := User{Name: "alice", Password: "secret", Wallets: []string{"alice"}}
+	unrestricted := User{Name: "admin", Password: "secret"}
+	tests := []struct {
+		name       string
+		routing    wasabi.RoutingMode
+		user       User
+		walletName string
+		method     wasabi.Method
+		allowed    bool
+	}{
+		{name: "restricted wallet method on root", user: restricted, method: wasabi.MethodListCoins},
+		{name: "restricted selectwallet on root", user: restricted, method: wasabi.MethodSelectWallet},
+		{name: "restricted loadwallet on root", user: restricted, method: wasabi.MethodLoadWallet},
+		{name: "restricted daemon method on root", user: restricted, method: wasabi.MethodGetStatus, allowed: true},
+		{name: "restricted wallet method on own wallet", user: restricted, walletName: "alice", method: wasabi.MethodListCoins, allowed: true},
+		{name: "restricted wallet method on other wallet", user: restricted, walletName: "bob", method: wasabi.MethodListCoins},
+		{name: "unrestricted wallet method on root", user: unrestricted, method: wasabi.MethodListCoins, allowed: true},
+		{name: "unrestricted loadwallet on root", user: unrestricted, method: wasabi.MethodLoadWallet, allowed: true},
+		{name: "selectwallet daemon wallet method on root", routing: wasabi.RoutingSelectWallet, user: unrestricted, method: wasabi.MethodListCoins},
+		{name: "selectwallet daemon selectwallet on root", routing: wasabi.RoutingSelectWallet, user: unrestricted, method: wasabi.MethodSelectWallet},
+		{name: "selectwallet daemon daemon method on root", routing: wasabi.RoutingSelectWallet, user: unrestricted, method: wasabi.MethodGetStatus, allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(Options{
+				Backends: []Backend{{Name: "main", Host: "localhost", Routing: tt.routing}},
+				Users:    []User{restricted, unrestricted},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rpcErr := p.authorize(tt.user, tt.walletName, tt.method)
+			if allowed := rpcErr == nil; allowed != tt.allowed {
+				t.Fatalf("authorize() = %v, want allowed %v", rpcErr, tt.allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// The encoder (the codeword tables, addECCAndInterleave, reedSolomonDivisor, numRawDataModules and the drawing and masking of the matrix) is ported from the QR Code generator library of Project Nayuki:
+//
+// QR Code generator library
+//
+// Copyright (c) Project Nayuki. (MIT License)
+// https://www.nayuki.io/page/qr-code-generator-library
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//   - The above copyright notice and this permission notice shall be included in
+//     all copies or substantial portions of the Software.
+//   - The Software is provided "as is", without warranty of any kind, express or
+//     implied, including but not limited to the warranties of merchantability,
+//     fitness for a particular purpose and noninfringement. In no event shall the
+//     authors or copyright holders be liable for any claim, damages or other
+//     liability, whether in an action of contract, tort or otherwise, arising from,
+//     out of or in connection with the Software or the use or other dealings in the
+//     Software.
+
+package qrcode
+
+// matrix is the module grid under construction.
+type matrix struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// newCode draws the function patterns and the codewords, and applies the mask with the lowest penalty.
+func newCode(version int, level Level, codewords []byte) *Code {
+	size := version*4 + 17
+	m := &matrix{size: size, modules: make([][]bool, size), isFunction: make([][]bool, size)}
+	for i := range m.modules {
+		m.modules[i] = make([]bool, size)
+		m.isFunction[i] = make([]bool, size)
+	}
+	m.drawFunctionPatterns(version, level)
+	m.drawCodewords(codewords)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormatBits(level, mask)
+		if penalty := m.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		// Masks are their own inverse.
+		m.applyMask(mask)
+	}
+	m.applyMask(bestMask)
+	m.drawFormatBits(level, bestMask)
+	return &Code{Version: version, Size: size, Level: level, modules: m.modules}
+}
+
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.isFunction[y][x] = true
+}
+
+func (m *matrix) drawFunctionPatterns(version int, level Level) {
+	for i := 0; i < m.size; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+	m.drawFinderPattern(3, 3)
+	m.drawFinderPattern(m.size-4, 3)
+	m.drawFinderPattern(3, m.size-4)
+
+	positions := alignmentPatternPositions(version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// Skip the positions overlapping the finder patterns.
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			m.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// Reserve the format bits; they are drawn after masking.
+	m.drawFormatBits(level, 0)
+	m.drawVersion(version)
+}
+
+func (m *matrix) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			dist := max(abs(dx), abs(dy))
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < m.size && yy >= 0 && yy < m.size {
+				m.setFunction(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (m *matrix) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPatternPositions returns the ascending coordinates of the centers of the alignment patterns.
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	positions := make([]int, numAlign)
+	positions[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+func (m *matrix) drawFormatBits(level Level, mask int) {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.setFunction(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(i))
+	}
+	m.setFunction(8, m.size-8, true)
+}
+
+func (m *matrix) drawVersion(version int) {
+	if version < 7 {
+		return
+	}
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 == 1
+		a, b := m.size-11+i%3, i/3
+		m.setFunction(a, b, dark)
+		m.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places the codewords in the zigzag order of the specification.
+func (m *matrix) drawCodewords(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < m.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = m.size - 1 - vert
+				}
+				if !m.isFunction[y][x] && i < len(codewords)*8 {
+					m.modules[y][x] = (codewords[i>>3]>>(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !m.isFunction[y][x] {
+				m.modules[y][x] = !m.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty computes the penalty score of the specification used to select the mask.
+func (m *matrix) penalty() int {
+	dark := func(x, y int) bool {
+		return x >= 0 && y >= 0 && x < m.size && y < m.size && m.modules[y][x]
+	}
+	result := 0
+	// Runs of five or more modules of the same color, in rows and columns.
+	for pass := 0; pass < 2; pass++ {
+		for a := 0; a < m.size; a++ {
+			run := 0
+			for b := 0; b < m.size; b++ {
+				current, previous := dark(b, a), dark(b-1, a)
+				if pass == 1 {
+					current, previous = dark(a, b), dark(a, b-1)
+				}
+				if b > 0 && current == previous {
+					run++
+				} else {
+					run = 1
+				}
+				if run == 5 {
+					result += 3
+				} else if run > 5 {
+					result++
+				}
+			}
+		}
+	}
+	// 2x2 blocks of the same color.
+	for y := 0; y < m.size-1; y++ {
+		for x := 0; x < m.size-1; x++ {
+			c := dark(x, y)
+			if c == dark(x+1, y) && c == dark(x, y+1) && c == dark(x+1, y+1) {
+				result += 3
+			}
+		}
+	}
+	// Finder-like patterns 1:1:3:1:1 preceded or followed by four light modules.
+	pattern := []bool{true, false, true, true, true, false, true}
+	matches := func(get func(i int) bool) bool {
+		for i, p := range pattern {
+			if get(i) != p {
+				return false
+			}
+		}
+		light := func(from int) bool {
+			for i := from; i < from+4; i++ {
+				if get(i) {
+					return false
+				}
+			}
+			return true
+		}
+		return light(-4) || light(7)
+	}
+	for a := 0; a < m.size; a++ {
+		for b := -1; b < m.size; b++ {
+			if matches(func(i int) bool { return dark(b+i, a) }) {
+				result += 40
+			}
+			if matches(func(i int) bool { return dark(a, b+i) }) {
+				result += 40
+			}
+		}
+	}
+	// Balance of dark and light modules.
+	total, darkCount := m.size*m.size, 0
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.modules[y][x] {
+				darkCount++
+			}
+		}
+	}
+	k := (abs(darkCount*20-total*10)+total-1)/total - 1
+	result += max(k, 0) * 10
+	return result
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,249 @@
+// The encoder (the codeword tables, addECCAndInterleave, reedSolomonDivisor, numRawDataModules and the drawing and masking of the matrix) is ported from the QR Code generator library of Project Nayuki:
+//
+// QR Code generator library
+//
+// Copyright (c) Project Nayuki. (MIT License)
+// https://www.nayuki.io/page/qr-code-generator-library
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//   - The above copyright notice and this permission notice shall be included in
+//     all copies or substantial portions of the Software.
+//   - The Software is provided "as is", without warranty of any kind, express or
+//     implied, including but not limited to the warranties of merchantability,
+//     fitness for a particular purpose and noninfringement. In no event shall the
+//     authors or copyright holders be liable for any claim, damages or other
+//     liability, whether in an action of contract, tort or otherwise, arising from,
+//     out of or in connection with the Software or the use or other dealings in the
+//     Software.
+
+// Package qrcode encodes receiving addresses and BIP21 URIs as QR codes and renders them as PNG, SVG or text for terminals, without dependencies outside the standard library.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Level is the error correction level of a QR code.
+type Level int
+
+const (
+	// Low recovers about 7% of the code.
+	Low Level = iota
+	// Medium recovers about 15% of the code.
+	Medium
+	// Quartile recovers about 25% of the code.
+	Quartile
+	// High recovers about 30% of the code.
+	High
+)
+
+// formatBits returns the bits of the level in the format information.
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// ErrTooLong is returned when the data does not fit in the largest QR code at the requested level.
+var ErrTooLong = errors.New("data too long for a qr code")
+
+// Code is an encoded QR code.
+type Code struct {
+	// Version is the version of the code (1 to 40).
+	Version int
+	// Size is the width and height of the code in modules, without quiet zone.
+	Size    int
+	Level   Level
+	modules [][]bool
+}
+
+// Dark reports whether the module at column x and row y is dark. Coordinates outside the code are light.
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.modules[y][x]
+}
+
+// Encode encodes the text in byte mode using the smallest version fitting at the level.
+func Encode(text string, level Level) (*Code, error) {
+	if level < Low || level > High {
+		return nil, fmt.Errorf("invalid error correction level %d", level)
+	}
+	data := []byte(text)
+	version := 1
+	for ; version <= 40; version++ {
+		if 4+charCountBits(version)+8*len(data) <= numDataCodewords(version, level)*8 {
+			break
+		}
+	}
+	if version > 40 {
+		return nil, ErrTooLong
+	}
+	codewords := addECCAndInterleave(dataCodewords(data, version, level), version, level)
+	return newCode(version, level, codewords), nil
+}
+
+// EncodeAddress encodes the address as a bitcoin: URI at Medium level.
+func EncodeAddress(address wasabi.Address) (*Code, error) {
+	return Encode(wasabi.BuildBIP21(address, 0, ""), Medium)
+}
+
+// EncodeNewAddress encodes the address of a GetNewAddress response. The label is kept private and not encoded.
+func EncodeNewAddress(resp wasabi.GetNewAddressResponse) (*Code, error) {
+	return EncodeAddress(resp.Address)
+}
+
+// EncodeURI encodes a BIP21 URI at Medium level.
+func EncodeURI(uri wasabi.BIP21URI) (*Code, error) {
+	return Encode(uri.String(), Medium)
+}
+
+// charCountBits returns the length of the character count of byte mode.
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// dataCodewords returns the data codewords: byte mode segment, terminator and padding.
+func dataCodewords(data []byte, version int, level Level) []byte {
+	capacity := numDataCodewords(version, level) * 8
+	var bits bitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+	return codewords
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 == 1)
+	}
+}
+
+// eccCodewordsPerBlock and numErrorCorrectionBlocks are indexed by level and version (index 0 is unused).
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// numRawDataModules returns the number of modules available for data and error correction codewords.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numErrorCorrectionBlocks[level][version]
+}
+
+// addECCAndInterleave splits the data into blocks, appends the Reed-Solomon codewords of each block and interleaves the blocks.
+func addECCAndInterleave(data []byte, version int, level Level) []byte {
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	blockECCLen := eccCodewordsPerBlock[level][version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockECCLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		datLen := shortBlockLen - blockECCLen
+		if i >= numShortBlocks {
+			datLen++
+		}
+		block := append([]byte(nil), data[k:k+datLen]...)
+		k += datLen
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			// Skip the padding of the short blocks.
+			if i != shortBlockLen-blockECCLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
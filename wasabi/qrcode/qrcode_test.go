@@ -0,0 +1,70 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+const (
+	testAddressURI = "bitcoin:tb1qttn7vxzfh62ssm7asg6ycwwxxdxsjlj8qvd9ez"
+	testPaymentURI = "bitcoin:tb1qttn7vxzfh62ssm7asg6ycwwxxdxsjlj8qvd9ez?amount=0.0105&label=rent%20for%20june"
+)
+
+var testLongText = strings.Repeat("the quick brown fox jumps over the lazy dog. ", 8)
+
+// TestEncodeGolden compares the matrices with testdata/v<version>-<level>.txt ('#' for dark modules). The golden matrices were produced by two independent encoders, github.com/boombuler/barcode and github.com/skip2/go-qrcode, which agree module for module on these inputs, mask included.
+func TestEncodeGolden(t *testing.T) {
+	tests := []struct {
+		text    string
+		level   Level
+		version int
+	}{
+		{testAddressURI, Low, 3},
+		{testAddressURI, Medium, 4},
+		{testPaymentURI, Quartile, 8},
+		{testPaymentURI, High, 9},
+		{testLongText, Low, 12},
+		{testLongText, Medium, 14},
+		{testLongText, High, 20},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("v%02d-%c", tt.version, "LMQH"[tt.level])
+		t.Run(name, func(t *testing.T) {
+			golden, err := os.ReadFile("testdata/" + name + ".txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			code, err := Encode(tt.text, tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code.Version != tt.version || code.Size != 17+4*tt.version {
+				t.Fatalf("Version = %d, Size = %d, want %d and %d", code.Version, code.Size, tt.version, 17+4*tt.version)
+			}
+			rows := strings.Split(strings.TrimSuffix(string(golden), "\n"), "\n")
+			if len(rows) != code.Size {
+				t.Fatalf("golden has %d rows, want %d", len(rows), code.Size)
+			}
+			for y, row := range rows {
+				for x := 0; x < code.Size; x++ {
+					if dark := row[x] == '#'; code.Dark(x, y) != dark {
+						t.Fatalf("module (%d, %d) dark = %v, want %v", x, y, code.Dark(x, y), dark)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	// Version 40 holds 2953 bytes at Low and 1273 at High.
+	if _, err := Encode(strings.Repeat("a", 2953), Low); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Encode(strings.Repeat("a", 1274), High); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("Encode() = %v, want ErrTooLong", err)
+	}
+}
@@ -0,0 +1,86 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// QuietZone is the width in modules of the light border around the rendered codes.
+const QuietZone = 4
+
+// Image returns the code as a grayscale image with the quiet zone, each module being scale pixels wide.
+func (c *Code) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	width := (c.Size + 2*QuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, width, width))
+	for y := 0; y < width; y++ {
+		for x := 0; x < width; x++ {
+			value := color.Gray{Y: 0xff}
+			if c.Dark(x/scale-QuietZone, y/scale-QuietZone) {
+				value = color.Gray{Y: 0}
+			}
+			img.SetGray(x, y, value)
+		}
+	}
+	return img
+}
+
+// PNG returns the code as a PNG image, each module being scale pixels wide.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.Image(scale)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG returns the code as an SVG image, each module being scale units wide.
+func (c *Code) SVG(scale int) string {
+	if scale < 1 {
+		scale = 1
+	}
+	width := c.Size + 2*QuietZone
+	var path strings.Builder
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+QuietZone, y+QuietZone)
+			}
+		}
+	}
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" version="1.1" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges">`+
+		`<rect width="100%%" height="100%%" fill="#ffffff"/><path d="%s" fill="#000000"/></svg>`,
+		width, width, width*scale, width*scale, path.String())
+}
+
+// Terminal returns the code as text using half block characters, two rows of modules per line. Dark modules are drawn as blocks; set inverted for terminals with a dark background, so the blocks draw the light modules instead.
+func (c *Code) Terminal(inverted bool) string {
+	const border = 2
+	var sb strings.Builder
+	for y := -border; y < c.Size+border; y += 2 {
+		for x := -border; x < c.Size+border; x++ {
+			top, bottom := c.Dark(x, y), c.Dark(x, y+1)
+			if inverted {
+				top, bottom = !top, !bottom
+			}
+			switch {
+			case top && bottom:
+				sb.WriteRune('█')
+			case top:
+				sb.WriteRune('▀')
+			case bottom:
+				sb.WriteRune('▄')
+			default:
+				sb.WriteRune(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,486 @@
+// Package queue is a durable queue of payment intents, the backbone of withdrawal-style integrations: an intent is accepted once, executed with Send or PayInCoinJoin, and followed until its transaction is confirmed.
+//
+//	q := queue.New(client, store, queue.Options{Password: passwords, Confirmations: 3, Bus: bus})
+//	item, err := q.Enqueue(queue.Intent{Key: withdrawalID, WalletName: "hot", Address: addr, Amount: 100_000})
+//	go q.Run(ctx)
+//
+// Intents are deduplicated by their key: enqueueing a key again returns the existing item, so a request retried after a timeout does not pay twice. Items are executed in the order they were enqueued.
+//
+// The queue survives restarts as long as its store does: FileStore keeps it in a JSON file, the boltqueue module in a bbolt database, and Store may be implemented on other databases. Items found executing when the queue starts were interrupted and are marked as failed, since they may have been paid. So are items whose connection to the daemon failed after the request was sent: they are never sent twice.
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Method is the way an intent is executed.
+type Method string
+
+const (
+	// MethodSend sends the payment in its own transaction.
+	MethodSend Method = "send"
+	// MethodPayInCoinJoin registers the payment to be paid in a coinjoin.
+	MethodPayInCoinJoin Method = "payincoinjoin"
+)
+
+// State is the state of a queued item.
+type State string
+
+const (
+	// StateQueued is the state of an item waiting to be executed.
+	StateQueued State = "queued"
+	// StateExecuting is the state of an item being executed.
+	StateExecuting State = "executing"
+	// StateSubmitted is the state of a payment in coinjoin registered in the wallet and waiting for a coinjoin.
+	StateSubmitted State = "submitted"
+	// StateBroadcast is the state of an item whose transaction is broadcast and waits for its confirmations.
+	StateBroadcast State = "broadcast"
+	// StateConfirmed is the state of an item whose transaction has the configured number of confirmations.
+	StateConfirmed State = "confirmed"
+	// StateFailed is the state of an item rejected by the wallet, or interrupted while executing or cut off from the daemon after its request was sent. The latter may have been paid, see Item.Error.
+	StateFailed State = "failed"
+	// StateCancelled is the state of a cancelled item.
+	StateCancelled State = "cancelled"
+)
+
+// Final reports whether the state does not change anymore.
+func (s State) Final() bool {
+	return s == StateConfirmed || s == StateFailed || s == StateCancelled
+}
+
+// Intent is a payment to be made.
+type Intent struct {
+	// Key is the idempotency key of the intent, e.g. the id of the withdrawal. Required.
+	Key        string         `json:"key"`
+	WalletName string         `json:"walletName"`
+	Address    wasabi.Address `json:"address"`
+	Amount     wasabi.Amount  `json:"amount"`
+	Label      string         `json:"label,omitempty"`
+	// Method is the way the intent is executed. Default is MethodSend.
+	Method Method `json:"method"`
+	// FeeTarget and FeeRate set the fee of sends (see wasabi.SendRequest). If both are zero, DefaultFeeTarget is used.
+	FeeTarget wasabi.FeeTarget `json:"feeTarget,omitempty"`
+	FeeRate   float64          `json:"feeRate,omitempty"`
+}
+
+// Item is a queued intent and its progress.
+type Item struct {
+	ID string `json:"id"`
+	Intent
+	State     State     `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// SubmittedAt is the time the intent was sent or registered in the wallet.
+	SubmittedAt time.Time `json:"submittedAt,omitempty"`
+	// PaymentID is the id of a payment in coinjoin (see wasabi.Client.ListPaymentsInCoinJoin).
+	PaymentID string `json:"paymentId,omitempty"`
+	// TxID is the id of the transaction paying the intent.
+	TxID wasabi.TxID `json:"txid,omitempty"`
+	// Confirmations is the number of confirmations of the transaction at the last check.
+	Confirmations int       `json:"confirmations,omitempty"`
+	ConfirmedAt   time.Time `json:"confirmedAt,omitempty"`
+	// Error is the reason of a failed item, or the last error of a cancellation.
+	Error string `json:"error,omitempty"`
+}
+
+// DefaultFeeTarget is the confirmation target of sends without fee target and fee rate.
+const DefaultFeeTarget = wasabi.FeeTargetHour
+
+// ErrKeyConflict is returned when an intent is enqueued with the key of a different intent.
+var ErrKeyConflict = errors.New("key is already used by a different intent")
+
+// EventItemChanged is the type of ItemChangedEvent.
+const EventItemChanged wasabi.EventType = "QueueItemChanged"
+
+// ItemChangedEvent is published when the state of an item changes.
+type ItemChangedEvent struct {
+	Item Item
+}
+
+// Type implements wasabi.Event.
+func (ItemChangedEvent) Type() wasabi.EventType { return EventItemChanged }
+
+// Options holds the options of a Queue.
+type Options struct {
+	// Password returns the password of a wallet when one of its items is executed. Required.
+	Password func(walletName string) (string, error)
+	// Confirmations is the number of confirmations after which an item is confirmed. Default is 1.
+	Confirmations int
+	// Bus receives the state changes of the items. Optional.
+	Bus *wasabi.EventBus
+	// Interval is the interval between the processings of the queue. Default is wasabi.DefaultPollInterval.
+	Interval time.Duration
+	// OnError is called with the errors of the processings which are retried at the next processing: unreachable daemon, failing store.
+	OnError func(err error)
+	// Clock provides the time of the state changes. Default is wasabi.SystemClock.
+	Clock wasabi.Clock
+}
+
+// Queue keeps the queued items, executes them and follows their transactions.
+type Queue struct {
+	client wasabi.Client
+	store  Store
+	opts   Options
+	mutex  sync.Mutex
+}
+
+// New creates a queue executing the intents with the client and keeping the items in the store.
+func New(c wasabi.Client, store Store, opts Options) *Queue {
+	if opts.Confirmations <= 0 {
+		opts.Confirmations = 1
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = wasabi.DefaultPollInterval
+	}
+	if opts.Clock == nil {
+		opts.Clock = wasabi.SystemClock
+	}
+	return &Queue{client: c, store: store, opts: opts}
+}
+
+// Enqueue validates and stores the intent as a queued item. If an item with the key of the intent exists, it is returned instead, or ErrKeyConflict if its intent differs.
+func (q *Queue) Enqueue(intent Intent) (Item, error) {
+	if intent.Key == "" {
+		return Item{}, fmt.Errorf("key must not be empty")
+	}
+	if intent.WalletName == "" {
+		return Item{}, fmt.Errorf("wallet name must not be empty")
+	}
+	address, err := wasabi.ParseAddress(string(intent.Address))
+	if err != nil {
+		return Item{}, err
+	}
+	intent.Address = address
+	if intent.Amount <= 0 {
+		return Item{}, fmt.Errorf("amount must be positive")
+	}
+	if intent.Method == "" {
+		intent.Method = MethodSend
+	}
+	if intent.Method != MethodSend && intent.Method != MethodPayInCoinJoin {
+		return Item{}, fmt.Errorf("unknown method %q", intent.Method)
+	}
+	if intent.FeeTarget != 0 && intent.FeeRate != 0 {
+		return Item{}, fmt.Errorf("fee target and fee rate must not be set both")
+	}
+	if intent.FeeTarget != 0 {
+		if err := intent.FeeTarget.Validate(); err != nil {
+			return Item{}, err
+		}
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	existing, err := q.store.ItemByKey(intent.Key)
+	if err == nil {
+		if existing.Intent != intent {
+			return existing, fmt.Errorf("%w: %s", ErrKeyConflict, intent.Key)
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return Item{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Item{}, err
+	}
+	now := q.opts.Clock.Now()
+	item := Item{ID: id, Intent: intent, State: StateQueued, CreatedAt: now, UpdatedAt: now}
+	if err := q.store.PutItem(item); err != nil {
+		return Item{}, err
+	}
+	return item, nil
+}
+
+// Cancel cancels a queued item, or a submitted payment in coinjoin which is not paid yet.
+func (q *Queue) Cancel(id string) (Item, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	item, err := q.store.Item(id)
+	if err != nil {
+		return Item{}, err
+	}
+	switch item.State {
+	case StateQueued:
+	case StateSubmitted:
+		if err := q.client.CancelPaymentInCoinJoin(item.WalletName, item.PaymentID); err != nil {
+			return item, err
+		}
+	default:
+		return item, fmt.Errorf("item %s is %s, not queued or submitted", id, item.State)
+	}
+	return q.change(context.Background(), item, StateCancelled)
+}
+
+// Get returns the item, or ErrNotFound.
+func (q *Queue) Get(id string) (Item, error) {
+	return q.store.Item(id)
+}
+
+// GetByKey returns the item of the intent with the key, or ErrNotFound.
+func (q *Queue) GetByKey(key string) (Item, error) {
+	return q.store.ItemByKey(key)
+}
+
+// List returns all items in the order they were enqueued.
+func (q *Queue) List() ([]Item, error) {
+	return q.store.Items()
+}
+
+// Run marks the interrupted items as failed, then processes the queue until the context is done.
+func (q *Queue) Run(ctx context.Context) error {
+	if q.opts.Password == nil {
+		return fmt.Errorf("password must not be nil")
+	}
+	if err := q.recover(ctx); err != nil {
+		return err
+	}
+	ticker := q.opts.Clock.NewTicker(q.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if err := q.Process(ctx); err != nil {
+			q.onError(ctx, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// recover marks the items interrupted while executing as failed.
+func (q *Queue) recover(ctx context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	items, err := q.store.Items()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.State == StateExecuting {
+			item.Error = "interrupted while executing: check the wallet history before enqueueing it again"
+			if _, err := q.change(ctx, item, StateFailed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Process executes the queued items, follows the submitted payments in coinjoin and counts the confirmations of the broadcast transactions. Run calls it at each interval.
+func (q *Queue) Process(ctx context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	items, err := q.store.Items()
+	if err != nil {
+		return err
+	}
+	p := processing{queue: q, ctx: ctx, client: q.client.WithContext(ctx)}
+	var errs []error
+	for _, item := range items {
+		var err error
+		switch item.State {
+		case StateQueued:
+			err = p.execute(item)
+		case StateSubmitted:
+			err = p.track(item)
+		case StateBroadcast:
+			err = p.confirm(item)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("item %s: %w", item.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// processing is a processing of the queue, caching the wallet data it fetches.
+type processing struct {
+	queue    *Queue
+	ctx      context.Context
+	client   wasabi.Client
+	payments map[string][]wasabi.ListPaymentsInCoinJoinResponseItem
+	history  map[string][]wasabi.Transaction
+	best     uint64
+}
+
+// execute sends or registers the intent of the item. Errors which happen before the request is sent (unreachable daemon, missing password, failing store) are returned and the item stays queued; rejections fail the item. A connection failing or the context ending after the request was sent fails the item too, since the daemon may have paid it.
+func (p *processing) execute(item Item) error {
+	q := p.queue
+	if q.opts.Password == nil {
+		return fmt.Errorf("password must not be nil")
+	}
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	password, err := q.opts.Password(item.WalletName)
+	if err != nil {
+		return fmt.Errorf("password of wallet %s: %w", item.WalletName, err)
+	}
+	if item, err = q.change(p.ctx, item, StateExecuting); err != nil {
+		return err
+	}
+	state := StateBroadcast
+	switch item.Method {
+	case MethodPayInCoinJoin:
+		item.PaymentID, err = p.client.PayInCoinJoin(item.WalletName, item.Address, item.Amount, password)
+		state = StateSubmitted
+	default:
+		req := wasabi.SendRequest{
+			Payments:  []wasabi.Payment{{SendTo: item.Address, Amount: item.Amount, Label: item.Label}},
+			FeeTarget: item.FeeTarget,
+			FeeRate:   item.FeeRate,
+			Password:  password,
+		}
+		if req.FeeTarget == 0 && req.FeeRate == 0 {
+			req.FeeTarget = DefaultFeeTarget
+		}
+		var resp wasabi.SendResponse
+		resp, err = p.client.Send(item.WalletName, req)
+		item.TxID = resp.TransactionID
+	}
+	if err != nil {
+		if notSent(err) {
+			if _, storeErr := q.change(p.ctx, item, StateQueued); storeErr != nil {
+				return errors.Join(err, storeErr)
+			}
+			return err
+		}
+		if wasabi.Classify(err) == wasabi.ErrorCategoryConnection || p.ctx.Err() != nil {
+			item.Error = fmt.Sprintf("interrupted after the request was sent (%v): check the wallet history before enqueueing it again", err)
+			if _, storeErr := q.change(p.ctx, item, StateFailed); storeErr != nil {
+				return errors.Join(err, storeErr)
+			}
+			return err
+		}
+		item.Error = err.Error()
+		_, err = q.change(p.ctx, item, StateFailed)
+		return err
+	}
+	item.SubmittedAt = q.opts.Clock.Now()
+	if _, err := q.change(p.ctx, item, state); err != nil {
+		return fmt.Errorf("submitted but not recorded: %w", err)
+	}
+	return nil
+}
+
+// notSent reports whether the error of a call shows that its request never reached the daemon: the connection to the daemon could not be opened.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return wasabi.Classify(err) == wasabi.ErrorCategoryConnection && errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// track moves a submitted payment in coinjoin to broadcast once its coinjoin transaction is known.
+func (p *processing) track(item Item) error {
+	if p.payments == nil {
+		p.payments = map[string][]wasabi.ListPaymentsInCoinJoinResponseItem{}
+	}
+	payments, ok := p.payments[item.WalletName]
+	if !ok {
+		var err error
+		if payments, err = p.client.ListPaymentsInCoinJoin(item.WalletName); err != nil {
+			return err
+		}
+		p.payments[item.WalletName] = payments
+	}
+	for _, payment := range payments {
+		if payment.ID != item.PaymentID || len(payment.State) == 0 {
+			continue
+		}
+		last := payment.State[len(payment.State)-1]
+		if last.Status != wasabi.PaymentStatusFinished || last.TxID == "" {
+			return nil
+		}
+		item.TxID = last.TxID
+		_, err := p.queue.change(p.ctx, item, StateBroadcast)
+		return err
+	}
+	return nil
+}
+
+// confirm counts the confirmations of the transaction of a broadcast item.
+func (p *processing) confirm(item Item) error {
+	if p.history == nil {
+		status, err := p.client.GetStatus()
+		if err != nil {
+			return err
+		}
+		p.best = status.BestBlockchainHeight
+		p.history = map[string][]wasabi.Transaction{}
+	}
+	history, ok := p.history[item.WalletName]
+	if !ok {
+		var err error
+		if history, err = p.client.GetHistory(item.WalletName); err != nil {
+			return err
+		}
+		p.history[item.WalletName] = history
+	}
+	confirmations := 0
+	for _, tx := range history {
+		if tx.TxID == item.TxID && tx.Height > 0 && uint64(tx.Height) <= p.best {
+			confirmations = int(p.best-uint64(tx.Height)) + 1
+			break
+		}
+	}
+	if confirmations == item.Confirmations {
+		return nil
+	}
+	item.Confirmations = confirmations
+	if confirmations < p.queue.opts.Confirmations {
+		item.UpdatedAt = p.queue.opts.Clock.Now()
+		return p.queue.store.PutItem(item)
+	}
+	item.ConfirmedAt = p.queue.opts.Clock.Now()
+	_, err := p.queue.change(p.ctx, item, StateConfirmed)
+	return err
+}
+
+// change stores the item in the state and publishes the change.
+func (q *Queue) change(ctx context.Context, item Item, state State) (Item, error) {
+	item.State = state
+	item.UpdatedAt = q.opts.Clock.Now()
+	if err := q.store.PutItem(item); err != nil {
+		return item, err
+	}
+	if q.opts.Bus != nil {
+		q.opts.Bus.Publish(ctx, ItemChangedEvent{Item: item})
+	}
+	return item, nil
+}
+
+func (q *Queue) onError(ctx context.Context, err error) {
+	if q.opts.OnError != nil && ctx.Err() == nil {
+		q.opts.OnError(err)
+	}
+}
+
+// sortItems orders the items by their creation.
+func sortItems(items []Item) {
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.Before(items[j].CreatedAt)
+		}
+		return items[i].ID < items[j].ID
+	})
+}
+
+// newID returns a random item id.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate item id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,48 @@
+package queue
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+func TestExecuteConnectionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		state State
+	}{
+		{name: "dial failure", err: &wasabi.ConnectionError{Err: &net.OpError{Op: "dial", Net: "tcp", Err: io.EOF}}, state: StateQueued},
+		{name: "connection lost after sending", err: &wasabi.ConnectionError{Err: io.ErrUnexpectedEOF}, state: StateFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := wasabitest.NewMockClient()
+			client.On(wasabi.MethodSend).ReturnError(tt.err)
+			q := New(client, NewMemoryStore(), Options{Password: func(string) (string, error) { return "password", nil }})
+			item, err := q.Enqueue(Intent{Key: "withdrawal-1", WalletName: "hot", Address: "tb1qttn7vxzfh62ssm7asg6ycwwxxdxsjlj8qvd9ez", Amount: 100_000})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := q.Process(context.Background()); err == nil {
+				t.Fatal("Process() = nil error, want the connection error")
+			}
+			// A failed item is not sent again.
+			_ = q.Process(context.Background())
+			if item, err = q.Get(item.ID); err != nil {
+				t.Fatal(err)
+			}
+			if item.State != tt.state {
+				t.Fatalf("state = %s, want %s", item.State, tt.state)
+			}
+			sends := len(client.CallsOf(wasabi.MethodSend))
+			if tt.state == StateFailed && sends != 1 {
+				t.Fatalf("send called %d times, want 1", sends)
+			}
+		})
+	}
+}
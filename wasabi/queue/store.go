@@ -0,0 +1,159 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNotFound is returned by stores for unknown items.
+var ErrNotFound = errors.New("item not found")
+
+// Store persists the items of a Queue. Implementations on a database must make PutItem durable before returning, since the queue relies on it to not pay twice.
+type Store interface {
+	// Item returns the item with the id, or ErrNotFound.
+	Item(id string) (Item, error)
+	// ItemByKey returns the item with the intent key, or ErrNotFound.
+	ItemByKey(key string) (Item, error)
+	// PutItem creates or replaces the item with the same id.
+	PutItem(item Item) error
+	// Items returns all items ordered by their creation.
+	Items() ([]Item, error)
+}
+
+// MemoryStore is a Store keeping the items in memory.
+type MemoryStore struct {
+	mutex sync.RWMutex
+	items map[string]Item
+	// keys maps the intent keys to the item ids.
+	keys map[string]string
+}
+
+// NewMemoryStore creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]Item{}, keys: map[string]string{}}
+}
+
+// Item implements Store.
+func (s *MemoryStore) Item(id string) (Item, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	item, ok := s.items[id]
+	if !ok {
+		return Item{}, ErrNotFound
+	}
+	return item, nil
+}
+
+// ItemByKey implements Store.
+func (s *MemoryStore) ItemByKey(key string) (Item, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	id, ok := s.keys[key]
+	if !ok {
+		return Item{}, ErrNotFound
+	}
+	return s.items[id], nil
+}
+
+// PutItem implements Store.
+func (s *MemoryStore) PutItem(item Item) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.items[item.ID] = item
+	s.keys[item.Key] = item.ID
+	return nil
+}
+
+// Items implements Store.
+func (s *MemoryStore) Items() ([]Item, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	items := make([]Item, 0, len(s.items))
+	for _, item := range s.items {
+		items = append(items, item)
+	}
+	sortItems(items)
+	return items, nil
+}
+
+// FileStore is a Store keeping the items as JSON in a file. The file is rewritten atomically after each change, which suits queues of up to some thousands of items.
+type FileStore struct {
+	path   string
+	memory *MemoryStore
+	mutex  sync.Mutex
+}
+
+// OpenFileStore opens the items in the file, which is created on the first change if it does not exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, memory: NewMemoryStore()}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var items map[string]Item
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		s.memory.PutItem(item)
+	}
+	return s, nil
+}
+
+// Item implements Store.
+func (s *FileStore) Item(id string) (Item, error) {
+	return s.memory.Item(id)
+}
+
+// ItemByKey implements Store.
+func (s *FileStore) ItemByKey(key string) (Item, error) {
+	return s.memory.ItemByKey(key)
+}
+
+// PutItem implements Store.
+func (s *FileStore) PutItem(item Item) error {
+	return s.change(func() error { return s.memory.PutItem(item) })
+}
+
+// Items implements Store.
+func (s *FileStore) Items() ([]Item, error) {
+	return s.memory.Items()
+}
+
+// change applies the change in memory and writes the file, synced to disk before it replaces the previous one.
+func (s *FileStore) change(apply func() error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := apply(); err != nil {
+		return err
+	}
+	s.memory.mutex.RLock()
+	content, err := json.MarshalIndent(s.memory.items, "", "  ")
+	s.memory.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,133 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrReadOnly is matched (with errors.Is) by the errors of mutating calls on a read-only client.
+var ErrReadOnly = errors.New("client is read-only")
+
+// ReadOnlyError is returned by a read-only client when a call would change the state of a wallet or the daemon.
+type ReadOnlyError struct {
+	Method Method
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Method, ErrReadOnly)
+}
+
+func (e *ReadOnlyError) Unwrap() error {
+	return ErrReadOnly
+}
+
+// readOnlyClient rejects the mutating methods (see Method.IsMutating) and passes the other calls to the wrapped client.
+type readOnlyClient struct {
+	Client
+}
+
+// NewReadOnlyClient returns a client which rejects every call that changes the state of a wallet or the daemon (send, broadcast, createwallet, coinjoin control, stop, etc.) with a ReadOnlyError, for dashboards and analysts. Raw calls of methods which are not registered are rejected too, as they may change the state.
+func NewReadOnlyClient(c Client) Client {
+	if ro, ok := c.(*readOnlyClient); ok {
+		return ro
+	}
+	return &readOnlyClient{Client: c}
+}
+
+// check returns a ReadOnlyError if the method is mutating or unknown.
+func (c *readOnlyClient) check(method Method) error {
+	if spec, ok := LookupMethod(method); ok && !spec.Mutating {
+		return nil
+	}
+	return &ReadOnlyError{Method: method}
+}
+
+func (c *readOnlyClient) CreateWallet(walletName string, password string) (string, error) {
+	return "", c.check(MethodCreateWallet)
+}
+
+func (c *readOnlyClient) GetNewAddress(walletName string, label string) (GetNewAddressResponse, error) {
+	return GetNewAddressResponse{}, c.check(MethodGetNewAddress)
+}
+
+func (c *readOnlyClient) Send(walletName string, req SendRequest) (SendResponse, error) {
+	return SendResponse{}, c.check(MethodSend)
+}
+
+func (c *readOnlyClient) Broadcast(walletName string, hex string) (TxID, error) {
+	return "", c.check(MethodBroadcast)
+}
+
+func (c *readOnlyClient) StartCoinJoin(walletName string, password string, opts StartCoinJoinOptions) error {
+	return c.check(MethodStartCoinJoin)
+}
+
+func (c *readOnlyClient) StartCoinJoinSweep(walletName string, password string, outputWalletName string) error {
+	return c.check(MethodStartCoinJoinSweep)
+}
+
+func (c *readOnlyClient) StopCoinJoin(walletName string) error {
+	return c.check(MethodStopCoinJoin)
+}
+
+func (c *readOnlyClient) Stop() error {
+	return c.check(MethodStop)
+}
+
+func (c *readOnlyClient) ExcludeFromCoinJoin(walletName string, txID TxID, index int, exclude bool) error {
+	return c.check(MethodExcludeFromCoinJoin)
+}
+
+func (c *readOnlyClient) ExcludeCoinsFromCoinJoin(walletName string, coins []Coin, exclude bool) error {
+	return c.check(MethodExcludeFromCoinJoin)
+}
+
+func (c *readOnlyClient) RecoverWallet(walletName string, mnemonic string, password string) error {
+	return c.check(MethodRecoverWallet)
+}
+
+func (c *readOnlyClient) PayInCoinJoin(walletName string, address Address, amount Amount, password string) (string, error) {
+	return "", c.check(MethodPayInCoinJoin)
+}
+
+func (c *readOnlyClient) CancelPaymentInCoinJoin(walletName string, paymentID string) error {
+	return c.check(MethodCancelPaymentInCoinJoin)
+}
+
+func (c *readOnlyClient) RawCall(walletName string, method string, params interface{}, out interface{}) error {
+	if method == "" {
+		return fmt.Errorf("method must not be empty")
+	}
+	if err := c.check(Method(method)); err != nil {
+		return err
+	}
+	return c.Client.RawCall(walletName, method, params, out)
+}
+
+func (c *readOnlyClient) CallRaw(walletName string, method string, params interface{}, w io.Writer) error {
+	if method == "" {
+		return fmt.Errorf("method must not be empty")
+	}
+	if err := c.check(Method(method)); err != nil {
+		return err
+	}
+	return c.Client.CallRaw(walletName, method, params, w)
+}
+
+func (c *readOnlyClient) Wallet(walletName string) *Wallet {
+	return NewWallet(c, walletName)
+}
+
+func (c *readOnlyClient) WithContext(ctx context.Context) Client {
+	return &readOnlyClient{Client: c.Client.WithContext(ctx)}
+}
+
+func (c *readOnlyClient) With(opts ...ClientOption) (Client, error) {
+	derived, err := c.Client.With(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlyClient{Client: derived}, nil
+}
@@ -0,0 +1,59 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RecoveryProgress describes the progress of a wallet recovery.
+type RecoveryProgress struct {
+	// State is the state of the recovered wallet. It is empty while the wallet is not loaded yet.
+	State WalletState
+	// FiltersCount is the number of block filters known by the daemon.
+	FiltersCount int
+	// FiltersLeft is the number of block filters which still have to be processed.
+	FiltersLeft int
+}
+
+// RecoverWalletOptions holds the options of RecoverWalletAndWait.
+type RecoverWalletOptions struct {
+	// Interval is the interval between the progress checks. Default is DefaultPollInterval.
+	Interval time.Duration
+	// OnProgress is called after each progress check. Optional.
+	OnProgress func(progress RecoveryProgress)
+	// Clock provides the timers of the checks. Default is SystemClock.
+	Clock Clock
+}
+
+// RecoverWalletAndWait recovers a wallet, loads it and waits until it is started and all block filters are processed, so the balance and history of the wallet are complete. It returns when the recovery is completed, a call fails or the context is done.
+func RecoverWalletAndWait(ctx context.Context, c Client, walletName string, mnemonic string, password string, opts RecoverWalletOptions) error {
+	c = c.WithContext(ctx)
+	if err := c.RecoverWallet(walletName, mnemonic, password); err != nil {
+		return err
+	}
+	if err := c.LoadWallet(walletName); err != nil {
+		return err
+	}
+	return poll(ctx, opts.Clock, opts.Interval, func() (bool, error) {
+		var progress RecoveryProgress
+		status, err := c.GetStatus()
+		if err != nil {
+			return false, err
+		}
+		progress.FiltersCount = status.FiltersCount
+		progress.FiltersLeft = status.FiltersLeft
+		info, err := c.GetWalletInfo(walletName)
+		switch {
+		case errors.Is(err, ErrorWalletIsNotFullyLoadedYet):
+		case err != nil:
+			return false, err
+		default:
+			progress.State = info.State
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		return progress.State.IsRunning() && progress.FiltersLeft == 0, nil
+	})
+}
@@ -0,0 +1,53 @@
+package wasabi
+
+// redacted is the placeholder for secret values in logs and dumps.
+const redacted = "[REDACTED]"
+
+// secretParamNames holds the names of secret parameters of methods with named parameters.
+var secretParamNames = map[string]bool{
+	"password": true,
+	"mnemonic": true,
+}
+
+// redactParams returns a copy of the request parameters with passwords and mnemonics replaced by a placeholder. The original parameters are not modified.
+func redactParams(method Method, params interface{}) interface{} {
+	switch p := params.(type) {
+	case []interface{}:
+		spec, _ := LookupMethod(method)
+		indexes := spec.SecretParams
+		if len(indexes) == 0 {
+			return p
+		}
+		out := make([]interface{}, len(p))
+		copy(out, p)
+		for _, i := range indexes {
+			if i < len(out) {
+				out[i] = redacted
+			}
+		}
+		return out
+	case batchParams:
+		out := make(batchParams, len(p))
+		for i, params := range p {
+			out[i], _ = redactParams(method, params).([]interface{})
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(p))
+		for k, v := range p {
+			if secretParamNames[k] {
+				v = redacted
+			}
+			out[k] = v
+		}
+		return out
+	default:
+		return params
+	}
+}
+
+// isSecretResult reports whether the result of the method is a secret.
+func isSecretResult(method Method) bool {
+	spec, _ := LookupMethod(method)
+	return spec.SecretResult
+}
@@ -0,0 +1,133 @@
+package wasabi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// MethodSpec describes an rpc method. The built-in methods are registered by the package, custom methods can be added with RegisterMethod.
+type MethodSpec struct {
+	// Name is the name of the rpc method.
+	Name Method
+	// WalletScoped reports whether the method must be called on a wallet.
+	WalletScoped bool
+	// Mutating reports whether the method changes the state of a wallet or the daemon. Mutating calls are recorded by the audit sink.
+	Mutating bool
+	// SecretParams are the positions of secret positional parameters (passwords, mnemonics), which are redacted in logs, dumps and audit entries. Named parameters called password or mnemonic are always redacted.
+	SecretParams []int
+	// Optional reports whether the method is missing in older daemons. Optional methods are probed before their first use (see Client.Capabilities) and calls fail with ErrUnsupportedMethod if the daemon does not support them. Optional methods which are mutating are never probed, see Since.
+	Optional bool
+	// Since is the daemon version which added an optional method, e.g. "2.1.0". The probed methods tell the daemon version, and a mutating optional method is supported if the daemon is at least Since. Mutating optional methods without Since are never gated.
+	Since string
+	// SecretResult reports whether the result is a secret (e.g. a mnemonic).
+	SecretResult bool
+	// Encode converts the arguments passed to Invoke into JSON-RPC params. If nil, the arguments are sent as positional params (or no params if there are no arguments).
+	Encode func(args []interface{}) (interface{}, error)
+	// Result is the type of the result. If nil, the result is discarded.
+	Result reflect.Type
+}
+
+var registry = struct {
+	mutex   sync.RWMutex
+	methods map[Method]MethodSpec
+}{methods: map[Method]MethodSpec{}}
+
+func init() {
+	typeOf := func(v interface{}) reflect.Type { return reflect.TypeOf(v) }
+	for _, spec := range []MethodSpec{
+		{Name: MethodGetStatus, Result: typeOf(GetStatusResponse{})},
+		{Name: MethodCreateWallet, Mutating: true, SecretParams: []int{1}, SecretResult: true, Result: typeOf("")},
+		{Name: MethodLoadWallet},
+		{Name: MethodListCoins, WalletScoped: true, Result: typeOf([]ListCoinsResponse{})},
+		{Name: MethodListUnspentCoins, WalletScoped: true, Result: typeOf([]ListCoinsResponse{})},
+		{Name: MethodGetWalletInfo, WalletScoped: true, Result: typeOf(GetWalletInfoResponse{})},
+		{Name: MethodGetNewAddress, WalletScoped: true, Mutating: true, Result: typeOf(GetNewAddressResponse{})},
+		{Name: MethodSend, WalletScoped: true, Mutating: true, Result: typeOf(SendResponse{})},
+		{Name: MethodBuild, WalletScoped: true, Result: typeOf("")},
+		{Name: MethodBroadcast, Mutating: true, Result: typeOf("")},
+		{Name: MethodGetHistory, WalletScoped: true, Result: typeOf([]Transaction{})},
+		{Name: MethodListKeys, WalletScoped: true, Result: typeOf([]GeneratedKey{})},
+		{Name: MethodStartCoinJoin, WalletScoped: true, Mutating: true, SecretParams: []int{0}},
+		{Name: MethodStartCoinJoinSweep, WalletScoped: true, Optional: true, Since: "2.1.0", Mutating: true, SecretParams: []int{0}},
+		{Name: MethodStopCoinJoin, WalletScoped: true, Mutating: true},
+		{Name: MethodStop, Mutating: true},
+		{Name: MethodGetFeeRates, Result: typeOf(GetFeeRatesResponse{})},
+		{Name: MethodListWallets, Result: typeOf([]ListWalletsResponseItem{})},
+		{Name: MethodExcludeFromCoinJoin, WalletScoped: true, Optional: true, Since: "2.1.0", Mutating: true},
+		{Name: MethodRecoverWallet, Mutating: true, SecretParams: []int{1, 2}},
+		{Name: MethodBuildUnsafeTransaction, WalletScoped: true, Optional: true, Since: "2.1.0", Result: typeOf("")},
+		{Name: MethodPayInCoinJoin, WalletScoped: true, Optional: true, Since: "2.2.0", Mutating: true, SecretParams: []int{2}, Result: typeOf("")},
+		{Name: MethodListPaymentsInCoinJoin, WalletScoped: true, Optional: true, Since: "2.2.0", Result: typeOf([]ListPaymentsInCoinJoinResponseItem{})},
+		{Name: MethodCancelPaymentInCoinJoin, WalletScoped: true, Optional: true, Since: "2.2.0", Mutating: true},
+		{Name: MethodCancelTransaction, WalletScoped: true, Optional: true, Since: "2.2.0", SecretParams: []int{1}, Result: typeOf("")},
+		{Name: MethodSpeedUpTransaction, WalletScoped: true, Optional: true, Since: "2.2.0", SecretParams: []int{1}, Result: typeOf("")},
+		{Name: MethodSelectWallet},
+	} {
+		registry.methods[spec.Name] = spec
+	}
+}
+
+// RegisterMethod registers a custom rpc method, so it can be called with Invoke and is redacted and audited like the built-in methods. It fails if a method with the same name is already registered.
+func RegisterMethod(spec MethodSpec) error {
+	if spec.Name == "" {
+		return fmt.Errorf("method name must not be empty")
+	}
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if _, ok := registry.methods[spec.Name]; ok {
+		return fmt.Errorf("method %s is already registered", spec.Name)
+	}
+	registry.methods[spec.Name] = spec
+	return nil
+}
+
+// LookupMethod returns the spec of a registered method.
+func LookupMethod(name Method) (MethodSpec, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	spec, ok := registry.methods[name]
+	return spec, ok
+}
+
+// Methods returns the specs of all registered methods sorted by name.
+func Methods() []MethodSpec {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	specs := make([]MethodSpec, 0, len(registry.methods))
+	for _, spec := range registry.methods {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+// Invoke calls a registered method. The arguments are encoded with the Encode function of the method and the result is decoded into a value of the registered Result type. If the daemon does not know the method, the error matches ErrUnsupportedMethod.
+func Invoke(c Client, walletName string, name Method, args ...interface{}) (interface{}, error) {
+	spec, ok := LookupMethod(name)
+	if !ok {
+		return nil, fmt.Errorf("method %s is not registered", name)
+	}
+	if spec.WalletScoped && walletName == "" {
+		return nil, fmt.Errorf("method %s requires a wallet name", name)
+	}
+	var params interface{}
+	switch {
+	case spec.Encode != nil:
+		var err error
+		if params, err = spec.Encode(args); err != nil {
+			return nil, fmt.Errorf("failed to encode params of %s: %w", name, err)
+		}
+	case len(args) > 0:
+		params = args
+	}
+	if spec.Result == nil {
+		return nil, c.RawCall(walletName, name.String(), params, nil)
+	}
+	result := reflect.New(spec.Result)
+	if err := c.RawCall(walletName, name.String(), params, result.Interface()); err != nil {
+		return nil, err
+	}
+	return result.Elem().Interface(), nil
+}
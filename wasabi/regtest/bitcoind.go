@@ -0,0 +1,70 @@
+package regtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// bitcoind is a minimal JSON-RPC client of bitcoind.
+type bitcoind struct {
+	url      string
+	user     string
+	password string
+}
+
+type bitcoindError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *bitcoindError) Error() string {
+	return fmt.Sprintf("bitcoind error %d: %s", e.Code, e.Message)
+}
+
+// call calls the method of bitcoind, or of its wallet if walletName is set, and decodes the result into out (which may be nil).
+func (b *bitcoind) call(ctx context.Context, walletName string, method string, out interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	payload, err := json.Marshal(map[string]interface{}{"jsonrpc": "1.0", "id": method, "method": method, "params": params})
+	if err != nil {
+		return err
+	}
+	url := b.url
+	if walletName != "" {
+		url += "/wallet/" + walletName
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(b.user, b.password)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *bitcoindError  `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("bitcoind %s: http status %d: %s", method, resp.StatusCode, body)
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Result, out)
+}
@@ -0,0 +1,378 @@
+// Package regtest runs a bitcoind regtest node and a Wasabi daemon in docker containers, so tests can exercise real send and coinjoin flows.
+// The containers are managed with testcontainers-go, which reaches the docker daemon of the environment (see DOCKER_HOST). The package is a module of its own, so the client does not depend on testcontainers-go.
+package regtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+)
+
+// EnvVar is the environment variable enabling the tests using Require. Starting the containers takes minutes, so they are skipped by default.
+const EnvVar = "WASABI_REGTEST"
+
+// Default images and credentials of the harness.
+const (
+	DefaultBitcoindImage = "bitcoin/bitcoin:27.1"
+	// DefaultWasabiImage is the image built from the Dockerfile of the README.
+	DefaultWasabiImage  = "wasabi_wallet"
+	DefaultRPCUser      = "regtest"
+	DefaultRPCPassword  = "regtest"
+	bitcoindRPCPort     = "18443/tcp"
+	wasabiRPCPort       = "37128/tcp"
+	minerWallet         = "miner"
+	coinbaseMaturity    = 100
+	defaultStartTimeout = 5 * time.Minute
+)
+
+// DefaultWasabiArgs returns the command of the Wasabi container: a daemon on regtest, without tor, connected to the bitcoind container and to the backend at backendURI.
+func DefaultWasabiArgs(backendURI string) []string {
+	return []string{
+		"dotnet", "run", "--",
+		"--network=regtest",
+		"--jsonrpcserverenabled=true",
+		"--jsonrpcserverprefixes=http://+:37128/",
+		"--usetor=false",
+		"--enablegpu=false",
+		"--datadir=/wasabi/data",
+		"--regtestbitcoinp2pendpoint=bitcoind:18444",
+		"--regtestbackenduri=" + backendURI,
+	}
+}
+
+// Options holds the options of a harness.
+type Options struct {
+	// BitcoindImage is the image of bitcoind. Default is DefaultBitcoindImage.
+	BitcoindImage string
+	// WasabiImage is the image of the Wasabi daemon. Default is DefaultWasabiImage.
+	WasabiImage string
+	// WasabiArgs is the command of the Wasabi container. Default is DefaultWasabiArgs(BackendURI).
+	WasabiArgs []string
+	// BackendImage is the image of the Wasabi backend, started before the daemon with the network alias backend. If empty, no backend container is started and the daemon uses BackendURI.
+	BackendImage string
+	// BackendArgs is the command of the backend container. Default is the command of the image.
+	BackendArgs []string
+	// BackendURI is the uri of the backend used by the daemon. Default is http://backend:37127/.
+	BackendURI string
+	// RPCUser and RPCPassword are the rpc credentials of bitcoind. Default is DefaultRPCUser and DefaultRPCPassword.
+	RPCUser     string
+	RPCPassword string
+	// StartTimeout limits the time to start the containers and to wait for the daemon. Default is 5 minutes.
+	StartTimeout time.Duration
+	// PollInterval is the interval of the waiting helpers. Default is 1 second.
+	PollInterval time.Duration
+}
+
+// Harness is a running bitcoind regtest node with a Wasabi daemon.
+type Harness struct {
+	opts       Options
+	network    *testcontainers.DockerNetwork
+	containers []testcontainers.Container
+	bitcoind   *bitcoind
+	client     wasabi.Client
+	mineTo     string
+}
+
+// Require starts a harness for the test, or skips the test if EnvVar is not set or docker is not available. The harness is closed when the test ends.
+func Require(t testing.TB, opts Options) *Harness {
+	t.Helper()
+	if os.Getenv(EnvVar) == "" {
+		t.Skipf("regtest tests are disabled, set %s=1 to run them", EnvVar)
+	}
+	if err := dockerHealth(context.Background()); err != nil {
+		t.Skipf("docker is not available: %v", err)
+	}
+	h, err := Start(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("failed to start regtest harness: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := h.Close(); err != nil {
+			t.Errorf("failed to close regtest harness: %v", err)
+		}
+	})
+	return h
+}
+
+// dockerHealth returns an error if the docker daemon cannot be reached.
+func dockerHealth(ctx context.Context) error {
+	provider, err := testcontainers.NewDockerProvider()
+	if err != nil {
+		return err
+	}
+	return provider.Health(ctx)
+}
+
+func withDefaults(opts Options) Options {
+	if opts.BitcoindImage == "" {
+		opts.BitcoindImage = DefaultBitcoindImage
+	}
+	if opts.WasabiImage == "" {
+		opts.WasabiImage = DefaultWasabiImage
+	}
+	if opts.BackendURI == "" {
+		opts.BackendURI = "http://backend:37127/"
+	}
+	if opts.WasabiArgs == nil {
+		opts.WasabiArgs = DefaultWasabiArgs(opts.BackendURI)
+	}
+	if opts.RPCUser == "" {
+		opts.RPCUser = DefaultRPCUser
+	}
+	if opts.RPCPassword == "" {
+		opts.RPCPassword = DefaultRPCPassword
+	}
+	if opts.StartTimeout <= 0 {
+		opts.StartTimeout = defaultStartTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	return opts
+}
+
+// Start starts the containers, mines the coins spent by the harness and waits until the daemon is synchronized. The containers are removed if the start fails.
+func Start(ctx context.Context, opts Options) (*Harness, error) {
+	opts = withDefaults(opts)
+	ctx, cancel := context.WithTimeout(ctx, opts.StartTimeout)
+	defer cancel()
+	h := &Harness{opts: opts}
+	if err := h.start(ctx); err != nil {
+		h.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+// start starts the network and the containers of the harness. The started containers are recorded, so Close removes them if a later step fails.
+func (h *Harness) start(ctx context.Context) error {
+	opts := h.opts
+
+	nw, err := network.New(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create the network: %w", err)
+	}
+	h.network = nw
+
+	bitcoindContainer, err := h.startContainer(ctx, "bitcoind", opts.BitcoindImage, bitcoindRPCPort,
+		"-regtest",
+		"-server",
+		"-rpcbind=0.0.0.0",
+		"-rpcallowip=0.0.0.0/0",
+		"-rpcuser="+opts.RPCUser,
+		"-rpcpassword="+opts.RPCPassword,
+		"-fallbackfee=0.0002",
+		"-txindex=1",
+		"-blockfilterindex=1",
+		"-peerblockfilters=1",
+		"-whitelist=0.0.0.0/0",
+	)
+	if err != nil {
+		return err
+	}
+	address, err := bitcoindContainer.PortEndpoint(ctx, bitcoindRPCPort, "")
+	if err != nil {
+		return err
+	}
+	h.bitcoind = &bitcoind{url: "http://" + address, user: opts.RPCUser, password: opts.RPCPassword}
+	if err := h.waitFor(ctx, func() (bool, error) {
+		return h.bitcoind.call(ctx, "", "getblockchaininfo", nil) == nil, nil
+	}); err != nil {
+		return fmt.Errorf("bitcoind did not start: %w", err)
+	}
+	if err := h.bitcoind.call(ctx, "", "createwallet", nil, minerWallet); err != nil {
+		return err
+	}
+	if err := h.bitcoind.call(ctx, minerWallet, "getnewaddress", &h.mineTo); err != nil {
+		return err
+	}
+	if _, err := h.Mine(ctx, coinbaseMaturity+1); err != nil {
+		return err
+	}
+
+	if opts.BackendImage != "" {
+		if _, err := h.startContainer(ctx, "backend", opts.BackendImage, "", opts.BackendArgs...); err != nil {
+			return err
+		}
+	}
+	wasabiContainer, err := h.startContainer(ctx, "wasabi", opts.WasabiImage, wasabiRPCPort, opts.WasabiArgs...)
+	if err != nil {
+		return err
+	}
+	address, err = wasabiContainer.PortEndpoint(ctx, wasabiRPCPort, "")
+	if err != nil {
+		return err
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return err
+	}
+	h.client, err = wasabi.NewClient(wasabi.Config{Host: host, Port: portNumber})
+	if err != nil {
+		return err
+	}
+	if err := h.WaitSynced(ctx); err != nil {
+		return fmt.Errorf("wasabi daemon did not synchronize: %w", err)
+	}
+	return nil
+}
+
+// startContainer starts a container on the network of the harness with the alias and publishes the port (if set) on a random host port. If args is empty, the command of the image is run.
+func (h *Harness) startContainer(ctx context.Context, alias string, image string, port string, args ...string) (testcontainers.Container, error) {
+	req := testcontainers.ContainerRequest{
+		Image:          image,
+		Cmd:            args,
+		Networks:       []string{h.network.Name},
+		NetworkAliases: map[string][]string{h.network.Name: {alias}},
+	}
+	if port != "" {
+		req.ExposedPorts = []string{port}
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{ContainerRequest: req, Started: true})
+	if container != nil {
+		h.containers = append(h.containers, container)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", alias, err)
+	}
+	return container, nil
+}
+
+// waitFor calls check every poll interval until it reports done or the context is done.
+func (h *Harness) waitFor(ctx context.Context, check func() (bool, error)) error {
+	ticker := time.NewTicker(h.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close removes the containers and the network of the harness.
+func (h *Harness) Close() error {
+	ctx := context.Background()
+	var errs []error
+	for i := len(h.containers) - 1; i >= 0; i-- {
+		if err := h.containers[i].Terminate(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	h.containers = nil
+	if h.network != nil {
+		if err := h.network.Remove(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		h.network = nil
+	}
+	return errors.Join(errs...)
+}
+
+// Client returns a client of the Wasabi daemon.
+func (h *Harness) Client() wasabi.Client {
+	return h.client
+}
+
+// BitcoindCall calls a bitcoind rpc method (of the miner wallet if walletName is set) and decodes the result into out, which may be nil.
+func (h *Harness) BitcoindCall(ctx context.Context, walletName string, method string, out interface{}, params ...interface{}) error {
+	return h.bitcoind.call(ctx, walletName, method, out, params...)
+}
+
+// Mine mines blocks to the miner wallet of bitcoind and returns their hashes.
+func (h *Harness) Mine(ctx context.Context, blocks int) ([]string, error) {
+	var hashes []string
+	err := h.bitcoind.call(ctx, "", "generatetoaddress", &hashes, blocks, h.mineTo)
+	return hashes, err
+}
+
+// SendToAddress sends the amount from the miner wallet of bitcoind to the address and returns the txid. The transaction is not mined.
+func (h *Harness) SendToAddress(ctx context.Context, address wasabi.Address, amount wasabi.Amount) (wasabi.TxID, error) {
+	var txID wasabi.TxID
+	err := h.bitcoind.call(ctx, minerWallet, "sendtoaddress", &txID, address, amount.FormatBTC())
+	return txID, err
+}
+
+// WaitSynced waits until the daemon is connected to its backend and has processed the filters of the best block of bitcoind.
+func (h *Harness) WaitSynced(ctx context.Context) error {
+	c := h.client.WithContext(ctx)
+	return h.waitFor(ctx, func() (bool, error) {
+		var height uint64
+		if err := h.bitcoind.call(ctx, "", "getblockcount", &height); err != nil {
+			return false, err
+		}
+		status, err := c.GetStatus()
+		if err != nil {
+			// The daemon is not listening yet.
+			return false, nil
+		}
+		return status.BackendStatus == wasabi.BackendStatusConnected && status.FiltersLeft == 0 && status.BestBlockchainHeight >= height, nil
+	})
+}
+
+// CreateWallet creates a wallet with the password, loads it and waits until it is started.
+func (h *Harness) CreateWallet(ctx context.Context, walletName string, password string) (*wasabi.Wallet, error) {
+	c := h.client.WithContext(ctx)
+	if _, err := c.CreateWallet(walletName, password); err != nil {
+		return nil, err
+	}
+	if err := wasabi.EnsureWalletLoaded(ctx, c, walletName, wasabi.EnsureWalletLoadedOptions{MaxBackoff: h.opts.PollInterval}); err != nil {
+		return nil, err
+	}
+	return c.Wallet(walletName).WithPassword(wasabi.StaticPassword(password)), nil
+}
+
+// FundWallet sends the amount from the miner to a new address of the wallet labeled label, mines the blocks needed for the confirmations and waits until the wallet sees them. It returns the txid.
+func (h *Harness) FundWallet(ctx context.Context, walletName string, amount wasabi.Amount, label string, confirmations int) (wasabi.TxID, error) {
+	c := h.client.WithContext(ctx)
+	address, err := c.GetNewAddress(walletName, label)
+	if err != nil {
+		return "", err
+	}
+	txID, err := h.SendToAddress(ctx, address.Address, amount)
+	if err != nil {
+		return "", err
+	}
+	if confirmations > 0 {
+		if _, err := h.Mine(ctx, confirmations); err != nil {
+			return "", err
+		}
+		if err := h.WaitSynced(ctx); err != nil {
+			return "", err
+		}
+		err = wasabi.WaitForConfirmations(ctx, c, walletName, txID, confirmations, wasabi.WaitForConfirmationsOptions{Interval: h.opts.PollInterval})
+		return txID, err
+	}
+	// Unconfirmed transactions are awaited in the history.
+	err = h.waitFor(ctx, func() (bool, error) {
+		history, err := c.GetHistory(walletName)
+		if err != nil {
+			return false, err
+		}
+		for _, tx := range history {
+			if tx.TxID == txID {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return txID, err
+}
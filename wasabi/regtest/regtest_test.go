@@ -0,0 +1,82 @@
+package regtest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+func TestWithDefaults(t *testing.T) {
+	opts := withDefaults(Options{})
+	if opts.BitcoindImage != DefaultBitcoindImage || opts.WasabiImage != DefaultWasabiImage || opts.RPCUser != DefaultRPCUser || opts.RPCPassword != DefaultRPCPassword {
+		t.Errorf("withDefaults() = %+v", opts)
+	}
+	if opts.StartTimeout != defaultStartTimeout || opts.PollInterval != time.Second {
+		t.Errorf("withDefaults() timeouts = %v, %v", opts.StartTimeout, opts.PollInterval)
+	}
+	if !reflect.DeepEqual(opts.WasabiArgs, DefaultWasabiArgs("http://backend:37127/")) {
+		t.Errorf("withDefaults() WasabiArgs = %v", opts.WasabiArgs)
+	}
+
+	opts = withDefaults(Options{BackendURI: "http://example:1/", WasabiArgs: []string{}})
+	if len(opts.WasabiArgs) != 0 {
+		t.Errorf("withDefaults() replaced the empty WasabiArgs with %v", opts.WasabiArgs)
+	}
+}
+
+// TestSend runs against the containers and is skipped unless WASABI_REGTEST is set.
+func TestSend(t *testing.T) {
+	h := Require(t, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	sender, err := h.CreateWallet(ctx, "sender", "pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver, err := h.CreateWallet(ctx, "receiver", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.FundWallet(ctx, sender.Name(), 1_000_000, "funding", 1); err != nil {
+		t.Fatal(err)
+	}
+	balance, err := sender.Balance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Confirmed != 1_000_000 {
+		t.Fatalf("confirmed balance = %d, want 1000000", balance.Confirmed)
+	}
+
+	address, err := receiver.NewAddress("sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, err := sender.Send(wasabi.SendRequest{
+		Payments:  []wasabi.Payment{{SendTo: address.Address, Amount: 100_000, Label: "receiver"}},
+		FeeTarget: wasabi.FeeTargetHour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Mine(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.WaitSynced(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := wasabi.WaitForConfirmations(ctx, h.Client(), receiver.Name(), sent.TransactionID, 1, wasabi.WaitForConfirmationsOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	coins, err := receiver.ListUnspentCoins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coins) != 1 || coins[0].Amount != 100_000 || coins[0].TxID != sent.TransactionID {
+		t.Fatalf("receiver coins = %+v, want the payment of 100000 sat", coins)
+	}
+}
@@ -0,0 +1,44 @@
+package wasabi
+
+import "fmt"
+
+// BroadcastError is returned by the build-and-broadcast helpers when the transaction was built but the broadcast failed. If Err is a ConnectionError, the request may have reached the daemon and the replacement may have been broadcast; check the history before building another replacement. Otherwise the daemon rejected the broadcast and the original transaction is still pending. The built transaction is kept in Hex, so it can be inspected or broadcast again.
+type BroadcastError struct {
+	// Hex is the built transaction which was not broadcast.
+	Hex string
+	Err error
+}
+
+func (e *BroadcastError) Error() string {
+	return fmt.Sprintf("failed to broadcast the built transaction: %v", e.Err)
+}
+
+func (e *BroadcastError) Unwrap() error {
+	return e.Err
+}
+
+// CancelAndBroadcast cancels an unconfirmed transaction by building a replacement which pays back to the wallet, and broadcasts it. It returns the id of the replacement transaction. If the broadcast fails, a BroadcastError is returned.
+func CancelAndBroadcast(c Client, walletName string, txID TxID, password string) (TxID, error) {
+	txHex, err := c.CancelTransaction(walletName, txID, password)
+	if err != nil {
+		return "", err
+	}
+	return broadcastBuilt(c, walletName, txHex)
+}
+
+// SpeedUpAndBroadcast speeds up an unconfirmed transaction by building a replacement with a higher fee, and broadcasts it. It returns the id of the replacement transaction. If the broadcast fails, a BroadcastError is returned.
+func SpeedUpAndBroadcast(c Client, walletName string, txID TxID, password string) (TxID, error) {
+	txHex, err := c.SpeedUpTransaction(walletName, txID, password)
+	if err != nil {
+		return "", err
+	}
+	return broadcastBuilt(c, walletName, txHex)
+}
+
+func broadcastBuilt(c Client, walletName string, txHex string) (TxID, error) {
+	newTxID, err := c.Broadcast(walletName, txHex)
+	if err != nil {
+		return "", &BroadcastError{Hex: txHex, Err: err}
+	}
+	return newTxID, nil
+}
@@ -0,0 +1,121 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultRetryBackoff is the wait before the first retry of a RetryPolicy without backoff.
+const DefaultRetryBackoff = 200 * time.Millisecond
+
+// RetryPolicy sends failed calls again. The zero value does not retry.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a call, the first one included. Zero or one does not retry.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled before each following one. Default is DefaultRetryBackoff.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between two attempts. Zero does not cap.
+	MaxBackoff time.Duration
+	// RetryMutating retries state-changing calls too (see Method.IsMutating). They are not retried by default, as a call failing after reaching the daemon, e.g. a send whose response was lost, may have taken effect.
+	RetryMutating bool
+	// Retryable reports whether a failed call is sent again. Default retries connection errors.
+	Retryable func(call *CallInfo, err error) bool
+	// Clock provides the timers of the backoff. Default is SystemClock.
+	Clock Clock
+}
+
+// retries reports whether the attempt which failed with err is sent again.
+func (p RetryPolicy) retries(call *CallInfo, err error) bool {
+	if err == nil || call.Attempt >= p.MaxAttempts {
+		return false
+	}
+	if call.Method.IsMutating() && !p.RetryMutating {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(call, err)
+	}
+	var connectionErr *ConnectionError
+	return errors.As(err, &connectionErr)
+}
+
+// backoff returns the wait before the next attempt of the call.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// wait waits the backoff before the next attempt of the call. It returns false if the context is done first.
+func (p RetryPolicy) wait(ctx context.Context, attempt int) bool {
+	timer := clockOrDefault(p.Clock).NewTimer(p.backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+type retryPolicyContextKey struct{}
+
+// noRetry is the policy set by WithoutRetry.
+var noRetry = &RetryPolicy{MaxAttempts: 1}
+
+// WithRetryPolicy returns a context whose calls follow the policy instead of the retry policy of the client (see Config.Retry), e.g. to retry monitoring calls more aggressively.
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyContextKey{}, &policy)
+}
+
+// WithoutRetry returns a context whose calls are sent once: neither the retry policy of the client nor interceptors setting CallInfo.Retry send them again, e.g. for a single Broadcast from an operator tool.
+func WithoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryPolicyContextKey{}, noRetry)
+}
+
+// RetryPolicyFromContext returns the policy set with WithRetryPolicy or WithoutRetry.
+func RetryPolicyFromContext(ctx context.Context) (RetryPolicy, bool) {
+	policy, ok := ctx.Value(retryPolicyContextKey{}).(*RetryPolicy)
+	if !ok {
+		return RetryPolicy{}, false
+	}
+	return *policy, true
+}
+
+// isNoRetry reports whether the context was returned by WithoutRetry.
+func isNoRetry(ctx context.Context) bool {
+	policy, _ := ctx.Value(retryPolicyContextKey{}).(*RetryPolicy)
+	return policy == noRetry
+}
+
+// retryPolicy returns the retry policy of the calls made with the context.
+func (c *client) retryPolicy(ctx context.Context) RetryPolicy {
+	if policy, ok := RetryPolicyFromContext(ctx); ok {
+		return policy
+	}
+	return c.retry
+}
+
+// retries reports whether the attempt of the call which failed with err is sent again, after waiting the backoff of the policy.
+func (c *client) retries(call *CallInfo, err error) bool {
+	if isNoRetry(call.Context) {
+		return false
+	}
+	if call.Retry {
+		return true
+	}
+	policy := c.retryPolicy(call.Context)
+	return policy.retries(call, err) && policy.wait(call.Context, call.Attempt)
+}
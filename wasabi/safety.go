@@ -0,0 +1,24 @@
+package wasabi
+
+import "fmt"
+
+// SafetyFlags are client-side checks of a send or build request. They refuse requests which are likely mistakes before they reach the daemon.
+type SafetyFlags struct {
+	// MaxFeeRate refuses a FeeRate above it (in satoshi per virtual byte), e.g. a rate given per kilobyte by mistake. Zero disables the check.
+	MaxFeeRate float64
+	// RequireCoins refuses a request without Coins, so only coins picked by the caller are spent.
+	RequireCoins bool
+}
+
+// check returns an error if the request fails one of the checks.
+func (f SafetyFlags) check(coins []Coin, feeRate float64) error {
+	switch {
+	case f.MaxFeeRate < 0:
+		return fmt.Errorf("max fee rate must not be negative")
+	case f.MaxFeeRate > 0 && feeRate > f.MaxFeeRate:
+		return fmt.Errorf("fee rate %v sat/vB is above the max fee rate %v sat/vB", feeRate, f.MaxFeeRate)
+	case f.RequireCoins && len(coins) == 0:
+		return fmt.Errorf("coins must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed cron expression with the five standard fields (minute, hour, day of month, month, day of week), e.g. "0 9 * * 1" for every Monday at 9:00. The descriptors @hourly, @daily, @weekly, @monthly and @yearly are accepted too. Months and days of week may be given by their three-letter English names.
+type Cron struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool
+}
+
+var cronDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+var (
+	monthNames = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	dayNames   = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+)
+
+// ParseCron parses a cron expression.
+func ParseCron(expr string) (Cron, error) {
+	c := Cron{expr: expr}
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return Cron{}, fmt.Errorf("minute of %q: %w", expr, err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return Cron{}, fmt.Errorf("hour of %q: %w", expr, err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return Cron{}, fmt.Errorf("day of month of %q: %w", expr, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return Cron{}, fmt.Errorf("month of %q: %w", expr, err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7, dayNames); err != nil {
+		return Cron{}, fmt.Errorf("day of week of %q: %w", expr, err)
+	}
+	// Sunday is both 0 and 7.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	// Like cron, a field starting with * (e.g. */2) is not a restriction of the day.
+	c.domRestricted = !strings.HasPrefix(fields[2], "*")
+	c.dowRestricted = !strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges (1-5) and steps (*/15, 1-30/2) into a bit set.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		first, last := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			from, to, _ := strings.Cut(rng, "-")
+			var err error
+			if first, err = cronValue(from, names); err != nil {
+				return 0, err
+			}
+			if last, err = cronValue(to, names); err != nil {
+				return 0, err
+			}
+		default:
+			value, err := cronValue(rng, names)
+			if err != nil {
+				return 0, err
+			}
+			first, last = value, value
+			if hasStep {
+				last = max
+			}
+		}
+		if first < min || last > max || first > last {
+			return 0, fmt.Errorf("%q is out of the range %d-%d", item, min, max)
+		}
+		increment := 1
+		if hasStep {
+			var err error
+			if increment, err = strconv.Atoi(step); err != nil || increment <= 0 {
+				return 0, fmt.Errorf("invalid step %q", step)
+			}
+		}
+		for value := first; value <= last; value += increment {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToLower(s)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return value, nil
+}
+
+// String returns the expression the cron was parsed from.
+func (c Cron) String() string {
+	return c.expr
+}
+
+// Next returns the first time matching the expression strictly after t, in the location of t. It returns the zero time if no time matches within five years (e.g. for February 30).
+// The expression is matched against the wall clock: a time skipped when the clock is turned forward matches right after the change (2:30 is 3:30), and a time repeated when the clock is turned back matches once.
+func (c Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	limit := wall.AddDate(5, 0, 0)
+	for {
+		if wall = c.nextWall(wall, limit); wall.IsZero() {
+			return time.Time{}
+		}
+		next := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, loc)
+		// The wall clock time is before t if it was repeated, or if it was skipped and matched by an earlier time already.
+		if next.After(t) {
+			return next
+		}
+	}
+}
+
+// nextWall returns the first wall clock time (in UTC, which has no clock changes) matching the expression strictly after wall, or the zero time if none matches before the limit.
+func (c Cron) nextWall(wall, limit time.Time) time.Time {
+	t := wall.Add(time.Minute)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the day matches the day of month and the day of week. Like cron, if both are restricted, either may match.
+func (c Cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domRestricted && c.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
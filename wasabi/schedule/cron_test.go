@@ -0,0 +1,135 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "0 9 * * 1"},
+		{expr: "*/15 9-17 * * mon-fri"},
+		{expr: "0 0 1,15 jan,jul *"},
+		{expr: "0 12 * * 7"},
+		{expr: "  @Daily  "},
+		{expr: "5-55/10 * * * *"},
+		{expr: "0 9 * *", wantErr: true},
+		{expr: "0 9 * * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "0 24 * * *", wantErr: true},
+		{expr: "0 0 0 * *", wantErr: true},
+		{expr: "0 0 * 13 *", wantErr: true},
+		{expr: "0 0 * * 8", wantErr: true},
+		{expr: "0 0 * * sunday", wantErr: true},
+		{expr: "10-5 * * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "*/x * * * *", wantErr: true},
+		{expr: "@every 5m", wantErr: true},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCron(%q) = %v, want error %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if err == nil && c.String() != tt.expr {
+			t.Errorf("ParseCron(%q).String() = %q", tt.expr, c.String())
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []time.Time
+	}{
+		{name: "every monday", expr: "0 9 * * 1", from: utc(3, 1, 12, 0), want: []time.Time{utc(3, 4, 9, 0), utc(3, 11, 9, 0)}},
+		{name: "strictly after", expr: "0 9 * * *", from: utc(3, 1, 9, 0), want: []time.Time{utc(3, 2, 9, 0)}},
+		{name: "seconds are dropped", expr: "* * * * *", from: utc(3, 1, 9, 0).Add(30 * time.Second), want: []time.Time{utc(3, 1, 9, 1)}},
+		{name: "steps", expr: "*/20 9 * * *", from: utc(3, 1, 8, 0), want: []time.Time{utc(3, 1, 9, 0), utc(3, 1, 9, 20), utc(3, 1, 9, 40), utc(3, 2, 9, 0)}},
+		{name: "names and ranges", expr: "30 8 * feb-mar sat,sun", from: utc(3, 1, 12, 0), want: []time.Time{utc(3, 2, 8, 30), utc(3, 3, 8, 30), utc(3, 9, 8, 30)}},
+		{name: "sunday is 7", expr: "0 0 * * 7", from: utc(3, 1, 12, 0), want: []time.Time{utc(3, 3, 0, 0)}},
+		{name: "day of month or day of week", expr: "0 0 15 * fri", from: utc(3, 7, 0, 0), want: []time.Time{utc(3, 8, 0, 0), utc(3, 15, 0, 0), utc(3, 22, 0, 0)}},
+		{name: "step of the day of month is not a restriction", expr: "0 0 */2 * mon", from: utc(3, 1, 0, 0), want: []time.Time{utc(3, 11, 0, 0), utc(3, 25, 0, 0)}},
+		{name: "step of the day of week is not a restriction", expr: "0 0 1 * */2", from: utc(3, 1, 0, 0), want: []time.Time{utc(6, 1, 0, 0), utc(8, 1, 0, 0)}},
+		{name: "leap day", expr: "0 0 29 2 *", from: utc(3, 1, 0, 0), want: []time.Time{time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)}},
+		{name: "end of months", expr: "@monthly", from: utc(1, 31, 12, 0), want: []time.Time{utc(2, 1, 0, 0), utc(3, 1, 0, 0)}},
+		{name: "never", expr: "0 0 30 2 *", from: utc(3, 1, 0, 0), want: []time.Time{{}}},
+		{
+			name: "daily in a location",
+			expr: "0 9 * * *",
+			from: time.Date(2024, 3, 30, 12, 0, 0, 0, berlin),
+			want: []time.Time{time.Date(2024, 3, 31, 9, 0, 0, 0, berlin), time.Date(2024, 4, 1, 9, 0, 0, 0, berlin)},
+		},
+		{
+			// 2:30 does not exist on March 31 in Berlin, the clock is turned forward from 2:00 to 3:00.
+			name: "time skipped by the clock change",
+			expr: "30 2 * * *",
+			from: time.Date(2024, 3, 30, 12, 0, 0, 0, berlin),
+			want: []time.Time{time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC), time.Date(2024, 4, 1, 2, 30, 0, 0, berlin)},
+		},
+		{
+			name: "every half hour across the clock turned forward",
+			expr: "*/30 * * * *",
+			from: time.Date(2024, 3, 31, 1, 0, 0, 0, berlin),
+			want: []time.Time{time.Date(2024, 3, 31, 1, 30, 0, 0, berlin), time.Date(2024, 3, 31, 3, 0, 0, 0, berlin), time.Date(2024, 3, 31, 3, 30, 0, 0, berlin)},
+		},
+		{
+			// 2:30 happens twice on October 27 in Berlin, the clock is turned back from 3:00 to 2:00.
+			name: "time repeated by the clock change",
+			expr: "30 2 * * *",
+			from: time.Date(2024, 10, 26, 12, 0, 0, 0, berlin),
+			want: []time.Time{time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC), time.Date(2024, 10, 28, 2, 30, 0, 0, berlin)},
+		},
+		{
+			name: "every half hour across the clock turned back",
+			expr: "*/30 * * * *",
+			from: time.Date(2024, 10, 27, 1, 0, 0, 0, berlin),
+			want: []time.Time{time.Date(2024, 10, 26, 23, 30, 0, 0, time.UTC), time.Date(2024, 10, 27, 1, 0, 0, 0, time.UTC), time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC), time.Date(2024, 10, 27, 2, 0, 0, 0, time.UTC)},
+		},
+		{
+			// The wall clock times of the repeated hour match in its second pass only.
+			name: "from the first pass of the repeated hour",
+			expr: "*/15 * * * *",
+			from: time.Date(2024, 10, 27, 0, 10, 0, 0, time.UTC).In(berlin),
+			want: []time.Time{time.Date(2024, 10, 27, 1, 15, 0, 0, time.UTC)},
+		},
+		{
+			name: "from the second pass of the repeated hour",
+			expr: "*/15 * * * *",
+			from: time.Date(2024, 10, 27, 1, 10, 0, 0, time.UTC).In(berlin),
+			want: []time.Time{time.Date(2024, 10, 27, 1, 15, 0, 0, time.UTC), time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := tt.from
+			for i, want := range tt.want {
+				next = c.Next(next)
+				if !next.Equal(want) {
+					t.Fatalf("execution %d = %v, want %v", i, next, want)
+				}
+				if !next.IsZero() && next.Location() != tt.from.Location() {
+					t.Fatalf("execution %d in %v, want %v", i, next.Location(), tt.from.Location())
+				}
+			}
+		})
+	}
+}
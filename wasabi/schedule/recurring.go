@@ -0,0 +1,287 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Policy decides what happens to an execution of a recurring payment which cannot be paid at its time, because the balance is too low or the fee rate is above the ceiling.
+type Policy string
+
+const (
+	// PolicySkip fails the execution, so it is skipped. Executions missed while the scheduler was not running are skipped too.
+	PolicySkip Policy = "skip"
+	// PolicyRetry keeps the execution waiting until the retry window elapses.
+	PolicyRetry Policy = "retry"
+)
+
+// RecurringStatus is the status of a recurring payment.
+type RecurringStatus string
+
+const (
+	RecurringActive    RecurringStatus = "active"
+	RecurringCancelled RecurringStatus = "cancelled"
+	// RecurringFinished is the status of a recurring payment past its end.
+	RecurringFinished RecurringStatus = "finished"
+)
+
+// maxMaterialized is the maximum number of executions created for a recurring payment by one check, so a frequent schedule missed for a long time does not flood the store.
+const maxMaterialized = 100
+
+// Recurring is a payment repeated on a cron schedule.
+type Recurring struct {
+	ID string `json:"id"`
+	// Payment is the template of the executions: wallet, address, amount, label, method, funds, fee and fee rate ceiling. Its time, expiry and status are set per execution.
+	Payment Payment `json:"payment"`
+	// Cron is the cron expression of the executions (see ParseCron).
+	Cron string `json:"cron"`
+	// Location is the IANA time zone of the cron expression, e.g. Europe/Berlin. Default is UTC.
+	Location string `json:"location,omitempty"`
+	// Start and End bound the executions. A zero start is the time of scheduling, a zero end never ends.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitempty"`
+	// Policy is applied to executions which cannot be paid at their time. Default is PolicySkip.
+	Policy Policy `json:"policy"`
+	// RetryWindow is how long an execution waits with PolicyRetry. Default is until the next execution.
+	RetryWindow time.Duration `json:"retryWindow,omitempty"`
+	// Skipped are the times of the upcoming executions cancelled with SkipExecution.
+	Skipped []time.Time `json:"skipped,omitempty"`
+	// Next is the time of the next execution.
+	Next      time.Time       `json:"next"`
+	Status    RecurringStatus `json:"status"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// schedule returns the parsed cron expression and the location of the recurring payment.
+func (r Recurring) schedule() (Cron, *time.Location, error) {
+	cron, err := ParseCron(r.Cron)
+	if err != nil {
+		return Cron{}, nil, err
+	}
+	location := time.UTC
+	if r.Location != "" {
+		if location, err = time.LoadLocation(r.Location); err != nil {
+			return Cron{}, nil, err
+		}
+	}
+	return cron, location, nil
+}
+
+// next returns the first execution after t which is not skipped, or the zero time if there is none before the end.
+func (r Recurring) next(cron Cron, location *time.Location, t time.Time) time.Time {
+	for {
+		t = cron.Next(t.In(location))
+		if t.IsZero() || (!r.End.IsZero() && t.After(r.End)) {
+			return time.Time{}
+		}
+		if !r.skipped(t) {
+			return t
+		}
+	}
+}
+
+func (r Recurring) skipped(t time.Time) bool {
+	for _, skipped := range r.Skipped {
+		if skipped.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduleRecurring validates and stores a recurring payment. Its id, status, creation time and next execution are set by the scheduler.
+func (s *Scheduler) ScheduleRecurring(r Recurring) (Recurring, error) {
+	if err := validate(&r.Payment); err != nil {
+		return Recurring{}, err
+	}
+	cron, location, err := r.schedule()
+	if err != nil {
+		return Recurring{}, err
+	}
+	if r.Policy == "" {
+		r.Policy = PolicySkip
+	}
+	if r.Policy != PolicySkip && r.Policy != PolicyRetry {
+		return Recurring{}, fmt.Errorf("unknown policy %q", r.Policy)
+	}
+	if r.RetryWindow < 0 {
+		return Recurring{}, fmt.Errorf("retry window must not be negative")
+	}
+	now := s.opts.Clock.Now()
+	if r.Start.IsZero() {
+		r.Start = now
+	}
+	if r.ID, err = newID(); err != nil {
+		return Recurring{}, err
+	}
+	r.Status = RecurringActive
+	r.CreatedAt = now
+	r.Skipped = nil
+	if r.Next = r.next(cron, location, r.Start.Add(-time.Nanosecond)); r.Next.IsZero() {
+		return Recurring{}, fmt.Errorf("cron expression %q has no execution between the start and the end", r.Cron)
+	}
+	if err := s.store.PutRecurring(r); err != nil {
+		return Recurring{}, err
+	}
+	return r, nil
+}
+
+// CancelRecurring cancels the recurring payment and its pending executions.
+func (s *Scheduler) CancelRecurring(id string) (Recurring, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	r, err := s.store.Recurring(id)
+	if err != nil {
+		return Recurring{}, err
+	}
+	if r.Status != RecurringActive {
+		return r, fmt.Errorf("recurring payment %s is %s, not active", id, r.Status)
+	}
+	r.Status = RecurringCancelled
+	if err := s.store.PutRecurring(r); err != nil {
+		return r, err
+	}
+	payments, err := s.store.Payments()
+	if err != nil {
+		return r, err
+	}
+	for _, p := range payments {
+		if p.RecurringID == id && p.Status == StatusPending {
+			p.Status = StatusCancelled
+			if err := s.store.PutPayment(p); err != nil {
+				return r, err
+			}
+		}
+	}
+	return r, nil
+}
+
+// GetRecurring returns the recurring payment, or ErrNotFound.
+func (s *Scheduler) GetRecurring(id string) (Recurring, error) {
+	return s.store.Recurring(id)
+}
+
+// ListRecurring returns all recurring payments in creation order.
+func (s *Scheduler) ListRecurring() ([]Recurring, error) {
+	return s.store.Recurrings()
+}
+
+// Upcoming returns the times of the next n executions of the active recurring payment, without the skipped ones.
+func (s *Scheduler) Upcoming(id string, n int) ([]time.Time, error) {
+	r, err := s.store.Recurring(id)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status != RecurringActive {
+		return nil, nil
+	}
+	cron, location, err := r.schedule()
+	if err != nil {
+		return nil, err
+	}
+	var times []time.Time
+	for t := r.Next; !t.IsZero() && len(times) < n; t = r.next(cron, location, t) {
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// SkipExecution cancels the upcoming execution of the recurring payment at the time, which must be one of its upcoming executions (see Upcoming).
+func (s *Scheduler) SkipExecution(id string, at time.Time) (Recurring, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	r, err := s.store.Recurring(id)
+	if err != nil {
+		return Recurring{}, err
+	}
+	if r.Status != RecurringActive {
+		return r, fmt.Errorf("recurring payment %s is %s, not active", id, r.Status)
+	}
+	cron, location, err := r.schedule()
+	if err != nil {
+		return r, err
+	}
+	// The upcoming executions are walked, since executions moved by a clock change are not matched by the expression at their time.
+	upcoming := r.Next
+	for !upcoming.IsZero() && upcoming.Before(at) {
+		upcoming = r.next(cron, location, upcoming)
+	}
+	if !upcoming.Equal(at) {
+		return r, fmt.Errorf("%v is not an upcoming execution of recurring payment %s", at, id)
+	}
+	r.Skipped = append(r.Skipped, at)
+	if at.Equal(r.Next) {
+		r.Next = r.next(cron, location, at)
+		if r.Next.IsZero() {
+			r.Status = RecurringFinished
+		}
+	}
+	// Skipped times before the next execution are not needed anymore.
+	kept := r.Skipped[:0]
+	for _, t := range r.Skipped {
+		if !t.Before(r.Next) {
+			kept = append(kept, t)
+		}
+	}
+	r.Skipped = kept
+	return r, s.store.PutRecurring(r)
+}
+
+// materialize creates the due executions of the active recurring payments.
+func (s *Scheduler) materialize(ctx context.Context, now time.Time) error {
+	recurrings, err := s.store.Recurrings()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, r := range recurrings {
+		if r.Status != RecurringActive || r.Next.After(now) {
+			continue
+		}
+		if err := s.materializeRecurring(ctx, r, now); err != nil {
+			errs = append(errs, fmt.Errorf("recurring payment %s: %w", r.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Scheduler) materializeRecurring(ctx context.Context, r Recurring, now time.Time) error {
+	cron, location, err := r.schedule()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < maxMaterialized && !r.Next.IsZero() && !r.Next.After(now); i++ {
+		at := r.Next
+		next := r.next(cron, location, at)
+		p := r.Payment
+		p.RecurringID = r.ID
+		p.At = at
+		p.Expires = at
+		if r.Policy == PolicyRetry {
+			switch {
+			case r.RetryWindow > 0:
+				p.Expires = at.Add(r.RetryWindow)
+			case !next.IsZero():
+				p.Expires = next
+			default:
+				p.Expires = time.Time{}
+			}
+		}
+		if err := s.add(&p); err != nil {
+			return err
+		}
+		if r.Policy == PolicySkip && !next.IsZero() && !next.After(now) {
+			s.fail(ctx, p, fmt.Errorf("missed while the scheduler was not running"))
+		}
+		r.Next = next
+		if r.Next.IsZero() {
+			r.Status = RecurringFinished
+		}
+		if err := s.store.PutRecurring(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
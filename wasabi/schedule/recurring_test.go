@@ -0,0 +1,265 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// executions returns the statuses of the executions of the recurring payment by their time.
+func executions(t *testing.T, scheduler *Scheduler, id string) map[time.Time]Status {
+	t.Helper()
+	payments, err := scheduler.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := map[time.Time]Status{}
+	for _, p := range payments {
+		if p.RecurringID == id {
+			statuses[p.At] = p.Status
+		}
+	}
+	return statuses
+}
+
+func TestScheduleRecurringValidation(t *testing.T) {
+	_, scheduler, _, address := newTestScheduler(t, NewMemoryStore(), Options{})
+	payment := Payment{WalletName: "w", Address: address, Amount: 1000}
+	tests := []struct {
+		name      string
+		recurring Recurring
+		wantErr   string
+	}{
+		{name: "invalid payment", recurring: Recurring{Payment: Payment{WalletName: "w", Address: address}, Cron: "@daily"}, wantErr: "amount"},
+		{name: "invalid cron", recurring: Recurring{Payment: payment, Cron: "every day"}, wantErr: "5 fields"},
+		{name: "unknown location", recurring: Recurring{Payment: payment, Cron: "@daily", Location: "Mars/Olympus"}, wantErr: "Mars/Olympus"},
+		{name: "unknown policy", recurring: Recurring{Payment: payment, Cron: "@daily", Policy: "maybe"}, wantErr: "unknown policy"},
+		{name: "negative retry window", recurring: Recurring{Payment: payment, Cron: "@daily", RetryWindow: -time.Hour}, wantErr: "retry window"},
+		{name: "no execution", recurring: Recurring{Payment: payment, Cron: "@daily", End: start.Add(time.Hour)}, wantErr: "no execution"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := scheduler.ScheduleRecurring(tt.recurring); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ScheduleRecurring() = %v, want an error with %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	r, err := scheduler.ScheduleRecurring(Recurring{Payment: payment, Cron: "0 9 * * 1", Location: "Europe/Berlin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != RecurringActive || r.Policy != PolicySkip || !r.Start.Equal(start) || !r.Next.Equal(time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("recurring payment = %+v, want the first execution on Monday at 9:00 in Berlin", r)
+	}
+}
+
+func TestRecurringSkipsMissedExecutions(t *testing.T) {
+	s, scheduler, clock, address := newTestScheduler(t, NewMemoryStore(), Options{})
+	r, err := scheduler.ScheduleRecurring(Recurring{Payment: Payment{WalletName: "w", Address: address, Amount: 10_000}, Cron: "@daily"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(n int) time.Time { return time.Date(2024, 3, n, 0, 0, 0, 0, time.UTC) }
+
+	clock.Set(day(2))
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The change of the first execution is confirmed.
+	s.Mine(1)
+	// The scheduler was not running on March 3 and 4.
+	clock.Set(day(5).Add(time.Hour))
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := map[time.Time]Status{day(2): StatusExecuted, day(3): StatusFailed, day(4): StatusFailed, day(5): StatusExecuted}
+	got := executions(t, scheduler, r.ID)
+	if len(got) != len(want) {
+		t.Fatalf("executions = %v, want %v", got, want)
+	}
+	for at, status := range want {
+		if got[at] != status {
+			t.Fatalf("executions = %v, want %v", got, want)
+		}
+	}
+	if r, _ := scheduler.GetRecurring(r.ID); !r.Next.Equal(day(6)) {
+		t.Fatalf("next execution = %v, want %v", r.Next, day(6))
+	}
+}
+
+func TestRecurringPolicies(t *testing.T) {
+	tests := []struct {
+		policy      Policy
+		retryWindow time.Duration
+		wantStatus  Status
+	}{
+		{policy: PolicySkip, wantStatus: StatusFailed},
+		{policy: PolicyRetry, wantStatus: StatusExecuted},
+		{policy: PolicyRetry, retryWindow: 10 * time.Minute, wantStatus: StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy)+" "+tt.retryWindow.String(), func(t *testing.T) {
+			s, scheduler, clock, address := newTestScheduler(t, NewMemoryStore(), Options{})
+			// The balance covers the payment only after it was waiting for half an hour.
+			r, err := scheduler.ScheduleRecurring(Recurring{Payment: Payment{WalletName: "w", Address: address, Amount: 1_500_000}, Cron: "0 13 * * *", Policy: tt.policy, RetryWindow: tt.retryWindow})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range []time.Duration{time.Hour, 30 * time.Minute} {
+				clock.Advance(d)
+				if err := scheduler.Check(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := s.Fund("w", 1_000_000, "bonus"); err != nil {
+				t.Fatal(err)
+			}
+			s.Mine(1)
+			clock.Advance(30 * time.Minute)
+			if err := scheduler.Check(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := executions(t, scheduler, r.ID)[r.Next]; got != tt.wantStatus {
+				t.Fatalf("execution = %s, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUpcomingAndSkipExecution(t *testing.T) {
+	_, scheduler, clock, address := newTestScheduler(t, NewMemoryStore(), Options{})
+	hour := func(n int) time.Time { return start.Add(time.Duration(n) * time.Hour) }
+	r, err := scheduler.ScheduleRecurring(Recurring{Payment: Payment{WalletName: "w", Address: address, Amount: 10_000}, Cron: "0 * * * *", End: hour(4)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	upcoming, err := scheduler.Upcoming(r.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first execution is at the start.
+	if len(upcoming) != 5 || !upcoming[0].Equal(hour(0)) || !upcoming[4].Equal(hour(4)) {
+		t.Fatalf("Upcoming() = %v, want the 5 executions until the end", upcoming)
+	}
+
+	if _, err := scheduler.SkipExecution(r.ID, hour(2).Add(time.Minute)); err == nil {
+		t.Fatal("SkipExecution() of a time without execution = nil error")
+	}
+	if _, err := scheduler.SkipExecution(r.ID, hour(2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scheduler.SkipExecution(r.ID, hour(2)); err == nil {
+		t.Fatal("SkipExecution() of a skipped execution = nil error")
+	}
+	if r, err = scheduler.SkipExecution(r.ID, hour(0)); err != nil || !r.Next.Equal(hour(1)) {
+		t.Fatalf("SkipExecution() of the next execution = %v, %v, want the next execution after the skipped ones", r.Next, err)
+	}
+
+	clock.Set(hour(4))
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := executions(t, scheduler, r.ID); len(got) != 3 || got[hour(1)] == "" || got[hour(3)] == "" || got[hour(4)] == "" {
+		t.Fatalf("executions = %v, want the executions which were not skipped", got)
+	}
+	if r, _ := scheduler.GetRecurring(r.ID); r.Status != RecurringFinished {
+		t.Fatalf("status after the end = %s, want finished", r.Status)
+	}
+	if upcoming, err := scheduler.Upcoming(r.ID, 10); err != nil || upcoming != nil {
+		t.Fatalf("Upcoming() of a finished recurring payment = %v, %v", upcoming, err)
+	}
+}
+
+func TestSkipExecutionMovedByClockChange(t *testing.T) {
+	_, scheduler, clock, address := newTestScheduler(t, NewMemoryStore(), Options{})
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Set(time.Date(2024, 3, 30, 12, 0, 0, 0, berlin))
+	r, err := scheduler.ScheduleRecurring(Recurring{Payment: Payment{WalletName: "w", Address: address, Amount: 10_000}, Cron: "30 2 * * *", Location: "Europe/Berlin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2:30 does not exist on March 31, the execution is at 3:30.
+	moved := time.Date(2024, 3, 31, 3, 30, 0, 0, berlin)
+	if !r.Next.Equal(moved) {
+		t.Fatalf("next execution = %v, want %v", r.Next, moved)
+	}
+	if r, err = scheduler.SkipExecution(r.ID, moved); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 4, 1, 2, 30, 0, 0, berlin); !r.Next.Equal(want) {
+		t.Fatalf("next execution = %v, want %v", r.Next, want)
+	}
+}
+
+func TestCancelRecurring(t *testing.T) {
+	_, scheduler, clock, address := newTestScheduler(t, NewMemoryStore(), Options{})
+	// The first execution waits for a balance the wallet does not have.
+	r, err := scheduler.ScheduleRecurring(Recurring{Payment: Payment{WalletName: "w", Address: address, Amount: 2_000_000}, Cron: "@hourly", Policy: PolicyRetry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(30 * time.Minute)
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r, err = scheduler.CancelRecurring(r.ID); err != nil || r.Status != RecurringCancelled {
+		t.Fatalf("CancelRecurring() = %s, %v", r.Status, err)
+	}
+	got := executions(t, scheduler, r.ID)
+	if len(got) != 1 {
+		t.Fatalf("executions = %v, want the waiting execution", got)
+	}
+	for at, status := range got {
+		if status != StatusCancelled {
+			t.Fatalf("execution at %v = %s, want cancelled", at, status)
+		}
+	}
+	if _, err := scheduler.CancelRecurring(r.ID); err == nil {
+		t.Fatal("CancelRecurring() of a cancelled recurring payment = nil error")
+	}
+	if _, err := scheduler.SkipExecution(r.ID, r.Next); err == nil {
+		t.Fatal("SkipExecution() of a cancelled recurring payment = nil error")
+	}
+	if _, err := scheduler.GetRecurring("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetRecurring(unknown) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStoreRecurring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, scheduler, _, address := newTestScheduler(t, store, Options{})
+	r, err := scheduler.ScheduleRecurring(Recurring{Payment: Payment{WalletName: "w", Address: address, Amount: 1000, Method: MethodPayInCoinJoin}, Cron: "@daily", Policy: PolicyRetry, RetryWindow: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recurrings, err := reopened.Recurrings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recurrings) != 1 {
+		t.Fatalf("reopened recurring payments = %+v", recurrings)
+	}
+	got := recurrings[0]
+	if got.ID != r.ID || got.Cron != "@daily" || got.Payment.Funds != FundsPrivate || got.RetryWindow != time.Hour || !got.Next.Equal(r.Next) {
+		t.Fatalf("reopened recurring payment = %+v, want %+v", got, r)
+	}
+	if _, err := reopened.Recurring("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Recurring(unknown) = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,461 @@
+// Package schedule executes future-dated payments: a payment is kept until its time has come and the wallet has enough confirmed (or private) balance, then it is sent or paid in a coinjoin, and the outcome is published on an event bus.
+//
+//	s := schedule.New(client, store, schedule.Options{Password: passwords, Bus: bus})
+//	p, err := s.Schedule(schedule.Payment{WalletName: "w", Address: addr, Amount: 100_000, At: due})
+//	go s.Run(ctx)
+//
+// Recurring payments (payroll, DCA) are scheduled with a cron expression (see ScheduleRecurring); each execution becomes a payment of its own when it is due.
+//
+// Passwords are not persisted: they are asked from Options.Password when a payment is executed.
+package schedule
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Method is the way a payment is executed.
+type Method string
+
+const (
+	// MethodSend sends the payment in its own transaction.
+	MethodSend Method = "send"
+	// MethodPayInCoinJoin registers the payment to be paid in a coinjoin.
+	MethodPayInCoinJoin Method = "payincoinjoin"
+)
+
+// Funds is the balance which must cover a payment before it is executed.
+type Funds string
+
+const (
+	// FundsConfirmed waits for enough confirmed balance.
+	FundsConfirmed Funds = "confirmed"
+	// FundsPrivate waits for enough private balance (coins which reached the anonymity score target).
+	FundsPrivate Funds = "private"
+)
+
+// Status is the status of a scheduled payment.
+type Status string
+
+const (
+	// StatusPending is the status of a payment waiting for its time or the balance.
+	StatusPending Status = "pending"
+	// StatusExecuting is the status of a payment being executed. Payments found executing when the scheduler starts were interrupted and are marked as failed, since they may have been paid.
+	StatusExecuting Status = "executing"
+	// StatusExecuted is the status of a paid payment.
+	StatusExecuted Status = "executed"
+	// StatusFailed is the status of a payment rejected by the wallet, expired or interrupted.
+	StatusFailed Status = "failed"
+	// StatusCancelled is the status of a cancelled payment.
+	StatusCancelled Status = "cancelled"
+)
+
+// Payment is a scheduled payment.
+type Payment struct {
+	ID         string         `json:"id"`
+	WalletName string         `json:"walletName"`
+	Address    wasabi.Address `json:"address"`
+	Amount     wasabi.Amount  `json:"amount"`
+	Label      string         `json:"label,omitempty"`
+	// Method is the way the payment is executed. Default is MethodSend.
+	Method Method `json:"method"`
+	// Funds is the balance which must cover the amount. Default is FundsConfirmed for sends and FundsPrivate for payments in coinjoin.
+	Funds Funds `json:"funds"`
+	// FeeTarget and FeeRate set the fee of sends (see wasabi.SendRequest). If both are zero, DefaultFeeTarget is used.
+	FeeTarget wasabi.FeeTarget `json:"feeTarget,omitempty"`
+	FeeRate   float64          `json:"feeRate,omitempty"`
+	// MaxFeeRate is the ceiling of the fee rate of a send in sat/vB: while the fee rate (or the estimation for the fee target) is higher, the payment waits like for the balance. Zero disables the ceiling.
+	MaxFeeRate float64 `json:"maxFeeRate,omitempty"`
+	// RecurringID is the id of the recurring payment the payment is an execution of.
+	RecurringID string `json:"recurringId,omitempty"`
+	// At is the time the payment is due.
+	At time.Time `json:"at"`
+	// Expires is the time after which a payment still waiting for the balance fails. Zero waits forever.
+	Expires time.Time `json:"expires,omitempty"`
+
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ExecutedAt is the time the payment was executed or failed.
+	ExecutedAt time.Time `json:"executedAt,omitempty"`
+	// TxID is the transaction id of a sent payment.
+	TxID wasabi.TxID `json:"txid,omitempty"`
+	// PaymentID is the id of a payment in coinjoin (see wasabi.Client.ListPaymentsInCoinJoin).
+	PaymentID string `json:"paymentId,omitempty"`
+	// Error is the reason of a failed payment.
+	Error string `json:"error,omitempty"`
+}
+
+// DefaultFeeTarget is the confirmation target of sends without fee target and fee rate.
+const DefaultFeeTarget = wasabi.FeeTargetHour
+
+// Event types of the outcomes of scheduled payments.
+const (
+	// EventPaymentExecuted is the type of PaymentExecutedEvent.
+	EventPaymentExecuted wasabi.EventType = "ScheduledPaymentExecuted"
+	// EventPaymentFailed is the type of PaymentFailedEvent.
+	EventPaymentFailed wasabi.EventType = "ScheduledPaymentFailed"
+)
+
+// PaymentExecutedEvent is published when a scheduled payment is executed.
+type PaymentExecutedEvent struct {
+	Payment Payment
+}
+
+// Type implements wasabi.Event.
+func (PaymentExecutedEvent) Type() wasabi.EventType { return EventPaymentExecuted }
+
+// PaymentFailedEvent is published when a scheduled payment fails.
+type PaymentFailedEvent struct {
+	Payment Payment
+	Err     error
+}
+
+// Type implements wasabi.Event.
+func (PaymentFailedEvent) Type() wasabi.EventType { return EventPaymentFailed }
+
+// Options holds the options of a Scheduler.
+type Options struct {
+	// Password returns the password of a wallet when one of its payments is executed. Required.
+	Password func(walletName string) (string, error)
+	// Bus receives the outcomes of the payments. Optional.
+	Bus *wasabi.EventBus
+	// Interval is the interval between the checks of the due payments. Default is wasabi.DefaultPollInterval.
+	Interval time.Duration
+	// OnError is called with the errors of the checks which are retried at the next check: unreachable daemon, failing store.
+	OnError func(err error)
+	// Clock provides the time of the checks. Default is wasabi.SystemClock.
+	Clock wasabi.Clock
+}
+
+// Scheduler keeps the scheduled payments and executes the due ones.
+type Scheduler struct {
+	client wasabi.Client
+	store  Store
+	opts   Options
+	mutex  sync.Mutex
+}
+
+// New creates a scheduler executing the payments with the client and keeping them in the store.
+func New(c wasabi.Client, store Store, opts Options) *Scheduler {
+	if opts.Interval <= 0 {
+		opts.Interval = wasabi.DefaultPollInterval
+	}
+	if opts.Clock == nil {
+		opts.Clock = wasabi.SystemClock
+	}
+	return &Scheduler{client: c, store: store, opts: opts}
+}
+
+// Schedule validates and stores a payment. Its id, status and creation time are set by the scheduler.
+func (s *Scheduler) Schedule(p Payment) (Payment, error) {
+	if p.At.IsZero() {
+		return Payment{}, fmt.Errorf("time must not be zero")
+	}
+	if err := validate(&p); err != nil {
+		return Payment{}, err
+	}
+	p.RecurringID = ""
+	if err := s.add(&p); err != nil {
+		return Payment{}, err
+	}
+	return p, nil
+}
+
+// add stores a new pending payment.
+func (s *Scheduler) add(p *Payment) error {
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	p.ID = id
+	p.Status = StatusPending
+	p.CreatedAt = s.opts.Clock.Now()
+	p.ExecutedAt, p.TxID, p.PaymentID, p.Error = time.Time{}, "", "", ""
+	return s.store.PutPayment(*p)
+}
+
+// validate checks the payment and sets the defaults of its method and funds.
+func validate(p *Payment) error {
+	if p.WalletName == "" {
+		return fmt.Errorf("wallet name must not be empty")
+	}
+	address, err := wasabi.ParseAddress(string(p.Address))
+	if err != nil {
+		return err
+	}
+	p.Address = address
+	if p.Amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if p.Method == "" {
+		p.Method = MethodSend
+	}
+	if p.Method != MethodSend && p.Method != MethodPayInCoinJoin {
+		return fmt.Errorf("unknown method %q", p.Method)
+	}
+	if p.Funds == "" {
+		p.Funds = FundsConfirmed
+		if p.Method == MethodPayInCoinJoin {
+			p.Funds = FundsPrivate
+		}
+	}
+	if p.Funds != FundsConfirmed && p.Funds != FundsPrivate {
+		return fmt.Errorf("unknown funds %q", p.Funds)
+	}
+	if p.FeeTarget != 0 && p.FeeRate != 0 {
+		return fmt.Errorf("fee target and fee rate must not be set both")
+	}
+	if p.FeeTarget != 0 {
+		if err := p.FeeTarget.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.MaxFeeRate < 0 {
+		return fmt.Errorf("max fee rate must not be negative")
+	}
+	return nil
+}
+
+// Cancel cancels a pending payment.
+func (s *Scheduler) Cancel(id string) (Payment, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	p, err := s.store.Payment(id)
+	if err != nil {
+		return Payment{}, err
+	}
+	if p.Status != StatusPending {
+		return p, fmt.Errorf("payment %s is %s, not pending", id, p.Status)
+	}
+	p.Status = StatusCancelled
+	return p, s.store.PutPayment(p)
+}
+
+// Get returns the payment, or ErrNotFound.
+func (s *Scheduler) Get(id string) (Payment, error) {
+	return s.store.Payment(id)
+}
+
+// List returns all payments ordered by their time.
+func (s *Scheduler) List() ([]Payment, error) {
+	return s.store.Payments()
+}
+
+// Run marks the interrupted payments as failed, then checks the due payments until the context is done.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.opts.Password == nil {
+		return fmt.Errorf("password must not be nil")
+	}
+	if err := s.recover(ctx); err != nil {
+		return err
+	}
+	ticker := s.opts.Clock.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Check(ctx); err != nil {
+			s.onError(ctx, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// recover marks the payments interrupted while executing as failed.
+func (s *Scheduler) recover(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	payments, err := s.store.Payments()
+	if err != nil {
+		return err
+	}
+	for _, p := range payments {
+		if p.Status == StatusExecuting {
+			s.fail(ctx, p, errors.New("interrupted while executing: check the wallet history before scheduling it again"))
+		}
+	}
+	return nil
+}
+
+// Check creates the due executions of the recurring payments and executes the due payments whose wallet has enough balance. Run calls it at each interval.
+func (s *Scheduler) Check(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := s.opts.Clock.Now()
+	var errs []error
+	if err := s.materialize(ctx, now); err != nil {
+		errs = append(errs, err)
+	}
+	payments, err := s.store.Payments()
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	c := s.client.WithContext(ctx)
+	balances := map[string]*wasabi.Balance{}
+	feeRates := map[wasabi.FeeTarget]float64{}
+	for _, p := range payments {
+		if p.Status != StatusPending || p.At.After(now) {
+			continue
+		}
+		balance, ok := balances[p.WalletName]
+		if !ok {
+			b, err := wasabi.GetBalance(c, p.WalletName)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("balance of wallet %s: %w", p.WalletName, err))
+				continue
+			}
+			balance = &b
+			balances[p.WalletName] = balance
+		}
+		available := balance.Confirmed
+		if p.Funds == FundsPrivate {
+			available = balance.Private
+		}
+		if available < p.Amount {
+			if !p.Expires.IsZero() && !now.Before(p.Expires) {
+				s.fail(ctx, p, fmt.Errorf("expired with %v of %s balance available", available, p.Funds))
+			}
+			continue
+		}
+		if p.Method == MethodSend && p.MaxFeeRate > 0 {
+			rate, err := s.feeRate(c, p, feeRates)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if rate > p.MaxFeeRate {
+				if !p.Expires.IsZero() && !now.Before(p.Expires) {
+					s.fail(ctx, p, fmt.Errorf("expired with a fee rate of %g sat/vB above the ceiling of %g sat/vB", rate, p.MaxFeeRate))
+				}
+				continue
+			}
+		}
+		if err := s.execute(ctx, c, p); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// The coins of the payment are spent, so later payments of the wallet must not count them.
+		balance.Confirmed -= p.Amount
+		balance.Private -= p.Amount
+	}
+	return errors.Join(errs...)
+}
+
+// feeRate returns the fee rate of the send of the payment, caching the estimations of the check.
+func (s *Scheduler) feeRate(c wasabi.Client, p Payment, cache map[wasabi.FeeTarget]float64) (float64, error) {
+	if p.FeeRate != 0 {
+		return p.FeeRate, nil
+	}
+	target := p.FeeTarget
+	if target == 0 {
+		target = DefaultFeeTarget
+	}
+	if rate, ok := cache[target]; ok {
+		return rate, nil
+	}
+	rate, err := wasabi.EstimateFeeRate(c, target)
+	if err != nil {
+		return 0, fmt.Errorf("fee rate of payment %s: %w", p.ID, err)
+	}
+	cache[target] = rate
+	return rate, nil
+}
+
+// execute pays the payment. Errors which may be transient (unreachable daemon, missing password, failing store) are returned and the payment is retried at the next check; rejections fail the payment.
+func (s *Scheduler) execute(ctx context.Context, c wasabi.Client, p Payment) error {
+	password, err := s.opts.Password(p.WalletName)
+	if err != nil {
+		return fmt.Errorf("password of wallet %s: %w", p.WalletName, err)
+	}
+	p.Status = StatusExecuting
+	if err := s.store.PutPayment(p); err != nil {
+		return err
+	}
+	switch p.Method {
+	case MethodPayInCoinJoin:
+		p.PaymentID, err = c.PayInCoinJoin(p.WalletName, p.Address, p.Amount, password)
+	default:
+		req := wasabi.SendRequest{
+			Payments:  []wasabi.Payment{{SendTo: p.Address, Amount: p.Amount, Label: p.Label}},
+			FeeTarget: p.FeeTarget,
+			FeeRate:   p.FeeRate,
+			Password:  password,
+		}
+		if req.FeeTarget == 0 && req.FeeRate == 0 {
+			req.FeeTarget = DefaultFeeTarget
+		}
+		var resp wasabi.SendResponse
+		resp, err = c.Send(p.WalletName, req)
+		p.TxID = resp.TransactionID
+	}
+	if err != nil {
+		if wasabi.Classify(err) == wasabi.ErrorCategoryConnection || ctx.Err() != nil {
+			p.Status = StatusPending
+			if storeErr := s.store.PutPayment(p); storeErr != nil {
+				return errors.Join(err, storeErr)
+			}
+			return fmt.Errorf("payment %s: %w", p.ID, err)
+		}
+		s.fail(ctx, p, err)
+		return nil
+	}
+	p.Status = StatusExecuted
+	p.ExecutedAt = s.opts.Clock.Now()
+	if err := s.store.PutPayment(p); err != nil {
+		return fmt.Errorf("payment %s executed but not recorded: %w", p.ID, err)
+	}
+	s.publish(ctx, PaymentExecutedEvent{Payment: p})
+	return nil
+}
+
+// fail marks the payment as failed and publishes the failure.
+func (s *Scheduler) fail(ctx context.Context, p Payment, err error) {
+	p.Status = StatusFailed
+	p.ExecutedAt = s.opts.Clock.Now()
+	p.Error = err.Error()
+	if storeErr := s.store.PutPayment(p); storeErr != nil {
+		s.onError(ctx, storeErr)
+	}
+	s.publish(ctx, PaymentFailedEvent{Payment: p, Err: err})
+}
+
+func (s *Scheduler) publish(ctx context.Context, event wasabi.Event) {
+	if s.opts.Bus != nil {
+		s.opts.Bus.Publish(ctx, event)
+	}
+}
+
+func (s *Scheduler) onError(ctx context.Context, err error) {
+	if s.opts.OnError != nil && ctx.Err() == nil {
+		s.opts.OnError(err)
+	}
+}
+
+// sortPayments orders the payments by their time, then by their creation.
+func sortPayments(payments []Payment) {
+	sort.Slice(payments, func(i, j int) bool {
+		if !payments[i].At.Equal(payments[j].At) {
+			return payments[i].At.Before(payments[j].At)
+		}
+		return payments[i].CreatedAt.Before(payments[j].CreatedAt)
+	})
+}
+
+// newID returns a random payment id.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate payment id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,255 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi/wasabitest"
+)
+
+var start = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestScheduler starts a fake daemon with the wallet "w" (password "pw") holding 1000000 sat and returns a scheduler of the daemon and the address of the wallet "payee".
+func newTestScheduler(t *testing.T, store Store, opts Options) (*wasabitest.Server, *Scheduler, *wasabitest.FakeClock, wasabi.Address) {
+	t.Helper()
+	s := wasabitest.NewServer(wasabitest.ServerOptions{})
+	t.Cleanup(s.Close)
+	if err := s.AddWallet("w", "pw"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddWallet("payee", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Fund("w", 1_000_000, "salary"); err != nil {
+		t.Fatal(err)
+	}
+	s.Mine(1)
+	address, err := s.Client().GetNewAddress("payee", "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := wasabitest.NewFakeClock(start)
+	opts.Clock = clock
+	if opts.Password == nil {
+		opts.Password = func(string) (string, error) { return "pw", nil }
+	}
+	return s, New(s.Client(), store, opts), clock, address.Address
+}
+
+func TestScheduleValidation(t *testing.T) {
+	_, scheduler, _, address := newTestScheduler(t, NewMemoryStore(), Options{})
+	tests := []struct {
+		name    string
+		payment Payment
+		wantErr string
+	}{
+		{name: "zero time", payment: Payment{WalletName: "w", Address: address, Amount: 1000}, wantErr: "time must not be zero"},
+		{name: "no wallet", payment: Payment{Address: address, Amount: 1000, At: start}, wantErr: "wallet name"},
+		{name: "invalid address", payment: Payment{WalletName: "w", Address: "nope", Amount: 1000, At: start}, wantErr: "nope"},
+		{name: "zero amount", payment: Payment{WalletName: "w", Address: address, At: start}, wantErr: "amount must be positive"},
+		{name: "unknown method", payment: Payment{WalletName: "w", Address: address, Amount: 1000, At: start, Method: "lightning"}, wantErr: "unknown method"},
+		{name: "unknown funds", payment: Payment{WalletName: "w", Address: address, Amount: 1000, At: start, Funds: "borrowed"}, wantErr: "unknown funds"},
+		{name: "fee target and rate", payment: Payment{WalletName: "w", Address: address, Amount: 1000, At: start, FeeTarget: wasabi.FeeTargetHour, FeeRate: 5}, wantErr: "must not be set both"},
+		{name: "negative fee rate ceiling", payment: Payment{WalletName: "w", Address: address, Amount: 1000, At: start, MaxFeeRate: -1}, wantErr: "max fee rate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := scheduler.Schedule(tt.payment); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Schedule() = %v, want an error with %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	p, err := scheduler.Schedule(Payment{WalletName: "w", Address: address, Amount: 1000, At: start, Method: MethodPayInCoinJoin, RecurringID: "r"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID == "" || p.Status != StatusPending || p.Funds != FundsPrivate || p.RecurringID != "" || !p.CreatedAt.Equal(start) {
+		t.Fatalf("scheduled payment = %+v", p)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	bus := wasabi.NewEventBus()
+	sub := bus.Subscribe(wasabi.SubscribeOptions{}, EventPaymentExecuted, EventPaymentFailed)
+	defer sub.Close()
+	s, scheduler, clock, address := newTestScheduler(t, NewMemoryStore(), Options{Bus: bus})
+	due, err := scheduler.Schedule(Payment{WalletName: "w", Address: address, Amount: 600_000, Label: "rent", At: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second payment, created later, is not covered by the balance left by the first one.
+	clock.Advance(time.Minute)
+	uncovered, err := scheduler.Schedule(Payment{WalletName: "w", Address: address, Amount: 600_000, At: start.Add(time.Hour), Expires: start.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := scheduler.Get(due.ID); p.Status != StatusPending {
+		t.Fatalf("status before the time of the payment = %s, want pending", p.Status)
+	}
+
+	clock.Advance(time.Hour)
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	p, err := scheduler.Get(due.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Status != StatusExecuted || p.TxID == "" || !p.ExecutedAt.Equal(clock.Now()) {
+		t.Fatalf("due payment = %+v, want it executed", p)
+	}
+	if event := <-sub.Events(); event.(PaymentExecutedEvent).Payment.ID != due.ID {
+		t.Fatalf("event = %+v, want the execution of the due payment", event)
+	}
+	history, err := s.Client().GetHistory("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].TxID != p.TxID || history[0].Label != "rent" {
+		t.Fatalf("history = %+v, want the send of the payment", history)
+	}
+	if p, _ := scheduler.Get(uncovered.ID); p.Status != StatusPending {
+		t.Fatalf("uncovered payment = %s, want pending", p.Status)
+	}
+
+	clock.Advance(time.Hour)
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := scheduler.Get(uncovered.ID); p.Status != StatusFailed || !strings.Contains(p.Error, "expired") {
+		t.Fatalf("uncovered payment past its expiry = %s (%s), want failed", p.Status, p.Error)
+	}
+	if event := <-sub.Events(); event.Type() != EventPaymentFailed {
+		t.Fatalf("event = %+v, want the failure of the uncovered payment", event)
+	}
+}
+
+func TestCheckMaxFeeRate(t *testing.T) {
+	s, scheduler, _, address := newTestScheduler(t, NewMemoryStore(), Options{})
+	// DefaultFeeTarget is estimated at 12 sat/vB by the fake daemon.
+	p, err := scheduler.Schedule(Payment{WalletName: "w", Address: address, Amount: 100_000, At: start, MaxFeeRate: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := scheduler.Get(p.ID); p.Status != StatusPending {
+		t.Fatalf("status above the fee rate ceiling = %s, want pending", p.Status)
+	}
+
+	s.SetFeeRates(wasabi.GetFeeRatesResponse{"2": 10, "6": 8, "1008": 1})
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := scheduler.Get(p.ID); p.Status != StatusExecuted {
+		t.Fatalf("status below the fee rate ceiling = %s (%s), want executed", p.Status, p.Error)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	passwordErr := errors.New("vault sealed")
+	password := ""
+	_, scheduler, _, address := newTestScheduler(t, NewMemoryStore(), Options{Password: func(string) (string, error) {
+		if password == "" {
+			return "", passwordErr
+		}
+		return password, nil
+	}})
+
+	// A missing password is retried at the next check.
+	retried, err := scheduler.Schedule(Payment{WalletName: "w", Address: address, Amount: 100_000, At: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Check(context.Background()); !errors.Is(err, passwordErr) {
+		t.Fatalf("Check() = %v, want the password error", err)
+	}
+	if p, _ := scheduler.Get(retried.ID); p.Status != StatusPending {
+		t.Fatalf("status without password = %s, want pending", p.Status)
+	}
+
+	// A rejection of the wallet fails the payment.
+	password = "wrong"
+	if err := scheduler.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := scheduler.Get(retried.ID); p.Status != StatusFailed || p.Error == "" {
+		t.Fatalf("status with a wrong password = %s, want failed", p.Status)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	_, scheduler, _, address := newTestScheduler(t, NewMemoryStore(), Options{})
+	p, err := scheduler.Schedule(Payment{WalletName: "w", Address: address, Amount: 100_000, At: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := scheduler.Cancel(p.ID); err != nil || p.Status != StatusCancelled {
+		t.Fatalf("Cancel() = %s, %v", p.Status, err)
+	}
+	if _, err := scheduler.Cancel(p.ID); err == nil {
+		t.Fatal("Cancel() of a cancelled payment = nil error")
+	}
+	if _, err := scheduler.Cancel("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Cancel(unknown) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRunRecoversInterruptedPayments(t *testing.T) {
+	store := NewMemoryStore()
+	_, scheduler, _, address := newTestScheduler(t, store, Options{})
+	store.PutPayment(Payment{ID: "interrupted", WalletName: "w", Address: address, Amount: 100_000, At: start, Status: StatusExecuting})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := scheduler.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := scheduler.Get("interrupted"); p.Status != StatusFailed || !strings.Contains(p.Error, "interrupted") {
+		t.Fatalf("interrupted payment = %s (%s), want failed", p.Status, p.Error)
+	}
+
+	if err := New(nil, store, Options{}).Run(ctx); err == nil {
+		t.Fatal("Run() without password = nil error")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, scheduler, _, address := newTestScheduler(t, store, Options{})
+	later, err := scheduler.Schedule(Payment{WalletName: "w", Address: address, Amount: 1000, At: start.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sooner, err := scheduler.Schedule(Payment{WalletName: "w", Address: address, Amount: 1000, At: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payments, err := reopened.Payments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 2 || payments[0].ID != sooner.ID || payments[1].ID != later.ID {
+		t.Fatalf("reopened payments = %+v, want both payments ordered by their time", payments)
+	}
+}
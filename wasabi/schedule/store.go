@@ -0,0 +1,207 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by stores for unknown payments and recurring payments.
+var ErrNotFound = errors.New("payment not found")
+
+// Store persists the payments and recurring payments of a Scheduler.
+type Store interface {
+	// Payment returns the payment with the id, or ErrNotFound.
+	Payment(id string) (Payment, error)
+	// PutPayment creates or replaces the payment with the same id.
+	PutPayment(p Payment) error
+	// Payments returns all payments ordered by their time.
+	Payments() ([]Payment, error)
+	// Recurring returns the recurring payment with the id, or ErrNotFound.
+	Recurring(id string) (Recurring, error)
+	// PutRecurring creates or replaces the recurring payment with the same id.
+	PutRecurring(r Recurring) error
+	// Recurrings returns all recurring payments ordered by their creation time.
+	Recurrings() ([]Recurring, error)
+}
+
+// data is the content of a store.
+type data struct {
+	Payments  map[string]Payment   `json:"payments"`
+	Recurring map[string]Recurring `json:"recurring"`
+}
+
+// MemoryStore is a Store keeping the payments in memory.
+type MemoryStore struct {
+	mutex sync.RWMutex
+	data  data
+}
+
+// NewMemoryStore creates an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: data{Payments: map[string]Payment{}, Recurring: map[string]Recurring{}}}
+}
+
+// Payment implements Store.
+func (s *MemoryStore) Payment(id string) (Payment, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	p, ok := s.data.Payments[id]
+	if !ok {
+		return Payment{}, ErrNotFound
+	}
+	return p, nil
+}
+
+// PutPayment implements Store.
+func (s *MemoryStore) PutPayment(p Payment) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Payments[p.ID] = p
+	return nil
+}
+
+// Payments implements Store.
+func (s *MemoryStore) Payments() ([]Payment, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	payments := make([]Payment, 0, len(s.data.Payments))
+	for _, p := range s.data.Payments {
+		payments = append(payments, p)
+	}
+	sortPayments(payments)
+	return payments, nil
+}
+
+// Recurring implements Store.
+func (s *MemoryStore) Recurring(id string) (Recurring, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	r, ok := s.data.Recurring[id]
+	if !ok {
+		return Recurring{}, ErrNotFound
+	}
+	return cloneRecurring(r), nil
+}
+
+// PutRecurring implements Store.
+func (s *MemoryStore) PutRecurring(r Recurring) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Recurring[r.ID] = cloneRecurring(r)
+	return nil
+}
+
+// Recurrings implements Store.
+func (s *MemoryStore) Recurrings() ([]Recurring, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	recurrings := make([]Recurring, 0, len(s.data.Recurring))
+	for _, r := range s.data.Recurring {
+		recurrings = append(recurrings, cloneRecurring(r))
+	}
+	sort.Slice(recurrings, func(i, j int) bool {
+		if !recurrings[i].CreatedAt.Equal(recurrings[j].CreatedAt) {
+			return recurrings[i].CreatedAt.Before(recurrings[j].CreatedAt)
+		}
+		return recurrings[i].ID < recurrings[j].ID
+	})
+	return recurrings, nil
+}
+
+// cloneRecurring copies the skipped times, so stored recurring payments are not changed by callers.
+func cloneRecurring(r Recurring) Recurring {
+	r.Skipped = append([]time.Time(nil), r.Skipped...)
+	return r
+}
+
+// FileStore is a Store keeping the payments as JSON in a file. The file is rewritten atomically after each change.
+type FileStore struct {
+	path   string
+	memory *MemoryStore
+	mutex  sync.Mutex
+}
+
+// OpenFileStore opens the payments in the file, which is created on the first change if it does not exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, memory: NewMemoryStore()}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &s.memory.data); err != nil {
+		return nil, err
+	}
+	if s.memory.data.Payments == nil {
+		s.memory.data.Payments = map[string]Payment{}
+	}
+	if s.memory.data.Recurring == nil {
+		s.memory.data.Recurring = map[string]Recurring{}
+	}
+	return s, nil
+}
+
+// Payment implements Store.
+func (s *FileStore) Payment(id string) (Payment, error) {
+	return s.memory.Payment(id)
+}
+
+// PutPayment implements Store.
+func (s *FileStore) PutPayment(p Payment) error {
+	return s.change(func() error { return s.memory.PutPayment(p) })
+}
+
+// Payments implements Store.
+func (s *FileStore) Payments() ([]Payment, error) {
+	return s.memory.Payments()
+}
+
+// Recurring implements Store.
+func (s *FileStore) Recurring(id string) (Recurring, error) {
+	return s.memory.Recurring(id)
+}
+
+// PutRecurring implements Store.
+func (s *FileStore) PutRecurring(r Recurring) error {
+	return s.change(func() error { return s.memory.PutRecurring(r) })
+}
+
+// Recurrings implements Store.
+func (s *FileStore) Recurrings() ([]Recurring, error) {
+	return s.memory.Recurrings()
+}
+
+// change applies the change in memory and writes the file.
+func (s *FileStore) change(apply func() error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := apply(); err != nil {
+		return err
+	}
+	s.memory.mutex.RLock()
+	content, err := json.MarshalIndent(s.memory.data, "", "  ")
+	s.memory.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,103 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// WalletStatePredicate selects wallet states.
+type WalletStatePredicate func(state WalletState) bool
+
+// IsState returns a predicate which selects the given state.
+func IsState(state WalletState) WalletStatePredicate {
+	return func(s WalletState) bool {
+		return s == state
+	}
+}
+
+// IsRunning selects the state of a started wallet.
+func IsRunning(state WalletState) bool {
+	return state.IsRunning()
+}
+
+// IsStopped selects the states of a wallet which is stopped or not initialized.
+func IsStopped(state WalletState) bool {
+	return state.IsStopped()
+}
+
+// IsRunning reports whether the wallet is started, so its coins can be spent and mixed.
+func (s WalletState) IsRunning() bool {
+	return s == WalletStateStarted
+}
+
+// IsStopped reports whether the wallet is stopped or not initialized.
+func (s WalletState) IsStopped() bool {
+	return s == WalletStateStopped || s == WalletStateUninitialized
+}
+
+// IsTransitioning reports whether the wallet is on its way to another state: waiting for its initialization, starting or stopping.
+func (s WalletState) IsTransitioning() bool {
+	switch s {
+	case WalletStateWaitingForInit, WalletStateStarting, WalletStateStopping:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the wallet reached the end of its lifecycle, so it does not change state until it is loaded again.
+func (s WalletState) IsTerminal() bool {
+	return s == WalletStateStopped
+}
+
+// Rank returns the position of the state in the lifecycle of a wallet, from 0 for WalletStateUninitialized to 6 for WalletStateStopped, or -1 for unknown states.
+func (s WalletState) Rank() int {
+	for i, state := range walletStates {
+		if s == state {
+			return i
+		}
+	}
+	return -1
+}
+
+// Before reports whether the state comes before the other one in the lifecycle of a wallet. Unknown states are not ordered.
+func (s WalletState) Before(other WalletState) bool {
+	rank, otherRank := s.Rank(), other.Rank()
+	return rank >= 0 && otherRank >= 0 && rank < otherRank
+}
+
+// AtLeast reports whether the state is the other one or comes after it in the lifecycle of a wallet, e.g. to check that a wallet got at least to WalletStateStarted. Unknown states are not ordered.
+func (s WalletState) AtLeast(other WalletState) bool {
+	rank, otherRank := s.Rank(), other.Rank()
+	return rank >= 0 && otherRank >= 0 && rank >= otherRank
+}
+
+// WaitForWalletStateOptions holds the options of WaitForWalletState.
+type WaitForWalletStateOptions struct {
+	// Interval is the interval between the checks. Default is DefaultPollInterval.
+	Interval time.Duration
+	// OnState is called after each check with the current state of the wallet. Optional.
+	OnState func(state WalletState)
+	// Clock provides the timers of the checks. Default is SystemClock.
+	Clock Clock
+}
+
+// WaitForWalletState waits until the state of the wallet is selected by the predicate (e.g. IsRunning, IsStopped, IsState(WalletStateStarting)). A wallet which is not fully loaded yet is reported as WalletStateStarting. It returns when the state is reached, a call fails or the context is done.
+func WaitForWalletState(ctx context.Context, c Client, walletName string, predicate WalletStatePredicate, opts WaitForWalletStateOptions) error {
+	c = c.WithContext(ctx)
+	return poll(ctx, opts.Clock, opts.Interval, func() (bool, error) {
+		state := WalletStateStarting
+		info, err := c.GetWalletInfo(walletName)
+		switch {
+		case errors.Is(err, ErrorWalletIsNotFullyLoadedYet):
+		case err != nil:
+			return false, err
+		default:
+			state = info.State
+		}
+		if opts.OnState != nil {
+			opts.OnState(state)
+		}
+		return predicate(state), nil
+	})
+}
@@ -0,0 +1,120 @@
+package wasabi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsWindowSize is the number of most recent calls per method used to compute latency percentiles and the error rate.
+const statsWindowSize = 256
+
+// MethodStats holds the statistics of the calls of one method.
+type MethodStats struct {
+	// Calls is the total number of calls since the client was created.
+	Calls uint64
+	// Errors is the total number of failed calls since the client was created.
+	Errors uint64
+	// ErrorRate is the share of failed calls (0..1) among the most recent calls.
+	ErrorRate float64
+	// P50, P90 and P99 are the latency percentiles of the most recent calls.
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	// Max is the highest latency among the most recent calls.
+	Max time.Duration
+	// LastCall is the time of the last call.
+	LastCall time.Time
+}
+
+type callSample struct {
+	duration time.Duration
+	failed   bool
+}
+
+// methodStats is a ring buffer of the most recent calls of a method.
+type methodStats struct {
+	calls    uint64
+	errors   uint64
+	samples  [statsWindowSize]callSample
+	next     int
+	count    int
+	lastCall time.Time
+}
+
+// callStats collects the statistics of the calls of a client.
+type callStats struct {
+	mutex   sync.Mutex
+	methods map[Method]*methodStats
+}
+
+func newCallStats() *callStats {
+	return &callStats{methods: map[Method]*methodStats{}}
+}
+
+func (s *callStats) record(method Method, duration time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	m, ok := s.methods[method]
+	if !ok {
+		m = &methodStats{}
+		s.methods[method] = m
+	}
+	m.calls++
+	if err != nil {
+		m.errors++
+	}
+	m.samples[m.next] = callSample{duration: duration, failed: err != nil}
+	m.next = (m.next + 1) % statsWindowSize
+	if m.count < statsWindowSize {
+		m.count++
+	}
+	m.lastCall = time.Now()
+}
+
+func (s *callStats) snapshot() map[Method]MethodStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	result := make(map[Method]MethodStats, len(s.methods))
+	for method, m := range s.methods {
+		durations := make([]time.Duration, m.count)
+		failed := 0
+		for i := 0; i < m.count; i++ {
+			durations[i] = m.samples[i].duration
+			if m.samples[i].failed {
+				failed++
+			}
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		stats := MethodStats{
+			Calls:    m.calls,
+			Errors:   m.errors,
+			LastCall: m.lastCall,
+		}
+		if m.count > 0 {
+			stats.ErrorRate = float64(failed) / float64(m.count)
+			stats.P50 = percentile(durations, 0.5)
+			stats.P90 = percentile(durations, 0.9)
+			stats.P99 = percentile(durations, 0.99)
+			stats.Max = durations[len(durations)-1]
+		}
+		result[method] = stats
+	}
+	return result
+}
+
+// percentile returns the p-th percentile (nearest rank) of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func (c *client) Stats() map[Method]MethodStats {
+	return c.stats.snapshot()
+}
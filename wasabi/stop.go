@@ -0,0 +1,78 @@
+package wasabi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultStopTimeout is how long StopAndWait waits for the daemon to exit if no timeout is given.
+const DefaultStopTimeout = 2 * time.Minute
+
+// StopPhase is a phase of the shutdown of the daemon reported by StopAndWait.
+type StopPhase string
+
+const (
+	// StopPhaseRequested is reported once the daemon accepted the stop request (or dropped the connection while handling it).
+	StopPhaseRequested StopPhase = "requested"
+	// StopPhaseWaiting is reported after each check finding the daemon still reachable.
+	StopPhaseWaiting StopPhase = "waiting"
+	// StopPhaseStopped is reported once the daemon does not accept connections anymore.
+	StopPhaseStopped StopPhase = "stopped"
+)
+
+// StopProgress is the progress of the shutdown of the daemon.
+type StopProgress struct {
+	Phase StopPhase
+	// Elapsed is the time since the stop request was sent.
+	Elapsed time.Duration
+}
+
+// StopAndWaitOptions holds the options of StopAndWait.
+type StopAndWaitOptions struct {
+	// Timeout is how long to wait for the daemon to exit. Default is DefaultStopTimeout.
+	Timeout time.Duration
+	// Interval is the interval between the checks. Default is one second.
+	Interval time.Duration
+	// OnProgress is called when the stop request is accepted, after each check and once the daemon exited. Optional.
+	OnProgress func(progress StopProgress)
+	// Clock provides the timers of the checks. Default is SystemClock.
+	Clock Clock
+}
+
+// StopAndWait stops the daemon and waits until it exits, i.e. until its rpc port refuses connections. Stop returns as soon as the daemon accepted the request, while the daemon keeps shutting down its wallets for a while. It returns an error if the daemon is still reachable after the timeout, a call fails or the context is done.
+func StopAndWait(ctx context.Context, c Client, opts StopAndWaitOptions) error {
+	if opts.Clock == nil {
+		opts.Clock = SystemClock
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultStopTimeout
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	start := opts.Clock.Now()
+	progress := func(phase StopPhase) {
+		if opts.OnProgress != nil {
+			opts.OnProgress(StopProgress{Phase: phase, Elapsed: opts.Clock.Now().Sub(start)})
+		}
+	}
+	// The daemon may exit before its response is sent, so a connection error does not mean it was not stopped.
+	var connectionErr *ConnectionError
+	if err := c.WithContext(ctx).Stop(); err != nil && !errors.As(err, &connectionErr) {
+		return err
+	}
+	progress(StopPhaseRequested)
+	return poll(ctx, opts.Clock, opts.Interval, func() (bool, error) {
+		if !c.IsWasabiWalletUp() {
+			progress(StopPhaseStopped)
+			return true, nil
+		}
+		if elapsed := opts.Clock.Now().Sub(start); elapsed >= opts.Timeout {
+			return false, fmt.Errorf("daemon still running %v after the stop request", elapsed)
+		}
+		progress(StopPhaseWaiting)
+		return false, nil
+	})
+}
@@ -0,0 +1,252 @@
+package wasabi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// resultWriter is the out of CallRaw: the result of the response is copied to w as it is read instead of being decoded.
+type resultWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (rw *resultWriter) Write(p []byte) (int, error) {
+	n, err := rw.w.Write(p)
+	if err != nil && rw.err == nil {
+		rw.err = err
+	}
+	return n, err
+}
+
+func (c *client) CallRaw(walletName string, method string, params interface{}, w io.Writer) error {
+	if method == "" {
+		return fmt.Errorf("method must not be empty")
+	}
+	if w == nil {
+		return fmt.Errorf("writer must not be nil")
+	}
+	// A result partially written to w cannot be taken back, so the call is neither retried nor hedged.
+	clone := *c
+	clone.ctx = WithoutRetry(c.ctx)
+	return clone.do(Method(method), walletName, params, &resultWriter{w: w})
+}
+
+// streamClientResponse reads the response of a client request and copies its result to w without decoding it. Only the other members of the response (id, error) are held in memory. A null result is written as null unless the response holds an error.
+func streamClientResponse(r io.Reader, w io.Writer) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	if err := expectJSONByte(br, '{'); err != nil {
+		return err
+	}
+	var (
+		rpcErr    json.RawMessage
+		hasResult bool
+	)
+	for first := true; ; first = false {
+		b, err := nextJSONByte(br)
+		if err != nil {
+			return err
+		}
+		if b == '}' {
+			break
+		}
+		if !first {
+			if b != ',' {
+				return fmt.Errorf("invalid response: unexpected %q after a member", b)
+			}
+			if b, err = nextJSONByte(br); err != nil {
+				return err
+			}
+		}
+		if b != '"' {
+			return fmt.Errorf("invalid response: unexpected %q instead of a member name", b)
+		}
+		var name bytes.Buffer
+		name.WriteByte('"')
+		if err := copyJSONString(br, &name); err != nil {
+			return err
+		}
+		var key string
+		if err := json.Unmarshal(name.Bytes(), &key); err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		if err := expectJSONByte(br, ':'); err != nil {
+			return err
+		}
+		switch key {
+		case "result":
+			if b, err = peekJSONByte(br); err != nil {
+				return err
+			}
+			if b == 'n' {
+				err = copyJSONValue(br, discardBytes{})
+			} else {
+				hasResult = true
+				err = copyJSONValue(br, bw)
+			}
+		case "error":
+			var value bytes.Buffer
+			err = copyJSONValue(br, &value)
+			rpcErr = value.Bytes()
+		default:
+			err = copyJSONValue(br, discardBytes{})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if rpcErr != nil && string(rpcErr) != "null" {
+		jsonErr := &RPCError{}
+		if err := json.Unmarshal(rpcErr, jsonErr); err != nil {
+			return &RPCError{
+				Code:    E_SERVER,
+				Message: string(rpcErr),
+			}
+		}
+		return jsonErr
+	}
+	if !hasResult {
+		_, _ = bw.WriteString("null")
+	}
+	return bw.Flush()
+}
+
+// nextJSONByte reads the next byte which is not whitespace.
+func nextJSONByte(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return b, nil
+	}
+}
+
+// peekJSONByte skips whitespace and returns the next byte without reading it.
+func peekJSONByte(r *bufio.Reader) (byte, error) {
+	b, err := nextJSONByte(r)
+	if err != nil {
+		return 0, err
+	}
+	return b, r.UnreadByte()
+}
+
+// expectJSONByte reads the next byte which is not whitespace and returns an error if it is not want.
+func expectJSONByte(r *bufio.Reader, want byte) error {
+	b, err := nextJSONByte(r)
+	if err != nil {
+		return err
+	}
+	if b != want {
+		return fmt.Errorf("invalid response: unexpected %q instead of %q", b, want)
+	}
+	return nil
+}
+
+// copyJSONValue copies the next JSON value from r to w, checking only its structure.
+func copyJSONValue(r *bufio.Reader, w io.ByteWriter) error {
+	b, err := nextJSONByte(r)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteByte(b); err != nil {
+		return err
+	}
+	switch b {
+	case '"':
+		return copyJSONString(r, w)
+	case '{', '[':
+		return copyJSONContainer(r, w)
+	case '}', ']', ',', ':':
+		return fmt.Errorf("invalid response: unexpected %q instead of a value", b)
+	}
+	// A number or a literal runs until the next delimiter.
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		switch b {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return r.UnreadByte()
+		}
+		if err := w.WriteByte(b); err != nil {
+			return err
+		}
+	}
+}
+
+// copyJSONString copies the rest of a string whose opening quote was read.
+func copyJSONString(r *bufio.Reader, w io.ByteWriter) error {
+	escaped := false
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if err := w.WriteByte(b); err != nil {
+			return err
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case b == '\\':
+			escaped = true
+		case b == '"':
+			return nil
+		}
+	}
+}
+
+// copyJSONContainer copies the rest of an object or array whose opening bracket was read.
+func copyJSONContainer(r *bufio.Reader, w io.ByteWriter) error {
+	depth := 1
+	for depth > 0 {
+		b, err := r.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if err := w.WriteByte(b); err != nil {
+			return err
+		}
+		switch b {
+		case '"':
+			if err := copyJSONString(r, w); err != nil {
+				return err
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+// discardBytes is an io.ByteWriter discarding the bytes, for the skipped values.
+type discardBytes struct{}
+
+func (discardBytes) WriteByte(byte) error {
+	return nil
+}
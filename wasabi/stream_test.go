@@ -0,0 +1,139 @@
+package wasabi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStreamClientResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr error
+	}{
+		{name: "object", body: `{"jsonrpc":"2.0","id":1,"result":{"a":1,"b":[true,false,null]}}`, want: `{"a":1,"b":[true,false,null]}`},
+		{name: "result first", body: `{"result":[1,2],"id":"x","jsonrpc":"2.0"}`, want: `[1,2]`},
+		{name: "nested", body: `{"id":1,"result":[{"coins":[{"txid":"ab","labels":["x",{"y":[]}]}]},[[[]]]]}`, want: `[{"coins":[{"txid":"ab","labels":["x",{"y":[]}]}]},[[[]]]]`},
+		{name: "brackets and quotes in strings", body: `{"id":1,"result":{"label":"a}b]c{\"d\"\\","e":"\u00e9\n"}}`, want: `{"label":"a}b]c{\"d\"\\","e":"\u00e9\n"}`},
+		{name: "escaped member names", body: `{"id":1,"\u0072esult":"escaped","jsonrpc":"2.0"}`, want: `"escaped"`},
+		{name: "skipped members with structures", body: `{"meta":{"result":"no","x":["}"]},"result":7,"id":[1,{"a":"]"}]}`, want: `7`},
+		{name: "whitespace", body: " \n{ \"id\" : 1 ,\r\n\t\"result\" :\n 12.5e3 }\n", want: `12.5e3`},
+		{name: "string", body: `{"id":1,"result":"txid"}`, want: `"txid"`},
+		{name: "null result", body: `{"jsonrpc":"2.0","id":1,"result":null}`, want: `null`},
+		{name: "no result", body: `{"jsonrpc":"2.0","id":1}`, want: `null`},
+		{name: "null error", body: `{"id":1,"result":true,"error":null}`, want: `true`},
+		{name: "error", body: `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"wallet is not loaded"}}`, wantErr: &RPCError{Code: E_BAD_PARAMS, Message: "wallet is not loaded"}},
+		{name: "error after null result", body: `{"id":1,"result":null,"error":{"code":-1,"message":"failed"}}`, wantErr: &RPCError{Code: -1, Message: "failed"}},
+		{name: "undecodable error", body: `{"id":1,"error":"boom"}`, wantErr: &RPCError{Code: E_SERVER, Message: `"boom"`}},
+		{name: "truncated result", body: `{"id":1,"result":{"a":[1,2`, wantErr: io.ErrUnexpectedEOF},
+		{name: "truncated string", body: `{"id":1,"result":"abc`, wantErr: io.ErrUnexpectedEOF},
+		{name: "truncated after the result", body: `{"id":1,"result":42`, wantErr: io.ErrUnexpectedEOF},
+		{name: "truncated member name", body: `{"id":1,"res`, wantErr: io.ErrUnexpectedEOF},
+		{name: "empty", body: ``, wantErr: io.ErrUnexpectedEOF},
+		{name: "not an object", body: `[{"result":1}]`, wantErr: errInvalid},
+		{name: "missing comma", body: `{"id":1 "result":2}`, wantErr: errInvalid},
+		{name: "missing value", body: `{"id":1,"result":}`, wantErr: errInvalid},
+		{name: "member name not a string", body: `{id:1}`, wantErr: errInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := streamClientResponse(strings.NewReader(tt.body), &out)
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatal(err)
+				}
+				if out.String() != tt.want {
+					t.Fatalf("result = %s, want %s", out.String(), tt.want)
+				}
+			case *RPCError:
+				var rpcErr *RPCError
+				if !errors.As(err, &rpcErr) || rpcErr.Code != want.Code || rpcErr.Message != want.Message {
+					t.Fatalf("error = %v, want %v", err, want)
+				}
+			default:
+				if want == errInvalid {
+					if err == nil || !strings.HasPrefix(err.Error(), "invalid response") {
+						t.Fatalf("error = %v, want an invalid response", err)
+					}
+				} else if !errors.Is(err, want) {
+					t.Fatalf("error = %v, want %v", err, want)
+				}
+			}
+			if tt.wantErr != nil && out.Len() != 0 {
+				t.Fatalf("result = %s written with the error", out.String())
+			}
+		})
+	}
+}
+
+// errInvalid stands for the errors of malformed responses in the tests.
+var errInvalid = errors.New("invalid response")
+
+// failingWriter fails after n bytes.
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, io.ErrShortWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestCallRawStream(t *testing.T) {
+	// The result is larger than the buffers of the stream.
+	large := `[` + strings.Repeat(`{"txid":"`+strings.Repeat("ab", 32)+`","amount":1000},`, 1000) + `{}]`
+	body := `{"jsonrpc":"2.0","id":1,"result":` + large + `}`
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		w        io.Writer
+		category ErrorCategory
+		wantErr  error
+	}{
+		{name: "large result", status: http.StatusOK, body: body},
+		{name: "writer error", status: http.StatusOK, body: body, w: &failingWriter{n: 100}, wantErr: io.ErrShortWrite},
+		{name: "truncated", status: http.StatusOK, body: body[:len(body)/2], category: ErrorCategoryProtocol, wantErr: io.ErrUnexpectedEOF},
+		{name: "wallet rejection", status: http.StatusOK, body: `{"jsonrpc":"2.0","id":1,"error":{"code":-1,"message":"Incorrect password."}}`, category: ErrorCategoryWallet},
+		{name: "no method", status: http.StatusOK, body: noMethodResponse, category: ErrorCategoryProtocol, wantErr: ErrUnsupportedMethod},
+		{name: "http error", status: http.StatusInternalServerError, body: "boom", category: ErrorCategoryProtocol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(string) (int, string) { return tt.status, tt.body })
+			var out bytes.Buffer
+			w := tt.w
+			if w == nil {
+				w = &out
+			}
+			err := c.CallRaw("w", "listcoins", nil, w)
+			if tt.category == ErrorCategoryNone && tt.wantErr == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if out.String() != large {
+					t.Fatalf("result of %d bytes, want %d bytes", out.Len(), len(large))
+				}
+				return
+			}
+			if tt.category != ErrorCategoryNone && Classify(err) != tt.category {
+				t.Fatalf("CallRaw() = %v, want a %v error", err, tt.category)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CallRaw() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,64 +2,120 @@ package wasabi
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // Config holds the configuration of a wasabi rpc client.
 type Config struct {
-	// Host is the address of the wasabi rpc server (without protocol and port).
+	// Host is the address of the wasabi rpc server (without protocol and port). IPv6 addresses are given without brackets.
 	Host string
 	// Port is the port of the wasabi rpc server. Default is 37128.
 	Port int
 	// CustomHeaders is a map of custom headers to send with the request
 	CustomHeaders map[string]string
-	// Transport is the http transport to use for the request. If nil, http.DefaultClient is used
+	// Transport is the http transport to use for the request. If nil, the transport of HTTPClient (or http.DefaultClient) is used.
 	Transport http.RoundTripper
+	// HTTPClient is the http client sending the requests, e.g. an instrumented client with its timeouts, redirect policy and cookie jar. If Transport is set too, a copy of HTTPClient using Transport is used. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
 	// RpcUser is the rpc user to use for basic authentication
 	RpcUser string
 	// RpcPassword is the rpc password to use for basic authentication
 	RpcPassword string
+	// Logger is the logger used to log each call (method, wallet, duration and outcome). Passwords and mnemonics are redacted. If nil, nothing is logged.
+	// A *slog.Logger can be used directly, zap and logrus loggers can be adapted with NewZapLogger and NewLogrusLogger.
+	Logger Logger
+	// LogLevel is the level at which successful calls are logged. If nil, slog.LevelDebug is used.
+	LogLevel slog.Leveler
+	// LogErrorLevel is the level at which failed calls are logged. If nil, slog.LevelError is used.
+	LogErrorLevel slog.Leveler
+	// Tracer is used to start a span for each call. If nil, calls are not traced.
+	Tracer Tracer
+	// DebugWriter receives a dump of every http request and response (headers and JSON-RPC body). Passwords, mnemonics and the Authorization header are masked. The bodies streamed by CallRaw are dumped up to their first 4096 bytes. If nil, nothing is dumped.
+	DebugWriter io.Writer
+	// Interceptors are called around every call, in order before the request and in reverse order after the response.
+	Interceptors []Interceptor
+	// AuditSink records every state-changing call (send, broadcast, createwallet, startcoinjoin, ...) with redacted parameters. If nil, nothing is recorded.
+	AuditSink AuditSink
+	// CorrelationIDHeader is the http header carrying the correlation id of each request. Default is X-Request-ID.
+	CorrelationIDHeader string
+	// Routing is the strategy used to route calls to a wallet. Default is RoutingURLPath, RoutingSelectWallet is needed for older daemons.
+	Routing RoutingMode
+	// FailureBurst configures a callback invoked when calls of a method fail repeatedly, e.g. 5 consecutive getstatus failures.
+	FailureBurst FailureBurstConfig
+	// DisableNetworkGuard disables the check that the payment addresses of Send, Build and BuildUnsafeTransaction belong to the network of the daemon (see NetworkMismatchError). Meant for tests against daemons reporting an unexpected network.
+	DisableNetworkGuard bool
+	// FeePolicy is checked after Build and BuildUnsafeTransaction by decoding the built transaction: transactions paying a higher fee are refused with a FeeCeilingError and not returned. Send is not covered because the daemon broadcasts the transaction itself; use Build and Broadcast to send under the policy.
+	FeePolicy FeePolicy
+	// UseNumber decodes results with json.Number, so numbers never pass through float64: numbers in interface{} values (e.g. the out of RawCall) are json.Number, and numbers which do not fit the field they are decoded into are reported as NumberError instead of being truncated or zeroed.
+	UseNumber bool
+	// Retry is the retry policy of the calls. Calls can override it with WithRetryPolicy or opt out with WithoutRetry. The zero value does not retry.
+	Retry RetryPolicy
+	// Hedge sends read calls which are slow to answer to other endpoints of the daemon too (see HedgePolicy). The zero value does not hedge.
+	Hedge HedgePolicy
 }
 
-// Validate validates the config.
-func (c *Config) Validate() error {
+// checkHost returns an error if the host is empty or contains a path or a port. IPv6 addresses are given without brackets.
+func checkHost(host string) error {
 	switch {
-	case c.Host == "":
+	case host == "":
 		return fmt.Errorf("host must not be empty")
-	case strings.ContainsAny(c.Host, "/:"):
-		return fmt.Errorf("host must not contain / or :")
-	case c.Port == 0:
+	case strings.Contains(host, "/"), strings.Contains(host, ":") && net.ParseIP(host) == nil:
+		return fmt.Errorf("host must not contain / or :, except in an IPv6 address without brackets")
+	}
+	return nil
+}
+
+// Validate validates the config. It sets the default port and the Authorization header of the rpc credentials.
+func (c *Config) Validate() error {
+	if c.Port == 0 {
 		c.Port = 37128
+	}
+	if err := checkHost(c.Host); err != nil {
+		return err
+	}
+	switch {
 	case c.Port < 0 || c.Port > 65535:
 		return fmt.Errorf("port must be between 0 and 65535")
+	case c.Routing != RoutingURLPath && c.Routing != RoutingSelectWallet:
+		return fmt.Errorf("unknown routing mode %d", c.Routing)
+	case c.FeePolicy.MaxFee < 0 || c.FeePolicy.MaxFeePercent < 0:
+		return fmt.Errorf("fee policy limits must not be negative")
+	case c.Retry.MaxAttempts < 0 || c.Retry.Backoff < 0 || c.Retry.MaxBackoff < 0:
+		return fmt.Errorf("retry policy values must not be negative")
 	case c.RpcUser != "" && c.RpcPassword == "":
 		return fmt.Errorf("rpc password must not be empty if rpc user is set")
 	case c.RpcUser == "" && c.RpcPassword != "":
 		return fmt.Errorf("rpc user must not be empty if rpc password is set")
-	case c.CustomHeaders != nil:
-		for k, v := range c.CustomHeaders {
-			if k == "" {
-				return fmt.Errorf("custom header key must not be empty")
-			}
-			if v == "" {
-				return fmt.Errorf("custom header value must not be empty")
-			}
-			if k == "Authorization" {
-				return fmt.Errorf("custom header key must not be Authorization")
-			}
+	}
+	for k, v := range c.CustomHeaders {
+		if k == "" {
+			return fmt.Errorf("custom header key must not be empty")
+		}
+		if v == "" {
+			return fmt.Errorf("custom header value must not be empty")
+		}
+		if k == "Authorization" {
+			return fmt.Errorf("custom header key must not be Authorization")
 		}
-	case c.RpcUser != "" && c.RpcPassword != "":
-		basicAuth := base64.StdEncoding.EncodeToString([]byte(c.RpcUser + ":" + c.RpcPassword))
-		if c.CustomHeaders != nil {
-			c.CustomHeaders["Authorization"] = "Basic " + basicAuth
-		} else {
-			c.CustomHeaders = map[string]string{
-				"Authorization": "Basic " + basicAuth,
-			}
+	}
+	if c.RpcUser != "" {
+		// The headers are copied, so the map of the caller can be used for other configs.
+		headers := make(map[string]string, len(c.CustomHeaders)+1)
+		for k, v := range c.CustomHeaders {
+			headers[k] = v
 		}
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(c.RpcUser+":"+c.RpcPassword))
+		c.CustomHeaders = headers
 	}
 	return nil
 }
@@ -74,30 +130,68 @@ type BitcoinPeer struct {
 
 // GetStatusResponse provides the response of a getstatus request.
 type GetStatusResponse struct {
-	TorStatus            TorStatus      `json:"torStatus"`
+	TorStatus TorStatus `json:"torStatus"`
+	// OnionService is the state of the onion service of the daemon (e.g. "Running"). It is empty if the daemon does not report it.
+	OnionService         string         `json:"onionService,omitempty"`
 	BackendStatus        BackendStatus  `json:"backendStatus"`
 	BestBlockchainHeight uint64         `json:"bestBlockchainHeight,string"`
 	BestBlockchainHash   string         `json:"bestBlockchainHash"`
 	FiltersCount         int            `json:"filtersCount"`
 	FiltersLeft          int            `json:"filtersLeft"`
 	Network              BitcoinNetwork `json:"network"`
-	ExchangeRate         float64        `json:"exchangeRate"`
+	ExchangeRate         Decimal        `json:"exchangeRate"`
 	Peers                []BitcoinPeer  `json:"peers"`
+	// Extra holds the fields reported by newer daemons which are not covered by the fields above, keyed by their JSON name. Use ExtraField to decode them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the response of all daemon versions: the best blockchain height is accepted both as a string and as a number, and unknown fields are kept in Extra.
+func (r *GetStatusResponse) UnmarshalJSON(data []byte) error {
+	type alias GetStatusResponse
+	aux := struct {
+		*alias
+		BestBlockchainHeight json.RawMessage `json:"bestBlockchainHeight"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if height := strings.Trim(string(aux.BestBlockchainHeight), `"`); height != "" && height != "null" {
+		value, err := strconv.ParseUint(height, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid bestBlockchainHeight %s: %w", aux.BestBlockchainHeight, err)
+		}
+		r.BestBlockchainHeight = value
+	}
+	extra, err := unknownJSONFields(data, reflect.TypeOf(*r))
+	if err != nil {
+		return err
+	}
+	r.Extra = extra
+	return nil
+}
+
+// ExtraField decodes the field reported by newer daemons with the given JSON name into out. It returns false if the daemon did not report the field.
+func (r GetStatusResponse) ExtraField(name string, out interface{}) (bool, error) {
+	raw, ok := r.Extra[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, out)
 }
 
 // ListCoinsResponse provides the response of a listcoins request.
 type ListCoinsResponse struct {
-	TxID                 string  `json:"txid"`
-	Index                int     `json:"index"`
-	Amount               int     `json:"amount"`
-	AnonymityScore       float64 `json:"anonymityScore"`
-	Confirmed            bool    `json:"confirmed"`
-	Confirmations        int     `json:"confirmations"`
-	KeyPath              string  `json:"keyPath"`
-	Address              string  `json:"address"`
-	SpentBy              *string `json:"spentBy,omitempty"` // may be null
-	Label                string  `json:"label,omitempty"`
-	ExcludedFromCoinJoin bool    `json:"excludedFromCoinjoin"`
+	TxID                 TxID           `json:"txid"`
+	Index                int            `json:"index"`
+	Amount               Amount         `json:"amount"`
+	AnonymityScore       float64        `json:"anonymityScore"`
+	Confirmed            bool           `json:"confirmed"`
+	Confirmations        int            `json:"confirmations"`
+	KeyPath              DerivationPath `json:"keyPath"`
+	Address              Address        `json:"address"`
+	SpentBy              *TxID          `json:"spentBy,omitempty"` // may be null
+	Label                string         `json:"label,omitempty"`
+	ExcludedFromCoinJoin bool           `json:"excludedFromCoinjoin"`
 }
 
 // GetWalletInfoResponse provides the response of a getwalletinfo request.
@@ -112,7 +206,7 @@ type GetWalletInfoResponse struct {
 	IsAutoCoinJoin       bool                `json:"isAutoCoinjoin"`
 	IsRedCoinIsolation   bool                `json:"isRedCoinIsolation"`
 	Accounts             []WalletInfoAccount `json:"accounts"`
-	Balance              int                 `json:"balance,omitempty"`
+	Balance              Amount              `json:"balance,omitempty"`
 	CoinJoinStatus       CoinJoinStatus      `json:"coinjoinStatus,omitempty"`
 }
 
@@ -125,52 +219,223 @@ type WalletInfoAccount struct {
 
 // GetNewAddressResponse provides the response of a getnewaddress request.
 type GetNewAddressResponse struct {
-	Address      string `json:"address"`
-	KeyPath      string `json:"keyPath"`
-	Label        string `json:"label"`
-	PublicKey    string `json:"publicKey"`
-	ScriptPubKey string `json:"scriptPubKey"`
+	Address      Address        `json:"address"`
+	KeyPath      DerivationPath `json:"keyPath"`
+	Label        string         `json:"label"`
+	PublicKey    string         `json:"publicKey"`
+	ScriptPubKey ScriptPubKey   `json:"scriptPubKey"`
 }
 
 // SendResponse provides the response of a send request.
 type SendResponse struct {
-	TransactionID string `json:"txid"`
+	TransactionID TxID   `json:"txid"`
 	Transaction   string `json:"tx"`
 }
 
+// SendRequest holds the parameters of a send request.
+type SendRequest struct {
+	// Payments are the outputs of the transaction.
+	Payments []Payment
+	// Coins are the inputs of the transaction. If empty, the daemon selects the coins.
+	Coins []Coin
+	// FeeTarget is the confirmation target used to estimate the fee, e.g. FeeTargetHour. Either FeeTarget or FeeRate must be set.
+	FeeTarget FeeTarget
+	// FeeRate is the fee rate in satoshi per virtual byte. Either FeeTarget or FeeRate must be set.
+	FeeRate float64
+	// Password is the password of the wallet.
+	Password string
+	// Safety holds client-side checks refusing likely mistakes before the request reaches the daemon.
+	Safety SafetyFlags
+	// PayjoinEndpoint is the BIP78 payjoin endpoint of the receiver (the pj parameter of a BIP21 URI). If set, the transaction is constructed as a payjoin with the receiver. Optional.
+	PayjoinEndpoint string
+}
+
+// params returns the JSON-RPC params of the request.
+func (r SendRequest) params() (map[string]interface{}, error) {
+	if err := r.Safety.check(r.Coins, r.FeeRate); err != nil {
+		return nil, err
+	}
+	params, err := transactionParams(r.Payments, r.Coins, r.Password)
+	if err != nil {
+		return nil, err
+	}
+	if r.PayjoinEndpoint != "" {
+		u, err := url.Parse(r.PayjoinEndpoint)
+		if err != nil || !u.IsAbs() {
+			return nil, fmt.Errorf("payjoin endpoint must be an absolute url")
+		}
+		params["payjoinUrl"] = r.PayjoinEndpoint
+	}
+	return feeParams(params, r.FeeTarget, r.FeeRate)
+}
+
+// BuildRequest holds the parameters of a build request.
+type BuildRequest struct {
+	// Payments are the outputs of the transaction.
+	Payments []Payment
+	// Coins are the inputs of the transaction. If empty, the daemon selects the coins.
+	Coins []Coin
+	// FeeTarget is the confirmation target used to estimate the fee, e.g. FeeTargetHour. Either FeeTarget or FeeRate must be set.
+	FeeTarget FeeTarget
+	// FeeRate is the fee rate in satoshi per virtual byte. Either FeeTarget or FeeRate must be set.
+	FeeRate float64
+	// Password is the password of the wallet.
+	Password string
+	// Safety holds client-side checks refusing likely mistakes before the request reaches the daemon.
+	Safety SafetyFlags
+}
+
+// params returns the JSON-RPC params of the request.
+func (r BuildRequest) params() (map[string]interface{}, error) {
+	if err := r.Safety.check(r.Coins, r.FeeRate); err != nil {
+		return nil, err
+	}
+	params, err := transactionParams(r.Payments, r.Coins, r.Password)
+	if err != nil {
+		return nil, err
+	}
+	return feeParams(params, r.FeeTarget, r.FeeRate)
+}
+
+// BuildUnsafeRequest holds the parameters of a buildunsafetransaction request.
+type BuildUnsafeRequest struct {
+	// Payments are the outputs of the transaction.
+	Payments []Payment
+	// Coins are the inputs of the transaction. If empty, the daemon selects the coins.
+	Coins []Coin
+	// FeeTarget is the confirmation target used to estimate the fee, e.g. FeeTargetHour. Either FeeTarget or FeeRate must be set.
+	FeeTarget FeeTarget
+	// FeeRate is the fee rate in satoshi per virtual byte. Either FeeTarget or FeeRate must be set.
+	FeeRate float64
+	// Password is the password of the wallet.
+	Password string
+	// MaxFee is the highest acceptable absolute fee. If set, the built transaction is decoded and refused with a FeeCeilingError if its fee is higher. Zero disables the check.
+	MaxFee Amount
+}
+
+// params returns the JSON-RPC params of the request.
+func (r BuildUnsafeRequest) params() (map[string]interface{}, error) {
+	if r.MaxFee < 0 {
+		return nil, fmt.Errorf("max fee must not be negative")
+	}
+	params, err := transactionParams(r.Payments, r.Coins, r.Password)
+	if err != nil {
+		return nil, err
+	}
+	return feeParams(params, r.FeeTarget, r.FeeRate)
+}
+
+// transactionParams returns the common params of transaction requests. The coins are omitted if empty, so the daemon selects the coins itself. The payment addresses are decoded locally, so typos fail before the daemon is called.
+func transactionParams(payments []Payment, coins []Coin, password string) (map[string]interface{}, error) {
+	for _, payment := range payments {
+		if _, err := payment.SendTo.Decode(); err != nil {
+			return nil, err
+		}
+	}
+	params := map[string]interface{}{"payments": payments, "password": password}
+	if len(coins) > 0 {
+		params["coins"] = coins
+	}
+	return params, nil
+}
+
+// feeParams adds the fee target or the fee rate to the params.
+func feeParams(params map[string]interface{}, feeTarget FeeTarget, feeRate float64) (map[string]interface{}, error) {
+	switch {
+	case feeTarget != 0 && feeRate != 0:
+		return nil, fmt.Errorf("fee target and fee rate must not be set both")
+	case feeRate != 0:
+		params["feeRate"] = feeRate
+	default:
+		if err := feeTarget.Validate(); err != nil {
+			return nil, err
+		}
+		params["feeTarget"] = feeTarget
+	}
+	return params, nil
+}
+
+// StartCoinJoinOptions holds the options of a startcoinjoin request.
+type StartCoinJoinOptions struct {
+	// StopWhenAllMixed stops the coinjoin when all coins reached the anonymity score target.
+	StopWhenAllMixed bool
+	// OverridePlebStop starts the coinjoin even if the wallet balance is below the pleb stop threshold.
+	OverridePlebStop bool
+}
+
 // Payment provides information about a payment.
 type Payment struct { // PaymentInfo
-	SendTo string `json:"sendto"`
-	Amount int    `json:"amount"`
-	Label  string `json:"label"`
+	SendTo Address `json:"sendto"`
+	Amount Amount  `json:"amount"`
+	Label  string  `json:"label"`
+	// SubtractFee subtracts the transaction fee from the amount of this payment. Only one payment of a transaction can subtract the fee.
+	SubtractFee bool `json:"subtractFee,omitempty"`
 }
 
 // Coin provides information about a coin.
 type Coin struct { // OutPoint
-	TransactionID string `json:"transactionid"`
-	Index         int    `json:"index"`
+	TransactionID TxID `json:"transactionid"`
+	Index         int  `json:"index"`
 }
 
 // Transaction provides information about a transaction in history.
 type Transaction struct {
-	DateTime         time.Time `json:"datetime"`
-	Height           int       `json:"height"`
-	Amount           int       `json:"amount"`
-	Label            string    `json:"label"`
-	Tx               string    `json:"tx"`
-	IsLikelyCoinJoin bool      `json:"islikelycoinjoin"`
+	DateTime time.Time `json:"datetime"`
+	Height   int       `json:"height"`
+	Amount   Amount    `json:"amount"` // negative for outgoing transactions
+	Label    string    `json:"label"`
+	// Tx is the id of the transaction under the name used by older daemons. Prefer TxID, which is set for all daemons.
+	Tx TxID `json:"tx"`
+	// TxID is the id of the transaction. It is set from tx for daemons which do not report txid, and tx is set from it for daemons which only report txid.
+	TxID             TxID `json:"txid,omitempty"`
+	IsLikelyCoinJoin bool `json:"islikelycoinjoin"`
+	// BlockHash is the hash of the block of a confirmed transaction. It is empty if the transaction is unconfirmed or the daemon does not report it.
+	BlockHash string `json:"blockhash,omitempty"`
+	// Fee is the fee of the transaction. It is nil if the daemon does not report it, which older daemons never do and newer ones only do for transactions paid by the wallet.
+	Fee *Amount `json:"fee,omitempty"`
+	// Extra holds the fields reported by newer daemons which are not covered by the fields above, keyed by their JSON name. Use ExtraField to decode them.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the history items of all daemon versions: the id is accepted as tx, as txid or as both, and unknown fields are kept in Extra.
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	type alias Transaction
+	if err := json.Unmarshal(data, (*alias)(t)); err != nil {
+		return err
+	}
+	switch {
+	case t.TxID == "":
+		t.TxID = t.Tx
+	case t.Tx == "":
+		t.Tx = t.TxID
+	}
+	extra, err := unknownJSONFields(data, reflect.TypeOf(*t))
+	if err != nil {
+		return err
+	}
+	t.Extra = extra
+	return nil
+}
+
+// ExtraField decodes the field reported by newer daemons with the given JSON name into out. It returns false if the daemon did not report the field.
+func (t Transaction) ExtraField(name string, out interface{}) (bool, error) {
+	raw, ok := t.Extra[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, out)
 }
 
 // GeneratedKey provides information about a generated key.
 type GeneratedKey struct {
-	FullKeyPath  string `json:"fullKeyPath"`
-	Internal     bool   `json:"internal"`
-	KeyState     int    `json:"keyState"`
-	Label        string `json:"label"`
-	ScriptPubKey string `json:"scriptPubKey"`
-	PubKey       string `json:"pubkey"`
-	PubKeyHash   string `json:"pubKeyHash"`
-	Address      string `json:"address"`
+	FullKeyPath  DerivationPath `json:"fullKeyPath"`
+	Internal     bool           `json:"internal"`
+	KeyState     KeyState       `json:"keyState"`
+	Label        string         `json:"label"`
+	ScriptPubKey ScriptPubKey   `json:"scriptPubKey"`
+	PubKey       string         `json:"pubkey"`
+	PubKeyHash   string         `json:"pubKeyHash"`
+	Address      Address        `json:"address"`
 }
 
 // GetFeeRatesResponse provides the response of a getfeerates request. It is a map of confirmation target (in blocks) to fee rate (in satoshi per byte).
@@ -179,25 +444,67 @@ type GetFeeRatesResponse map[string]int
 // ListWalletsResponseItem provides the response of a listwallets request.
 type ListWalletsResponseItem struct {
 	Name string `json:"walletName"`
+	// State is the state of the wallet. It is empty if the daemon does not report it.
+	State WalletState `json:"state,omitempty"`
+	// Loaded reports whether the wallet is loaded. It is nil if the daemon does not report it (see IsLoaded).
+	Loaded *bool `json:"loaded,omitempty"`
+	// Extra holds the fields reported by newer daemons which are not covered by the fields above, keyed by their JSON name.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the items of all daemon versions: the loaded flag is accepted as loaded and as isLoaded, and unknown fields are kept in Extra.
+func (i *ListWalletsResponseItem) UnmarshalJSON(data []byte) error {
+	type alias ListWalletsResponseItem
+	aux := struct {
+		*alias
+		IsLoaded *bool `json:"isLoaded"`
+	}{alias: (*alias)(i)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if i.Loaded == nil {
+		i.Loaded = aux.IsLoaded
+	}
+	extra, err := unknownJSONFields(data, reflect.TypeOf(*i))
+	if err != nil {
+		return err
+	}
+	delete(extra, "isLoaded")
+	if len(extra) == 0 {
+		extra = nil
+	}
+	i.Extra = extra
+	return nil
+}
+
+// IsLoaded reports whether the wallet is loaded, from the loaded flag or else the state reported by the daemon. known is false if the daemon reported neither.
+func (i ListWalletsResponseItem) IsLoaded() (loaded bool, known bool) {
+	switch {
+	case i.Loaded != nil:
+		return *i.Loaded, true
+	case i.State != "":
+		return i.State != WalletStateUninitialized && i.State != WalletStateStopped, true
+	}
+	return false, false
 }
 
 // ListPaymentsInCoinJoinResponseItem provides the item of a listpaymentsincoinjoin response list.
 type ListPaymentsInCoinJoinResponseItem struct {
 	// ID is the id of the payment (UUID). That id can be used to cancel the payment.
 	ID string `json:"id"`
-	// Amount is the amount of the payment in satoshi.
-	Amount int `json:"amount"`
+	// Amount is the amount of the payment.
+	Amount Amount `json:"amount"`
 	// Destination is the destination of the payment (ScriptPubKey hex).
-	Destination string `json:"destination"`
+	Destination ScriptPubKey `json:"destination"`
 	// State is the state history of the payment.
 	State []PaymentInCoinJoinStateHistoryItem `json:"state"`
 	// Address is the address of the payment.
-	Address string `json:"address"`
+	Address Address `json:"address"`
 }
 
 // PaymentInCoinJoinStateHistoryItem provides the item of a payment in coinjoin state history list.
 type PaymentInCoinJoinStateHistoryItem struct {
 	Status PaymentStatus `json:"status"`
 	Round  int           `json:"round,omitempty"`
-	TxID   string        `json:"txid,omitempty"`
+	TxID   TxID          `json:"txid,omitempty"`
 }
@@ -0,0 +1,109 @@
+package wasabi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{name: "default port", cfg: Config{Host: "localhost"}},
+		{name: "routing with default port", cfg: Config{Host: "localhost", Routing: 7}, err: "unknown routing mode 7"},
+		{name: "fee policy with default port", cfg: Config{Host: "localhost", FeePolicy: FeePolicy{MaxFee: -1}}, err: "fee policy limits must not be negative"},
+		{name: "retry with default port", cfg: Config{Host: "localhost", Retry: RetryPolicy{MaxAttempts: -3}}, err: "retry policy values must not be negative"},
+		{name: "port out of range", cfg: Config{Host: "localhost", Port: 70000}, err: "port must be between"},
+		{name: "empty header with default port", cfg: Config{Host: "localhost", CustomHeaders: map[string]string{"X-Tenant": ""}}, err: "custom header value must not be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("Validate() = %v, want nil", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("Validate() = %v, want %q", err, tt.err)
+			}
+			if tt.err == "" && tt.cfg.Port != 37128 {
+				t.Fatalf("port = %d, want the default port", tt.cfg.Port)
+			}
+		})
+	}
+}
+
+func TestConfigValidateCredentialsWithCustomHeaders(t *testing.T) {
+	headers := map[string]string{"X-Tenant": "a"}
+	for i := 0; i < 2; i++ {
+		cfg := Config{Host: "localhost", RpcUser: "user", RpcPassword: "pass", CustomHeaders: headers}
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate() = %v", err)
+		}
+		if got := cfg.CustomHeaders["Authorization"]; got != "Basic dXNlcjpwYXNz" {
+			t.Fatalf("Authorization = %q", got)
+		}
+		if cfg.CustomHeaders["X-Tenant"] != "a" {
+			t.Fatalf("custom header lost")
+		}
+	}
+	if _, ok := headers["Authorization"]; ok {
+		t.Fatalf("the headers of the caller were modified")
+	}
+}
+
+func TestGetStatusResponseFixtures(t *testing.T) {
+	tests := []struct {
+		version string
+		height  uint64
+	}{
+		{version: "2.0.4", height: 2871450},
+		{version: "2.1.0", height: 2871450},
+		{version: "2.2.1", height: 2905113},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("fixtures", "data", tt.version, "getstatus.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var response struct {
+				Result GetStatusResponse `json:"result"`
+			}
+			if err := json.Unmarshal(data, &response); err != nil {
+				t.Fatal(err)
+			}
+			status := response.Result
+			if status.BestBlockchainHeight != tt.height {
+				t.Errorf("BestBlockchainHeight = %d, want %d", status.BestBlockchainHeight, tt.height)
+			}
+			if status.TorStatus == "" || status.BackendStatus == "" || status.Network == "" || len(status.Peers) == 0 {
+				t.Errorf("incomplete status %+v", status)
+			}
+			if status.Extra != nil {
+				t.Errorf("Extra = %v, want every field decoded", status.Extra)
+			}
+		})
+	}
+}
+
+func TestGetStatusResponseExtra(t *testing.T) {
+	var status GetStatusResponse
+	if err := json.Unmarshal([]byte(`{"torStatus":"Running","onionService":"Running","bestBlockchainHeight":"12","coinjoinRounds":3}`), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.BestBlockchainHeight != 12 || status.OnionService != "Running" {
+		t.Fatalf("status = %+v, want height 12 and the onion service running", status)
+	}
+	var rounds int
+	if ok, err := status.ExtraField("coinjoinRounds", &rounds); !ok || err != nil || rounds != 3 {
+		t.Fatalf("ExtraField() = %v, %v, %d, want the unknown field", ok, err, rounds)
+	}
+	if len(status.Extra) != 1 {
+		t.Fatalf("Extra = %v, want only the unknown field", status.Extra)
+	}
+}
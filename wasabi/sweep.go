@@ -0,0 +1,93 @@
+package wasabi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// sweepMaxAttempts is the number of builds SweepAll tries before giving up.
+const sweepMaxAttempts = 8
+
+// SweepResult is a transaction built by SweepAll.
+type SweepResult struct {
+	// Hex is the signed transaction, ready to be broadcast.
+	Hex string
+	// Amount is the amount paid to the address.
+	Amount Amount
+	Fee    Amount
+	// Coins are the spent coins.
+	Coins []Coin
+}
+
+// SweepAll builds a transaction spending all unspent coins of the wallet to a single output paying the address, without change. The amount is the balance minus the fee: it starts from an estimation and Build is called again until the daemon builds a transaction without change. The transaction is not broadcast.
+func SweepAll(c Client, walletName string, address Address, feeRate float64, password string) (SweepResult, error) {
+	if feeRate <= 0 {
+		return SweepResult{}, fmt.Errorf("fee rate must be positive")
+	}
+	coins, err := c.ListUnspentCoins(walletName)
+	if err != nil {
+		return SweepResult{}, err
+	}
+	var total Amount
+	var outpoints []Coin
+	for _, coin := range coins {
+		if coin.SpentBy != nil && *coin.SpentBy != "" {
+			continue
+		}
+		total += coin.Amount
+		outpoints = append(outpoints, Coin{TransactionID: coin.TxID, Index: coin.Index})
+	}
+	if len(outpoints) == 0 {
+		return SweepResult{}, errors.New("no coins to sweep")
+	}
+
+	// The first estimate may be short for larger outputs than P2WPKH, the failed builds raise it.
+	fee := EstimateFee(feeRate, len(outpoints), 1)
+	var lastErr error
+	for attempt := 0; attempt < sweepMaxAttempts; attempt++ {
+		amount := total - fee
+		if amount <= 0 {
+			return SweepResult{}, fmt.Errorf("balance of %s does not cover the fee of %s", total, fee)
+		}
+		txHex, err := c.Build(walletName, BuildRequest{
+			Payments: []Payment{{SendTo: address, Amount: amount}},
+			Coins:    outpoints,
+			FeeRate:  feeRate,
+			Password: password,
+		})
+		if err != nil {
+			// Unknown daemon errors are usually caused by an amount leaving too little for the fee; known wallet rejections (e.g. a wrong password) are final.
+			if Classify(err) != ErrorCategoryDaemon {
+				return SweepResult{}, err
+			}
+			lastErr = err
+			fee += fee/2 + 1
+			continue
+		}
+		tx, err := DecodeTransaction(txHex)
+		if err != nil {
+			return SweepResult{}, err
+		}
+		var change Amount
+		for _, output := range tx.Outputs {
+			change += output.Amount
+		}
+		change -= amount
+		if change == 0 && len(tx.Outputs) == 1 {
+			return SweepResult{Hex: txHex, Amount: amount, Fee: total - amount, Coins: outpoints}, nil
+		}
+		// The fee of the built transaction is the part of the balance neither paid nor returned as change.
+		lastErr = fmt.Errorf("built transaction has %d outputs", len(tx.Outputs))
+		fee = total - amount - change
+	}
+	return SweepResult{}, fmt.Errorf("failed to build a sweep transaction after %d attempts: %w", sweepMaxAttempts, lastErr)
+}
+
+// SweepAll builds a transaction spending all unspent coins of the wallet to the address, with the password of the handle.
+func (w *Wallet) SweepAll(address Address, feeRate float64) (SweepResult, error) {
+	password, err := w.getPassword()
+	if err != nil {
+		return SweepResult{}, err
+	}
+	return SweepAll(w.client, w.name, address, feeRate, password)
+}
@@ -0,0 +1,20 @@
+package wasabi
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer starts a span for each rpc call. The otelwasabi package provides an OpenTelemetry implementation.
+type Tracer interface {
+	// Start starts a span with the given name and attributes as a child of the span in ctx (if any). The returned context holds the new span.
+	Start(ctx context.Context, spanName string, attributes map[string]string) (context.Context, Span)
+	// Inject writes the trace context of ctx into the headers of the outgoing request, so the trace can be continued by proxies in front of the daemon.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// End ends the span. The err is the result of the call, it is nil on success.
+	End(err error)
+}
@@ -0,0 +1,226 @@
+package wasabi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RawTransaction is a decoded bitcoin transaction.
+type RawTransaction struct {
+	// TxID is the transaction id (hex, byte-reversed hash of the non-witness serialization).
+	TxID     TxID
+	Version  int32
+	Inputs   []RawTxInput
+	Outputs  []RawTxOutput
+	LockTime uint32
+	// Size is the size of the serialized transaction in bytes.
+	Size int
+	// VSize is the virtual size of the transaction in virtual bytes.
+	VSize int
+}
+
+// RawTxInput is an input of a decoded transaction.
+type RawTxInput struct {
+	// PrevTxID is the id of the transaction of the spent output.
+	PrevTxID TxID
+	// PrevIndex is the index of the spent output.
+	PrevIndex uint32
+	Sequence  uint32
+}
+
+// RawTxOutput is an output of a decoded transaction.
+type RawTxOutput struct {
+	Amount Amount
+	// ScriptPubKey is the output script (hex).
+	ScriptPubKey ScriptPubKey
+}
+
+// DecodeTransaction decodes a hex encoded bitcoin transaction, as returned by Build and BuildUnsafeTransaction.
+func DecodeTransaction(txHex string) (*RawTransaction, error) {
+	raw, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hex: %w", err)
+	}
+	d := &txDecoder{r: bytes.NewReader(raw)}
+	tx := &RawTransaction{Size: len(raw)}
+
+	// The non-witness serialization is collected to compute the txid.
+	var stripped bytes.Buffer
+	tx.Version = int32(d.uint32())
+	binary.Write(&stripped, binary.LittleEndian, uint32(tx.Version))
+
+	inputCount := d.varInt()
+	segwit := false
+	if inputCount == 0 {
+		// Segwit marker (0x00) followed by flag (0x01).
+		if flag := d.byte(); flag != 1 {
+			return nil, fmt.Errorf("invalid segwit flag %d", flag)
+		}
+		segwit = true
+		inputCount = d.varInt()
+	}
+	start := len(raw) - d.r.Len()
+	for i := uint64(0); i < inputCount && d.err == nil; i++ {
+		prevHash := d.bytes(32)
+		input := RawTxInput{
+			PrevTxID:  TxID(reversedHex(prevHash)),
+			PrevIndex: d.uint32(),
+		}
+		d.bytes(int(d.varInt())) // scriptSig
+		input.Sequence = d.uint32()
+		tx.Inputs = append(tx.Inputs, input)
+	}
+	outputCount := d.varInt()
+	for i := uint64(0); i < outputCount && d.err == nil; i++ {
+		output := RawTxOutput{Amount: Amount(d.uint64())}
+		output.ScriptPubKey = ScriptPubKey(hex.EncodeToString(d.bytes(int(d.varInt()))))
+		tx.Outputs = append(tx.Outputs, output)
+	}
+	end := len(raw) - d.r.Len()
+	if d.err == nil {
+		writeVarInt(&stripped, inputCount)
+		stripped.Write(raw[start:end])
+	}
+	if segwit {
+		for i := uint64(0); i < inputCount && d.err == nil; i++ {
+			items := d.varInt()
+			for j := uint64(0); j < items && d.err == nil; j++ {
+				d.bytes(int(d.varInt()))
+			}
+		}
+	}
+	tx.LockTime = d.uint32()
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", d.err)
+	}
+	if d.r.Len() != 0 {
+		return nil, fmt.Errorf("invalid transaction: %d trailing bytes", d.r.Len())
+	}
+	binary.Write(&stripped, binary.LittleEndian, tx.LockTime)
+
+	first := sha256.Sum256(stripped.Bytes())
+	second := sha256.Sum256(first[:])
+	tx.TxID = TxID(reversedHex(second[:]))
+	weight := stripped.Len()*3 + len(raw)
+	tx.VSize = (weight + 3) / 4
+	return tx, nil
+}
+
+// OutputAmount returns the sum of the amounts of the outputs.
+func (tx *RawTransaction) OutputAmount() Amount {
+	var sum Amount
+	for _, output := range tx.Outputs {
+		sum += output.Amount
+	}
+	return sum
+}
+
+// txDecoder reads the fields of a serialized transaction. The first error is kept and all later reads return zero values.
+type txDecoder struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (d *txDecoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > d.r.Len() {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := make([]byte, n)
+	_, d.err = io.ReadFull(d.r, b)
+	return b
+}
+
+func (d *txDecoder) byte() byte {
+	b := d.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *txDecoder) uint32() uint32 {
+	b := d.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (d *txDecoder) uint64() uint64 {
+	b := d.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (d *txDecoder) varInt() uint64 {
+	switch prefix := d.byte(); prefix {
+	case 0xfd:
+		b := d.bytes(2)
+		if b == nil {
+			return 0
+		}
+		return uint64(binary.LittleEndian.Uint16(b))
+	case 0xfe:
+		return uint64(d.uint32())
+	case 0xff:
+		return d.uint64()
+	default:
+		return uint64(prefix)
+	}
+}
+
+func writeVarInt(w *bytes.Buffer, v uint64) {
+	switch {
+	case v < 0xfd:
+		w.WriteByte(byte(v))
+	case v <= 0xffff:
+		w.WriteByte(0xfd)
+		binary.Write(w, binary.LittleEndian, uint16(v))
+	case v <= 0xffffffff:
+		w.WriteByte(0xfe)
+		binary.Write(w, binary.LittleEndian, uint32(v))
+	default:
+		w.WriteByte(0xff)
+		binary.Write(w, binary.LittleEndian, v)
+	}
+}
+
+// reversedHex returns the hex encoding of b in reversed byte order, as used for txids.
+func reversedHex(b []byte) string {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return hex.EncodeToString(reversed)
+}
+
+// ErrUnknownInput is returned when the fee of a transaction cannot be computed because one of its inputs is not a coin of the wallet.
+var ErrUnknownInput = errors.New("input is not a coin of the wallet")
+
+// TransactionFee computes the fee of the transaction from the amounts of the wallet coins it spends.
+func TransactionFee(tx *RawTransaction, coins []ListCoinsResponse) (Amount, error) {
+	amounts := make(map[Coin]Amount, len(coins))
+	for _, coin := range coins {
+		amounts[Coin{TransactionID: coin.TxID, Index: coin.Index}] = coin.Amount
+	}
+	var in Amount
+	for _, input := range tx.Inputs {
+		amount, ok := amounts[Coin{TransactionID: input.PrevTxID, Index: int(input.PrevIndex)}]
+		if !ok {
+			return 0, fmt.Errorf("%w: %s:%d", ErrUnknownInput, input.PrevTxID, input.PrevIndex)
+		}
+		in += amount
+	}
+	return in - tx.OutputAmount(), nil
+}
@@ -0,0 +1,243 @@
+package wasabi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TxEventType is the type of a transaction event.
+type TxEventType int
+
+const (
+	// TxDetected is emitted when a transaction appears in the history of the wallet.
+	TxDetected TxEventType = iota + 1
+	// TxConfirmed is emitted when a transaction gets its first confirmation.
+	TxConfirmed
+	// TxReorged is emitted when a confirmed transaction becomes unconfirmed, is mined in another block or disappears from the history.
+	TxReorged
+)
+
+// String returns the string representation of the event type.
+func (t TxEventType) String() string {
+	switch t {
+	case TxDetected:
+		return "detected"
+	case TxConfirmed:
+		return "confirmed"
+	case TxReorged:
+		return "reorged"
+	default:
+		return "unknown"
+	}
+}
+
+// TxEvent is a change of a transaction in the history of a wallet.
+type TxEvent struct {
+	Type       TxEventType
+	WalletName string
+	// Transaction is the transaction as returned by GetHistory. For a transaction which disappeared from the history only Tx is set.
+	Transaction Transaction
+	// Height is the block height of the transaction, 0 if it is unconfirmed.
+	Height int
+	// PreviousHeight is the block height of the transaction known before the event, 0 if it was unconfirmed or unknown.
+	PreviousHeight int
+}
+
+// TxWatcherState is the state of a TxWatcher: the block height of every known transaction, 0 for unconfirmed ones.
+type TxWatcherState struct {
+	Heights map[TxID]int `json:"heights"`
+}
+
+// TxStateStore persists the state of a TxWatcher between restarts.
+type TxStateStore interface {
+	// Load returns the saved state. It returns an empty state if nothing was saved yet.
+	Load() (TxWatcherState, error)
+	// Save saves the state.
+	Save(state TxWatcherState) error
+}
+
+// FileTxStateStore is a TxStateStore keeping the state as JSON in a file. The file is replaced atomically on save.
+type FileTxStateStore struct {
+	Path string
+}
+
+// Load implements TxStateStore.
+func (s FileTxStateStore) Load() (TxWatcherState, error) {
+	var state TxWatcherState
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// Save implements TxStateStore.
+func (s FileTxStateStore) Save(state TxWatcherState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// TxWatcherOptions holds the options of a TxWatcher.
+type TxWatcherOptions struct {
+	// Interval is the interval between the history checks. Default is DefaultPollInterval.
+	Interval time.Duration
+	// Store persists the known transactions. Without a store the state is kept in memory only.
+	Store TxStateStore
+	// IgnoreExisting makes the first check record the current history without emitting events when no state was saved yet.
+	IgnoreExisting bool
+	// Buffer is the capacity of the events channel.
+	Buffer int
+	// OnError is called with the errors of the checks. If set, the watcher keeps running after a failed check; otherwise Run returns the error.
+	OnError func(err error)
+	// Clock provides the timers of the checks. Default is SystemClock.
+	Clock Clock
+}
+
+// TxWatcher periodically diffs the history of a wallet and emits events for new, confirmed and reorged transactions.
+type TxWatcher struct {
+	client     Client
+	walletName string
+	opts       TxWatcherOptions
+	events     chan TxEvent
+	state      TxWatcherState
+	runOnce    sync.Once
+}
+
+// NewTxWatcher creates a watcher of the history of the wallet. Call Run to start it and read the events from Events.
+func NewTxWatcher(c Client, walletName string, opts TxWatcherOptions) *TxWatcher {
+	return &TxWatcher{
+		client:     c,
+		walletName: walletName,
+		opts:       opts,
+		events:     make(chan TxEvent, opts.Buffer),
+	}
+}
+
+// Events returns the channel of the events. It is closed when Run returns.
+func (w *TxWatcher) Events() <-chan TxEvent {
+	return w.events
+}
+
+// Run watches the history until the context is done or a check fails (unless OnError is set). The state is saved after the events of a check are delivered, so after a restart events are neither missed nor replayed, except those of a check interrupted by the end of the context. Run can be called only once.
+func (w *TxWatcher) Run(ctx context.Context) error {
+	err := errors.New("the transaction watcher can be run only once")
+	w.runOnce.Do(func() {
+		defer close(w.events)
+		err = w.run(ctx)
+	})
+	return err
+}
+
+func (w *TxWatcher) run(ctx context.Context) error {
+	c := w.client.WithContext(ctx)
+	initialized := false
+	if w.opts.Store != nil {
+		state, err := w.opts.Store.Load()
+		if err != nil {
+			return err
+		}
+		w.state = state
+		initialized = state.Heights != nil
+	}
+	if w.state.Heights == nil {
+		w.state.Heights = map[TxID]int{}
+	}
+	silent := w.opts.IgnoreExisting && !initialized
+	return poll(ctx, w.opts.Clock, w.opts.Interval, func() (bool, error) {
+		events, err := w.check(c)
+		if err != nil {
+			if w.opts.OnError != nil {
+				w.opts.OnError(err)
+				return false, nil
+			}
+			return false, err
+		}
+		if !silent {
+			for _, event := range events {
+				select {
+				case w.events <- event:
+				case <-ctx.Done():
+					return false, ctx.Err()
+				}
+			}
+		}
+		silent = false
+		if w.opts.Store != nil {
+			if err := w.opts.Store.Save(w.state); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	})
+}
+
+// check diffs the history against the known state, updates the state and returns the events.
+func (w *TxWatcher) check(c Client) ([]TxEvent, error) {
+	history, err := c.GetHistory(w.walletName)
+	if err != nil {
+		return nil, err
+	}
+	status, err := c.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	var events []TxEvent
+	emit := func(typ TxEventType, tx Transaction, height, previous int) {
+		events = append(events, TxEvent{Type: typ, WalletName: w.walletName, Transaction: tx, Height: height, PreviousHeight: previous})
+	}
+	heights := make(map[TxID]int, len(history))
+	for _, tx := range history {
+		height := 0
+		if confirmationsAt(tx.Height, status.BestBlockchainHeight) > 0 {
+			height = tx.Height
+		}
+		heights[tx.TxID] = height
+		previous, known := w.state.Heights[tx.TxID]
+		switch {
+		case !known:
+			emit(TxDetected, tx, height, 0)
+			if height > 0 {
+				emit(TxConfirmed, tx, height, 0)
+			}
+		case previous == height:
+		case previous == 0:
+			emit(TxConfirmed, tx, height, previous)
+		default:
+			emit(TxReorged, tx, height, previous)
+			if height > 0 {
+				emit(TxConfirmed, tx, height, previous)
+			}
+		}
+	}
+	for txID, previous := range w.state.Heights {
+		if _, ok := heights[txID]; !ok && previous > 0 {
+			emit(TxReorged, Transaction{Tx: txID, TxID: txID}, 0, previous)
+		}
+	}
+	w.state.Heights = heights
+	return events, nil
+}
@@ -0,0 +1,221 @@
+package wasabi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DustThreshold is the smallest payment amount accepted by ValidateSend. Smaller outputs are not relayed by most nodes.
+const DustThreshold Amount = 546
+
+// ViolationCode identifies a rule checked by ValidateSend.
+type ViolationCode string
+
+const (
+	ViolationNoPayments           ViolationCode = "no_payments"
+	ViolationInvalidAddress       ViolationCode = "invalid_address"
+	ViolationNetworkMismatch      ViolationCode = "network_mismatch"
+	ViolationNonPositiveAmount    ViolationCode = "non_positive_amount"
+	ViolationDust                 ViolationCode = "dust"
+	ViolationDuplicateDestination ViolationCode = "duplicate_destination"
+	ViolationMultipleSubtractFee  ViolationCode = "multiple_subtract_fee"
+	ViolationUnknownCoin          ViolationCode = "unknown_coin"
+	ViolationLabelMixing          ViolationCode = "label_mixing"
+	ViolationInsufficientFunds    ViolationCode = "insufficient_funds"
+)
+
+// Violation is a problem of a send request found by ValidateSend.
+type Violation struct {
+	Code ViolationCode
+	// Payment is the index of the payment concerned, -1 if the violation is not about a payment.
+	Payment int
+	// Coin is the coin concerned, nil if the violation is not about a coin.
+	Coin    *Coin
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Code, v.Message)
+}
+
+// Violations is the result of ValidateSend.
+type Violations []Violation
+
+// Err returns a ValidationError holding the violations, or nil if there are none.
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
+
+// Has reports whether a violation has the code.
+func (v Violations) Has(code ViolationCode) bool {
+	for _, violation := range v {
+		if violation.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationError is an error holding the violations of a send request.
+type ValidationError struct {
+	Violations Violations
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.String()
+	}
+	return "invalid send request: " + strings.Join(messages, "; ")
+}
+
+// ValidateSend checks a send request without sending it: address validity and network, dust and non-positive amounts, duplicate destinations, fee subtraction, coins unknown to the wallet or mixing different labels, and whether the coins (or the whole balance if no coins are given) cover the payments and the fee estimated for feeTarget. The returned error is only set if the daemon cannot be queried.
+func ValidateSend(c Client, walletName string, payments []Payment, coins []Coin, feeTarget FeeTarget) (Violations, error) {
+	var violations Violations
+	add := func(code ViolationCode, payment int, coin *Coin, format string, args ...interface{}) {
+		violations = append(violations, Violation{Code: code, Payment: payment, Coin: coin, Message: fmt.Sprintf(format, args...)})
+	}
+
+	status, err := c.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payments) == 0 {
+		add(ViolationNoPayments, -1, nil, "the request has no payments")
+	}
+	destinations := map[Address]int{}
+	subtractFee := 0
+	var total Amount
+	for i, payment := range payments {
+		if err := ValidateAddress(string(payment.SendTo), status.Network); err != nil {
+			var mismatch *NetworkMismatchError
+			if errors.As(err, &mismatch) {
+				add(ViolationNetworkMismatch, i, nil, "%v", err)
+			} else {
+				add(ViolationInvalidAddress, i, nil, "%v", err)
+			}
+		}
+		switch {
+		case payment.Amount <= 0:
+			add(ViolationNonPositiveAmount, i, nil, "amount %d sat must be positive", payment.Amount)
+		case payment.Amount < DustThreshold:
+			add(ViolationDust, i, nil, "amount %d sat is below the dust threshold of %d sat", payment.Amount, DustThreshold)
+		}
+		if first, ok := destinations[payment.SendTo]; ok {
+			add(ViolationDuplicateDestination, i, nil, "address %s is also paid by payment %d", payment.SendTo, first)
+		} else {
+			destinations[payment.SendTo] = i
+		}
+		if payment.SubtractFee {
+			subtractFee++
+		}
+		total += payment.Amount
+	}
+	if subtractFee > 1 {
+		add(ViolationMultipleSubtractFee, -1, nil, "%d payments subtract the fee, at most one may", subtractFee)
+	}
+
+	unspent, err := c.ListUnspentCoins(walletName)
+	if err != nil {
+		return nil, err
+	}
+	byOutpoint := make(map[Coin]ListCoinsResponse, len(unspent))
+	for _, coin := range unspent {
+		if coin.SpentBy == nil || *coin.SpentBy == "" {
+			byOutpoint[Coin{TransactionID: coin.TxID, Index: coin.Index}] = coin
+		}
+	}
+	var inputs []Amount
+	if len(coins) > 0 {
+		labels := map[string]bool{}
+		for i := range coins {
+			coin, ok := byOutpoint[coins[i]]
+			if !ok {
+				add(ViolationUnknownCoin, -1, &coins[i], "coin %s:%d is not an unspent coin of the wallet", coins[i].TransactionID, coins[i].Index)
+				continue
+			}
+			inputs = append(inputs, coin.Amount)
+			labels[coin.Label] = true
+		}
+		if len(labels) > 1 {
+			names := make([]string, 0, len(labels))
+			for label := range labels {
+				if label == "" {
+					label = "(unlabeled)"
+				}
+				names = append(names, strconv.Quote(label))
+			}
+			sort.Strings(names)
+			add(ViolationLabelMixing, -1, nil, "the coins have different labels %s, spending them together links them", strings.Join(names, ", "))
+		}
+	} else {
+		for _, coin := range byOutpoint {
+			inputs = append(inputs, coin.Amount)
+		}
+	}
+
+	feeRate, err := EstimateFeeRate(c, feeTarget)
+	if err != nil {
+		return nil, err
+	}
+	// Count the inputs the daemon needs, largest first, and estimate the fee of a transaction with change.
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i] > inputs[j] })
+	var available, needed Amount
+	used := 0
+	for used < len(inputs) {
+		needed = total + EstimateFee(feeRate, used, len(payments)+1)
+		if subtractFee > 0 {
+			needed = total
+		}
+		if available >= needed {
+			break
+		}
+		available += inputs[used]
+		used++
+	}
+	needed = total + EstimateFee(feeRate, max(used, 1), len(payments)+1)
+	if subtractFee > 0 {
+		needed = total
+	}
+	if available < needed && len(payments) > 0 {
+		add(ViolationInsufficientFunds, -1, nil, "the coins provide %d sat, %d sat are needed including the estimated fee", available, needed)
+	}
+	return violations, nil
+}
+
+// EstimateFeeRate returns the daemon's fee rate estimation for the confirmation target, or for the nearest longer target.
+func EstimateFeeRate(c Client, feeTarget FeeTarget) (float64, error) {
+	rates, err := c.GetFeeRates()
+	if err != nil {
+		return 0, err
+	}
+	if rate, ok := rates[strconv.Itoa(feeTarget.Blocks())]; ok {
+		return float64(rate), nil
+	}
+	best, bestTarget := 0, -1
+	for key, rate := range rates {
+		target, err := strconv.Atoi(key)
+		if err != nil || target < feeTarget.Blocks() {
+			continue
+		}
+		if bestTarget < 0 || target < bestTarget {
+			best, bestTarget = rate, target
+		}
+	}
+	if bestTarget < 0 {
+		return 0, fmt.Errorf("no fee estimation for target %d", feeTarget)
+	}
+	return float64(best), nil
+}
+
+// ValidateSend checks a send request of the wallet without sending it, see ValidateSend.
+func (w *Wallet) ValidateSend(payments []Payment, coins []Coin, feeTarget FeeTarget) (Violations, error) {
+	return ValidateSend(w.client, w.name, payments, coins, feeTarget)
+}
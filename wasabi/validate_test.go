@@ -0,0 +1,107 @@
+package wasabi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestEstimateFee(t *testing.T) {
+	tests := []struct {
+		feeRate         float64
+		inputs, outputs int
+		want            Amount
+	}{
+		{feeRate: 1, inputs: 1, outputs: 1, want: 110},
+		{feeRate: 2, inputs: 1, outputs: 2, want: 282},
+		{feeRate: 1.1, inputs: 1, outputs: 2, want: 156},
+		{feeRate: 10, inputs: 3, outputs: 2, want: 2770},
+	}
+	for _, tt := range tests {
+		if got := EstimateFee(tt.feeRate, tt.inputs, tt.outputs); got != tt.want {
+			t.Errorf("EstimateFee(%g, %d, %d) = %d, want %d", tt.feeRate, tt.inputs, tt.outputs, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateFeeRate(t *testing.T) {
+	c := newTestClient(t, func(method string) (int, string) {
+		return http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"2":20,"6":12,"144":2}}`
+	})
+	tests := []struct {
+		target FeeTarget
+		want   float64
+	}{
+		{target: 2, want: 20},
+		{target: 3, want: 12},
+		{target: 6, want: 12},
+		{target: 100, want: 2},
+	}
+	for _, tt := range tests {
+		got, err := EstimateFeeRate(c, tt.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("EstimateFeeRate(%d) = %g, want %g", tt.target, got, tt.want)
+		}
+	}
+	if _, err := EstimateFeeRate(c, 200); err == nil {
+		t.Error("EstimateFeeRate(200) = nil error, want no estimation")
+	}
+}
+
+func TestValidateSend(t *testing.T) {
+	const (
+		address = "tb1qttn7vxzfh62ssm7asg6ycwwxxdxsjlj8qvd9ez"
+		other   = "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"
+	)
+	c := newTestClient(t, func(method string) (int, string) {
+		switch Method(method) {
+		case MethodGetStatus:
+			return http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"network":"TestNet"}}`
+		case MethodListUnspentCoins:
+			return http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":[
+				{"txid":"aa","index":0,"amount":10000,"label":"alice"},
+				{"txid":"bb","index":1,"amount":5000,"label":"bob"}]}`
+		case MethodGetFeeRates:
+			return http.StatusOK, `{"jsonrpc":"2.0","id":1,"result":{"6":10}}`
+		}
+		return http.StatusOK, noMethodResponse
+	})
+	tests := []struct {
+		name     string
+		payments []Payment
+		coins    []Coin
+		want     []ViolationCode
+	}{
+		// Both coins cover 12000 sat and the fee of 2 inputs and 2 outputs (2090 sat).
+		{name: "valid", payments: []Payment{{SendTo: address, Amount: 12000}}},
+		{name: "insufficient", payments: []Payment{{SendTo: address, Amount: 13000}}, want: []ViolationCode{ViolationInsufficientFunds}},
+		{name: "subtract fee", payments: []Payment{{SendTo: address, Amount: 15000, SubtractFee: true}}},
+		{name: "no payments", want: []ViolationCode{ViolationNoPayments}},
+		{name: "invalid address", payments: []Payment{{SendTo: "tb1qnotanaddress", Amount: 1000}}, want: []ViolationCode{ViolationInvalidAddress}},
+		{name: "network mismatch", payments: []Payment{{SendTo: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", Amount: 1000}}, want: []ViolationCode{ViolationNetworkMismatch}},
+		{name: "dust", payments: []Payment{{SendTo: address, Amount: 100}}, want: []ViolationCode{ViolationDust}},
+		{name: "duplicate", payments: []Payment{{SendTo: address, Amount: 1000}, {SendTo: address, Amount: 1000}}, want: []ViolationCode{ViolationDuplicateDestination}},
+		{name: "multiple subtract fee", payments: []Payment{{SendTo: address, Amount: 1000, SubtractFee: true}, {SendTo: other, Amount: 1000, SubtractFee: true}}, want: []ViolationCode{ViolationMultipleSubtractFee}},
+		{name: "unknown coin", payments: []Payment{{SendTo: address, Amount: 1000}}, coins: []Coin{{TransactionID: "cc"}}, want: []ViolationCode{ViolationUnknownCoin, ViolationInsufficientFunds}},
+		{name: "label mixing", payments: []Payment{{SendTo: address, Amount: 1000}}, coins: []Coin{{TransactionID: "aa"}, {TransactionID: "bb", Index: 1}}, want: []ViolationCode{ViolationLabelMixing}},
+		{name: "selected coins insufficient", payments: []Payment{{SendTo: address, Amount: 9000}}, coins: []Coin{{TransactionID: "aa"}}, want: []ViolationCode{ViolationInsufficientFunds}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := ValidateSend(c, "w", tt.payments, tt.coins, FeeTargetHour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(violations) != len(tt.want) {
+				t.Fatalf("violations = %v, want %v", violations, tt.want)
+			}
+			for _, code := range tt.want {
+				if !violations.Has(code) {
+					t.Fatalf("violations = %v, want %v", violations, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package wasabi
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultPollInterval is the interval between the calls of the waiting helpers if no interval is given.
+const DefaultPollInterval = 5 * time.Second
+
+// poll calls check immediately and then every interval of the clock (SystemClock if nil) until it reports done, returns an error or the context is done.
+func poll(ctx context.Context, clock Clock, interval time.Duration, check func() (bool, error)) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := clockOrDefault(clock).NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// pollWithBackoff works like poll, but the interval starts at initial and doubles after each check up to max.
+func pollWithBackoff(ctx context.Context, clock Clock, initial, max time.Duration, check func() (bool, error)) error {
+	clock = clockOrDefault(clock)
+	if initial <= 0 {
+		initial = 500 * time.Millisecond
+	}
+	if max < initial {
+		max = initial
+	}
+	interval := initial
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		timer := clock.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C():
+		}
+		interval = min(interval*2, max)
+	}
+}
@@ -0,0 +1,193 @@
+package wasabi
+
+// PasswordProvider returns the password of a wallet. It is called for each call which needs the password, so the password does not have to be kept in memory.
+type PasswordProvider func() (string, error)
+
+// StaticPassword returns a PasswordProvider which always returns the given password.
+func StaticPassword(password string) PasswordProvider {
+	return func() (string, error) {
+		return password, nil
+	}
+}
+
+// Wallet is a handle of one wallet. Its methods are the wallet methods of the Client without the walletName parameter.
+type Wallet struct {
+	client   Client
+	name     string
+	password PasswordProvider
+}
+
+// NewWallet returns a handle of the wallet with the given name.
+func NewWallet(c Client, walletName string) *Wallet {
+	return &Wallet{client: c, name: walletName}
+}
+
+// WithPassword returns a copy of the handle which gets the password of the wallet from the provider. Without a provider, the empty password is used.
+func (w *Wallet) WithPassword(provider PasswordProvider) *Wallet {
+	clone := *w
+	clone.password = provider
+	return &clone
+}
+
+// Name returns the name of the wallet.
+func (w *Wallet) Name() string {
+	return w.name
+}
+
+// Client returns the client of the handle.
+func (w *Wallet) Client() Client {
+	return w.client
+}
+
+func (w *Wallet) getPassword() (string, error) {
+	if w.password == nil {
+		return "", nil
+	}
+	return w.password()
+}
+
+// Load loads the wallet.
+func (w *Wallet) Load() error {
+	return w.client.LoadWallet(w.name)
+}
+
+// Info returns information about the wallet.
+func (w *Wallet) Info() (GetWalletInfoResponse, error) {
+	return w.client.GetWalletInfo(w.name)
+}
+
+// ListCoins returns the list of previously spent and currently unspent coins.
+func (w *Wallet) ListCoins() ([]ListCoinsResponse, error) {
+	return w.client.ListCoins(w.name)
+}
+
+// ListUnspentCoins returns the list of unspent coins.
+func (w *Wallet) ListUnspentCoins() ([]ListCoinsResponse, error) {
+	return w.client.ListUnspentCoins(w.name)
+}
+
+// NewAddress creates an address with the given label.
+func (w *Wallet) NewAddress(label string) (GetNewAddressResponse, error) {
+	return w.client.GetNewAddress(w.name, label)
+}
+
+// Send builds and broadcasts a transaction. If the request has no password, the password of the handle is used.
+func (w *Wallet) Send(req SendRequest) (SendResponse, error) {
+	if req.Password == "" {
+		password, err := w.getPassword()
+		if err != nil {
+			return SendResponse{}, err
+		}
+		req.Password = password
+	}
+	return w.client.Send(w.name, req)
+}
+
+// Build builds a transaction without broadcasting it. If the request has no password, the password of the handle is used.
+func (w *Wallet) Build(req BuildRequest) (string, error) {
+	if req.Password == "" {
+		password, err := w.getPassword()
+		if err != nil {
+			return "", err
+		}
+		req.Password = password
+	}
+	return w.client.Build(w.name, req)
+}
+
+// BuildUnsafeTransaction builds a transaction without checking fees. If the request has no password, the password of the handle is used.
+func (w *Wallet) BuildUnsafeTransaction(req BuildUnsafeRequest) (string, error) {
+	if req.Password == "" {
+		password, err := w.getPassword()
+		if err != nil {
+			return "", err
+		}
+		req.Password = password
+	}
+	return w.client.BuildUnsafeTransaction(w.name, req)
+}
+
+// Broadcast broadcasts a transaction and returns its id.
+func (w *Wallet) Broadcast(hex string) (TxID, error) {
+	return w.client.Broadcast(w.name, hex)
+}
+
+// History returns the list of all transactions of the wallet.
+func (w *Wallet) History() ([]Transaction, error) {
+	return w.client.GetHistory(w.name)
+}
+
+// ListKeys returns the list of all the generated keys.
+func (w *Wallet) ListKeys() ([]GeneratedKey, error) {
+	return w.client.ListKeys(w.name)
+}
+
+// StartCoinJoin starts coinjoin with the password of the handle.
+func (w *Wallet) StartCoinJoin(opts StartCoinJoinOptions) error {
+	password, err := w.getPassword()
+	if err != nil {
+		return err
+	}
+	return w.client.StartCoinJoin(w.name, password, opts)
+}
+
+// StartCoinJoinSweep starts a coinjoin to another wallet with the password of the handle.
+func (w *Wallet) StartCoinJoinSweep(outputWalletName string) error {
+	password, err := w.getPassword()
+	if err != nil {
+		return err
+	}
+	return w.client.StartCoinJoinSweep(w.name, password, outputWalletName)
+}
+
+// StopCoinJoin stops coinjoin.
+func (w *Wallet) StopCoinJoin() error {
+	return w.client.StopCoinJoin(w.name)
+}
+
+// ExcludeFromCoinJoin excludes a coin from coinjoin or includes it again.
+func (w *Wallet) ExcludeFromCoinJoin(txID TxID, index int, exclude bool) error {
+	return w.client.ExcludeFromCoinJoin(w.name, txID, index, exclude)
+}
+
+// ExcludeCoinsFromCoinJoin excludes many coins from coinjoin or includes them again.
+func (w *Wallet) ExcludeCoinsFromCoinJoin(coins []Coin, exclude bool) error {
+	return w.client.ExcludeCoinsFromCoinJoin(w.name, coins, exclude)
+}
+
+// PayInCoinJoin registers a payment in coinjoin with the password of the handle and returns the payment id.
+func (w *Wallet) PayInCoinJoin(address Address, amount Amount) (string, error) {
+	password, err := w.getPassword()
+	if err != nil {
+		return "", err
+	}
+	return w.client.PayInCoinJoin(w.name, address, amount, password)
+}
+
+// ListPaymentsInCoinJoin returns the list of payments in coinjoin.
+func (w *Wallet) ListPaymentsInCoinJoin() ([]ListPaymentsInCoinJoinResponseItem, error) {
+	return w.client.ListPaymentsInCoinJoin(w.name)
+}
+
+// CancelPaymentInCoinJoin cancels a pending payment in coinjoin.
+func (w *Wallet) CancelPaymentInCoinJoin(paymentID string) error {
+	return w.client.CancelPaymentInCoinJoin(w.name, paymentID)
+}
+
+// CancelTransaction builds a transaction cancelling the given one with the password of the handle. The result is not broadcast.
+func (w *Wallet) CancelTransaction(txID TxID) (string, error) {
+	password, err := w.getPassword()
+	if err != nil {
+		return "", err
+	}
+	return w.client.CancelTransaction(w.name, txID, password)
+}
+
+// SpeedUpTransaction builds a transaction speeding up the given one with the password of the handle. The result is not broadcast.
+func (w *Wallet) SpeedUpTransaction(txID TxID) (string, error) {
+	password, err := w.getPassword()
+	if err != nil {
+		return "", err
+	}
+	return w.client.SpeedUpTransaction(w.name, txID, password)
+}
@@ -0,0 +1,311 @@
+package wasabitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// CassetteMode is the mode of a Cassette.
+type CassetteMode int
+
+const (
+	// CassetteReplay answers the requests from the cassette file and never contacts the daemon. It is the default, so tests in CI are deterministic.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends the requests to the daemon and records them. The file is written by Save.
+	CassetteRecord
+	// CassetteAuto replays the cassette file if it exists and records it otherwise.
+	CassetteAuto
+)
+
+// redactedValue replaces the secrets in cassettes.
+const redactedValue = "[REDACTED]"
+
+// Interaction is a recorded JSON-RPC request and its response.
+type Interaction struct {
+	// Method is the rpc method of the request (of its first call for batches).
+	Method string `json:"method"`
+	// Path is the url path of the request, i.e. the wallet name for wallet calls.
+	Path string `json:"path"`
+	// Request is the JSON-RPC request with secrets redacted. The id of single requests is removed, as it is random.
+	Request json.RawMessage `json:"request"`
+	// Status is the http status of the response.
+	Status int `json:"status"`
+	// Response is the response body with secret results redacted.
+	Response json.RawMessage `json:"response"`
+}
+
+// CassetteOptions holds the options of a Cassette.
+type CassetteOptions struct {
+	// Mode is the mode of the cassette. Default is CassetteReplay.
+	Mode CassetteMode
+	// Transport sends the requests while recording. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Redact is called on each recorded interaction after passwords, mnemonics and secret results were redacted, e.g. to mask addresses or amounts. The same function must be used while replaying, because requests are matched after redaction.
+	Redact func(i *Interaction)
+}
+
+// Cassette is an http.RoundTripper which records the requests to a daemon and their responses to a file, and replays them in tests. Passwords and mnemonics in the params and secret results (see wasabi.MethodSpec) are redacted, so the files can be committed.
+// While replaying, each request is answered by the first unused interaction with the same path and the same redacted request.
+type Cassette struct {
+	path string
+	opts CassetteOptions
+	mode CassetteMode
+
+	mutex        sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// ErrNoInteraction is returned while replaying for requests which were not recorded.
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// NewCassette opens the cassette file at path. In replay mode the file must exist.
+func NewCassette(path string, opts CassetteOptions) (*Cassette, error) {
+	c := &Cassette{path: path, opts: opts, mode: opts.Mode}
+	if c.opts.Transport == nil {
+		c.opts.Transport = http.DefaultTransport
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && c.mode == CassetteAuto:
+		c.mode = CassetteRecord
+		return c, nil
+	case err != nil && c.mode != CassetteRecord:
+		return nil, err
+	case c.mode == CassetteRecord:
+		return c, nil
+	}
+	c.mode = CassetteReplay
+	var file struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// Recording reports whether the cassette records (instead of replaying).
+func (c *Cassette) Recording() bool {
+	return c.mode == CassetteRecord
+}
+
+// Interactions returns the interactions of the cassette.
+func (c *Cassette) Interactions() []Interaction {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Unused returns the recorded interactions which were not replayed, e.g. to check that a test made all the calls it made while recording.
+func (c *Cassette) Unused() []Interaction {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var unused []Interaction
+	for i, interaction := range c.interactions {
+		if i < len(c.used) && !c.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing while replaying.
+func (c *Cassette) Save() error {
+	if c.mode != CassetteRecord {
+		return nil
+	}
+	c.mutex.Lock()
+	data, err := json.MarshalIndent(struct {
+		Interactions []Interaction `json:"interactions"`
+	}{c.interactions}, "", "  ")
+	c.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	interaction := Interaction{Path: strings.Trim(req.URL.Path, "/")}
+	interaction.Method, interaction.Request = sanitizeRequest(body)
+
+	if c.mode == CassetteRecord {
+		return c.record(req, body, interaction)
+	}
+	if c.opts.Redact != nil {
+		c.opts.Redact(&interaction)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, recorded := range c.interactions {
+		if c.used[i] || recorded.Path != interaction.Path || !sameJSON(recorded.Request, interaction.Request) {
+			continue
+		}
+		c.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+			StatusCode:    recorded.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader(withRequestID(recorded.Response, body))),
+			ContentLength: -1,
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for %s on /%s: %s", ErrNoInteraction, interaction.Method, interaction.Path, interaction.Request)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, interaction Interaction) (*http.Response, error) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := c.opts.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction.Status = resp.StatusCode
+	interaction.Response = sanitizeResponse(wasabi.Method(interaction.Method), respBody)
+	if c.opts.Redact != nil {
+		c.opts.Redact(&interaction)
+	}
+	c.mutex.Lock()
+	c.interactions = append(c.interactions, interaction)
+	c.used = append(c.used, true)
+	c.mutex.Unlock()
+	return resp, nil
+}
+
+// sanitizeRequest returns the method and the redacted request body. The id of a single request is removed; the ids of batch requests are sequential and kept.
+func sanitizeRequest(body []byte) (string, json.RawMessage) {
+	var single map[string]interface{}
+	if err := json.Unmarshal(body, &single); err == nil {
+		delete(single, "id")
+		method, _ := single["method"].(string)
+		single["params"] = redactJSONParams(wasabi.Method(method), single["params"])
+		return method, mustMarshal(single)
+	}
+	var batch []map[string]interface{}
+	if err := json.Unmarshal(body, &batch); err == nil && len(batch) > 0 {
+		method, _ := batch[0]["method"].(string)
+		for _, request := range batch {
+			name, _ := request["method"].(string)
+			request["params"] = redactJSONParams(wasabi.Method(name), request["params"])
+		}
+		return method, mustMarshal(batch)
+	}
+	return "", mustMarshal(string(body))
+}
+
+// redactJSONParams replaces the secret positional params of the method and the password and mnemonic named params.
+func redactJSONParams(method wasabi.Method, params interface{}) interface{} {
+	switch p := params.(type) {
+	case []interface{}:
+		spec, _ := wasabi.LookupMethod(method)
+		for _, i := range spec.SecretParams {
+			if i < len(p) {
+				p[i] = redactedValue
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range []string{"password", "mnemonic"} {
+			if _, ok := p[name]; ok {
+				p[name] = redactedValue
+			}
+		}
+	}
+	return params
+}
+
+// sanitizeResponse redacts the result of methods returning secrets. The random id of a single response is removed, it is set again on replay.
+func sanitizeResponse(method wasabi.Method, body []byte) json.RawMessage {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		if json.Valid(body) {
+			return json.RawMessage(body)
+		}
+		return mustMarshal(string(body))
+	}
+	delete(response, "id")
+	if spec, _ := wasabi.LookupMethod(method); spec.SecretResult {
+		if result, ok := response["result"]; ok && string(result) != "null" {
+			response["result"] = mustMarshal(redactedValue)
+		}
+	}
+	return mustMarshal(response)
+}
+
+// withRequestID returns the recorded response of a single request with the id of the replayed request, so the client accepts it.
+func withRequestID(response json.RawMessage, request []byte) []byte {
+	var fields map[string]json.RawMessage
+	var req struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(response, &fields) != nil || json.Unmarshal(request, &req) != nil || req.ID == nil {
+		var text string
+		if json.Unmarshal(response, &text) == nil {
+			return []byte(text)
+		}
+		return response
+	}
+	fields["id"] = req.ID
+	return mustMarshal(fields)
+}
+
+func sameJSON(a, b json.RawMessage) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(mustMarshal(x), mustMarshal(y))
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// Values decoded from JSON can always be encoded.
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,151 @@
+package wasabitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// cassetteClient returns a client of the server sending its requests through the cassette.
+func cassetteClient(t *testing.T, s *Server, cassette *Cassette) wasabi.Client {
+	t.Helper()
+	config := s.Config()
+	config.Transport = cassette
+	c, err := wasabi.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// cassetteSession makes the calls recorded and replayed by the tests.
+func cassetteSession(t *testing.T, c wasabi.Client) (mnemonic string, sent wasabi.SendResponse) {
+	t.Helper()
+	mnemonic, err := c.CreateWallet("new", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := c.GetNewAddress("w", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, err = c.Send("w", wasabi.SendRequest{
+		Payments:  []wasabi.Payment{{SendTo: address.Address, Amount: 100_000, Label: "alice"}},
+		FeeTarget: wasabi.FeeTargetHour,
+		Password:  "pw",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mnemonic, sent
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	s, _ := newFundedServer(t, ServerOptions{})
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	redact := func(i *Interaction) {
+		i.Request = bytes.ReplaceAll(i.Request, []byte("alice"), []byte("someone"))
+		i.Response = bytes.ReplaceAll(i.Response, []byte("alice"), []byte("someone"))
+	}
+
+	recorder, err := NewCassette(path, CassetteOptions{Mode: CassetteAuto, Redact: redact})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !recorder.Recording() {
+		t.Fatal("Recording() = false for a missing cassette in auto mode")
+	}
+	mnemonic, recorded := cassetteSession(t, cassetteClient(t, s, recorder))
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{`"secret"`, `"pw"`, mnemonic, "alice"} {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("the cassette contains %s:\n%s", secret, content)
+		}
+	}
+	var file struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		t.Fatal(err)
+	}
+	for _, interaction := range file.Interactions {
+		if strings.Contains(string(interaction.Request), `"id"`) {
+			t.Errorf("the random request id was recorded: %s", interaction.Request)
+		}
+	}
+
+	// Replay without the daemon.
+	s.Close()
+	player, err := NewCassette(path, CassetteOptions{Mode: CassetteAuto, Redact: redact})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.Recording() {
+		t.Fatal("Recording() = true for an existing cassette in auto mode")
+	}
+	c := cassetteClient(t, s, player)
+	replayedMnemonic, replayed := cassetteSession(t, c)
+	if replayedMnemonic != redactedValue {
+		t.Errorf("replayed mnemonic = %q, want %q", replayedMnemonic, redactedValue)
+	}
+	if replayed != recorded {
+		t.Errorf("replayed send = %+v, want %+v", replayed, recorded)
+	}
+	if unused := player.Unused(); len(unused) != 0 {
+		t.Errorf("Unused() = %+v", unused)
+	}
+	if _, err := c.GetStatus(); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("GetStatus() not recorded = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestCassetteReplayOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	content := `{"interactions": [
+		{"method": "getwalletinfo", "path": "w", "request": {"jsonrpc": "2.0", "method": "getwalletinfo", "params": null}, "status": 200, "response": {"jsonrpc": "2.0", "result": {"walletName": "w", "anonScoreTarget": 5}}},
+		{"method": "getwalletinfo", "path": "w", "request": {"jsonrpc": "2.0", "method": "getwalletinfo", "params": null}, "status": 200, "response": {"jsonrpc": "2.0", "result": {"walletName": "w", "anonScoreTarget": 7}}},
+		{"method": "getwalletinfo", "path": "v", "request": {"jsonrpc": "2.0", "method": "getwalletinfo", "params": null}, "status": 200, "response": {"jsonrpc": "2.0", "result": {"walletName": "v", "anonScoreTarget": 9}}}
+	]}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCassette(filepath.Join(t.TempDir(), "missing.json"), CassetteOptions{}); err == nil {
+		t.Fatal("NewCassette() of a missing file in replay mode = nil error")
+	}
+	player, err := NewCassette(path, CassetteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := wasabi.NewClient(wasabi.Config{Host: "127.0.0.1", Port: 1, Transport: player})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []int{5, 7} {
+		info, err := c.GetWalletInfo("w")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.AnonScoreTarget != want {
+			t.Fatalf("AnonScoreTarget = %d, want %d", info.AnonScoreTarget, want)
+		}
+	}
+	if _, err := c.GetWalletInfo("w"); !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("GetWalletInfo() after the recorded calls = %v, want ErrNoInteraction", err)
+	}
+	if unused := player.Unused(); len(unused) != 1 || unused[0].Path != "v" {
+		t.Fatalf("Unused() = %+v, want the call of wallet v", unused)
+	}
+}
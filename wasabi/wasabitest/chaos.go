@@ -0,0 +1,288 @@
+package wasabitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Fault is a fault injected by a ChaosTransport.
+type Fault string
+
+const (
+	// FaultLatency delays the request.
+	FaultLatency Fault = "latency"
+	// FaultDrop drops the connection, either before the request reaches the daemon or after the daemon handled it and before the response arrives.
+	FaultDrop Fault = "drop"
+	// FaultTruncate cuts the response body, so it is not valid JSON.
+	FaultTruncate Fault = "truncate"
+	// FaultServerError answers with a 5xx status without sending the request.
+	FaultServerError Fault = "server_error"
+	// FaultDuplicate sends the request twice concurrently, as a proxy retrying an in-flight request would.
+	FaultDuplicate Fault = "duplicate"
+)
+
+// ErrConnectionDropped is returned for the requests dropped by a ChaosTransport.
+var ErrConnectionDropped = errors.New("connection dropped by chaos transport")
+
+// ChaosOptions holds the options of a ChaosTransport. The rates are probabilities between 0 and 1 and are drawn for each request.
+type ChaosOptions struct {
+	// Transport sends the requests. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Seed seeds the random faults, so the faults of a test are reproducible.
+	Seed int64
+	// Clock provides the timers of the latency. Default is wasabi.SystemClock.
+	Clock wasabi.Clock
+	// MinLatency and MaxLatency bound the random latency added to each request. No latency is added if MaxLatency is zero.
+	MinLatency, MaxLatency time.Duration
+	// DropRate is the probability that the connection drops. Half of the dropped requests never reach the daemon, the other half are lost after the daemon handled them.
+	DropRate float64
+	// TruncateRate is the probability that the response body is truncated.
+	TruncateRate float64
+	// ServerErrorRate is the probability that a burst of 5xx responses starts.
+	ServerErrorRate float64
+	// ServerErrorBurst is the number of consecutive requests failing in a burst. Default is 1.
+	ServerErrorBurst int
+	// ServerErrorStatus is the status of the 5xx responses. Default is 503.
+	ServerErrorStatus int
+	// DuplicateRate is the probability that the request is sent twice concurrently. The response of the first request is returned.
+	DuplicateRate float64
+	// Methods restricts the faults to the requests of these methods (the first call of batches). Default is all methods.
+	Methods []wasabi.Method
+	// OnFault is called with each injected fault. Optional.
+	OnFault func(fault Fault, method wasabi.Method)
+}
+
+// ChaosTransport is an http.RoundTripper injecting random faults (latency, dropped connections, truncated responses, 5xx bursts and duplicated requests), to test the resilience of callers and interceptors, e.g. retries and circuit breakers.
+//
+//	chaos := wasabitest.NewChaosTransport(wasabitest.ChaosOptions{DropRate: 0.1, ServerErrorRate: 0.05, ServerErrorBurst: 3})
+//	cfg := server.Config()
+//	cfg.Transport = chaos
+type ChaosTransport struct {
+	opts    ChaosOptions
+	methods map[wasabi.Method]bool
+
+	mutex    sync.Mutex
+	rand     *rand.Rand
+	burst    int
+	disabled bool
+	faults   map[Fault]int
+}
+
+// NewChaosTransport creates a transport injecting faults into the requests sent by opts.Transport.
+func NewChaosTransport(opts ChaosOptions) *ChaosTransport {
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+	if opts.Clock == nil {
+		opts.Clock = wasabi.SystemClock
+	}
+	if opts.ServerErrorBurst <= 0 {
+		opts.ServerErrorBurst = 1
+	}
+	if opts.ServerErrorStatus == 0 {
+		opts.ServerErrorStatus = http.StatusServiceUnavailable
+	}
+	t := &ChaosTransport{
+		opts:   opts,
+		rand:   rand.New(rand.NewSource(opts.Seed)),
+		faults: map[Fault]int{},
+	}
+	if len(opts.Methods) > 0 {
+		t.methods = map[wasabi.Method]bool{}
+		for _, method := range opts.Methods {
+			t.methods[method] = true
+		}
+	}
+	return t
+}
+
+// SetEnabled enables or disables the faults, e.g. to set up a test without faults. The transport is enabled when created.
+func (t *ChaosTransport) SetEnabled(enabled bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.disabled = !enabled
+}
+
+// Faults returns the number of injected faults by kind.
+func (t *ChaosTransport) Faults() map[Fault]int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	faults := make(map[Fault]int, len(t.faults))
+	for fault, n := range t.faults {
+		faults[fault] = n
+	}
+	return faults
+}
+
+// chaosPlan holds the faults drawn for a request.
+type chaosPlan struct {
+	latency     time.Duration
+	serverError bool
+	dropBefore  bool
+	dropAfter   bool
+	truncate    bool
+	duplicate   bool
+	cut         float64
+}
+
+// plan draws the faults of a request of the method.
+func (t *ChaosTransport) plan(method wasabi.Method) chaosPlan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var p chaosPlan
+	if t.disabled || (t.methods != nil && !t.methods[method]) {
+		return p
+	}
+	if t.opts.MaxLatency > 0 {
+		p.latency = t.opts.MinLatency
+		if spread := t.opts.MaxLatency - t.opts.MinLatency; spread > 0 {
+			p.latency += time.Duration(t.rand.Int63n(int64(spread)))
+		}
+	}
+	if t.burst == 0 && t.hit(t.opts.ServerErrorRate) {
+		t.burst = t.opts.ServerErrorBurst
+	}
+	if t.burst > 0 {
+		t.burst--
+		p.serverError = true
+		return p
+	}
+	if t.hit(t.opts.DropRate) {
+		if t.rand.Intn(2) == 0 {
+			p.dropBefore = true
+		} else {
+			p.dropAfter = true
+		}
+	}
+	p.truncate = t.hit(t.opts.TruncateRate)
+	p.duplicate = t.hit(t.opts.DuplicateRate)
+	p.cut = t.rand.Float64()
+	return p
+}
+
+func (t *ChaosTransport) hit(rate float64) bool {
+	return rate > 0 && t.rand.Float64() < rate
+}
+
+func (t *ChaosTransport) record(fault Fault, method wasabi.Method) {
+	t.mutex.Lock()
+	t.faults[fault]++
+	t.mutex.Unlock()
+	if t.opts.OnFault != nil {
+		t.opts.OnFault(fault, method)
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ChaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	method := requestMethod(body)
+	p := t.plan(method)
+
+	if p.latency > 0 {
+		t.record(FaultLatency, method)
+		timer := t.opts.Clock.NewTimer(p.latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C():
+		}
+	}
+	if p.serverError {
+		t.record(FaultServerError, method)
+		status := t.opts.ServerErrorStatus
+		return chaosResponse(req, status, fmt.Sprintf("%d %s", status, http.StatusText(status))), nil
+	}
+	if p.dropBefore {
+		t.record(FaultDrop, method)
+		return nil, ErrConnectionDropped
+	}
+
+	if p.duplicate {
+		t.record(FaultDuplicate, method)
+		duplicate := req.Clone(req.Context())
+		duplicate.Body = io.NopCloser(bytes.NewReader(body))
+		go func() {
+			resp, err := t.opts.Transport.RoundTrip(duplicate)
+			if err == nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := t.opts.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if p.dropAfter {
+		t.record(FaultDrop, method)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, ErrConnectionDropped
+	}
+	if p.truncate {
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(respBody) > 0 {
+			t.record(FaultTruncate, method)
+			respBody = respBody[:int(p.cut*float64(len(respBody)))]
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		resp.ContentLength = int64(len(respBody))
+		resp.Header.Del("Content-Length")
+	}
+	return resp, nil
+}
+
+// requestMethod returns the method of a JSON-RPC request, or of the first call of a batch.
+func requestMethod(body []byte) wasabi.Method {
+	var request struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(body, &request) == nil {
+		return wasabi.Method(request.Method)
+	}
+	var batch []struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(body, &batch) == nil && len(batch) > 0 {
+		return wasabi.Method(batch[0].Method)
+	}
+	return ""
+}
+
+func chaosResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}},
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,164 @@
+package wasabitest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// chaosClient returns a client of the server sending its requests through a chaos transport with the options.
+func chaosClient(t *testing.T, s *Server, opts ChaosOptions) (wasabi.Client, *ChaosTransport) {
+	t.Helper()
+	chaos := NewChaosTransport(opts)
+	config := s.Config()
+	config.Transport = chaos
+	c, err := wasabi.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, chaos
+}
+
+func TestChaosServerErrorBurst(t *testing.T) {
+	s := NewServer(ServerOptions{})
+	defer s.Close()
+	var injected []wasabi.Method
+	c, chaos := chaosClient(t, s, ChaosOptions{
+		ServerErrorRate:   1,
+		ServerErrorBurst:  2,
+		ServerErrorStatus: http.StatusBadGateway,
+		Methods:           []wasabi.Method{wasabi.MethodGetStatus},
+		OnFault:           func(fault Fault, method wasabi.Method) { injected = append(injected, method) },
+	})
+	for i := 0; i < 3; i++ {
+		var httpErr *wasabi.HTTPError
+		if _, err := c.GetStatus(); !errors.As(err, &httpErr) || httpErr.Status != http.StatusBadGateway {
+			t.Fatalf("GetStatus() = %v, want http status 502", err)
+		}
+	}
+	// The other methods are not affected.
+	if _, err := c.ListWallets(); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Requests()) != 1 {
+		t.Fatalf("the daemon received %d requests, want only the unaffected one", len(s.Requests()))
+	}
+	if faults := chaos.Faults(); faults[FaultServerError] != 3 || len(faults) != 1 {
+		t.Fatalf("Faults() = %v, want 3 server errors", faults)
+	}
+	if len(injected) != 3 || injected[0] != wasabi.MethodGetStatus {
+		t.Fatalf("OnFault was called with %v", injected)
+	}
+
+	chaos.SetEnabled(false)
+	if _, err := c.GetStatus(); err != nil {
+		t.Fatalf("GetStatus() with the faults disabled = %v", err)
+	}
+}
+
+func TestChaosDrop(t *testing.T) {
+	s := NewServer(ServerOptions{})
+	defer s.Close()
+	c, chaos := chaosClient(t, s, ChaosOptions{DropRate: 1, Seed: 1})
+	const calls = 20
+	for i := 0; i < calls; i++ {
+		_, err := c.GetStatus()
+		var connErr *wasabi.ConnectionError
+		if !errors.As(err, &connErr) || !errors.Is(err, ErrConnectionDropped) {
+			t.Fatalf("GetStatus() = %v, want a dropped connection", err)
+		}
+	}
+	// The requests dropped after the daemon handled them reached it, the others did not.
+	reached := len(s.Requests())
+	if reached == 0 || reached == calls {
+		t.Fatalf("%d of %d dropped requests reached the daemon, want some of them", reached, calls)
+	}
+	if faults := chaos.Faults(); faults[FaultDrop] != calls {
+		t.Fatalf("Faults() = %v, want %d drops", faults, calls)
+	}
+}
+
+func TestChaosTruncate(t *testing.T) {
+	s := NewServer(ServerOptions{})
+	defer s.Close()
+	c, chaos := chaosClient(t, s, ChaosOptions{TruncateRate: 1})
+	for i := 0; i < 5; i++ {
+		if _, err := c.GetStatus(); err == nil {
+			t.Fatal("GetStatus() of a truncated response = nil error")
+		}
+	}
+	if faults := chaos.Faults(); faults[FaultTruncate] != 5 {
+		t.Fatalf("Faults() = %v, want 5 truncations", faults)
+	}
+}
+
+func TestChaosDuplicate(t *testing.T) {
+	s := NewServer(ServerOptions{})
+	defer s.Close()
+	c, _ := chaosClient(t, s, ChaosOptions{DuplicateRate: 1})
+	if _, err := c.GetStatus(); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.Requests()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("the daemon received %d requests, want the duplicate too", len(s.Requests()))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestChaosLatency(t *testing.T) {
+	s := NewServer(ServerOptions{})
+	defer s.Close()
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	c, chaos := chaosClient(t, s, ChaosOptions{Clock: clock, MinLatency: time.Second, MaxLatency: time.Second})
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.GetStatus()
+		done <- err
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clock.BlockUntil(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Requests()) != 0 {
+		t.Fatal("the request reached the daemon before the latency passed")
+	}
+	clock.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if faults := chaos.Faults(); faults[FaultLatency] != 1 {
+		t.Fatalf("Faults() = %v, want 1 latency", faults)
+	}
+
+	// A cancelled request does not wait for the latency.
+	cancelled, cancelRequest := context.WithCancel(context.Background())
+	cancelRequest()
+	if _, err := c.WithContext(cancelled).GetStatus(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetStatus() with a cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestRequestMethod(t *testing.T) {
+	tests := []struct {
+		body string
+		want wasabi.Method
+	}{
+		{body: `{"jsonrpc":"2.0","method":"getstatus"}`, want: wasabi.MethodGetStatus},
+		{body: `[{"method":"excludefromcoinjoin"},{"method":"getstatus"}]`, want: wasabi.MethodExcludeFromCoinJoin},
+		{body: `[]`},
+		{body: `not json`},
+	}
+	for _, tt := range tests {
+		if got := requestMethod([]byte(tt.body)); got != tt.want {
+			t.Errorf("requestMethod(%s) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package wasabitest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// FakeClock is a wasabi.Clock whose time only moves when Advance or Set is called, so tests of the waiting helpers and watchers run without sleeping.
+//
+//	clock := wasabitest.NewFakeClock(time.Now())
+//	go wasabi.WaitForConfirmations(ctx, c, "w", txID, 1, wasabi.WaitForConfirmationsOptions{Clock: clock})
+//	clock.BlockUntil(ctx, 1) // the helper checked once and waits for the next tick
+//	clock.Advance(wasabi.DefaultPollInterval)
+type FakeClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	changed chan struct{}
+}
+
+// fakeWaiter is a pending timer or ticker of a FakeClock.
+type fakeWaiter struct {
+	clock  *FakeClock
+	when   time.Time
+	period time.Duration // zero for timers
+	c      chan time.Time
+}
+
+// NewFakeClock creates a fake clock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+// Now implements wasabi.Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// NewTimer implements wasabi.Clock.
+func (c *FakeClock) NewTimer(d time.Duration) wasabi.Timer {
+	return c.add(d, 0)
+}
+
+// NewTicker implements wasabi.Clock. It panics if d is not positive, like time.NewTicker.
+func (c *FakeClock) NewTicker(d time.Duration) wasabi.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return fakeTicker{c.add(d, d)}
+}
+
+func (c *FakeClock) add(d, period time.Duration) *fakeWaiter {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	w := &fakeWaiter{clock: c, when: c.now.Add(d), period: period, c: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	c.fire()
+	c.notify()
+	return w
+}
+
+// Advance moves the time forward by d and fires the timers and tickers which are due. Like time.Ticker, a ticker whose channel is full drops the ticks.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	c.fire()
+	c.notify()
+}
+
+// Set sets the time and fires the timers and tickers which are due. Setting a time before the current time fires nothing.
+func (c *FakeClock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = now
+	c.fire()
+	c.notify()
+}
+
+// Waiters returns the number of pending timers and tickers.
+func (c *FakeClock) Waiters() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n timers and tickers are pending, i.e. the code under test reached its waits, or the context is done.
+func (c *FakeClock) BlockUntil(ctx context.Context, n int) error {
+	for {
+		c.mutex.Lock()
+		pending, changed := len(c.waiters), c.changed
+		c.mutex.Unlock()
+		if pending >= n {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// fire sends the time to the due waiters. The caller must hold the mutex.
+func (c *FakeClock) fire() {
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.when.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		select {
+		case w.c <- w.when:
+		default:
+		}
+		if w.period > 0 {
+			for !w.when.After(c.now) {
+				w.when = w.when.Add(w.period)
+			}
+			pending = append(pending, w)
+		}
+	}
+	c.waiters = pending
+}
+
+// notify wakes up BlockUntil. The caller must hold the mutex.
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// stop removes the waiter and reports whether it was pending.
+func (c *FakeClock) stop(w *fakeWaiter) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, pending := range c.waiters {
+		if pending == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.notify()
+			return true
+		}
+	}
+	return false
+}
+
+func (w *fakeWaiter) C() <-chan time.Time {
+	return w.c
+}
+
+func (w *fakeWaiter) Stop() bool {
+	return w.clock.stop(w)
+}
+
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeWaiter.Stop()
+}
+
+var _ wasabi.Clock = (*FakeClock)(nil)
@@ -0,0 +1,160 @@
+package wasabitest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// DefaultPhaseDuration is the time a simulated coinjoin round spends in each phase if no duration is given.
+const DefaultPhaseDuration = time.Minute
+
+// RoundResult is the result of a coinjoin round of a wallet of the fake daemon.
+type RoundResult struct {
+	// WalletName is the wallet which took part in the round.
+	WalletName string
+	// Round is the number of the round, as reported in the payment in coinjoin states.
+	Round int
+	// Failed reports that the round failed; its payments are pending again.
+	Failed bool
+	// TxID is the id of the coinjoin transaction. It is empty if the round failed or the wallet had nothing to mix.
+	TxID wasabi.TxID
+	// Paid are the ids of the payments in coinjoin paid by the round.
+	Paid []string
+}
+
+// CoinJoinSimulatorOptions holds the options of a CoinJoinSimulator.
+type CoinJoinSimulatorOptions struct {
+	// PhaseDuration is the time a round spends in each phase (in schedule, in progress, in critical phase) when the simulator runs. Default is DefaultPhaseDuration.
+	PhaseDuration time.Duration
+	// Clock drives the rounds when the simulator runs. Default is wasabi.SystemClock; with a FakeClock the rounds advance with the clock.
+	Clock wasabi.Clock
+	// FailureRate is the probability (between 0 and 1) that a round fails in its critical phase, e.g. because another participant did not sign.
+	FailureRate float64
+	// Seed seeds the random failures, so the failures of a test are reproducible.
+	Seed int64
+	// OnRound is called after each round. Optional.
+	OnRound func(result RoundResult)
+}
+
+// CoinJoinSimulator runs the coinjoin rounds of the wallets of a fake daemon, so payment in coinjoin workflows can be tested without waiting for real rounds. In each step, every wallet with a running coinjoin moves to its next phase: in schedule, in progress (pending payments in coinjoin move to in progress) and in critical phase, after which the round completes (the coins are mixed and the payments finished) or fails (the payments are pending again).
+type CoinJoinSimulator struct {
+	server *Server
+	opts   CoinJoinSimulatorOptions
+
+	mutex  sync.Mutex
+	rand   *rand.Rand
+	fail   map[string]int
+	rounds []RoundResult
+}
+
+// NewCoinJoinSimulator creates a simulator of the coinjoin rounds of the server. Call Step to advance the rounds manually or Run to advance them every PhaseDuration.
+func NewCoinJoinSimulator(s *Server, opts CoinJoinSimulatorOptions) *CoinJoinSimulator {
+	if opts.PhaseDuration <= 0 {
+		opts.PhaseDuration = DefaultPhaseDuration
+	}
+	if opts.Clock == nil {
+		opts.Clock = wasabi.SystemClock
+	}
+	return &CoinJoinSimulator{
+		server: s,
+		opts:   opts,
+		rand:   rand.New(rand.NewSource(opts.Seed)),
+		fail:   map[string]int{},
+	}
+}
+
+// FailRounds makes the next n rounds of the wallet fail, regardless of FailureRate.
+func (sim *CoinJoinSimulator) FailRounds(walletName string, n int) {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+	sim.fail[walletName] += n
+}
+
+// Step moves the coinjoin of every wallet with a running coinjoin to its next phase. It returns the results of the rounds which ended.
+func (sim *CoinJoinSimulator) Step() []RoundResult {
+	sim.mutex.Lock()
+	sim.server.mutex.Lock()
+	names := make([]string, 0, len(sim.server.wallets))
+	for name := range sim.server.wallets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var results []RoundResult
+	for _, name := range names {
+		w := sim.server.wallets[name]
+		if w.coinJoinStatus == wasabi.CoinJoinStatusIdle {
+			continue
+		}
+		fail := false
+		if w.coinJoinStatus == wasabi.CoinJoinStatusInCriticalPhase {
+			if sim.fail[name] > 0 {
+				sim.fail[name]--
+				fail = true
+			} else {
+				fail = sim.opts.FailureRate > 0 && sim.rand.Float64() < sim.opts.FailureRate
+			}
+		}
+		result, err := sim.server.stepCoinJoin(w, fail)
+		if err == nil && result != nil {
+			results = append(results, *result)
+		}
+	}
+	sim.rounds = append(sim.rounds, results...)
+	sim.server.mutex.Unlock()
+	sim.mutex.Unlock()
+
+	if sim.opts.OnRound != nil {
+		for _, result := range results {
+			sim.opts.OnRound(result)
+		}
+	}
+	return results
+}
+
+// CompleteRound steps the coinjoin of the wallet until its current round ended and returns the result of the round.
+func (sim *CoinJoinSimulator) CompleteRound(walletName string) (RoundResult, error) {
+	for {
+		sim.server.mutex.Lock()
+		w, ok := sim.server.wallets[walletName]
+		running := ok && w.coinJoinStatus != wasabi.CoinJoinStatusIdle
+		sim.server.mutex.Unlock()
+		if !ok {
+			return RoundResult{}, fmt.Errorf("wallet %s does not exist", walletName)
+		}
+		if !running {
+			return RoundResult{}, fmt.Errorf("coinjoin of wallet %s is not running", walletName)
+		}
+		for _, result := range sim.Step() {
+			if result.WalletName == walletName {
+				return result, nil
+			}
+		}
+	}
+}
+
+// Rounds returns the results of all rounds which ended.
+func (sim *CoinJoinSimulator) Rounds() []RoundResult {
+	sim.mutex.Lock()
+	defer sim.mutex.Unlock()
+	return append([]RoundResult(nil), sim.rounds...)
+}
+
+// Run steps the rounds every PhaseDuration of the clock until the context is done.
+func (sim *CoinJoinSimulator) Run(ctx context.Context) error {
+	ticker := sim.opts.Clock.NewTicker(sim.opts.PhaseDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+			sim.Step()
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package wasabitest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// paymentStatus returns the last status of the only payment in coinjoin of the wallet.
+func paymentStatus(t *testing.T, c wasabi.Client, walletName string) wasabi.PaymentInCoinJoinStateHistoryItem {
+	t.Helper()
+	payments, err := c.ListPaymentsInCoinJoin(walletName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 1 {
+		t.Fatalf("payments in coinjoin = %+v, want 1", payments)
+	}
+	state := payments[0].State
+	return state[len(state)-1]
+}
+
+func TestCoinJoinSimulatorPayment(t *testing.T) {
+	s, c := newFundedServer(t, ServerOptions{})
+	if err := s.AddWallet("payee", ""); err != nil {
+		t.Fatal(err)
+	}
+	address, err := c.GetNewAddress("payee", "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentID, err := c.PayInCoinJoin("w", address.Address, 200_000, "pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StartCoinJoin("w", "pw", wasabi.StartCoinJoinOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var notified []RoundResult
+	sim := NewCoinJoinSimulator(s, CoinJoinSimulatorOptions{OnRound: func(result RoundResult) { notified = append(notified, result) }})
+	sim.FailRounds("w", 1)
+	steps := []struct {
+		wantCoinJoin wasabi.CoinJoinStatus
+		wantPayment  wasabi.PaymentStatus
+		wantResult   bool
+	}{
+		{wantCoinJoin: wasabi.CoinJoinStatusInProgress, wantPayment: wasabi.PaymentStatusPending},
+		{wantCoinJoin: wasabi.CoinJoinStatusInCriticalPhase, wantPayment: wasabi.PaymentStatusInProgress},
+		// The first round fails and the payment is pending again.
+		{wantCoinJoin: wasabi.CoinJoinStatusInSchedule, wantPayment: wasabi.PaymentStatusPending, wantResult: true},
+		{wantCoinJoin: wasabi.CoinJoinStatusInProgress, wantPayment: wasabi.PaymentStatusPending},
+		{wantCoinJoin: wasabi.CoinJoinStatusInCriticalPhase, wantPayment: wasabi.PaymentStatusInProgress},
+		{wantCoinJoin: wasabi.CoinJoinStatusInSchedule, wantPayment: wasabi.PaymentStatusFinished, wantResult: true},
+	}
+	for i, step := range steps {
+		results := sim.Step()
+		if (len(results) == 1) != step.wantResult {
+			t.Fatalf("step %d: results = %+v, want a result %v", i, results, step.wantResult)
+		}
+		info, err := c.GetWalletInfo("w")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.CoinJoinStatus != step.wantCoinJoin {
+			t.Fatalf("step %d: coinjoin status = %q, want %q", i, info.CoinJoinStatus, step.wantCoinJoin)
+		}
+		if status := paymentStatus(t, c, "w"); status.Status != step.wantPayment {
+			t.Fatalf("step %d: payment status = %+v, want %q", i, status, step.wantPayment)
+		}
+	}
+
+	rounds := sim.Rounds()
+	if len(rounds) != 2 || !rounds[0].Failed || rounds[0].TxID != "" {
+		t.Fatalf("Rounds() = %+v, want a failed round first", rounds)
+	}
+	paid := rounds[1]
+	if paid.Failed || paid.TxID == "" || len(paid.Paid) != 1 || paid.Paid[0] != paymentID {
+		t.Fatalf("second round = %+v, want the payment %s paid", paid, paymentID)
+	}
+	if len(notified) != 2 {
+		t.Fatalf("OnRound was called %d times, want 2", len(notified))
+	}
+	if finished := paymentStatus(t, c, "w"); finished.TxID != paid.TxID || finished.Round != paid.Round {
+		t.Fatalf("finished state = %+v, want the round %d and transaction %s", finished, paid.Round, paid.TxID)
+	}
+
+	// The coinjoin paid the payment out of the wallet and left one mixed coin.
+	history, err := c.GetHistory("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history[0].TxID != paid.TxID || !history[0].IsLikelyCoinJoin || history[0].Amount > -200_000 {
+		t.Fatalf("last transaction = %+v, want the coinjoin paying 200000 sat", history[0])
+	}
+	coins, err := c.ListUnspentCoins("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coins) != 1 || coins[0].AnonymityScore < float64(s.opts.AnonScoreTarget) {
+		t.Fatalf("wallet coins = %+v, want one mixed coin", coins)
+	}
+}
+
+func TestCoinJoinSimulatorCompleteRound(t *testing.T) {
+	s, c := newFundedServer(t, ServerOptions{})
+	sim := NewCoinJoinSimulator(s, CoinJoinSimulatorOptions{})
+	if _, err := sim.CompleteRound("w"); err == nil {
+		t.Fatal("CompleteRound() without a running coinjoin = nil error")
+	}
+	if _, err := sim.CompleteRound("missing"); err == nil {
+		t.Fatal("CompleteRound() of a missing wallet = nil error")
+	}
+	if err := c.StartCoinJoin("w", "pw", wasabi.StartCoinJoinOptions{StopWhenAllMixed: true}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := sim.CompleteRound("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Failed || result.TxID == "" {
+		t.Fatalf("CompleteRound() = %+v, want a coinjoin", result)
+	}
+	info, err := c.GetWalletInfo("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.CoinJoinStatus != wasabi.CoinJoinStatusIdle {
+		t.Fatalf("coinjoin status = %q after all coins were mixed, want idle", info.CoinJoinStatus)
+	}
+}
+
+func TestCoinJoinSimulatorFailureRate(t *testing.T) {
+	s, c := newFundedServer(t, ServerOptions{})
+	if err := c.StartCoinJoin("w", "pw", wasabi.StartCoinJoinOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	sim := NewCoinJoinSimulator(s, CoinJoinSimulatorOptions{FailureRate: 1})
+	for i := 0; i < 3; i++ {
+		result, err := sim.CompleteRound("w")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Failed {
+			t.Fatalf("round %d = %+v, want a failure", i, result)
+		}
+	}
+}
+
+func TestCoinJoinSimulatorRun(t *testing.T) {
+	s, c := newFundedServer(t, ServerOptions{})
+	if err := c.StartCoinJoin("w", "pw", wasabi.StartCoinJoinOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	rounds := make(chan RoundResult, 1)
+	sim := NewCoinJoinSimulator(s, CoinJoinSimulatorOptions{
+		Clock:         clock,
+		PhaseDuration: time.Minute,
+		OnRound:       func(result RoundResult) { rounds <- result },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sim.Run(ctx) }()
+	if err := clock.BlockUntil(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// The round passes in schedule, in progress and in critical phase before it ends.
+	for i := 0; i < 3; i++ {
+		clock.Advance(time.Minute)
+		if i < 2 {
+			waitForStatus(t, c, []wasabi.CoinJoinStatus{wasabi.CoinJoinStatusInProgress, wasabi.CoinJoinStatusInCriticalPhase}[i])
+		}
+	}
+	select {
+	case result := <-rounds:
+		if result.Failed || result.TxID == "" {
+			t.Fatalf("round = %+v, want a coinjoin", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no round ended after three phases")
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Run() = %v, want context.Canceled", err)
+	}
+}
+
+// waitForStatus waits until the coinjoin of the wallet "w" has the status, since Run steps the rounds in its own goroutine.
+func waitForStatus(t *testing.T, c wasabi.Client, want wasabi.CoinJoinStatus) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		info, err := c.GetWalletInfo("w")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.CoinJoinStatus == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("coinjoin status = %q, want %q", info.CoinJoinStatus, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,420 @@
+// Package wasabitest provides helpers to test code which uses the wasabi client without a running daemon.
+package wasabitest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// Pseudo methods used to set expectations on the Client methods which are not rpc methods.
+const (
+	MethodIsWasabiWalletUp wasabi.Method = "iswasabiwalletup"
+	MethodCapabilities     wasabi.Method = "capabilities"
+	MethodPing             wasabi.Method = "ping"
+)
+
+// ErrUnexpectedCall is returned by the MockClient for calls which match no expectation.
+var ErrUnexpectedCall = errors.New("unexpected call")
+
+// Matcher matches one argument of a call.
+type Matcher func(arg interface{}) bool
+
+// Anything matches any argument.
+var Anything Matcher = func(interface{}) bool { return true }
+
+// Call is a call recorded by the MockClient. RawCall calls are recorded with the method they call.
+type Call struct {
+	Method wasabi.Method
+	Args   []interface{}
+}
+
+func (c Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = fmt.Sprintf("%#v", arg)
+	}
+	return fmt.Sprintf("%s(%s)", c.Method, strings.Join(args, ", "))
+}
+
+// Expectation is an expected call of the MockClient and its canned response.
+type Expectation struct {
+	method wasabi.Method
+	args   []interface{}
+	result interface{}
+	err    error
+	run    func(args []interface{}) (interface{}, error)
+	times  int
+	calls  int
+}
+
+// Return sets the response of the call. The result must be of the type returned by the Client method (or nil for the zero value); it is ignored for methods which only return an error.
+func (e *Expectation) Return(result interface{}, err error) *Expectation {
+	e.result = result
+	e.err = err
+	return e
+}
+
+// ReturnError sets the error returned by the call.
+func (e *Expectation) ReturnError(err error) *Expectation {
+	return e.Return(nil, err)
+}
+
+// Run sets a function computing the response of the call from its arguments. It replaces the response set by Return.
+func (e *Expectation) Run(fn func(args []interface{}) (interface{}, error)) *Expectation {
+	e.run = fn
+	return e
+}
+
+// Times limits the expectation to n calls. Without a limit, the expectation matches any number of calls, and is met if it was called at least once.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Once limits the expectation to one call.
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+func (e *Expectation) matches(method wasabi.Method, args []interface{}) bool {
+	if e.method != method {
+		return false
+	}
+	if e.times > 0 && e.calls >= e.times {
+		return false
+	}
+	if e.args == nil {
+		return true
+	}
+	if len(e.args) != len(args) {
+		return false
+	}
+	for i, expected := range e.args {
+		if matcher, ok := expected.(Matcher); ok {
+			if !matcher(args[i]) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(expected, args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Expectation) met() bool {
+	if e.times > 0 {
+		return e.calls == e.times
+	}
+	return e.calls > 0
+}
+
+func (e *Expectation) String() string {
+	if e.args == nil {
+		return fmt.Sprintf("%s(...)", e.method)
+	}
+	return Call{Method: e.method, Args: e.args}.String()
+}
+
+// MockClient is a Client whose calls are answered by expectations and recorded. It is safe for concurrent use.
+type MockClient struct {
+	mutex        sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+	unexpected   []Call
+}
+
+var _ wasabi.Client = (*MockClient)(nil)
+
+// NewMockClient returns a MockClient without expectations.
+func NewMockClient() *MockClient {
+	return &MockClient{}
+}
+
+// On adds an expectation of a call of the method. Without args, any arguments match. Otherwise the args are the arguments of the Client method (walletName first) and are compared with reflect.DeepEqual, unless they are a Matcher. Expectations are matched in the order they were added.
+func (m *MockClient) On(method wasabi.Method, args ...interface{}) *Expectation {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	e := &Expectation{method: method}
+	if len(args) > 0 {
+		e.args = args
+	}
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// Calls returns the recorded calls, including the unexpected ones.
+func (m *MockClient) Calls() []Call {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsOf returns the recorded calls of the method.
+func (m *MockClient) CallsOf(method wasabi.Method) []Call {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset removes the expectations and the recorded calls.
+func (m *MockClient) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.expectations = nil
+	m.calls = nil
+	m.unexpected = nil
+}
+
+// AssertExpectations fails the test if an expectation was not met or if a call matched no expectation.
+func (m *MockClient) AssertExpectations(t testing.TB) {
+	t.Helper()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, e := range m.expectations {
+		if e.met() {
+			continue
+		}
+		if e.times > 0 {
+			t.Errorf("wasabitest: expected %d calls of %s, got %d", e.times, e, e.calls)
+		} else {
+			t.Errorf("wasabitest: expected a call of %s", e)
+		}
+	}
+	for _, call := range m.unexpected {
+		t.Errorf("wasabitest: unexpected call %s", call)
+	}
+}
+
+// call records the call and returns the response of the first matching expectation.
+func (m *MockClient) call(method wasabi.Method, args ...interface{}) (interface{}, error) {
+	m.mutex.Lock()
+	call := Call{Method: method, Args: args}
+	m.calls = append(m.calls, call)
+	var expectation *Expectation
+	for _, e := range m.expectations {
+		if e.matches(method, args) {
+			expectation = e
+			break
+		}
+	}
+	if expectation == nil {
+		m.unexpected = append(m.unexpected, call)
+		m.mutex.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedCall, call)
+	}
+	expectation.calls++
+	run, result, err := expectation.run, expectation.result, expectation.err
+	m.mutex.Unlock()
+	if run != nil {
+		return run(args)
+	}
+	return result, err
+}
+
+// respond calls the mock and converts the result to the type returned by the Client method.
+func respond[T any](m *MockClient, method wasabi.Method, args ...interface{}) (T, error) {
+	var zero T
+	result, err := m.call(method, args...)
+	if result == nil {
+		return zero, err
+	}
+	value, ok := result.(T)
+	if !ok {
+		return zero, fmt.Errorf("wasabitest: result of %s is %T, want %T", method, result, zero)
+	}
+	return value, err
+}
+
+func (m *MockClient) IsWasabiWalletUp() bool {
+	up, err := respond[bool](m, MethodIsWasabiWalletUp)
+	return up && err == nil
+}
+
+func (m *MockClient) Ping(ctx context.Context) (wasabi.PingResult, error) {
+	return respond[wasabi.PingResult](m, MethodPing)
+}
+
+func (m *MockClient) GetStatus() (wasabi.GetStatusResponse, error) {
+	return respond[wasabi.GetStatusResponse](m, wasabi.MethodGetStatus)
+}
+
+func (m *MockClient) CreateWallet(walletName string, password string) (string, error) {
+	return respond[string](m, wasabi.MethodCreateWallet, walletName, password)
+}
+
+func (m *MockClient) LoadWallet(walletName string) error {
+	_, err := m.call(wasabi.MethodLoadWallet, walletName)
+	return err
+}
+
+func (m *MockClient) ListCoins(walletName string) ([]wasabi.ListCoinsResponse, error) {
+	return respond[[]wasabi.ListCoinsResponse](m, wasabi.MethodListCoins, walletName)
+}
+
+func (m *MockClient) ListUnspentCoins(walletName string) ([]wasabi.ListCoinsResponse, error) {
+	return respond[[]wasabi.ListCoinsResponse](m, wasabi.MethodListUnspentCoins, walletName)
+}
+
+func (m *MockClient) GetWalletInfo(walletName string) (wasabi.GetWalletInfoResponse, error) {
+	return respond[wasabi.GetWalletInfoResponse](m, wasabi.MethodGetWalletInfo, walletName)
+}
+
+func (m *MockClient) GetNewAddress(walletName string, label string) (wasabi.GetNewAddressResponse, error) {
+	return respond[wasabi.GetNewAddressResponse](m, wasabi.MethodGetNewAddress, walletName, label)
+}
+
+func (m *MockClient) Send(walletName string, req wasabi.SendRequest) (wasabi.SendResponse, error) {
+	return respond[wasabi.SendResponse](m, wasabi.MethodSend, walletName, req)
+}
+
+func (m *MockClient) Build(walletName string, req wasabi.BuildRequest) (string, error) {
+	return respond[string](m, wasabi.MethodBuild, walletName, req)
+}
+
+func (m *MockClient) Broadcast(walletName string, hex string) (wasabi.TxID, error) {
+	return respond[wasabi.TxID](m, wasabi.MethodBroadcast, walletName, hex)
+}
+
+func (m *MockClient) GetHistory(walletName string) ([]wasabi.Transaction, error) {
+	return respond[[]wasabi.Transaction](m, wasabi.MethodGetHistory, walletName)
+}
+
+func (m *MockClient) ListKeys(walletName string) ([]wasabi.GeneratedKey, error) {
+	return respond[[]wasabi.GeneratedKey](m, wasabi.MethodListKeys, walletName)
+}
+
+func (m *MockClient) StartCoinJoin(walletName string, password string, opts wasabi.StartCoinJoinOptions) error {
+	_, err := m.call(wasabi.MethodStartCoinJoin, walletName, password, opts)
+	return err
+}
+
+func (m *MockClient) StartCoinJoinSweep(walletName string, password string, outputWalletName string) error {
+	_, err := m.call(wasabi.MethodStartCoinJoinSweep, walletName, password, outputWalletName)
+	return err
+}
+
+func (m *MockClient) StopCoinJoin(walletName string) error {
+	_, err := m.call(wasabi.MethodStopCoinJoin, walletName)
+	return err
+}
+
+func (m *MockClient) Stop() error {
+	_, err := m.call(wasabi.MethodStop)
+	return err
+}
+
+func (m *MockClient) GetFeeRates() (wasabi.GetFeeRatesResponse, error) {
+	return respond[wasabi.GetFeeRatesResponse](m, wasabi.MethodGetFeeRates)
+}
+
+func (m *MockClient) ListWallets() ([]wasabi.ListWalletsResponseItem, error) {
+	return respond[[]wasabi.ListWalletsResponseItem](m, wasabi.MethodListWallets)
+}
+
+func (m *MockClient) ExcludeFromCoinJoin(walletName string, txID wasabi.TxID, index int, exclude bool) error {
+	_, err := m.call(wasabi.MethodExcludeFromCoinJoin, walletName, txID, index, exclude)
+	return err
+}
+
+// ExcludeCoinsFromCoinJoin is recorded as one call of MethodExcludeFromCoinJoin with the walletName, the coins and exclude.
+func (m *MockClient) ExcludeCoinsFromCoinJoin(walletName string, coins []wasabi.Coin, exclude bool) error {
+	_, err := m.call(wasabi.MethodExcludeFromCoinJoin, walletName, coins, exclude)
+	return err
+}
+
+func (m *MockClient) RecoverWallet(walletName string, mnemonic string, password string) error {
+	_, err := m.call(wasabi.MethodRecoverWallet, walletName, mnemonic, password)
+	return err
+}
+
+func (m *MockClient) BuildUnsafeTransaction(walletName string, req wasabi.BuildUnsafeRequest) (string, error) {
+	return respond[string](m, wasabi.MethodBuildUnsafeTransaction, walletName, req)
+}
+
+func (m *MockClient) PayInCoinJoin(walletName string, address wasabi.Address, amount wasabi.Amount, password string) (string, error) {
+	return respond[string](m, wasabi.MethodPayInCoinJoin, walletName, address, amount, password)
+}
+
+func (m *MockClient) ListPaymentsInCoinJoin(walletName string) ([]wasabi.ListPaymentsInCoinJoinResponseItem, error) {
+	return respond[[]wasabi.ListPaymentsInCoinJoinResponseItem](m, wasabi.MethodListPaymentsInCoinJoin, walletName)
+}
+
+func (m *MockClient) CancelPaymentInCoinJoin(walletName string, paymentID string) error {
+	_, err := m.call(wasabi.MethodCancelPaymentInCoinJoin, walletName, paymentID)
+	return err
+}
+
+func (m *MockClient) CancelTransaction(walletName string, txID wasabi.TxID, password string) (string, error) {
+	return respond[string](m, wasabi.MethodCancelTransaction, walletName, txID, password)
+}
+
+func (m *MockClient) SpeedUpTransaction(walletName string, txID wasabi.TxID, password string) (string, error) {
+	return respond[string](m, wasabi.MethodSpeedUpTransaction, walletName, txID, password)
+}
+
+func (m *MockClient) Capabilities() (wasabi.Capabilities, error) {
+	return respond[wasabi.Capabilities](m, MethodCapabilities)
+}
+
+// RawCall is recorded with the called method, the walletName and the params. The result of the expectation is copied into out through JSON.
+func (m *MockClient) RawCall(walletName string, method string, params interface{}, out interface{}) error {
+	result, err := m.call(wasabi.Method(method), walletName, params)
+	if err != nil || result == nil || out == nil {
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("wasabitest: failed to encode result of %s: %w", method, err)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// CallRaw is recorded like RawCall. The result of the expectation is written to w as JSON.
+func (m *MockClient) CallRaw(walletName string, method string, params interface{}, w io.Writer) error {
+	result, err := m.call(wasabi.Method(method), walletName, params)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("wasabitest: failed to encode result of %s: %w", method, err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (m *MockClient) Wallet(walletName string) *wasabi.Wallet {
+	return wasabi.NewWallet(m, walletName)
+}
+
+// WithContext returns the MockClient itself, so the calls of the returned client are recorded with the others.
+func (m *MockClient) WithContext(ctx context.Context) wasabi.Client {
+	return m
+}
+
+// With returns the MockClient itself, so the calls of the derived client are recorded with the others. The options are ignored.
+func (m *MockClient) With(opts ...wasabi.ClientOption) (wasabi.Client, error) {
+	return m, nil
+}
+
+// Stats returns no statistics.
+func (m *MockClient) Stats() map[wasabi.Method]wasabi.MethodStats {
+	return map[wasabi.Method]wasabi.MethodStats{}
+}
@@ -0,0 +1,139 @@
+package wasabitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// errorRecorder is a testing.TB recording the errors reported by AssertExpectations instead of failing the test.
+type errorRecorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *errorRecorder) Helper() {}
+
+func (r *errorRecorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockClientMatching(t *testing.T) {
+	m := NewMockClient()
+	m.On(wasabi.MethodGetNewAddress, "w", "alice").Return(wasabi.GetNewAddressResponse{Address: "a1"}, nil)
+	m.On(wasabi.MethodGetNewAddress, "w", Anything).Return(wasabi.GetNewAddressResponse{Address: "any"}, nil)
+	m.On(wasabi.MethodGetNewAddress).ReturnError(errors.New("other wallet"))
+
+	tests := []struct {
+		walletName, label string
+		want              wasabi.Address
+		wantErr           bool
+	}{
+		{walletName: "w", label: "alice", want: "a1"},
+		{walletName: "w", label: "bob", want: "any"},
+		{walletName: "v", label: "alice", wantErr: true},
+	}
+	for _, tt := range tests {
+		resp, err := m.GetNewAddress(tt.walletName, tt.label)
+		if (err != nil) != tt.wantErr || resp.Address != tt.want {
+			t.Errorf("GetNewAddress(%q, %q) = %q, %v, want %q, error %v", tt.walletName, tt.label, resp.Address, err, tt.want, tt.wantErr)
+		}
+	}
+	if calls := m.CallsOf(wasabi.MethodGetNewAddress); len(calls) != 3 || calls[2].Args[0] != "v" {
+		t.Errorf("CallsOf() = %v", calls)
+	}
+	r := &errorRecorder{TB: t}
+	m.AssertExpectations(r)
+	if len(r.errors) != 0 {
+		t.Errorf("AssertExpectations() reported %v", r.errors)
+	}
+}
+
+func TestMockClientTimes(t *testing.T) {
+	m := NewMockClient()
+	m.On(wasabi.MethodGetStatus).Return(wasabi.GetStatusResponse{BestBlockchainHeight: 1}, nil).Once()
+	m.On(wasabi.MethodGetStatus).Return(wasabi.GetStatusResponse{BestBlockchainHeight: 2}, nil).Times(2)
+	for i, want := range []uint64{1, 2, 2} {
+		status, err := m.GetStatus()
+		if err != nil || status.BestBlockchainHeight != want {
+			t.Fatalf("call %d: GetStatus() = %d, %v, want %d", i, status.BestBlockchainHeight, err, want)
+		}
+	}
+	if _, err := m.GetStatus(); !errors.Is(err, ErrUnexpectedCall) {
+		t.Fatalf("GetStatus() after the expected calls = %v, want ErrUnexpectedCall", err)
+	}
+	r := &errorRecorder{TB: t}
+	m.AssertExpectations(r)
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "unexpected call getstatus()") {
+		t.Fatalf("AssertExpectations() reported %v, want the unexpected call", r.errors)
+	}
+}
+
+func TestMockClientUnmet(t *testing.T) {
+	m := NewMockClient()
+	m.On(wasabi.MethodStop)
+	m.On(wasabi.MethodLoadWallet, "w").Times(2)
+	if err := m.LoadWallet("w"); err != nil {
+		t.Fatal(err)
+	}
+	r := &errorRecorder{TB: t}
+	m.AssertExpectations(r)
+	if len(r.errors) != 2 {
+		t.Fatalf("AssertExpectations() reported %v, want the two unmet expectations", r.errors)
+	}
+	m.Reset()
+	r = &errorRecorder{TB: t}
+	m.AssertExpectations(r)
+	if len(r.errors) != 0 || len(m.Calls()) != 0 {
+		t.Fatalf("after Reset, AssertExpectations() reported %v and %d calls remain", r.errors, len(m.Calls()))
+	}
+}
+
+func TestMockClientRun(t *testing.T) {
+	m := NewMockClient()
+	m.On(wasabi.MethodBroadcast).Run(func(args []interface{}) (interface{}, error) {
+		return wasabi.TxID("id-of-" + args[1].(string)), nil
+	})
+	txID, err := m.Broadcast("w", "0200")
+	if err != nil || txID != "id-of-0200" {
+		t.Fatalf("Broadcast() = %q, %v", txID, err)
+	}
+}
+
+func TestMockClientWrongResultType(t *testing.T) {
+	m := NewMockClient()
+	m.On(wasabi.MethodGetHistory).Return("not a history", nil)
+	if _, err := m.GetHistory("w"); err == nil || !strings.Contains(err.Error(), "want []wasabi.Transaction") {
+		t.Fatalf("GetHistory() = %v, want a result type error", err)
+	}
+}
+
+func TestMockClientRawCall(t *testing.T) {
+	m := NewMockClient()
+	m.On("getwalletinfo", "w", Anything).Return(map[string]interface{}{"walletName": "w", "anonScoreTarget": 5}, nil)
+	var info wasabi.GetWalletInfoResponse
+	if err := m.RawCall("w", "getwalletinfo", nil, &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.WalletName != "w" || info.AnonScoreTarget != 5 {
+		t.Fatalf("RawCall() decoded %+v", info)
+	}
+	var b strings.Builder
+	if err := m.CallRaw("w", "getwalletinfo", nil, &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != `{"anonScoreTarget":5,"walletName":"w"}` {
+		t.Fatalf("CallRaw() wrote %s", b.String())
+	}
+	// The derived clients record their calls with the others.
+	if _, err := m.WithContext(context.Background()).Wallet("w").Client().GetWalletInfo("w"); !errors.Is(err, ErrUnexpectedCall) {
+		t.Fatalf("GetWalletInfo() = %v, want ErrUnexpectedCall", err)
+	}
+	if calls := m.Calls(); len(calls) != 3 {
+		t.Fatalf("Calls() = %v, want 3 calls", calls)
+	}
+}
@@ -0,0 +1,1040 @@
+package wasabitest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// dustThreshold is the smallest output created by the fake daemon, smaller change is added to the fee.
+const dustThreshold wasabi.Amount = 546
+
+// fakeWallet is the in-memory state of a wallet of the fake daemon.
+type fakeWallet struct {
+	name             string
+	password         string
+	mnemonic         string
+	loaded           bool
+	keys             []*wasabi.GeneratedKey
+	coins            []*fakeCoin
+	txs              []*fakeTx
+	coinJoinStatus   wasabi.CoinJoinStatus
+	round            int // the current coinjoin round, zero when the wallet is not in a round
+	stopWhenAllMixed bool
+	sweepTo          string
+	payments         []*wasabi.ListPaymentsInCoinJoinResponseItem
+}
+
+// fakeCoin is a coin of a fake wallet. Its confirmations are derived from its height.
+type fakeCoin struct {
+	txID      wasabi.TxID
+	index     int
+	amount    wasabi.Amount
+	anonScore float64
+	height    int
+	keyPath   wasabi.DerivationPath
+	address   wasabi.Address
+	label     string
+	spentBy   wasabi.TxID
+	excluded  bool
+}
+
+// fakeTx is a transaction of a fake wallet: the wallet coins it spends and creates, and the history entry.
+type fakeTx struct {
+	id       wasabi.TxID
+	hex      string
+	inputs   []*fakeCoin
+	outputs  []txOutput
+	created  []*fakeCoin
+	fee      wasabi.Amount
+	amount   wasabi.Amount
+	label    string
+	height   int
+	time     time.Time
+	coinJoin bool
+}
+
+// builtTx is a transaction built (but not broadcast yet) by the fake daemon.
+type builtTx struct {
+	wallet   *fakeWallet
+	tx       *fakeTx
+	replaces *fakeTx
+}
+
+// AddWallet creates a loaded wallet with the password.
+func (s *Server) AddWallet(walletName string, password string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, err := s.createWallet(walletName, password, ""); err != nil {
+		return err
+	}
+	s.wallets[walletName].loaded = true
+	return nil
+}
+
+// Fund sends an unconfirmed coin of the amount to a new address of the wallet with the label, as if it was paid by someone else. Use Mine to confirm it.
+func (s *Server) Fund(walletName string, amount wasabi.Amount, label string) (wasabi.ListCoinsResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	w, ok := s.wallets[walletName]
+	if !ok {
+		return wasabi.ListCoinsResponse{}, fmt.Errorf("wallet %s does not exist", walletName)
+	}
+	if amount <= 0 {
+		return wasabi.ListCoinsResponse{}, fmt.Errorf("amount must be positive")
+	}
+	key := s.cleanKey(w, false)
+	key.Label = label
+	script, _ := hex.DecodeString(string(key.ScriptPubKey))
+	s.sequence++
+	funding := []wasabi.Coin{{TransactionID: wasabi.TxID(hash32("funding", s.sequence)), Index: 0}}
+	txHex, txID := serializeTx(funding, []txOutput{{amount: amount, script: script}})
+	tx := &fakeTx{id: txID, hex: txHex, outputs: []txOutput{{amount: amount, script: script}}, label: label}
+	tx.created = s.ownedOutputs(w, txID, tx.outputs)
+	tx.amount = amount
+	s.apply(w, tx)
+	return s.coinResponse(tx.created[0]), nil
+}
+
+// Mine mines blocks, confirming all unconfirmed transactions in the first one.
+func (s *Server) Mine(blocks int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if blocks <= 0 {
+		return
+	}
+	for _, w := range s.wallets {
+		for _, tx := range w.txs {
+			if tx.height == 0 {
+				tx.height = s.height + 1
+				for _, coin := range tx.created {
+					coin.height = tx.height
+				}
+			}
+		}
+	}
+	s.height += blocks
+}
+
+// Height returns the best blockchain height.
+func (s *Server) Height() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.height
+}
+
+// SetFeeRates sets the fee rates reported by the daemon and used to build transactions.
+func (s *Server) SetFeeRates(rates wasabi.GetFeeRatesResponse) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.feeRates = rates
+}
+
+// SetBackendStatus sets the backend status reported by the daemon.
+func (s *Server) SetBackendStatus(status wasabi.BackendStatus) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.backendStatus = status
+}
+
+// SetTorStatus sets the tor status reported by the daemon.
+func (s *Server) SetTorStatus(status wasabi.TorStatus) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.torStatus = status
+}
+
+// SetExchangeRate sets the USD exchange rate reported by the daemon.
+func (s *Server) SetExchangeRate(rate float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.exchangeRate = wasabi.DecimalFromFloat(rate)
+}
+
+// StepCoinJoin moves the coinjoin of the wallet to its next state: in schedule, in progress, in critical phase and back to in schedule (or idle) once the round completed. Coins below the anonymity score target are mixed into one coin reaching the target, and pending payments in coinjoin are paid.
+func (s *Server) StepCoinJoin(walletName string) (wasabi.CoinJoinStatus, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	w, ok := s.wallets[walletName]
+	if !ok {
+		return "", fmt.Errorf("wallet %s does not exist", walletName)
+	}
+	if _, err := s.stepCoinJoin(w, false); err != nil {
+		return w.coinJoinStatus, err
+	}
+	return w.coinJoinStatus, nil
+}
+
+// stepCoinJoin moves the coinjoin of the wallet to its next state. In the critical phase the round completes, or fails if fail is set. It returns the result of the round once it ended.
+func (s *Server) stepCoinJoin(w *fakeWallet, fail bool) (*RoundResult, error) {
+	switch w.coinJoinStatus {
+	case wasabi.CoinJoinStatusInSchedule:
+		s.sequence++
+		w.round = s.sequence
+		w.coinJoinStatus = wasabi.CoinJoinStatusInProgress
+	case wasabi.CoinJoinStatusInProgress:
+		w.coinJoinStatus = wasabi.CoinJoinStatusInCriticalPhase
+		for _, payment := range w.payments {
+			if lastPaymentStatus(payment) == wasabi.PaymentStatusPending {
+				payment.State = append(payment.State, wasabi.PaymentInCoinJoinStateHistoryItem{Status: wasabi.PaymentStatusInProgress, Round: w.round})
+			}
+		}
+	case wasabi.CoinJoinStatusInCriticalPhase:
+		if fail {
+			return s.failRound(w), nil
+		}
+		return s.completeRound(w), nil
+	default:
+		return nil, fmt.Errorf("coinjoin of wallet %s is not running", w.name)
+	}
+	return nil, nil
+}
+
+// CompleteCoinJoinRound steps the coinjoin of the wallet until a round completed.
+func (s *Server) CompleteCoinJoinRound(walletName string) error {
+	for {
+		s.mutex.Lock()
+		w, ok := s.wallets[walletName]
+		critical := ok && w.coinJoinStatus == wasabi.CoinJoinStatusInCriticalPhase
+		s.mutex.Unlock()
+		if _, err := s.StepCoinJoin(walletName); err != nil {
+			return err
+		}
+		if critical {
+			return nil
+		}
+	}
+}
+
+func (s *Server) createWallet(walletName string, password string, mnemonic string) (*fakeWallet, *wasabi.RPCError) {
+	if walletName == "" {
+		return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: "Wallet name must not be empty."}
+	}
+	if _, ok := s.wallets[walletName]; ok {
+		return nil, daemonError("Wallet '%s' already exists.", walletName)
+	}
+	if mnemonic == "" {
+		mnemonic = fakeMnemonic(walletName)
+	}
+	w := &fakeWallet{name: walletName, password: password, mnemonic: mnemonic, coinJoinStatus: wasabi.CoinJoinStatusIdle}
+	s.topUpKeys(w)
+	s.wallets[walletName] = w
+	return w, nil
+}
+
+// mnemonicWords are the words of the fake recovery phrases.
+var mnemonicWords = []string{"abandon", "ability", "able", "about", "above", "absent", "absorb", "abstract", "absurd", "abuse", "access", "accident", "account", "accuse", "achieve", "acid"}
+
+func fakeMnemonic(seed string) string {
+	hash := hash20("mnemonic", seed)
+	words := make([]string, 12)
+	for i := range words {
+		words[i] = mnemonicWords[int(hash[i])%len(mnemonicWords)]
+	}
+	return strings.Join(words, " ")
+}
+
+// deriveKey adds the next key of the chain of the wallet.
+func (s *Server) deriveKey(w *fakeWallet, internal bool) *wasabi.GeneratedKey {
+	index := 0
+	for _, key := range w.keys {
+		if key.Internal == internal {
+			index++
+		}
+	}
+	coinType, chain := 1, 0
+	if s.opts.Network == wasabi.BitcoinNetworkMainnet {
+		coinType = 0
+	}
+	if internal {
+		chain = 1
+	}
+	pubKey := "02" + hash32(w.name, w.mnemonic, internal, index)
+	pubKeyHash := hash20(pubKey)
+	address, _ := p2wpkhAddress(s.opts.Network, pubKeyHash)
+	key := &wasabi.GeneratedKey{
+		FullKeyPath:  wasabi.DerivationPath{84 + wasabi.HardenedKeyStart, uint32(coinType) + wasabi.HardenedKeyStart, wasabi.HardenedKeyStart, uint32(chain), uint32(index)},
+		Internal:     internal,
+		KeyState:     wasabi.KeyStateClean,
+		ScriptPubKey: wasabi.ScriptPubKey("0014" + hex.EncodeToString(pubKeyHash)),
+		PubKey:       pubKey,
+		PubKeyHash:   hex.EncodeToString(pubKeyHash),
+		Address:      address,
+	}
+	w.keys = append(w.keys, key)
+	return key
+}
+
+// topUpKeys derives keys until both chains have a gap of clean keys after the last used one.
+func (s *Server) topUpKeys(w *fakeWallet) {
+	for _, internal := range []bool{false, true} {
+		gap := 0
+		for _, key := range w.keys {
+			if key.Internal != internal {
+				continue
+			}
+			if key.KeyState == wasabi.KeyStateClean && key.Label == "" {
+				gap++
+			} else {
+				gap = 0
+			}
+		}
+		for ; gap < wasabi.DefaultGapLimit; gap++ {
+			s.deriveKey(w, internal)
+		}
+	}
+}
+
+// cleanKey returns the first clean unlabeled key of the chain.
+func (s *Server) cleanKey(w *fakeWallet, internal bool) *wasabi.GeneratedKey {
+	for _, key := range w.keys {
+		if key.Internal == internal && key.KeyState == wasabi.KeyStateClean && key.Label == "" {
+			return key
+		}
+	}
+	return s.deriveKey(w, internal)
+}
+
+// ownedOutputs returns the coins of the wallet created by the outputs of the transaction.
+func (s *Server) ownedOutputs(w *fakeWallet, txID wasabi.TxID, outputs []txOutput) []*fakeCoin {
+	var coins []*fakeCoin
+	for i, output := range outputs {
+		script := wasabi.ScriptPubKey(hex.EncodeToString(output.script))
+		for _, key := range w.keys {
+			if key.ScriptPubKey == script {
+				coins = append(coins, &fakeCoin{
+					txID:      txID,
+					index:     i,
+					amount:    output.amount,
+					anonScore: 1,
+					keyPath:   key.FullKeyPath,
+					address:   key.Address,
+					label:     key.Label,
+				})
+				break
+			}
+		}
+	}
+	return coins
+}
+
+// makeTx builds a transaction of the wallet spending the coins into the outputs. It is not applied to the wallet.
+func (s *Server) makeTx(w *fakeWallet, inputs []*fakeCoin, outputs []txOutput, label string) *fakeTx {
+	outpoints := make([]wasabi.Coin, len(inputs))
+	var in, out wasabi.Amount
+	for i, coin := range inputs {
+		outpoints[i] = wasabi.Coin{TransactionID: coin.txID, Index: coin.index}
+		in += coin.amount
+	}
+	for _, output := range outputs {
+		out += output.amount
+	}
+	txHex, txID := serializeTx(outpoints, outputs)
+	tx := &fakeTx{id: txID, hex: txHex, inputs: inputs, outputs: outputs, fee: in - out, label: label}
+	tx.created = s.ownedOutputs(w, txID, outputs)
+	tx.amount = -in
+	for _, coin := range tx.created {
+		tx.amount += coin.amount
+	}
+	return tx
+}
+
+// apply adds the transaction to the wallet: its inputs are spent, its outputs are added as coins and it appears in the history.
+func (s *Server) apply(w *fakeWallet, tx *fakeTx) {
+	tx.height = 0
+	tx.time = s.opts.Now()
+	for _, coin := range tx.inputs {
+		coin.spentBy = tx.id
+	}
+	for _, coin := range tx.created {
+		coin.height = 0
+		w.coins = append(w.coins, coin)
+		for _, key := range w.keys {
+			if key.FullKeyPath.Equal(coin.keyPath) {
+				key.KeyState = wasabi.KeyStateUsed
+			}
+		}
+	}
+	w.txs = append(w.txs, tx)
+	s.topUpKeys(w)
+}
+
+// undo removes a transaction applied to the wallet, e.g. when it is replaced.
+func (s *Server) undo(w *fakeWallet, tx *fakeTx) {
+	for _, coin := range tx.inputs {
+		if coin.spentBy == tx.id {
+			coin.spentBy = ""
+		}
+	}
+	coins := w.coins[:0]
+	for _, coin := range w.coins {
+		if coin.txID != tx.id {
+			coins = append(coins, coin)
+		}
+	}
+	w.coins = coins
+	txs := w.txs[:0]
+	for _, other := range w.txs {
+		if other != tx {
+			txs = append(txs, other)
+		}
+	}
+	w.txs = txs
+}
+
+func (s *Server) findTx(w *fakeWallet, txID wasabi.TxID) *fakeTx {
+	for _, tx := range w.txs {
+		if tx.id == txID {
+			return tx
+		}
+	}
+	return nil
+}
+
+func (s *Server) unspentCoins(w *fakeWallet) []*fakeCoin {
+	var coins []*fakeCoin
+	for _, coin := range w.coins {
+		if coin.spentBy == "" {
+			coins = append(coins, coin)
+		}
+	}
+	return coins
+}
+
+func (s *Server) balance(w *fakeWallet) wasabi.Amount {
+	var balance wasabi.Amount
+	for _, coin := range s.unspentCoins(w) {
+		balance += coin.amount
+	}
+	return balance
+}
+
+// feeRate returns the fee rate of the confirmation target, or of the nearest longer target.
+func (s *Server) feeRate(target int) (float64, bool) {
+	best := -1
+	for key := range s.feeRates {
+		value, err := strconv.Atoi(key)
+		if err == nil && value >= target && (best < 0 || value < best) {
+			best = value
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+	return float64(s.feeRates[strconv.Itoa(best)]), true
+}
+
+func fee(rate float64, inputs, outputs int) wasabi.Amount {
+	return wasabi.EstimateFee(rate, inputs, outputs)
+}
+
+func (s *Server) coinResponse(coin *fakeCoin) wasabi.ListCoinsResponse {
+	resp := wasabi.ListCoinsResponse{
+		TxID:                 coin.txID,
+		Index:                coin.index,
+		Amount:               coin.amount,
+		AnonymityScore:       coin.anonScore,
+		Confirmed:            coin.height > 0,
+		KeyPath:              coin.keyPath,
+		Address:              coin.address,
+		Label:                coin.label,
+		ExcludedFromCoinJoin: coin.excluded,
+	}
+	if coin.height > 0 {
+		resp.Confirmations = s.height - coin.height + 1
+	}
+	if coin.spentBy != "" {
+		spentBy := coin.spentBy
+		resp.SpentBy = &spentBy
+	}
+	return resp
+}
+
+func (s *Server) status() wasabi.GetStatusResponse {
+	return wasabi.GetStatusResponse{
+		TorStatus:            s.torStatus,
+		BackendStatus:        s.backendStatus,
+		BestBlockchainHeight: uint64(s.height),
+		BestBlockchainHash:   hash32("block", s.height),
+		FiltersCount:         s.height,
+		Network:              s.opts.Network,
+		ExchangeRate:         s.exchangeRate,
+		Peers: []wasabi.BitcoinPeer{{
+			IsConnected: true,
+			LastSeen:    s.opts.Now().UTC(),
+			Endpoint:    "127.0.0.1:18444",
+			UserAgent:   "/Satoshi:27.0.0/",
+		}},
+	}
+}
+
+func (s *Server) listWallets() []wasabi.ListWalletsResponseItem {
+	names := make([]string, 0, len(s.wallets))
+	for name := range s.wallets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := make([]wasabi.ListWalletsResponseItem, len(names))
+	for i, name := range names {
+		items[i] = wasabi.ListWalletsResponseItem{Name: name}
+	}
+	return items
+}
+
+func (s *Server) listCoins(w *fakeWallet, unspentOnly bool) []wasabi.ListCoinsResponse {
+	coins := []wasabi.ListCoinsResponse{}
+	for _, coin := range w.coins {
+		if !unspentOnly || coin.spentBy == "" {
+			coins = append(coins, s.coinResponse(coin))
+		}
+	}
+	return coins
+}
+
+func (s *Server) walletInfo(w *fakeWallet) wasabi.GetWalletInfoResponse {
+	coinType := 1
+	if s.opts.Network == wasabi.BitcoinNetworkMainnet {
+		coinType = 0
+	}
+	return wasabi.GetWalletInfoResponse{
+		WalletName:           w.name,
+		WalletFile:           "Wallets/" + w.name + ".json",
+		State:                wasabi.WalletStateStarted,
+		MasterKeyFingerprint: hex.EncodeToString(hash20("fingerprint", w.name, w.mnemonic)[:4]),
+		AnonScoreTarget:      s.opts.AnonScoreTarget,
+		IsRedCoinIsolation:   false,
+		Accounts: []wasabi.WalletInfoAccount{{
+			Name:      "segwit",
+			PublicKey: "tpub" + hash32("account", w.name, w.mnemonic),
+			KeyPath:   fmt.Sprintf("m/84'/%d'/0'", coinType),
+		}},
+		Balance:        s.balance(w),
+		CoinJoinStatus: w.coinJoinStatus,
+	}
+}
+
+func (s *Server) history(w *fakeWallet) []wasabi.Transaction {
+	history := make([]wasabi.Transaction, 0, len(w.txs))
+	for i := len(w.txs) - 1; i >= 0; i-- {
+		tx := w.txs[i]
+		history = append(history, wasabi.Transaction{
+			DateTime:         tx.time,
+			Height:           tx.height,
+			Amount:           tx.amount,
+			Label:            tx.label,
+			Tx:               tx.id,
+			IsLikelyCoinJoin: tx.coinJoin,
+		})
+	}
+	return history
+}
+
+func (s *Server) listKeys(w *fakeWallet) []wasabi.GeneratedKey {
+	keys := make([]wasabi.GeneratedKey, len(w.keys))
+	for i, key := range w.keys {
+		keys[i] = *key
+	}
+	return keys
+}
+
+func (s *Server) listPayments(w *fakeWallet) []wasabi.ListPaymentsInCoinJoinResponseItem {
+	payments := make([]wasabi.ListPaymentsInCoinJoinResponseItem, len(w.payments))
+	for i, payment := range w.payments {
+		payments[i] = *payment
+		payments[i].State = append([]wasabi.PaymentInCoinJoinStateHistoryItem(nil), payment.State...)
+	}
+	return payments
+}
+
+func lastPaymentStatus(payment *wasabi.ListPaymentsInCoinJoinResponseItem) wasabi.PaymentStatus {
+	if len(payment.State) == 0 {
+		return ""
+	}
+	return payment.State[len(payment.State)-1].Status
+}
+
+func (s *Server) rpcCreateWallet(params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var walletName, password string
+	if err := decodeParams(params, 1, &walletName, &password); err != nil {
+		return nil, err
+	}
+	w, err := s.createWallet(walletName, password, "")
+	if err != nil {
+		return nil, err
+	}
+	return w.mnemonic, nil
+}
+
+func (s *Server) rpcRecoverWallet(params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var walletName, mnemonic, password string
+	if err := decodeParams(params, 2, &walletName, &mnemonic, &password); err != nil {
+		return nil, err
+	}
+	if len(strings.Fields(mnemonic)) != 12 {
+		return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: "Invalid mnemonic."}
+	}
+	_, err := s.createWallet(walletName, password, mnemonic)
+	return nil, err
+}
+
+func (s *Server) rpcLoadWallet(params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var walletName string
+	if err := decodeParams(params, 1, &walletName); err != nil {
+		return nil, err
+	}
+	w, ok := s.wallets[walletName]
+	if !ok {
+		return nil, daemonError("Wallet '%s' does not exist.", walletName)
+	}
+	w.loaded = true
+	return nil, nil
+}
+
+func (s *Server) rpcSelectWallet(params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var walletName string
+	if err := decodeParams(params, 1, &walletName); err != nil {
+		return nil, err
+	}
+	if _, ok := s.wallets[walletName]; !ok {
+		return nil, daemonError("Wallet '%s' does not exist.", walletName)
+	}
+	s.selected = walletName
+	return nil, nil
+}
+
+func (s *Server) rpcGetNewAddress(w *fakeWallet, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var label string
+	if err := decodeParams(params, 0, &label); err != nil {
+		return nil, err
+	}
+	key := s.cleanKey(w, false)
+	key.Label = label
+	key.KeyState = wasabi.KeyStateLocked
+	s.topUpKeys(w)
+	return wasabi.GetNewAddressResponse{
+		Address:      key.Address,
+		KeyPath:      key.FullKeyPath,
+		Label:        key.Label,
+		PublicKey:    key.PubKey,
+		ScriptPubKey: key.ScriptPubKey,
+	}, nil
+}
+
+// txParams are the params of send, build and buildunsafetransaction.
+type txParams struct {
+	Payments   []wasabi.Payment `json:"payments"`
+	Coins      []wasabi.Coin    `json:"coins"`
+	FeeTarget  int              `json:"feeTarget"`
+	FeeRate    float64          `json:"feeRate"`
+	Password   string           `json:"password"`
+	PayjoinURL string           `json:"payjoinUrl"`
+}
+
+// buildTx builds the transaction of a send, build or buildunsafetransaction request. It is not applied to the wallet.
+func (s *Server) buildTx(w *fakeWallet, params json.RawMessage) (*fakeTx, *wasabi.RPCError) {
+	var p txParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: fmt.Sprintf("Invalid params: %v.", err)}
+	}
+	if p.Password != w.password {
+		return nil, walletError(wasabi.ErrorIncorrectPassword)
+	}
+	if len(p.Payments) == 0 {
+		return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: "Missing payments."}
+	}
+	rate := p.FeeRate
+	if rate == 0 {
+		var ok bool
+		if rate, ok = s.feeRate(p.FeeTarget); !ok {
+			return nil, walletError(wasabi.ErrorCannotGetFeeEstimations)
+		}
+	}
+
+	var outputs []txOutput
+	var total wasabi.Amount
+	subtractFee := -1
+	var labels []string
+	for i, payment := range p.Payments {
+		if err := wasabi.ValidateAddress(string(payment.SendTo), s.opts.Network); err != nil {
+			return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: fmt.Sprintf("Invalid address %s.", payment.SendTo)}
+		}
+		if payment.Amount <= 0 {
+			return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: "Amount must be positive."}
+		}
+		if payment.SubtractFee {
+			if subtractFee >= 0 {
+				return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: "Only one payment can subtract the fee."}
+			}
+			subtractFee = i
+		}
+		script, _ := scriptForAddress(payment.SendTo)
+		outputs = append(outputs, txOutput{amount: payment.Amount, script: script})
+		total += payment.Amount
+		if payment.Label != "" {
+			labels = append(labels, payment.Label)
+		}
+	}
+
+	var inputs []*fakeCoin
+	var in wasabi.Amount
+	if len(p.Coins) > 0 {
+		for _, outpoint := range p.Coins {
+			coin := s.findUnspentCoin(w, outpoint.TransactionID, outpoint.Index)
+			if coin == nil {
+				return nil, daemonError("Coin %s:%d was not found.", outpoint.TransactionID, outpoint.Index)
+			}
+			inputs = append(inputs, coin)
+			in += coin.amount
+		}
+	} else {
+		candidates := s.unspentCoins(w)
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].amount > candidates[j].amount })
+		for _, coin := range candidates {
+			needed := total
+			if subtractFee < 0 {
+				needed += fee(rate, len(inputs), len(outputs)+1)
+			}
+			if in >= needed && len(inputs) > 0 {
+				break
+			}
+			inputs = append(inputs, coin)
+			in += coin.amount
+		}
+	}
+
+	txFee := fee(rate, len(inputs), len(outputs)+1)
+	change := in - total - txFee
+	if subtractFee >= 0 {
+		change = in - total
+		if change < dustThreshold {
+			txFee = fee(rate, len(inputs), len(outputs))
+		}
+		outputs[subtractFee].amount -= txFee
+		if outputs[subtractFee].amount < dustThreshold {
+			return nil, walletError(wasabi.ErrorNotPossibleToSubtractTheFee)
+		}
+	}
+	if change < 0 {
+		return nil, daemonError("Insufficient funds: %d sat needed, %d sat available.", total+txFee, in)
+	}
+	if change >= dustThreshold {
+		script, _ := s.cleanKey(w, true).ScriptPubKey.Bytes()
+		outputs = append(outputs, txOutput{amount: change, script: script})
+	}
+	return s.makeTx(w, inputs, outputs, strings.Join(labels, ", ")), nil
+}
+
+func (s *Server) findUnspentCoin(w *fakeWallet, txID wasabi.TxID, index int) *fakeCoin {
+	for _, coin := range w.coins {
+		if coin.txID == txID && coin.index == index && coin.spentBy == "" {
+			return coin
+		}
+	}
+	return nil
+}
+
+func (s *Server) rpcSend(w *fakeWallet, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	tx, err := s.buildTx(w, params)
+	if err != nil {
+		return nil, err
+	}
+	s.apply(w, tx)
+	return wasabi.SendResponse{TransactionID: tx.id, Transaction: tx.hex}, nil
+}
+
+func (s *Server) rpcBuild(w *fakeWallet, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	tx, err := s.buildTx(w, params)
+	if err != nil {
+		return nil, err
+	}
+	s.built[tx.hex] = &builtTx{wallet: w, tx: tx}
+	return tx.hex, nil
+}
+
+func (s *Server) rpcBroadcast(walletName string, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var txHex string
+	if err := decodeParams(params, 1, &txHex); err != nil {
+		return nil, err
+	}
+	built, ok := s.built[txHex]
+	if !ok {
+		raw, err := wasabi.DecodeTransaction(txHex)
+		if err != nil {
+			return nil, daemonError("Invalid transaction: %v.", err)
+		}
+		return raw.TxID, nil
+	}
+	if built.replaces != nil {
+		if s.findTx(built.wallet, built.replaces.id) != built.replaces || built.replaces.height > 0 {
+			return nil, daemonError("The replaced transaction %s is not pending anymore.", built.replaces.id)
+		}
+		s.undo(built.wallet, built.replaces)
+	}
+	for _, coin := range built.tx.inputs {
+		if coin.spentBy != "" {
+			return nil, daemonError("bad-txns-inputs-missingorspent")
+		}
+	}
+	delete(s.built, txHex)
+	s.apply(built.wallet, built.tx)
+	return built.tx.id, nil
+}
+
+// rpcReplaceTransaction builds a transaction replacing a pending outgoing transaction: with a higher fee paid from its change (speed up), or sending all its inputs back to the wallet (cancel).
+func (s *Server) rpcReplaceTransaction(w *fakeWallet, params json.RawMessage, cancel bool) (interface{}, *wasabi.RPCError) {
+	var txID wasabi.TxID
+	var password string
+	if err := decodeParams(params, 2, &txID, &password); err != nil {
+		return nil, err
+	}
+	if password != w.password {
+		return nil, walletError(wasabi.ErrorIncorrectPassword)
+	}
+	reason := wasabi.ErrorTransactionNotSpeedupable
+	if cancel {
+		reason = wasabi.ErrorTransactionNotCancellable
+	}
+	tx := s.findTx(w, txID)
+	if tx == nil || tx.height > 0 || tx.coinJoin || len(tx.inputs) == 0 {
+		return nil, walletError(reason)
+	}
+	var in wasabi.Amount
+	for _, coin := range tx.inputs {
+		in += coin.amount
+	}
+
+	var outputs []txOutput
+	if cancel {
+		newFee := tx.fee*3/2 + fee(1, len(tx.inputs), 1)
+		if in-newFee < dustThreshold {
+			return nil, walletError(reason)
+		}
+		script, _ := s.cleanKey(w, true).ScriptPubKey.Bytes()
+		outputs = []txOutput{{amount: in - newFee, script: script}}
+	} else {
+		extra := tx.fee/2 + fee(1, len(tx.inputs), len(tx.outputs))
+		change := -1
+		for _, coin := range tx.created {
+			if coin.keyPath.IsInternal() && (change < 0 || coin.amount > tx.outputs[change].amount) {
+				change = coin.index
+			}
+		}
+		if change < 0 || tx.outputs[change].amount-extra < dustThreshold {
+			return nil, walletError(reason)
+		}
+		outputs = append([]txOutput(nil), tx.outputs...)
+		outputs[change].amount -= extra
+	}
+	replacement := s.makeTx(w, tx.inputs, outputs, tx.label)
+	s.built[replacement.hex] = &builtTx{wallet: w, tx: replacement, replaces: tx}
+	return replacement.hex, nil
+}
+
+func (s *Server) rpcStartCoinJoin(w *fakeWallet, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var password string
+	var stopWhenAllMixed, overridePlebStop bool
+	if err := decodeParams(params, 1, &password, &stopWhenAllMixed, &overridePlebStop); err != nil {
+		return nil, err
+	}
+	if password != w.password {
+		return nil, walletError(wasabi.ErrorIncorrectPassword)
+	}
+	if s.balance(w) == 0 {
+		return nil, walletError(wasabi.ErrorNotEnoughCoins)
+	}
+	w.coinJoinStatus = wasabi.CoinJoinStatusInSchedule
+	w.stopWhenAllMixed = stopWhenAllMixed
+	w.sweepTo = ""
+	return nil, nil
+}
+
+func (s *Server) rpcStartCoinJoinSweep(w *fakeWallet, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var password, outputWalletName string
+	if err := decodeParams(params, 2, &password, &outputWalletName); err != nil {
+		return nil, err
+	}
+	if password != w.password {
+		return nil, walletError(wasabi.ErrorIncorrectPassword)
+	}
+	if _, ok := s.wallets[outputWalletName]; !ok || outputWalletName == w.name {
+		return nil, walletError(wasabi.ErrorOutputWalletNameInvalid)
+	}
+	w.coinJoinStatus = wasabi.CoinJoinStatusInSchedule
+	w.stopWhenAllMixed = true
+	w.sweepTo = outputWalletName
+	return nil, nil
+}
+
+// failRound fails the coinjoin round of the wallet, e.g. because a participant did not sign: no transaction is created and the payments of the round are pending again.
+func (s *Server) failRound(w *fakeWallet) *RoundResult {
+	result := &RoundResult{WalletName: w.name, Round: w.round, Failed: true}
+	for _, payment := range w.payments {
+		if lastPaymentStatus(payment) == wasabi.PaymentStatusInProgress {
+			payment.State = append(payment.State, wasabi.PaymentInCoinJoinStateHistoryItem{Status: wasabi.PaymentStatusPending})
+		}
+	}
+	w.round = 0
+	w.coinJoinStatus = wasabi.CoinJoinStatusInSchedule
+	return result
+}
+
+// completeRound completes the coinjoin round of the wallet: the coins below the target (all coins if payments are pending, or when sweeping) are mixed into one coin reaching the target and the pending payments are paid.
+func (s *Server) completeRound(w *fakeWallet) *RoundResult {
+	round := w.round
+	if round == 0 {
+		s.sequence++
+		round = s.sequence
+	}
+	w.round = 0
+	result := &RoundResult{WalletName: w.name, Round: round}
+	target := float64(s.opts.AnonScoreTarget)
+	pending := false
+	for _, payment := range w.payments {
+		if status := lastPaymentStatus(payment); status == wasabi.PaymentStatusPending || status == wasabi.PaymentStatusInProgress {
+			pending = true
+		}
+	}
+	var inputs []*fakeCoin
+	var in wasabi.Amount
+	for _, coin := range s.unspentCoins(w) {
+		if !coin.excluded && (coin.anonScore < target || pending || w.sweepTo != "") {
+			inputs = append(inputs, coin)
+			in += coin.amount
+		}
+	}
+
+	if len(inputs) > 0 {
+		rate, _ := s.feeRate(144)
+		rate = math.Max(rate, 1)
+		remaining := in - fee(rate, len(inputs), len(w.payments)+1)
+		var outputs []txOutput
+		var paid []*wasabi.ListPaymentsInCoinJoinResponseItem
+		for _, payment := range w.payments {
+			if status := lastPaymentStatus(payment); status != wasabi.PaymentStatusPending && status != wasabi.PaymentStatusInProgress {
+				continue
+			}
+			if remaining-payment.Amount < dustThreshold {
+				continue
+			}
+			script, _ := payment.Destination.Bytes()
+			outputs = append(outputs, txOutput{amount: payment.Amount, script: script})
+			remaining -= payment.Amount
+			paid = append(paid, payment)
+		}
+		owner := w
+		if w.sweepTo != "" {
+			owner = s.wallets[w.sweepTo]
+		}
+		script, _ := s.cleanKey(owner, true).ScriptPubKey.Bytes()
+		outputs = append(outputs, txOutput{amount: remaining, script: script})
+
+		tx := s.makeTx(w, inputs, outputs, "")
+		tx.coinJoin = true
+		for _, coin := range tx.created {
+			coin.anonScore = target
+		}
+		s.apply(w, tx)
+		if owner != w {
+			received := &fakeTx{id: tx.id, hex: tx.hex, outputs: outputs, coinJoin: true}
+			received.created = s.ownedOutputs(owner, tx.id, outputs)
+			for _, coin := range received.created {
+				coin.anonScore = target
+				received.amount += coin.amount
+			}
+			s.apply(owner, received)
+		}
+		for _, payment := range paid {
+			payment.State = append(payment.State, wasabi.PaymentInCoinJoinStateHistoryItem{Status: wasabi.PaymentStatusFinished, Round: round, TxID: tx.id})
+			result.Paid = append(result.Paid, payment.ID)
+		}
+		result.TxID = tx.id
+	}
+
+	w.coinJoinStatus = wasabi.CoinJoinStatusInSchedule
+	if w.sweepTo != "" {
+		w.coinJoinStatus = wasabi.CoinJoinStatusIdle
+		return result
+	}
+	if w.stopWhenAllMixed {
+		allMixed := true
+		for _, coin := range s.unspentCoins(w) {
+			if !coin.excluded && coin.anonScore < target {
+				allMixed = false
+			}
+		}
+		if allMixed {
+			w.coinJoinStatus = wasabi.CoinJoinStatusIdle
+		}
+	}
+	return result
+}
+
+func (s *Server) rpcExcludeFromCoinJoin(w *fakeWallet, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var txID wasabi.TxID
+	var index int
+	var exclude bool
+	if err := decodeParams(params, 3, &txID, &index, &exclude); err != nil {
+		return nil, err
+	}
+	coin := s.findUnspentCoin(w, txID, index)
+	if coin == nil {
+		return nil, daemonError("Coin %s:%d was not found.", txID, index)
+	}
+	coin.excluded = exclude
+	return nil, nil
+}
+
+func (s *Server) rpcPayInCoinJoin(w *fakeWallet, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var address wasabi.Address
+	var password string
+	var amount wasabi.Amount
+	if err := decodeParams(params, 3, &address, &amount, &password); err != nil {
+		return nil, err
+	}
+	if password != w.password {
+		return nil, walletError(wasabi.ErrorIncorrectPassword)
+	}
+	if err := wasabi.ValidateAddress(string(address), s.opts.Network); err != nil {
+		return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: fmt.Sprintf("Invalid address %s.", address)}
+	}
+	if amount <= 0 {
+		return nil, &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: "Amount must be positive."}
+	}
+	script, _ := scriptForAddress(address)
+	s.sequence++
+	id := hash32("payment", s.sequence)
+	id = id[:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:32]
+	w.payments = append(w.payments, &wasabi.ListPaymentsInCoinJoinResponseItem{
+		ID:          id,
+		Amount:      amount,
+		Destination: wasabi.ScriptPubKey(hex.EncodeToString(script)),
+		State:       []wasabi.PaymentInCoinJoinStateHistoryItem{{Status: wasabi.PaymentStatusPending}},
+		Address:     address,
+	})
+	return id, nil
+}
+
+func (s *Server) rpcCancelPayment(w *fakeWallet, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	var paymentID string
+	if err := decodeParams(params, 1, &paymentID); err != nil {
+		return nil, err
+	}
+	for i, payment := range w.payments {
+		if payment.ID != paymentID {
+			continue
+		}
+		if lastPaymentStatus(payment) != wasabi.PaymentStatusPending {
+			return nil, walletError(wasabi.ErrorPaymentNotPending)
+		}
+		w.payments = append(w.payments[:i], w.payments[i+1:]...)
+		return nil, nil
+	}
+	return nil, walletError(wasabi.ErrorPaymentNotFound)
+}
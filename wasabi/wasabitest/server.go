@@ -0,0 +1,456 @@
+package wasabitest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// ServerOptions holds the options of a fake daemon.
+type ServerOptions struct {
+	// Network is the network reported by the daemon and used for its addresses. Default is wasabi.BitcoinNetworkRegtest.
+	Network wasabi.BitcoinNetwork
+	// RpcUser and RpcPassword enable basic authentication. Requests without the credentials are answered with http status 401.
+	RpcUser     string
+	RpcPassword string
+	// Unsupported are the methods the daemon does not know; calls are answered with E_NO_METHOD, like older daemons do.
+	Unsupported []wasabi.Method
+	// AnonScoreTarget is the anonymity score target of the wallets, reached by the coins mixed in a coinjoin round. Default is 5.
+	AnonScoreTarget int
+	// Height is the initial best blockchain height. Default is 100.
+	Height int
+	// Now returns the time of new transactions. Default is time.Now.
+	Now func() time.Time
+}
+
+// Request is a JSON-RPC request received by the fake daemon. The requests of a batch are recorded one by one.
+type Request struct {
+	Method     wasabi.Method
+	WalletName string
+	Params     json.RawMessage
+	Header     http.Header
+}
+
+// Rule scripts the answer of the fake daemon to the calls of a method: a delay, an error instead of the result and a state transition after the call.
+type Rule struct {
+	method     wasabi.Method
+	walletName string
+	delay      time.Duration
+	rpcErr     *wasabi.RPCError
+	httpStatus int
+	then       func(s *Server)
+	times      int
+	calls      int
+}
+
+// ForWallet limits the rule to the calls of the wallet.
+func (r *Rule) ForWallet(walletName string) *Rule {
+	r.walletName = walletName
+	return r
+}
+
+// Delay delays the answer. The delay ends early if the client gives up the request.
+func (r *Rule) Delay(d time.Duration) *Rule {
+	r.delay = d
+	return r
+}
+
+// Fail answers the calls with the JSON-RPC error instead of calling the method.
+func (r *Rule) Fail(err *wasabi.RPCError) *Rule {
+	r.rpcErr = err
+	return r
+}
+
+// FailWallet answers the calls with the wallet error, as the daemon does when it rejects a request.
+func (r *Rule) FailWallet(reason wasabi.WalletError) *Rule {
+	return r.Fail(walletError(reason))
+}
+
+// FailHTTP answers the calls with the http status, as a proxy in front of the daemon may do.
+func (r *Rule) FailHTTP(status int) *Rule {
+	r.httpStatus = status
+	return r
+}
+
+// Then calls fn after each call, e.g. to move the state of the daemon forward. fn may use the methods of the server.
+func (r *Rule) Then(fn func(s *Server)) *Rule {
+	r.then = fn
+	return r
+}
+
+// Times limits the rule to n calls, later calls are answered normally. Without a limit, the rule applies to all calls.
+func (r *Rule) Times(n int) *Rule {
+	r.times = n
+	return r
+}
+
+// Once limits the rule to one call.
+func (r *Rule) Once() *Rule {
+	return r.Times(1)
+}
+
+// Server is a fake Wasabi daemon serving the JSON-RPC api over http with an in-memory model of wallets, coins, history and coinjoin. It is safe for concurrent use.
+type Server struct {
+	opts   ServerOptions
+	server *httptest.Server
+
+	mutex          sync.Mutex
+	wallets        map[string]*fakeWallet
+	selected       string
+	height         int
+	feeRates       wasabi.GetFeeRatesResponse
+	backendStatus  wasabi.BackendStatus
+	torStatus      wasabi.TorStatus
+	exchangeRate   wasabi.Decimal
+	built          map[string]*builtTx
+	rules          []*Rule
+	requests       []Request
+	stopped        bool
+	sequence       int
+	unsupportedSet map[wasabi.Method]bool
+}
+
+// DefaultFeeRates are the fee rates reported by a fake daemon until they are changed with SetFeeRates.
+var DefaultFeeRates = wasabi.GetFeeRatesResponse{"2": 20, "3": 18, "6": 12, "18": 8, "36": 5, "72": 3, "144": 2, "432": 1, "1008": 1}
+
+// NewServer starts a fake daemon. It must be closed with Close.
+func NewServer(opts ServerOptions) *Server {
+	if opts.Network == "" {
+		opts.Network = wasabi.BitcoinNetworkRegtest
+	}
+	if opts.AnonScoreTarget == 0 {
+		opts.AnonScoreTarget = 5
+	}
+	if opts.Height == 0 {
+		opts.Height = 100
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	s := &Server{
+		opts:           opts,
+		wallets:        map[string]*fakeWallet{},
+		height:         opts.Height,
+		feeRates:       DefaultFeeRates,
+		backendStatus:  wasabi.BackendStatusConnected,
+		torStatus:      wasabi.TorStatusRunning,
+		exchangeRate:   wasabi.DecimalFromFloat(60000),
+		built:          map[string]*builtTx{},
+		unsupportedSet: map[wasabi.Method]bool{},
+	}
+	for _, method := range opts.Unsupported {
+		s.unsupportedSet[method] = true
+	}
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the base url of the server.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Config returns the config of a client of the server.
+func (s *Server) Config() wasabi.Config {
+	host, port, _ := net.SplitHostPort(s.server.Listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return wasabi.Config{
+		Host:        host,
+		Port:        portNumber,
+		RpcUser:     s.opts.RpcUser,
+		RpcPassword: s.opts.RpcPassword,
+	}
+}
+
+// Client returns a client of the server.
+func (s *Server) Client() wasabi.Client {
+	c, err := wasabi.NewClient(s.Config())
+	if err != nil {
+		// The config of the server is always valid.
+		panic(err)
+	}
+	return c
+}
+
+// On adds a rule for the calls of the method. Rules are matched in the order they were added; the first matching rule applies.
+func (s *Server) On(method wasabi.Method) *Rule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	r := &Rule{method: method}
+	s.rules = append(s.rules, r)
+	return r
+}
+
+// Requests returns the requests received by the server.
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Stopped reports whether the daemon was stopped with the stop method. A stopped daemon answers all requests with http status 503.
+func (s *Server) Stopped() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.stopped
+}
+
+type rpcRequest struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcResponse struct {
+	Version string           `json:"jsonrpc"`
+	ID      json.RawMessage  `json:"id,omitempty"`
+	Result  interface{}      `json:"result"`
+	Error   *wasabi.RPCError `json:"error,omitempty"`
+	status  int
+	then    func(s *Server)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.opts.RpcUser != "" {
+		expected := "Basic " + base64.StdEncoding.EncodeToString([]byte(s.opts.RpcUser+":"+s.opts.RpcPassword))
+		if r.Header.Get("Authorization") != expected {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+	if s.Stopped() {
+		http.Error(w, "daemon stopped", http.StatusServiceUnavailable)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	walletName := strings.Trim(r.URL.Path, "/")
+
+	var responses []*rpcResponse
+	batch := len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '['
+	if batch {
+		var requests []rpcRequest
+		if err := json.Unmarshal(body, &requests); err != nil {
+			writeJSON(w, &rpcResponse{Version: "2.0", Error: &wasabi.RPCError{Code: wasabi.E_PARSE, Message: err.Error()}})
+			return
+		}
+		for _, req := range requests {
+			responses = append(responses, s.serve(r, walletName, req))
+		}
+	} else {
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeJSON(w, &rpcResponse{Version: "2.0", Error: &wasabi.RPCError{Code: wasabi.E_PARSE, Message: err.Error()}})
+			return
+		}
+		responses = append(responses, s.serve(r, walletName, req))
+	}
+
+	for _, resp := range responses {
+		if resp.status != 0 {
+			http.Error(w, http.StatusText(resp.status), resp.status)
+			return
+		}
+	}
+	if batch {
+		writeJSON(w, responses)
+	} else {
+		writeJSON(w, responses[0])
+	}
+	for _, resp := range responses {
+		if resp.then != nil {
+			resp.then(s)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// serve answers one JSON-RPC request, applying the first matching rule.
+func (s *Server) serve(r *http.Request, walletName string, req rpcRequest) *rpcResponse {
+	method := wasabi.Method(req.Method)
+	resp := &rpcResponse{Version: "2.0", ID: req.ID}
+
+	s.mutex.Lock()
+	if walletName == "" && method != wasabi.MethodSelectWallet {
+		walletName = s.selected
+	}
+	s.requests = append(s.requests, Request{Method: method, WalletName: walletName, Params: req.Params, Header: r.Header.Clone()})
+	var rule *Rule
+	for _, candidate := range s.rules {
+		if candidate.method == method && (candidate.walletName == "" || candidate.walletName == walletName) && (candidate.times == 0 || candidate.calls < candidate.times) {
+			rule = candidate
+			rule.calls++
+			break
+		}
+	}
+	s.mutex.Unlock()
+
+	if rule != nil {
+		resp.then = rule.then
+		if rule.delay > 0 {
+			timer := time.NewTimer(rule.delay)
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				timer.Stop()
+			}
+		}
+		if rule.httpStatus != 0 {
+			resp.status = rule.httpStatus
+			return resp
+		}
+		if rule.rpcErr != nil {
+			resp.Error = rule.rpcErr
+			return resp
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	resp.Result, resp.Error = s.call(method, walletName, req.Params)
+	return resp
+}
+
+// call dispatches the method. The caller must hold the mutex.
+func (s *Server) call(method wasabi.Method, walletName string, params json.RawMessage) (interface{}, *wasabi.RPCError) {
+	if s.unsupportedSet[method] {
+		return nil, &wasabi.RPCError{Code: wasabi.E_NO_METHOD, Message: "Method not found."}
+	}
+	switch method {
+	case wasabi.MethodGetStatus:
+		return s.status(), nil
+	case wasabi.MethodCreateWallet:
+		return s.rpcCreateWallet(params)
+	case wasabi.MethodRecoverWallet:
+		return s.rpcRecoverWallet(params)
+	case wasabi.MethodLoadWallet:
+		return s.rpcLoadWallet(params)
+	case wasabi.MethodSelectWallet:
+		return s.rpcSelectWallet(params)
+	case wasabi.MethodListWallets:
+		return s.listWallets(), nil
+	case wasabi.MethodGetFeeRates:
+		return s.feeRates, nil
+	case wasabi.MethodStop:
+		s.stopped = true
+		return nil, nil
+	case wasabi.MethodBroadcast:
+		return s.rpcBroadcast(walletName, params)
+	}
+
+	spec, ok := wasabi.LookupMethod(method)
+	if !ok || !spec.WalletScoped {
+		return nil, &wasabi.RPCError{Code: wasabi.E_NO_METHOD, Message: "Method not found."}
+	}
+	if walletName == "" {
+		return nil, &wasabi.RPCError{Code: wasabi.E_INVALID_REQ, Message: "A wallet must be selected."}
+	}
+	w, ok := s.wallets[walletName]
+	if !ok {
+		return nil, daemonError("Wallet '%s' does not exist.", walletName)
+	}
+	if !w.loaded {
+		return nil, walletError(wasabi.ErrorWalletIsNotFullyLoadedYet)
+	}
+
+	switch method {
+	case wasabi.MethodListCoins:
+		return s.listCoins(w, false), nil
+	case wasabi.MethodListUnspentCoins:
+		return s.listCoins(w, true), nil
+	case wasabi.MethodGetWalletInfo:
+		return s.walletInfo(w), nil
+	case wasabi.MethodGetNewAddress:
+		return s.rpcGetNewAddress(w, params)
+	case wasabi.MethodSend:
+		return s.rpcSend(w, params)
+	case wasabi.MethodBuild, wasabi.MethodBuildUnsafeTransaction:
+		return s.rpcBuild(w, params)
+	case wasabi.MethodGetHistory:
+		return s.history(w), nil
+	case wasabi.MethodListKeys:
+		return s.listKeys(w), nil
+	case wasabi.MethodStartCoinJoin:
+		return s.rpcStartCoinJoin(w, params)
+	case wasabi.MethodStartCoinJoinSweep:
+		return s.rpcStartCoinJoinSweep(w, params)
+	case wasabi.MethodStopCoinJoin:
+		if w.round != 0 {
+			// Leaving a round fails it for the wallet.
+			s.failRound(w)
+		}
+		w.coinJoinStatus = wasabi.CoinJoinStatusIdle
+		return nil, nil
+	case wasabi.MethodExcludeFromCoinJoin:
+		return s.rpcExcludeFromCoinJoin(w, params)
+	case wasabi.MethodPayInCoinJoin:
+		return s.rpcPayInCoinJoin(w, params)
+	case wasabi.MethodListPaymentsInCoinJoin:
+		return s.listPayments(w), nil
+	case wasabi.MethodCancelPaymentInCoinJoin:
+		return s.rpcCancelPayment(w, params)
+	case wasabi.MethodCancelTransaction:
+		return s.rpcReplaceTransaction(w, params, true)
+	case wasabi.MethodSpeedUpTransaction:
+		return s.rpcReplaceTransaction(w, params, false)
+	}
+	return nil, &wasabi.RPCError{Code: wasabi.E_NO_METHOD, Message: "Method not found."}
+}
+
+// decodeParams decodes the positional params into out. At least required params must be present.
+func decodeParams(params json.RawMessage, required int, out ...interface{}) *wasabi.RPCError {
+	var values []json.RawMessage
+	if len(params) > 0 && string(params) != "null" {
+		if err := json.Unmarshal(params, &values); err != nil {
+			return &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: "Params must be an array."}
+		}
+	}
+	if len(values) < required {
+		return &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: fmt.Sprintf("Expected %d params, got %d.", required, len(values))}
+	}
+	for i, value := range values {
+		if i >= len(out) {
+			break
+		}
+		if err := json.Unmarshal(value, out[i]); err != nil {
+			return &wasabi.RPCError{Code: wasabi.E_BAD_PARAMS, Message: fmt.Sprintf("Invalid param %d: %v.", i, err)}
+		}
+	}
+	return nil
+}
+
+func walletError(reason wasabi.WalletError) *wasabi.RPCError {
+	return &wasabi.RPCError{Code: wasabi.E_INTERNAL, Message: string(reason)}
+}
+
+func daemonError(format string, args ...interface{}) *wasabi.RPCError {
+	return &wasabi.RPCError{Code: wasabi.E_INTERNAL, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,193 @@
+package wasabitest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// newFundedServer starts a fake daemon with the wallet "w" (password "pw") holding one confirmed coin of 1000000 sat.
+func newFundedServer(t *testing.T, opts ServerOptions) (*Server, wasabi.Client) {
+	t.Helper()
+	s := NewServer(opts)
+	t.Cleanup(s.Close)
+	if err := s.AddWallet("w", "pw"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Fund("w", 1_000_000, "salary"); err != nil {
+		t.Fatal(err)
+	}
+	s.Mine(1)
+	return s, s.Client()
+}
+
+func TestServerSend(t *testing.T) {
+	s, c := newFundedServer(t, ServerOptions{})
+	if err := s.AddWallet("payee", ""); err != nil {
+		t.Fatal(err)
+	}
+	address, err := c.GetNewAddress("payee", "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Send("w", wasabi.SendRequest{
+		Payments:  []wasabi.Payment{{SendTo: address.Address, Amount: 100_000, Label: "payee"}},
+		FeeTarget: wasabi.FeeTargetHour,
+		Password:  "wrong",
+	})
+	if wasabi.Classify(err) != wasabi.ErrorCategoryWallet {
+		t.Fatalf("Send() with a wrong password = %v, want a wallet rejection", err)
+	}
+	sent, err := c.Send("w", wasabi.SendRequest{
+		Payments:  []wasabi.Payment{{SendTo: address.Address, Amount: 100_000, Label: "payee"}},
+		FeeTarget: wasabi.FeeTargetHour,
+		Password:  "pw",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One input, the payment and the change at the rate of FeeTargetHour.
+	fee := wasabi.EstimateFee(float64(DefaultFeeRates["6"]), 1, 2)
+	history, err := c.GetHistory("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].TxID != sent.TransactionID || history[0].Amount != -(100_000+fee) || history[0].Height != 0 {
+		t.Fatalf("history = %+v, want the unconfirmed send of %d sat first", history, 100_000+fee)
+	}
+	coins, err := c.ListUnspentCoins("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coins) != 1 || coins[0].Amount != 1_000_000-100_000-fee || coins[0].Confirmed {
+		t.Fatalf("unspent coins = %+v, want the unconfirmed change", coins)
+	}
+
+	s.Mine(1)
+	coins, err = c.ListUnspentCoins("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !coins[0].Confirmed || coins[0].Confirmations != 1 {
+		t.Fatalf("change after mining = %+v, want one confirmation", coins[0])
+	}
+}
+
+func TestServerSpeedUp(t *testing.T) {
+	s, c := newFundedServer(t, ServerOptions{})
+	address, err := c.GetNewAddress("w", "self")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent, err := c.Send("w", wasabi.SendRequest{
+		Payments:  []wasabi.Payment{{SendTo: address.Address, Amount: 100_000}},
+		FeeTarget: wasabi.FeeTargetHour,
+		Password:  "pw",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHex, err := c.SpeedUpTransaction("w", sent.TransactionID, "pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacement, err := c.Broadcast("w", txHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, err := c.GetHistory("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range history {
+		if tx.TxID == sent.TransactionID {
+			t.Fatalf("the replaced transaction is still in the history: %+v", history)
+		}
+	}
+	if len(history) != 2 || history[0].TxID != replacement {
+		t.Fatalf("history = %+v, want the replacement %s and the funding", history, replacement)
+	}
+	if again, err := c.Broadcast("w", txHex); err != nil || again != replacement {
+		t.Fatalf("Broadcast() of the replacement again = %s, %v, want %s", again, err, replacement)
+	}
+	s.Mine(1)
+	if _, err := c.SpeedUpTransaction("w", replacement, "pw"); wasabi.Classify(err) != wasabi.ErrorCategoryWallet {
+		t.Fatalf("SpeedUpTransaction() of a confirmed transaction = %v, want a wallet rejection", err)
+	}
+}
+
+func TestServerRules(t *testing.T) {
+	s, c := newFundedServer(t, ServerOptions{})
+	s.On(wasabi.MethodGetStatus).FailHTTP(http.StatusBadGateway).Once()
+	s.On(wasabi.MethodGetWalletInfo).ForWallet("w").FailWallet(wasabi.ErrorWalletIsNotFullyLoadedYet).Once()
+	s.On(wasabi.MethodGetHistory).Then(func(s *Server) { s.Mine(1) })
+
+	var httpErr *wasabi.HTTPError
+	if _, err := c.GetStatus(); !errors.As(err, &httpErr) || httpErr.Status != http.StatusBadGateway {
+		t.Fatalf("GetStatus() = %v, want http status 502", err)
+	}
+	if _, err := c.GetStatus(); err != nil {
+		t.Fatalf("GetStatus() after the rule = %v", err)
+	}
+	var rejection *wasabi.WalletRejection
+	if _, err := c.GetWalletInfo("w"); !errors.As(err, &rejection) || rejection.Reason != wasabi.ErrorWalletIsNotFullyLoadedYet {
+		t.Fatalf("GetWalletInfo() = %v, want the wallet rejection", err)
+	}
+	if _, err := c.GetWalletInfo("w"); err != nil {
+		t.Fatalf("GetWalletInfo() after the rule = %v", err)
+	}
+	height := s.Height()
+	if _, err := c.GetHistory("w"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Height() != height+1 {
+		t.Fatalf("Height() = %d after the rule, want %d", s.Height(), height+1)
+	}
+
+	requests := s.Requests()
+	last := requests[len(requests)-1]
+	if last.Method != wasabi.MethodGetHistory || last.WalletName != "w" {
+		t.Fatalf("last request = %s on %q", last.Method, last.WalletName)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	s, c := newFundedServer(t, ServerOptions{Unsupported: []wasabi.Method{wasabi.MethodListKeys}})
+	if _, err := c.ListKeys("w"); !errors.Is(err, wasabi.ErrUnsupportedMethod) {
+		t.Fatalf("ListKeys() = %v, want ErrUnsupportedMethod", err)
+	}
+	if _, err := c.GetWalletInfo("missing"); wasabi.Classify(err) != wasabi.ErrorCategoryDaemon {
+		t.Fatalf("GetWalletInfo() of a missing wallet = %v, want a daemon error", err)
+	}
+	if err := c.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Stopped() {
+		t.Fatal("Stopped() = false after stop")
+	}
+	var httpErr *wasabi.HTTPError
+	if _, err := c.GetStatus(); !errors.As(err, &httpErr) || httpErr.Status != http.StatusServiceUnavailable {
+		t.Fatalf("GetStatus() of a stopped daemon = %v, want http status 503", err)
+	}
+}
+
+func TestServerBasicAuth(t *testing.T) {
+	s := NewServer(ServerOptions{RpcUser: "user", RpcPassword: "secret"})
+	defer s.Close()
+	if _, err := s.Client().GetStatus(); err != nil {
+		t.Fatal(err)
+	}
+	config := s.Config()
+	config.RpcPassword = "wrong"
+	c, err := wasabi.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var httpErr *wasabi.HTTPError
+	if _, err := c.GetStatus(); !errors.As(err, &httpErr) || httpErr.Status != http.StatusUnauthorized {
+		t.Fatalf("GetStatus() with a wrong password = %v, want http status 401", err)
+	}
+}
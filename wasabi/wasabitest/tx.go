@@ -0,0 +1,165 @@
+package wasabitest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/acfnv/go-wasabi-rpc-client/wasabi"
+)
+
+// txOutput is an output of a transaction built by the fake daemon.
+type txOutput struct {
+	amount wasabi.Amount
+	script []byte
+}
+
+// serializeTx returns the hex of a non-witness transaction spending the inputs into the outputs and its txid, so the result can be decoded with wasabi.DecodeTransaction.
+func serializeTx(inputs []wasabi.Coin, outputs []txOutput) (string, wasabi.TxID) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(2))
+	writeVarInt(&buf, uint64(len(inputs)))
+	for _, input := range inputs {
+		prev, _ := hex.DecodeString(string(input.TransactionID))
+		for i := len(prev) - 1; i >= 0; i-- {
+			buf.WriteByte(prev[i])
+		}
+		binary.Write(&buf, binary.LittleEndian, uint32(input.Index))
+		buf.WriteByte(0) // empty scriptSig
+		binary.Write(&buf, binary.LittleEndian, uint32(0xfffffffd))
+	}
+	writeVarInt(&buf, uint64(len(outputs)))
+	for _, output := range outputs {
+		binary.Write(&buf, binary.LittleEndian, uint64(output.amount))
+		writeVarInt(&buf, uint64(len(output.script)))
+		buf.Write(output.script)
+	}
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+
+	first := sha256.Sum256(buf.Bytes())
+	second := sha256.Sum256(first[:])
+	for i, j := 0, len(second)-1; i < j; i, j = i+1, j-1 {
+		second[i], second[j] = second[j], second[i]
+	}
+	return hex.EncodeToString(buf.Bytes()), wasabi.TxID(hex.EncodeToString(second[:]))
+}
+
+func writeVarInt(w *bytes.Buffer, v uint64) {
+	switch {
+	case v < 0xfd:
+		w.WriteByte(byte(v))
+	case v <= 0xffff:
+		w.WriteByte(0xfd)
+		binary.Write(w, binary.LittleEndian, uint16(v))
+	case v <= 0xffffffff:
+		w.WriteByte(0xfe)
+		binary.Write(w, binary.LittleEndian, uint32(v))
+	default:
+		w.WriteByte(0xff)
+		binary.Write(w, binary.LittleEndian, v)
+	}
+}
+
+// scriptForAddress returns the output script paying to the address.
+func scriptForAddress(address wasabi.Address) ([]byte, error) {
+	info, err := address.Decode()
+	if err != nil {
+		return nil, err
+	}
+	switch info.Type {
+	case wasabi.AddressTypeP2PKH:
+		return append(append([]byte{0x76, 0xa9, 0x14}, info.Program...), 0x88, 0xac), nil
+	case wasabi.AddressTypeP2SH:
+		return append(append([]byte{0xa9, 0x14}, info.Program...), 0x87), nil
+	}
+	version := byte(0)
+	if info.WitnessVersion > 0 {
+		version = byte(0x50 + info.WitnessVersion)
+	}
+	return append([]byte{version, byte(len(info.Program))}, info.Program...), nil
+}
+
+// segwitHRPs are the human-readable parts of the segwit addresses of each network.
+var segwitHRPs = map[wasabi.BitcoinNetwork]string{
+	wasabi.BitcoinNetworkMainnet: "bc",
+	wasabi.BitcoinNetworkTestnet: "tb",
+	wasabi.BitcoinNetworkRegtest: "bcrt",
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// p2wpkhAddress returns the segwit v0 address of the 20 bytes key hash on the network.
+func p2wpkhAddress(network wasabi.BitcoinNetwork, keyHash []byte) (wasabi.Address, error) {
+	hrp, ok := segwitHRPs[network]
+	if !ok {
+		return "", fmt.Errorf("unknown network %s", network)
+	}
+	data := []byte{0}
+	// Regroup the 8-bit program into 5-bit groups.
+	acc, bits := 0, uint(0)
+	for _, b := range keyHash {
+		acc = acc<<8 | int(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			data = append(data, byte(acc>>bits&31))
+		}
+	}
+	if bits > 0 {
+		data = append(data, byte(acc<<(5-bits)&31))
+	}
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ 1
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+	return wasabi.Address(sb.String()), nil
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	values := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	return values
+}
+
+func bech32Polymod(values []byte) int {
+	generator := [5]int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := 1
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ int(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// hash20 returns 20 bytes derived from the parts, used as fake key hashes.
+func hash20(parts ...interface{}) []byte {
+	sum := sha256.Sum256([]byte(fmt.Sprint(parts...)))
+	return sum[:20]
+}
+
+// hash32 returns a 32 bytes hex string derived from the parts, used as fake hashes and txids.
+func hash32(parts ...interface{}) string {
+	sum := sha256.Sum256([]byte(fmt.Sprint(parts...)))
+	return hex.EncodeToString(sum[:])
+}
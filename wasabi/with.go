@@ -0,0 +1,91 @@
+package wasabi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ClientOption changes a client derived with Client.With.
+type ClientOption func(*client) error
+
+// WithHeader sets a header sent with every request of the derived client, e.g. a tenant id in a multi-tenant gateway. It replaces a header of the same name set by Config.CustomHeaders.
+func WithHeader(key, value string) ClientOption {
+	return func(c *client) error {
+		if key == "" {
+			return fmt.Errorf("header key must not be empty")
+		}
+		headers := make(map[string]string, len(c.headers)+1)
+		for k, v := range c.headers {
+			headers[k] = v
+		}
+		headers[key] = value
+		c.headers = headers
+		return nil
+	}
+}
+
+// WithTimeout bounds every call of the derived client, in addition to the deadline of its context. Zero removes the bound.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *client) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout must not be negative")
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithEndpoint sends the calls of the derived client to another daemon. The http transport, and so its idle connections, stays shared; the detected capabilities, network, wallet selection and hedging endpoints are not, as they belong to the daemon.
+func WithEndpoint(host string, port int) ClientOption {
+	return func(c *client) error {
+		if err := checkHost(host); err != nil {
+			return err
+		}
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("port must be between 1 and 65535")
+		}
+		if host == c.host && port == c.port {
+			return nil
+		}
+		c.host = host
+		c.port = port
+		c.mutex = &sync.Mutex{}
+		c.selection = &walletSelection{}
+		c.capabilities = &capabilityCache{}
+		c.network = &networkCache{}
+		c.hedges = nil
+		return nil
+	}
+}
+
+func (c *client) With(opts ...ClientOption) (Client, error) {
+	clone := *c
+	for _, opt := range opts {
+		if err := opt(&clone); err != nil {
+			return nil, err
+		}
+	}
+	if len(clone.hedges) > 0 {
+		// The hedging endpoints serve the same daemon, so their requests get the same options.
+		hedges := make([]*client, len(clone.hedges))
+		for i, hedge := range clone.hedges {
+			derived, err := hedge.With(opts...)
+			if err != nil {
+				return nil, err
+			}
+			hedges[i] = derived.(*client)
+		}
+		clone.hedges = hedges
+	}
+	return &clone, nil
+}
+
+// callContext returns the context of a call, bounded by the timeout of the client if it has one.
+func (c *client) callContext() (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return c.ctx, func() {}
+	}
+	return context.WithTimeout(c.ctx, c.timeout)
+}